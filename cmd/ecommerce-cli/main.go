@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/ecommerce/payment-system/internal/cli/commands"
@@ -9,7 +8,6 @@ import (
 
 func main() {
 	if err := commands.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(commands.ExitCode(err))
 	}
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,7 +16,12 @@ type Config struct {
 	Decorators    DecoratorsConfig    `mapstructure:"decorators"`
 	Notifications NotificationsConfig `mapstructure:"notifications"`
 	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Cart          CartConfig          `mapstructure:"cart"`
+	Inventory     InventoryConfig     `mapstructure:"inventory"`
+	Checkout      CheckoutConfig      `mapstructure:"checkout"`
+	Restrictions  []RestrictionConfig `mapstructure:"restrictions"`
 	CLI           CLIConfig           `mapstructure:"cli"`
+	Features      FeatureFlags        `mapstructure:"features"`
 }
 
 type AppConfig struct {
@@ -27,9 +33,15 @@ type AppConfig struct {
 type DatabaseConfig struct {
 	Driver          string        `mapstructure:"driver"`
 	Path            string        `mapstructure:"path"`
+	DSN             string        `mapstructure:"dsn"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	ConnectAttempts int           `mapstructure:"connect_attempts"`
+	ConnectDelay    time.Duration `mapstructure:"connect_delay"`
+	Replicas        []string      `mapstructure:"replicas"`
+	ReadAfterWrite  time.Duration `mapstructure:"read_after_write"`
+	SeedDataset     string        `mapstructure:"seed_dataset"`
 }
 
 type LoggingConfig struct {
@@ -40,12 +52,46 @@ type LoggingConfig struct {
 }
 
 type PaymentConfig struct {
-	Timeout       time.Duration    `mapstructure:"timeout"`
-	RetryAttempts int              `mapstructure:"retry_attempts"`
-	RetryDelay    time.Duration    `mapstructure:"retry_delay"`
-	CreditCard    CreditCardConfig `mapstructure:"credit_card"`
-	PayPal        PayPalConfig     `mapstructure:"paypal"`
-	Crypto        CryptoConfig     `mapstructure:"crypto"`
+	Timeout         time.Duration    `mapstructure:"timeout"`
+	RetryAttempts   int              `mapstructure:"retry_attempts"`
+	RetryDelay      time.Duration    `mapstructure:"retry_delay"`
+	RetryMaxDelay   time.Duration    `mapstructure:"retry_max_delay"`
+	RetryJitter     bool             `mapstructure:"retry_jitter"`
+	Sandbox         bool             `mapstructure:"sandbox"`
+	LimitCurrency   string           `mapstructure:"limit_currency"`
+	FreeOrderMax    float64          `mapstructure:"free_order_max"`
+	SplitTolerance  float64          `mapstructure:"split_tolerance"`
+	DefaultMethod   string           `mapstructure:"default_method"`
+	DefaultStrategy string           `mapstructure:"default_strategy"`
+	CreditCard      CreditCardConfig `mapstructure:"credit_card"`
+	PayPal          PayPalConfig     `mapstructure:"paypal"`
+	Crypto          CryptoConfig     `mapstructure:"crypto"`
+	Escrow          EscrowConfig     `mapstructure:"escrow"`
+}
+
+func (c PaymentConfig) IsMethodEnabled(method string) bool {
+	switch method {
+	case "credit_card":
+		return c.CreditCard.Enabled
+	case "paypal":
+		return c.PayPal.Enabled
+	case "crypto":
+		return c.Crypto.Enabled
+	default:
+		return true
+	}
+}
+
+// DisabledMethods lists the payment methods switched off under payment.*.
+// Methods without an enabled setting are always available.
+func (c PaymentConfig) DisabledMethods() []string {
+	disabled := []string{}
+	for _, method := range []string{"credit_card", "paypal", "crypto"} {
+		if !c.IsMethodEnabled(method) {
+			disabled = append(disabled, method)
+		}
+	}
+	return disabled
 }
 
 type CreditCardConfig struct {
@@ -67,18 +113,29 @@ type CryptoConfig struct {
 	SupportedCurrencies []string `mapstructure:"supported_currencies"`
 }
 
+// EscrowConfig bounds orders paid with the escrow strategy, in
+// payment.limit_currency.
+type EscrowConfig struct {
+	MinAmount float64 `mapstructure:"min_amount"`
+	MaxAmount float64 `mapstructure:"max_amount"`
+}
+
 type DecoratorsConfig struct {
 	Discount       DiscountConfig       `mapstructure:"discount"`
 	Cashback       CashbackConfig       `mapstructure:"cashback"`
 	FraudDetection FraudDetectionConfig `mapstructure:"fraud_detection"`
 	Tax            TaxConfig            `mapstructure:"tax"`
 	LoyaltyPoints  LoyaltyPointsConfig  `mapstructure:"loyalty_points"`
+	ServiceFee     ServiceFeeConfig     `mapstructure:"service_fee"`
 }
 
 type DiscountConfig struct {
-	Enabled        bool    `mapstructure:"enabled"`
-	MaxPercentage  float64 `mapstructure:"max_percentage"`
-	MaxFixedAmount float64 `mapstructure:"max_fixed_amount"`
+	Enabled                   bool    `mapstructure:"enabled"`
+	MaxPercentage             float64 `mapstructure:"max_percentage"`
+	MaxFixedAmount            float64 `mapstructure:"max_fixed_amount"`
+	CombinePercentageAndFixed bool    `mapstructure:"combine_percentage_and_fixed"`
+	// MaxTotalPercentage is of the pre-discount amount; zero means no cap.
+	MaxTotalPercentage float64 `mapstructure:"max_total_percentage"`
 }
 
 type CashbackConfig struct {
@@ -86,8 +143,26 @@ type CashbackConfig struct {
 	Tier1Threshold  float64 `mapstructure:"tier1_threshold"`
 	Tier1Percentage float64 `mapstructure:"tier1_percentage"`
 	Tier2Percentage float64 `mapstructure:"tier2_percentage"`
+	Payout          string  `mapstructure:"payout"`
+	PointsPerUnit   float64 `mapstructure:"points_per_unit"`
+	// Tiers lift the percentage for customers with enough loyalty points.
+	Tiers CustomerTiersConfig `mapstructure:"tiers"`
 }
 
+// CustomerTiersConfig ranks customers into silver and gold by loyalty points.
+type CustomerTiersConfig struct {
+	SilverPoints     int     `mapstructure:"silver_points"`
+	GoldPoints       int     `mapstructure:"gold_points"`
+	BronzePercentage float64 `mapstructure:"bronze_percentage"`
+	SilverPercentage float64 `mapstructure:"silver_percentage"`
+	GoldPercentage   float64 `mapstructure:"gold_percentage"`
+}
+
+const (
+	CashbackPayoutBalance       = "balance"
+	CashbackPayoutLoyaltyPoints = "loyalty_points"
+)
+
 type FraudDetectionConfig struct {
 	Enabled                  bool          `mapstructure:"enabled"`
 	MaxRiskScore             int           `mapstructure:"max_risk_score"`
@@ -95,19 +170,42 @@ type FraudDetectionConfig struct {
 	MaxTransactionsPerWindow int           `mapstructure:"max_transactions_per_window"`
 }
 
+// TaxConfig selects where tax rates come from. The "static" provider uses
+// DefaultRate and Rates, keyed by state, plus LocalRates keyed "STATE/City"
+// or "STATE/postal-code", which are added on top of the state rate; "http"
+// queries an external tax service at URL and caches each region's rate for
+// CacheTTL. CategoryRates, keyed by product category, replace the regional
+// rate for products in that category with either provider.
 type TaxConfig struct {
-	Enabled     bool               `mapstructure:"enabled"`
-	DefaultRate float64            `mapstructure:"default_rate"`
-	Rates       map[string]float64 `mapstructure:"rates"`
+	Enabled       bool               `mapstructure:"enabled"`
+	DefaultRate   float64            `mapstructure:"default_rate"`
+	Rates         map[string]float64 `mapstructure:"rates"`
+	LocalRates    map[string]float64 `mapstructure:"local_rates"`
+	CategoryRates map[string]float64 `mapstructure:"category_rates"`
+	Provider      string             `mapstructure:"provider"`
+	URL           string             `mapstructure:"url"`
+	Timeout       time.Duration      `mapstructure:"timeout"`
+	CacheTTL      time.Duration      `mapstructure:"cache_ttl"`
 }
 
 type LoyaltyPointsConfig struct {
 	Enabled                 bool    `mapstructure:"enabled"`
 	PointsToCurrencyRatio   float64 `mapstructure:"points_to_currency_ratio"`
 	MaxRedemptionPercentage float64 `mapstructure:"max_redemption_percentage"`
+	// EarnRate is the points earned per currency unit of a checkout.
+	EarnRate float64 `mapstructure:"earn_rate"`
+}
+
+type ServiceFeeConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	FeeType     string  `mapstructure:"fee_type"`
+	FeeValue    float64 `mapstructure:"fee_value"`
+	ExemptAbove float64 `mapstructure:"exempt_above"`
 }
 
 type NotificationsConfig struct {
+	// Timeout bounds how long observers may spend on one checkout event.
+	Timeout time.Duration `mapstructure:"timeout"`
 	Email   EmailConfig   `mapstructure:"email"`
 	SMS     SMSConfig     `mapstructure:"sms"`
 	Webhook WebhookConfig `mapstructure:"webhook"`
@@ -115,33 +213,122 @@ type NotificationsConfig struct {
 }
 
 type EmailConfig struct {
-	Enabled        bool   `mapstructure:"enabled"`
-	SMTPHost       string `mapstructure:"smtp_host"`
-	SMTPPort       int    `mapstructure:"smtp_port"`
-	FromAddress    string `mapstructure:"from_address"`
-	WorkerPoolSize int    `mapstructure:"worker_pool_size"`
+	Enabled        bool          `mapstructure:"enabled"`
+	SMTPHost       string        `mapstructure:"smtp_host"`
+	SMTPPort       int           `mapstructure:"smtp_port"`
+	FromAddress    string        `mapstructure:"from_address"`
+	WorkerPoolSize int           `mapstructure:"worker_pool_size"`
+	QueueSize      int           `mapstructure:"queue_size"`
+	EnqueueTimeout time.Duration `mapstructure:"enqueue_timeout"`
+	RetryAttempts  int           `mapstructure:"retry_attempts"`
+	RetryBackoff   time.Duration `mapstructure:"retry_backoff"`
+	Username       string        `mapstructure:"username"`
+	Password       string        `mapstructure:"password"`
+	DryRun         bool          `mapstructure:"dry_run"`
+	Events         []string      `mapstructure:"events"`
+	TemplateDir    string        `mapstructure:"template_dir"`
 }
 
 type SMSConfig struct {
-	Enabled   bool   `mapstructure:"enabled"`
-	Provider  string `mapstructure:"provider"`
-	RateLimit int    `mapstructure:"rate_limit"`
+	Enabled   bool     `mapstructure:"enabled"`
+	Provider  string   `mapstructure:"provider"`
+	RateLimit int      `mapstructure:"rate_limit"`
+	Events    []string `mapstructure:"events"`
 }
 
 type WebhookConfig struct {
 	Enabled       bool          `mapstructure:"enabled"`
+	URL           string        `mapstructure:"url"`
 	Timeout       time.Duration `mapstructure:"timeout"`
 	RetryAttempts int           `mapstructure:"retry_attempts"`
+	Secret        string        `mapstructure:"secret"`
+	Events        []string      `mapstructure:"events"`
+	DLQPath       string        `mapstructure:"dlq_path"`
 }
 
 type AuditConfig struct {
-	Enabled bool   `mapstructure:"enabled"`
-	LogPath string `mapstructure:"log_path"`
+	Enabled    bool              `mapstructure:"enabled"`
+	LogPath    string            `mapstructure:"log_path"`
+	Format     string            `mapstructure:"format"`
+	CartEvents bool              `mapstructure:"cart_events"`
+	Sinks      []AuditSinkConfig `mapstructure:"sinks"`
+}
+
+// AuditSinkConfig configures one audit destination. Type is file, http or
+// stdout; file sinks use Path and Format, http sinks use URL and Timeout.
+type AuditSinkConfig struct {
+	Type    string        `mapstructure:"type"`
+	Path    string        `mapstructure:"path"`
+	Format  string        `mapstructure:"format"`
+	URL     string        `mapstructure:"url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// AuditSinks returns the configured sinks, falling back to a single file
+// sink at LogPath when none are listed.
+func (c AuditConfig) AuditSinks() []AuditSinkConfig {
+	if len(c.Sinks) > 0 {
+		return c.Sinks
+	}
+	return []AuditSinkConfig{{Type: "file", Path: c.LogPath, Format: c.Format}}
+}
+
+// FileSink returns the first file sink, which the audit command reads from.
+func (c AuditConfig) FileSink() (AuditSinkConfig, bool) {
+	for _, sink := range c.AuditSinks() {
+		if sink.Type == "file" {
+			if sink.Path == "" {
+				sink.Path = c.LogPath
+			}
+			return sink, true
+		}
+	}
+	return AuditSinkConfig{}, false
 }
 
 type MetricsConfig struct {
 	Enabled        bool          `mapstructure:"enabled"`
 	ExportInterval time.Duration `mapstructure:"export_interval"`
+	// HTTPAddr serves /metrics in Prometheus format when set, e.g. ":9090".
+	HTTPAddr string `mapstructure:"http_addr"`
+}
+
+type CartConfig struct {
+	AbandonedTTL time.Duration `mapstructure:"abandoned_ttl"`
+}
+
+type InventoryConfig struct {
+	LowStockThreshold int           `mapstructure:"low_stock_threshold"`
+	ReservationTTL    time.Duration `mapstructure:"reservation_ttl"`
+	SweepInterval     time.Duration `mapstructure:"sweep_interval"`
+}
+
+type CheckoutConfig struct {
+	QuoteTTL      time.Duration       `mapstructure:"quote_ttl"`
+	QuoteSecret   string              `mapstructure:"quote_secret"`
+	SpendingLimit SpendingLimitConfig `mapstructure:"spending_limit"`
+	// AutoReprice refreshes cart prices from the catalog at checkout. A price
+	// increase then fails the checkout unless the buyer accepts it.
+	AutoReprice bool `mapstructure:"auto_reprice"`
+	// Timeout bounds a whole checkout, from inventory checks to the charge;
+	// 0 disables it. payment.timeout still bounds the charge itself.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// IdempotencyTTL is how long a pending checkout holds its idempotency key;
+	// a retry after that abandons it and charges afresh. 0 never releases it.
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
+}
+
+// SpendingLimitConfig caps how much a customer can spend in a rolling window.
+// Customer.SpendingLimit overrides Amount; an amount of 0 disables the cap.
+type SpendingLimitConfig struct {
+	Amount float64       `mapstructure:"amount"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+type RestrictionConfig struct {
+	Category   string   `mapstructure:"category"`
+	ProductIDs []string `mapstructure:"product_ids"`
+	Regions    []string `mapstructure:"regions"`
 }
 
 type CLIConfig struct {
@@ -175,17 +362,178 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
+func (c *Config) Validate() error {
+	if c.Payment.Sandbox && c.App.Environment == "production" {
+		return fmt.Errorf("payment.sandbox cannot be enabled when app.environment is production")
+	}
+
+	if c.Checkout.QuoteSecret == "" && c.App.Environment == "production" {
+		return fmt.Errorf("checkout.quote_secret is required when app.environment is production")
+	}
+
+	switch c.Decorators.Tax.Provider {
+	case "", "static":
+	case "http":
+		if c.Decorators.Tax.URL == "" {
+			return fmt.Errorf("decorators.tax.url is required when decorators.tax.provider is http")
+		}
+	default:
+		return fmt.Errorf("decorators.tax.provider %q is not supported (expected static or http)", c.Decorators.Tax.Provider)
+	}
+	for region, rate := range c.Decorators.Tax.LocalRates {
+		if !strings.Contains(region, "/") {
+			return fmt.Errorf("decorators.tax.local_rates key %q must be STATE/City or STATE/postal-code", region)
+		}
+		if rate < 0 {
+			return fmt.Errorf("decorators.tax.local_rates %q cannot be negative", region)
+		}
+	}
+	for category, rate := range c.Decorators.Tax.CategoryRates {
+		if rate < 0 {
+			return fmt.Errorf("decorators.tax.category_rates %q cannot be negative", category)
+		}
+	}
+
+	if err := c.Features.Validate(); err != nil {
+		return err
+	}
+
+	if c.Inventory.ReservationTTL <= 0 {
+		return fmt.Errorf("inventory.reservation_ttl must be positive")
+	}
+	if c.Inventory.SweepInterval <= 0 {
+		return fmt.Errorf("inventory.sweep_interval must be positive")
+	}
+
+	if c.Payment.RetryDelay < 0 || c.Payment.RetryMaxDelay < 0 {
+		return fmt.Errorf("payment.retry_delay and payment.retry_max_delay cannot be negative")
+	}
+
+	if c.Payment.SplitTolerance < 0 {
+		return fmt.Errorf("payment.split_tolerance cannot be negative")
+	}
+
+	if c.Payment.Escrow.MinAmount < 0 || c.Payment.Escrow.MaxAmount < c.Payment.Escrow.MinAmount {
+		return fmt.Errorf("payment.escrow needs 0 <= min_amount <= max_amount")
+	}
+
+	if c.Checkout.SpendingLimit.Amount < 0 {
+		return fmt.Errorf("checkout.spending_limit.amount cannot be negative")
+	}
+
+	if c.Checkout.Timeout < 0 {
+		return fmt.Errorf("checkout.timeout cannot be negative")
+	}
+
+	if c.Checkout.IdempotencyTTL < 0 {
+		return fmt.Errorf("checkout.idempotency_ttl cannot be negative")
+	}
+	if c.Checkout.IdempotencyTTL > 0 && c.Checkout.Timeout > 0 && c.Checkout.IdempotencyTTL <= c.Checkout.Timeout {
+		return fmt.Errorf("checkout.idempotency_ttl must be longer than checkout.timeout")
+	}
+
+	for i, sink := range c.Notifications.Audit.Sinks {
+		switch sink.Type {
+		case "file", "stdout":
+		case "http":
+			if sink.URL == "" {
+				return fmt.Errorf("notifications.audit.sinks[%d]: http sink requires a url", i)
+			}
+		default:
+			return fmt.Errorf("notifications.audit.sinks[%d]: unknown sink type %q (expected file, http or stdout)", i, sink.Type)
+		}
+	}
+
+	if c.Decorators.Discount.MaxTotalPercentage < 0 || c.Decorators.Discount.MaxTotalPercentage > 100 {
+		return fmt.Errorf("decorators.discount.max_total_percentage must be between 0 and 100")
+	}
+
+	if c.Decorators.LoyaltyPoints.EarnRate < 0 {
+		return fmt.Errorf("decorators.loyalty_points.earn_rate cannot be negative")
+	}
+
+	switch c.Decorators.Cashback.Payout {
+	case CashbackPayoutBalance, CashbackPayoutLoyaltyPoints:
+	default:
+		return fmt.Errorf("decorators.cashback.payout must be %q or %q, got %q",
+			CashbackPayoutBalance, CashbackPayoutLoyaltyPoints, c.Decorators.Cashback.Payout)
+	}
+
+	tiers := c.Decorators.Cashback.Tiers
+	if tiers.SilverPoints < 0 || tiers.GoldPoints < 0 {
+		return fmt.Errorf("decorators.cashback.tiers points cannot be negative")
+	}
+	if tiers.SilverPoints > 0 && tiers.GoldPoints > 0 && tiers.GoldPoints < tiers.SilverPoints {
+		return fmt.Errorf("decorators.cashback.tiers.gold_points must be at least silver_points")
+	}
+	for name, pct := range map[string]float64{
+		"bronze": tiers.BronzePercentage,
+		"silver": tiers.SilverPercentage,
+		"gold":   tiers.GoldPercentage,
+	} {
+		if pct < 0 || pct > 100 {
+			return fmt.Errorf("decorators.cashback.tiers.%s_percentage must be between 0 and 100", name)
+		}
+	}
+
+	return nil
+}
+
 func setDefaults(v *viper.Viper) {
 	v.SetDefault("app.name", "E-Commerce Payment System")
 	v.SetDefault("app.version", "1.0.0")
 	v.SetDefault("app.environment", "development")
 	v.SetDefault("database.driver", "sqlite3")
 	v.SetDefault("database.path", "data/ecommerce.db")
+	v.SetDefault("database.connect_attempts", 5)
+	v.SetDefault("database.connect_delay", "500ms")
+	v.SetDefault("database.read_after_write", "2s")
+	v.SetDefault("database.seed_dataset", "minimal")
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
 	v.SetDefault("payment.timeout", "30s")
 	v.SetDefault("payment.retry_attempts", 3)
+	v.SetDefault("payment.retry_delay", "1s")
+	v.SetDefault("payment.retry_max_delay", "10s")
+	v.SetDefault("payment.retry_jitter", true)
+	v.SetDefault("payment.sandbox", false)
+	v.SetDefault("payment.limit_currency", "USD")
+	v.SetDefault("payment.free_order_max", 0.0)
+	v.SetDefault("payment.split_tolerance", 0.01)
+	v.SetDefault("payment.default_method", "credit_card")
+	v.SetDefault("payment.default_strategy", "instant")
+	v.SetDefault("payment.escrow.min_amount", 100.0)
+	v.SetDefault("payment.escrow.max_amount", 50000.0)
+	v.SetDefault("cart.abandoned_ttl", "72h")
+	v.SetDefault("inventory.low_stock_threshold", 5)
+	v.SetDefault("inventory.reservation_ttl", "15m")
+	v.SetDefault("inventory.sweep_interval", "1m")
+	v.SetDefault("checkout.quote_ttl", "15m")
+	v.SetDefault("checkout.spending_limit.amount", 0)
+	v.SetDefault("checkout.spending_limit.window", "720h")
+	v.SetDefault("checkout.auto_reprice", false)
+	v.SetDefault("checkout.timeout", "2m")
+	v.SetDefault("checkout.idempotency_ttl", "15m")
+	v.SetDefault("decorators.tax.provider", "static")
+	v.SetDefault("decorators.tax.timeout", "3s")
+	v.SetDefault("decorators.tax.cache_ttl", "1h")
+	v.SetDefault("decorators.service_fee.fee_type", "flat")
+	v.SetDefault("decorators.loyalty_points.earn_rate", 1.0)
+	v.SetDefault("decorators.cashback.payout", "balance")
+	v.SetDefault("decorators.cashback.points_per_unit", 100.0)
+	v.SetDefault("notifications.timeout", "30s")
+	v.SetDefault("notifications.email.queue_size", 100)
+	v.SetDefault("notifications.email.enqueue_timeout", "2s")
+	v.SetDefault("notifications.email.retry_attempts", 3)
+	v.SetDefault("notifications.email.retry_backoff", "200ms")
+	v.SetDefault("notifications.email.dry_run", true)
+	v.SetDefault("notifications.audit.format", "json")
+	v.SetDefault("notifications.webhook.dlq_path", "logs/webhook_dlq.jsonl")
 }
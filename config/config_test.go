@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentConfigIsMethodEnabled(t *testing.T) {
+	cfg := PaymentConfig{
+		CreditCard: CreditCardConfig{Enabled: true},
+		PayPal:     PayPalConfig{Enabled: false},
+	}
+
+	t.Run("Uses Enabled Settings", func(t *testing.T) {
+		assert.True(t, cfg.IsMethodEnabled("credit_card"))
+		assert.False(t, cfg.IsMethodEnabled("paypal"))
+		assert.False(t, cfg.IsMethodEnabled("crypto"))
+	})
+
+	t.Run("Methods Without A Setting Are Enabled", func(t *testing.T) {
+		assert.True(t, cfg.IsMethodEnabled("gift_card"))
+		assert.True(t, cfg.IsMethodEnabled("wallet"))
+		assert.Equal(t, []string{"paypal", "crypto"}, cfg.DisabledMethods())
+	})
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FeatureBackorders       = "backorders"
+	FeatureTwoPhaseCheckout = "two_phase_checkout"
+)
+
+var knownFeatures = []string{
+	FeatureBackorders,
+	FeatureTwoPhaseCheckout,
+}
+
+// FeatureFlags toggles newer capabilities by name. Flags that are not set are
+// off, so features can ship dark and be enabled per deployment.
+type FeatureFlags map[string]bool
+
+func (f FeatureFlags) Enabled(name string) bool {
+	return f[name]
+}
+
+// KnownFeatures returns the names of all supported flags in sorted order.
+func KnownFeatures() []string {
+	names := make([]string, len(knownFeatures))
+	copy(names, knownFeatures)
+	return names
+}
+
+func (f FeatureFlags) Validate() error {
+	unknown := []string{}
+	for name := range f {
+		if !isKnownFeature(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("features: unknown flag(s) %s (expected one of %s)",
+		strings.Join(unknown, ", "), strings.Join(knownFeatures, ", "))
+}
+
+func isKnownFeature(name string) bool {
+	for _, known := range knownFeatures {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+const redacted = "[redacted]"
+
+// secretSettings are the setting names whose values Settings hides.
+var secretSettings = map[string]bool{
+	"password":     true,
+	"secret":       true,
+	"quote_secret": true,
+	"dsn":          true,
+	"replicas":     true,
+}
+
+// Settings flattens the effective configuration, after defaults and
+// environment overrides, into dotted keys as they are written in
+// config.yaml. Passwords, secrets and connection strings are redacted.
+func (c *Config) Settings() map[string]interface{} {
+	settings := make(map[string]interface{})
+	flattenSettings(reflect.ValueOf(*c), "", settings)
+	return settings
+}
+
+func flattenSettings(v reflect.Value, prefix string, settings map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		value := v.Field(i)
+		switch {
+		case secretSettings[name]:
+			if !value.IsZero() {
+				settings[key] = redacted
+			}
+		case value.Kind() == reflect.Struct:
+			flattenSettings(value, key, settings)
+		default:
+			if d, ok := value.Interface().(time.Duration); ok {
+				settings[key] = d.String()
+			} else {
+				settings[key] = value.Interface()
+			}
+		}
+	}
+}
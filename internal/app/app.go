@@ -2,23 +2,42 @@ package app
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/currency"
 	"github.com/ecommerce/payment-system/internal/facade"
+	"github.com/ecommerce/payment-system/internal/factory"
 	"github.com/ecommerce/payment-system/internal/observer"
 	"github.com/ecommerce/payment-system/internal/repository"
 	"github.com/ecommerce/payment-system/internal/service"
 	"github.com/ecommerce/payment-system/pkg/logger"
 )
 
+const fileStorePath = "data/store.json"
+
 type Application struct {
-	Config          *config.Config
-	Repository      repository.Repository
-	CartService     *service.CartService
-	CustomerService *service.CustomerService
-	CheckoutFacade  *facade.CheckoutFacade
-	EventSubject    *observer.Subject
+	Config             *config.Config
+	Repository         repository.Repository
+	CartService        *service.CartService
+	CustomerService    *service.CustomerService
+	InventoryService   *service.InventoryService
+	TransactionService *service.TransactionService
+	LoyaltyService     *service.LoyaltyService
+	OrderService       *service.OrderService
+	ScheduleService    *service.ScheduleService
+	BillingService     *service.BillingService
+	ReportService      *service.ReportService
+	EscrowService      *service.EscrowService
+	DiscountService    *service.DiscountService
+	CurrencyConverter  *currency.Converter
+	CheckoutFacade     *facade.CheckoutFacade
+	EventSubject       *observer.Subject
+	MetricsCollector   *observer.MetricsCollector
+	WebhookNotifier    *observer.WebhookNotifier
+
+	metricsServer *http.Server
 }
 
 func Initialize(configPath string) (*Application, error) {
@@ -39,36 +58,93 @@ func Initialize(configPath string) (*Application, error) {
 
 	logger.Info(fmt.Sprintf("Starting %s v%s", cfg.App.Name, cfg.App.Version))
 
-	var repo repository.Repository
+	if err := validatePaymentDefaults(cfg); err != nil {
+		return nil, err
+	}
 
-	useDatabase := cfg.App.Environment == "production" || os.Getenv("USE_DATABASE") == "true"
+	if cfg.Payment.Sandbox {
+		logger.Warn("Payment sandbox mode is enabled; processors will simulate failures")
+	}
 
-	if useDatabase {
-		repo, err = repository.NewSQLiteRepository(cfg.Database.Path)
+	seedDataset, err := repository.ParseSeedDataset(cfg.Database.SeedDataset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database.seed_dataset: %w", err)
+	}
+
+	var repo repository.Repository
+
+	if usesDatabase(cfg) {
+		repo, err = newDatabaseRepository(cfg.Database, seedDataset)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize database: %w", err)
 		}
-		fmt.Println("✓ Using SQLite database")
+		fmt.Fprintf(os.Stderr, "✓ Using %s database\n", cfg.Database.Driver)
 	} else {
-		repo, err = repository.NewFileRepository("data/store.json")
+		repo, err = repository.NewFileRepository(fileStorePath, seedDataset)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize file repository: %w", err)
 		}
 	}
 
-	cartService := service.NewCartService(repo)
-	customerService := service.NewCustomerService(repo)
-
 	eventSubject := observer.NewSubject()
 
+	restrictionRules := make([]service.ProductRestriction, 0, len(cfg.Restrictions))
+	for _, rule := range cfg.Restrictions {
+		restrictionRules = append(restrictionRules, service.ProductRestriction{
+			Category:   rule.Category,
+			ProductIDs: rule.ProductIDs,
+			Regions:    rule.Regions,
+		})
+	}
+	restrictions := service.NewRestrictionPolicy(restrictionRules)
+
+	cartService := service.NewCartService(repo, eventSubject, restrictions)
+	cartService.SetAllowBackorders(cfg.Features.Enabled(config.FeatureBackorders))
+	customerService := service.NewCustomerService(repo, eventSubject)
+	inventoryService := service.NewInventoryService(repo, eventSubject, service.InventoryOptions{
+		LowStockThreshold: cfg.Inventory.LowStockThreshold,
+		ReservationTTL:    cfg.Inventory.ReservationTTL,
+	})
+	transactionService := service.NewTransactionService(repo)
+	loyaltyService := service.NewLoyaltyService(customerService)
+	orderService := service.NewOrderService(repo)
+	scheduleService := service.NewScheduleService(repo)
+	billingService := service.NewBillingService(repo)
+	escrowService := service.NewEscrowService(repo, eventSubject)
+	discountService := service.NewDiscountService(repo)
+	currencyConverter := currency.NewDefaultConverter()
+
+	eventSubject.AttachFiltered(observer.NewLoyaltyLedger(repo), observer.LoyaltyEvents...)
+
 	if cfg.Notifications.Email.Enabled {
+		var templates *observer.EmailTemplates
+		if cfg.Notifications.Email.TemplateDir != "" {
+			templates, err = observer.LoadEmailTemplates(cfg.Notifications.Email.TemplateDir)
+			if err != nil {
+				return nil, fmt.Errorf("notifications.email.template_dir: %w", err)
+			}
+		}
 		emailNotifier := observer.NewEmailNotifier(
 			cfg.Notifications.Email.FromAddress,
 			cfg.Notifications.Email.SMTPHost,
 			cfg.Notifications.Email.SMTPPort,
 			cfg.Notifications.Email.WorkerPoolSize,
+			observer.EmailOptions{
+				QueueSize:      cfg.Notifications.Email.QueueSize,
+				EnqueueTimeout: cfg.Notifications.Email.EnqueueTimeout,
+				RetryAttempts:  cfg.Notifications.Email.RetryAttempts,
+				RetryBackoff:   cfg.Notifications.Email.RetryBackoff,
+				Username:       cfg.Notifications.Email.Username,
+				Password:       cfg.Notifications.Email.Password,
+				DryRun:         cfg.Notifications.Email.DryRun,
+				Templates:      templates,
+			},
 		)
-		eventSubject.Attach(emailNotifier)
+		events, err := subscribedEvents(cfg.Notifications.Email.Events, observer.PaymentEvents)
+		if err != nil {
+			return nil, fmt.Errorf("notifications.email.events: %w", err)
+		}
+		eventSubject.AttachFiltered(emailNotifier, events...)
 	}
 
 	if cfg.Notifications.SMS.Enabled {
@@ -76,41 +152,219 @@ func Initialize(configPath string) (*Application, error) {
 			cfg.Notifications.SMS.Provider,
 			cfg.Notifications.SMS.RateLimit,
 		)
-		eventSubject.Attach(smsNotifier)
+		events, err := subscribedEvents(cfg.Notifications.SMS.Events, observer.OutcomeEvents)
+		if err != nil {
+			return nil, fmt.Errorf("notifications.sms.events: %w", err)
+		}
+		eventSubject.AttachFiltered(smsNotifier, events...)
 	}
 
 	if cfg.Notifications.Audit.Enabled {
-		auditLogger, err := observer.NewAuditLogger(cfg.Notifications.Audit.LogPath)
+		sinks, err := newAuditSinks(cfg.Notifications.Audit)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create audit logger: %w", err)
 		}
-		eventSubject.Attach(auditLogger)
+		auditLogger := observer.NewAuditLoggerWithSinks(sinks...)
+		if cfg.Notifications.Audit.CartEvents {
+			eventSubject.Attach(auditLogger)
+		} else {
+			auditEvents := append(append([]observer.EventType{}, observer.PaymentEvents...), observer.LoyaltyEvents...)
+			eventSubject.AttachFiltered(auditLogger, auditEvents...)
+		}
 	}
 
+	var webhookNotifier *observer.WebhookNotifier
+	if cfg.Notifications.Webhook.Enabled && cfg.Notifications.Webhook.URL != "" {
+		webhookNotifier = observer.NewWebhookNotifier(
+			cfg.Notifications.Webhook.URL,
+			cfg.Notifications.Webhook.Timeout,
+			cfg.Notifications.Webhook.RetryAttempts,
+			cfg.Notifications.Webhook.Secret,
+			cfg.Notifications.Webhook.DLQPath,
+		)
+		webhookEvents := make([]observer.EventType, 0,
+			len(observer.PaymentEvents)+len(observer.InventoryEvents)+len(observer.LoyaltyEvents))
+		webhookEvents = append(webhookEvents, observer.PaymentEvents...)
+		webhookEvents = append(webhookEvents, observer.InventoryEvents...)
+		webhookEvents = append(webhookEvents, observer.LoyaltyEvents...)
+		events, err := subscribedEvents(cfg.Notifications.Webhook.Events, webhookEvents)
+		if err != nil {
+			return nil, fmt.Errorf("notifications.webhook.events: %w", err)
+		}
+		eventSubject.AttachFiltered(webhookNotifier, events...)
+	}
+
+	var metricsCollector *observer.MetricsCollector
 	if cfg.Metrics.Enabled {
-		metricsCollector := observer.NewMetricsCollector(cfg.Metrics.ExportInterval)
-		eventSubject.Attach(metricsCollector)
+		metricsCollector = observer.NewMetricsCollector(cfg.Metrics.ExportInterval)
+		eventSubject.AttachFiltered(metricsCollector, observer.OutcomeEvents...)
 	}
 
-	checkoutFacade := facade.NewCheckoutFacade(cfg, repo, eventSubject)
+	checkoutFacade, err := facade.NewCheckoutFacade(
+		cfg,
+		inventoryService,
+		customerService,
+		transactionService,
+		loyaltyService,
+		orderService,
+		scheduleService,
+		billingService,
+		discountService,
+		restrictions,
+		currencyConverter,
+		eventSubject,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var metricsServer *http.Server
+	if metricsCollector != nil && cfg.Metrics.HTTPAddr != "" {
+		metricsServer, err = metricsCollector.StartMetricsServer(cfg.Metrics.HTTPAddr)
+		if err != nil {
+			return nil, fmt.Errorf("metrics.http_addr: %w", err)
+		}
+	}
+
+	scheduleService.SetPaymentProvider(checkoutFacade.NewPayment)
+	billingService.SetPaymentProvider(checkoutFacade.NewPayment)
 
 	app := &Application{
-		Config:          cfg,
-		Repository:      repo,
-		CartService:     cartService,
-		CustomerService: customerService,
-		CheckoutFacade:  checkoutFacade,
-		EventSubject:    eventSubject,
+		Config:             cfg,
+		Repository:         repo,
+		CartService:        cartService,
+		CustomerService:    customerService,
+		InventoryService:   inventoryService,
+		TransactionService: transactionService,
+		LoyaltyService:     loyaltyService,
+		OrderService:       orderService,
+		ScheduleService:    scheduleService,
+		BillingService:     billingService,
+		ReportService:      service.NewReportService(repo),
+		EscrowService:      escrowService,
+		DiscountService:    discountService,
+		CurrencyConverter:  currencyConverter,
+		CheckoutFacade:     checkoutFacade,
+		EventSubject:       eventSubject,
+		MetricsCollector:   metricsCollector,
+		WebhookNotifier:    webhookNotifier,
+		metricsServer:      metricsServer,
 	}
+	inventoryService.StartSweeper(cfg.Inventory.SweepInterval)
 
 	logger.Info("Application initialized successfully")
 
 	return app, nil
 }
 
+func newDatabaseRepository(cfg config.DatabaseConfig, seedDataset repository.SeedDataset) (repository.Repository, error) {
+	opts := repository.ConnectOptions{
+		SeedDataset:     seedDataset,
+		MaxAttempts:     cfg.ConnectAttempts,
+		RetryDelay:      cfg.ConnectDelay,
+		MaxOpenConns:    cfg.MaxOpenConns,
+		MaxIdleConns:    cfg.MaxIdleConns,
+		ConnMaxLifetime: cfg.ConnMaxLifetime,
+	}
+
+	open := func(dsn string, opts repository.ConnectOptions) (repository.Repository, error) {
+		return openDatabase(cfg.Driver, dsn, opts)
+	}
+
+	primary, err := open(primaryDSN(cfg), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Replicas) == 0 {
+		return primary, nil
+	}
+
+	replicas := make([]repository.Repository, 0, len(cfg.Replicas))
+	replicaOpts := opts
+	replicaOpts.ReadOnly = true
+	for _, path := range cfg.Replicas {
+		replica, err := open(path, replicaOpts)
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			primary.Close()
+			return nil, fmt.Errorf("failed to open replica %s: %w", path, err)
+		}
+		replicas = append(replicas, replica)
+	}
+
+	logger.Info(fmt.Sprintf("Using %d read replica(s)", len(replicas)))
+
+	return repository.NewReplicatedRepository(primary, replicas, cfg.ReadAfterWrite), nil
+}
+
+func openDatabase(driver, dsn string, opts repository.ConnectOptions) (repository.Repository, error) {
+	switch driver {
+	case "postgres":
+		return repository.NewPostgresRepository(dsn, opts)
+	case "sqlite3", "":
+		return repository.NewSQLiteRepository(dsn, opts)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+func primaryDSN(cfg config.DatabaseConfig) string {
+	if cfg.Driver == "postgres" {
+		return cfg.DSN
+	}
+	return cfg.Path
+}
+
+// usesDatabase reports whether the application stores data in the configured
+// database rather than the local file store.
+func usesDatabase(cfg *config.Config) bool {
+	return cfg.App.Environment == "production" || os.Getenv("USE_DATABASE") == "true"
+}
+
+func validatePaymentDefaults(cfg *config.Config) error {
+	method := cfg.Payment.DefaultMethod
+	if !factory.NewPaymentFactory().IsSupported(method) {
+		return fmt.Errorf("payment.default_method %q is not a supported payment method", method)
+	}
+	if !cfg.Payment.IsMethodEnabled(method) {
+		return fmt.Errorf("payment.default_method %q is disabled", method)
+	}
+
+	strategyType := cfg.Payment.DefaultStrategy
+	if !factory.NewStrategyFactory().IsSupported(strategyType) {
+		return fmt.Errorf("payment.default_strategy %q is not a supported payment strategy", strategyType)
+	}
+
+	return nil
+}
+
 func (a *Application) Shutdown() error {
 	logger.Info("Shutting down application")
 
+	if err := a.InventoryService.Close(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to stop inventory sweeper: %v", err))
+	}
+
+	if a.metricsServer != nil {
+		if err := observer.StopMetricsServer(a.metricsServer); err != nil {
+			logger.Error(fmt.Sprintf("Failed to stop metrics server: %v", err))
+		}
+	}
+
+	// Checkout events still being delivered go first, then the observers,
+	// then the repository: the email queue drains here and the loyalty ledger
+	// may still be writing.
+	if err := a.CheckoutFacade.Close(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to close checkout facade: %v", err))
+	}
+
+	if err := a.EventSubject.Close(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to close observers: %v", err))
+	}
+
 	if err := a.Repository.Close(); err != nil {
 		logger.Error(fmt.Sprintf("Failed to close repository: %v", err))
 	}
@@ -121,3 +375,39 @@ func (a *Application) Shutdown() error {
 
 	return nil
 }
+
+func newAuditSinks(cfg config.AuditConfig) ([]observer.AuditSink, error) {
+	sinks := []observer.AuditSink{}
+
+	for _, sinkCfg := range cfg.AuditSinks() {
+		switch sinkCfg.Type {
+		case observer.AuditSinkFile:
+			path := sinkCfg.Path
+			if path == "" {
+				path = cfg.LogPath
+			}
+			sink, err := observer.NewFileAuditSink(path, sinkCfg.Format)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, sink)
+		case observer.AuditSinkHTTP:
+			sinks = append(sinks, observer.NewHTTPAuditSink(sinkCfg.URL, sinkCfg.Timeout))
+		case observer.AuditSinkStdout:
+			sinks = append(sinks, observer.NewStdoutAuditSink())
+		default:
+			return nil, fmt.Errorf("unknown audit sink type %q", sinkCfg.Type)
+		}
+	}
+
+	return sinks, nil
+}
+
+// subscribedEvents returns the configured event types for a notifier, or its
+// defaults when none are configured.
+func subscribedEvents(configured []string, defaults []observer.EventType) ([]observer.EventType, error) {
+	if len(configured) == 0 {
+		return defaults, nil
+	}
+	return observer.ParseEventTypes(configured)
+}
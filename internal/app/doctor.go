@@ -0,0 +1,244 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/factory"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/repository"
+)
+
+// Check is the outcome of one installation self-check. A failed critical
+// check means the application cannot start or check out.
+type Check struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Critical bool   `json:"critical"`
+	Detail   string `json:"detail"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+const doctorDialTimeout = 3 * time.Second
+
+// Diagnose runs the installation self-checks without initializing the
+// application, so it still reports on setups where Initialize would fail.
+// decorators are the ones the CLI enables by default at checkout.
+func Diagnose(configPath string, decorators []string) []Check {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return []Check{{
+			Name:     "Configuration",
+			Critical: true,
+			Detail:   err.Error(),
+			Hint:     fmt.Sprintf("Ensure config.yaml exists in %s and fix the reported setting", configPath),
+		}}
+	}
+
+	checks := []Check{{Name: "Configuration", Passed: true, Critical: true, Detail: "loaded and validated"}}
+
+	checks = append(checks, checkPaymentDefaults(cfg))
+	checks = append(checks, checkPaymentMethods(cfg))
+	if cfg.Logging.Output == "file" && cfg.Logging.FilePath != "" {
+		checks = append(checks, checkWritableDir("Log directory", filepath.Dir(cfg.Logging.FilePath)))
+	}
+	checks = append(checks, checkStorage(cfg)...)
+	checks = append(checks, checkNotifiers(cfg)...)
+	checks = append(checks, checkDecorators(cfg, decorators))
+	checks = append(checks, Check{
+		Name:   "Enabled decorators",
+		Passed: true,
+		Detail: strings.Join(factory.NewDecoratorFactory(cfg, nil).GetAvailableDecorators(), ", "),
+	})
+
+	return checks
+}
+
+func checkPaymentDefaults(cfg *config.Config) Check {
+	check := Check{Name: "Payment defaults", Critical: true}
+	if err := validatePaymentDefaults(cfg); err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Set payment.default_method and payment.default_strategy to an enabled method and a supported strategy"
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = fmt.Sprintf("%s / %s", cfg.Payment.DefaultMethod, cfg.Payment.DefaultStrategy)
+	return check
+}
+
+func checkPaymentMethods(cfg *config.Config) Check {
+	check := Check{Name: "Payment methods", Critical: true}
+
+	disabled := make(map[string]bool)
+	for _, method := range cfg.Payment.DisabledMethods() {
+		disabled[method] = true
+	}
+
+	enabled := []string{}
+	for _, method := range factory.NewPaymentFactory().GetSupportedTypes() {
+		if !disabled[method] {
+			enabled = append(enabled, method)
+		}
+	}
+	sort.Strings(enabled)
+
+	if len(enabled) == 0 {
+		check.Detail = "no payment method is enabled"
+		check.Hint = "Enable at least one method under payment.*"
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = strings.Join(enabled, ", ")
+	return check
+}
+
+func checkWritableDir(name, dir string) Check {
+	check := Check{Name: name, Critical: true, Detail: dir}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		check.Detail = err.Error()
+		check.Hint = fmt.Sprintf("Create %s or run from a directory where it can be created", dir)
+		return check
+	}
+
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = fmt.Sprintf("Grant the current user write access to %s", dir)
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.Passed = true
+	return check
+}
+
+func checkStorage(cfg *config.Config) []Check {
+	if !usesDatabase(cfg) {
+		return []Check{checkWritableDir("Data directory", filepath.Dir(fileStorePath))}
+	}
+
+	checks := []Check{}
+	if cfg.Database.Driver != "postgres" {
+		checks = append(checks, checkWritableDir("Data directory", filepath.Dir(cfg.Database.Path)))
+	}
+
+	check := Check{Name: "Database", Critical: true}
+	repo, err := openDatabase(cfg.Database.Driver, primaryDSN(cfg.Database), repository.ConnectOptions{
+		MaxAttempts: 1,
+		ReadOnly:    true,
+	})
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), doctorDialTimeout)
+		err = repo.Ping(ctx)
+		cancel()
+		repo.Close()
+	}
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "Check database.driver and database.path/dsn, and that the database server is running"
+		return append(checks, check)
+	}
+
+	check.Passed = true
+	check.Detail = fmt.Sprintf("%s reachable", cfg.Database.Driver)
+	return append(checks, check)
+}
+
+func checkNotifiers(cfg *config.Config) []Check {
+	notifications := cfg.Notifications
+	checks := []Check{}
+
+	if notifications.Email.Enabled {
+		check := eventsCheck("Email notifier", notifications.Email.Events)
+		if check.Passed && !notifications.Email.DryRun {
+			addr := net.JoinHostPort(notifications.Email.SMTPHost, strconv.Itoa(notifications.Email.SMTPPort))
+			conn, err := net.DialTimeout("tcp", addr, doctorDialTimeout)
+			if err != nil {
+				check.Passed = false
+				check.Detail = err.Error()
+				check.Hint = "Check notifications.email.smtp_host/smtp_port, or set dry_run: true"
+			} else {
+				conn.Close()
+				check.Detail = "SMTP server reachable at " + addr
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	if notifications.SMS.Enabled {
+		check := eventsCheck("SMS notifier", notifications.SMS.Events)
+		if check.Passed && notifications.SMS.Provider == "" {
+			check.Passed = false
+			check.Detail = "no provider configured"
+			check.Hint = "Set notifications.sms.provider"
+		}
+		checks = append(checks, check)
+	}
+
+	if notifications.Webhook.Enabled {
+		check := eventsCheck("Webhook notifier", notifications.Webhook.Events)
+		if check.Passed {
+			if _, err := url.ParseRequestURI(notifications.Webhook.URL); err != nil {
+				check.Passed = false
+				check.Detail = fmt.Sprintf("invalid url %q", notifications.Webhook.URL)
+				check.Hint = "Set notifications.webhook.url to an absolute http(s) URL"
+			}
+		}
+		checks = append(checks, check)
+	}
+
+	if notifications.Audit.Enabled {
+		check := Check{Name: "Audit logger", Passed: true, Detail: "ok"}
+		sinks, err := newAuditSinks(notifications.Audit)
+		if err != nil {
+			check.Passed = false
+			check.Detail = err.Error()
+			check.Hint = "Check notifications.audit.log_path and sink settings"
+		}
+		for _, sink := range sinks {
+			sink.Close()
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func eventsCheck(name string, events []string) Check {
+	check := Check{Name: name, Passed: true, Detail: "ok"}
+	if len(events) == 0 {
+		return check
+	}
+
+	if _, err := observer.ParseEventTypes(events); err != nil {
+		check.Passed = false
+		check.Detail = err.Error()
+		check.Hint = "Remove unknown names from the notifier's events list"
+	}
+	return check
+}
+
+func checkDecorators(cfg *config.Config, decorators []string) Check {
+	check := Check{Name: "Default decorators", Passed: true, Detail: fmt.Sprintf("%v enabled", decorators)}
+
+	err := factory.NewDecoratorFactory(cfg, nil).ValidateDecorators(decorators)
+	if err != nil {
+		check.Passed = false
+		check.Detail = err.Error()
+		check.Hint = "Enable them under decorators.* or pass --decorators at checkout"
+	}
+	return check
+}
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show recent audit log entries",
+	Long:  `Read the audit log (JSON lines or CSV, based on notifications.audit.format) and show the most recent entries.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := GetApplication()
+		auditCfg, ok := app.Config.Notifications.Audit.FileSink()
+		if !ok {
+			if jsonOutput() {
+				return fmt.Errorf("no file audit sink configured")
+			}
+			color.Yellow("No file audit sink configured")
+			return nil
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		eventType, _ := cmd.Flags().GetString("event")
+
+		entries, err := observer.ReadAuditLog(auditCfg.Path, auditCfg.Format)
+		if err != nil {
+			if os.IsNotExist(err) && jsonOutput() {
+				return printJSON([]observer.AuditEntry{})
+			}
+			if os.IsNotExist(err) {
+				color.Yellow("No audit log found at %s", auditCfg.Path)
+				return nil
+			}
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+
+		filtered := make([]observer.AuditEntry, 0, len(entries))
+		for _, entry := range entries {
+			if eventType == "" || entry.EventType == eventType {
+				filtered = append(filtered, entry)
+			}
+		}
+
+		if limit > 0 && len(filtered) > limit {
+			filtered = filtered[len(filtered)-limit:]
+		}
+
+		if jsonOutput() {
+			return printJSON(filtered)
+		}
+
+		if len(filtered) == 0 {
+			color.Yellow("No audit entries found")
+			return nil
+		}
+
+		color.Cyan("Audit Log (%s):", auditCfg.Path)
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Time", "Event", "Transaction", "Customer", "Amount", "Error"})
+
+		for _, entry := range filtered {
+			table.Append([]string{
+				entry.Timestamp,
+				entry.EventType,
+				entry.TransactionID,
+				entry.CustomerID,
+				fmt.Sprintf("$%.2f", entry.Amount),
+				entry.Error,
+			})
+		}
+
+		table.Render()
+		return nil
+	},
+}
+
+func init() {
+	auditCmd.Flags().IntP("limit", "l", 20, "Number of entries to show")
+	auditCmd.Flags().StringP("event", "e", "", "Only show entries of this event type")
+}
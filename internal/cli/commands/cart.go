@@ -5,9 +5,15 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ecommerce/payment-system/config"
 	"github.com/ecommerce/payment-system/internal/app"
+	"github.com/ecommerce/payment-system/internal/currency"
 	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/facade"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -21,9 +27,11 @@ func getCustomer(ctx context.Context, application *app.Application) (*domain.Cus
 
 	customer, err := application.Repository.GetCustomerByEmail(ctx, email)
 	if err != nil {
-		color.Yellow("⚠ Customer not found. Please register first:")
-		color.Yellow("  bin/ecommerce-cli.exe user register --email your@email.com --name \"Your Name\"")
-		return nil, fmt.Errorf("customer not found")
+		if !jsonOutput() {
+			color.Yellow("⚠ Customer not found. Please register first:")
+			color.Yellow("  bin/ecommerce-cli.exe user register --email your@email.com --name \"Your Name\"")
+		}
+		return nil, errors.NewNotFoundError("customer")
 	}
 
 	return customer, nil
@@ -52,6 +60,10 @@ var cartViewCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonOutput() {
+			return printCartJSON(cart)
+		}
+
 		if len(cart.Items) == 0 {
 			color.Yellow("Cart is empty")
 			return nil
@@ -60,17 +72,19 @@ var cartViewCmd = &cobra.Command{
 		table := tablewriter.NewWriter(os.Stdout)
 		table.SetHeader([]string{"Product", "SKU", "Price", "Quantity", "Total"})
 
-		for _, item := range cart.Items {
+		breakdown := cart.Breakdown(domain.PricingInputs{})
+
+		for _, line := range breakdown.Lines {
 			table.Append([]string{
-				item.Product.Name,
-				item.Product.SKU,
-				fmt.Sprintf("$%.2f", item.Price),
-				fmt.Sprintf("%d", item.Quantity),
-				fmt.Sprintf("$%.2f", item.Price*float64(item.Quantity)),
+				line.ProductName,
+				line.SKU,
+				fmt.Sprintf("$%.2f", line.UnitPrice),
+				fmt.Sprintf("%d", line.Quantity),
+				fmt.Sprintf("$%.2f", line.Total),
 			})
 		}
 
-		table.SetFooter([]string{"", "", "", "Total", fmt.Sprintf("$%.2f", cart.GetTotal())})
+		table.SetFooter([]string{"", "", "", "Total", fmt.Sprintf("$%.2f", breakdown.Total)})
 		table.Render()
 
 		return nil
@@ -110,11 +124,76 @@ var cartAddCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonOutput() {
+			return printUpdatedCartJSON(ctx, cart.ID)
+		}
+
 		color.Green("✓ Added %s x%d to cart", product.Name, quantity)
 		return nil
 	},
 }
 
+var cartUpdateCmd = &cobra.Command{
+	Use:   "update [product-id] [quantity]",
+	Short: "Set the quantity of a cart item (0 removes it)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		productID := args[0]
+		quantity, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid quantity: %w", err)
+		}
+		if quantity < 0 {
+			return fmt.Errorf("quantity must be 0 or more")
+		}
+
+		customer, err := getCustomer(ctx, app)
+		if err != nil {
+			return err
+		}
+
+		cart, err := app.CartService.GetOrCreateCart(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		inCart := false
+		for _, item := range cart.Items {
+			if item.ProductID == productID {
+				inCart = true
+				break
+			}
+		}
+		if !inCart {
+			return errors.NewNotFoundError("cart item " + productID)
+		}
+
+		if err := app.CartService.UpdateQuantity(ctx, cart.ID, productID, quantity); err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printUpdatedCartJSON(ctx, cart.ID)
+		}
+
+		updated, err := app.CartService.GetCart(ctx, cart.ID)
+		if err != nil {
+			return err
+		}
+
+		if quantity == 0 {
+			color.Green("✓ Item removed from cart")
+		} else {
+			color.Green("✓ Set %s quantity to %d", productID, quantity)
+		}
+		fmt.Printf("Cart total: $%.2f\n", updated.GetTotal())
+		return nil
+	},
+}
+
 var cartRemoveCmd = &cobra.Command{
 	Use:   "remove [product-id]",
 	Short: "Remove item from cart",
@@ -139,6 +218,10 @@ var cartRemoveCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonOutput() {
+			return printUpdatedCartJSON(ctx, cart.ID)
+		}
+
 		color.Green("✓ Item removed from cart")
 		return nil
 	},
@@ -165,14 +248,291 @@ var cartClearCmd = &cobra.Command{
 			return err
 		}
 
+		if jsonOutput() {
+			return printUpdatedCartJSON(ctx, cart.ID)
+		}
+
 		color.Green("✓ Cart cleared")
 		return nil
 	},
 }
 
+var cartRepriceCmd = &cobra.Command{
+	Use:   "reprice",
+	Short: "Refresh cart prices from the current catalog",
+	Long:  `Update each cart item to the product's current price and report the items whose price changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		customer, err := getCustomer(ctx, app)
+		if err != nil {
+			return err
+		}
+
+		cart, err := app.CartService.GetOrCreateCart(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		changes, err := app.CartService.Reprice(ctx, cart.ID)
+		if err != nil {
+			return fmt.Errorf("failed to reprice cart: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"changes":          changes,
+				"prices_increased": domain.PricesIncreased(changes),
+			})
+		}
+
+		if len(changes) == 0 {
+			color.Green("✓ Cart prices are up to date")
+			return nil
+		}
+
+		printPriceChanges(changes)
+		if domain.PricesIncreased(changes) {
+			color.Yellow("⚠ Some prices went up; checkout will charge the new prices")
+		}
+
+		return nil
+	},
+}
+
+func printPriceChanges(changes []domain.PriceChange) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Product", "Quantity", "Old Price", "New Price", "Change", "Line Change"})
+
+	for _, change := range changes {
+		table.Append([]string{
+			change.ProductName,
+			fmt.Sprintf("%d", change.Quantity),
+			fmt.Sprintf("$%.2f", change.OldPrice),
+			fmt.Sprintf("$%.2f", change.NewPrice),
+			fmt.Sprintf("%+.2f", change.Delta),
+			fmt.Sprintf("%+.2f", change.LineDelta),
+		})
+	}
+
+	table.Render()
+}
+
+var cartPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Purge abandoned carts",
+	Long:  `Remove carts that have not been updated within the configured TTL and emit abandonment events.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		if ttl <= 0 {
+			ttl = app.Config.Cart.AbandonedTTL
+		}
+
+		purged, err := app.CartService.PurgeAbandonedCarts(ctx, ttl)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"purged": purged, "ttl": ttl.String()})
+		}
+
+		color.Green("✓ Purged %d abandoned cart(s) older than %v", purged, ttl)
+		return nil
+	},
+}
+
+var cartTotalCmd = &cobra.Command{
+	Use:   "total",
+	Short: "Show cart total in a given currency",
+	Long:  `Show the cart subtotal converted to the requested currency. When decorators are given, a full quote is computed without reserving inventory or charging a payment.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		targetCurrency, _ := cmd.Flags().GetString("currency")
+		decorators, _ := cmd.Flags().GetStringSlice("decorators")
+		discount, _ := cmd.Flags().GetString("discount")
+		points, _ := cmd.Flags().GetInt("points")
+		method, _ := cmd.Flags().GetString("method")
+		applyConfigDefault(cmd, "method", &method, app.Config.Payment.DefaultMethod)
+
+		customer, err := getCustomer(ctx, app)
+		if err != nil {
+			return err
+		}
+
+		cart, err := app.CartService.GetOrCreateCart(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 {
+			if jsonOutput() {
+				return errors.NewValidationError("cart is empty")
+			}
+			color.Yellow("Cart is empty")
+			return nil
+		}
+
+		rate, err := app.CurrencyConverter.Rate(currency.DefaultCurrency, targetCurrency)
+		if err != nil {
+			return reportCurrencyError(cmd, err)
+		}
+
+		breakdown := cart.Breakdown(domain.PricingInputs{})
+		total := cartTotal{
+			Currency:  strings.ToUpper(targetCurrency),
+			Rate:      rate,
+			ItemCount: breakdown.ItemCount,
+			Subtotal:  breakdown.Subtotal * rate,
+		}
+
+		var prepared *facade.CheckoutQuote
+		if len(decorators) > 0 {
+			prepared, err = app.CheckoutFacade.PrepareCheckout(ctx, cart, customer, domain.CheckoutOptions{
+				PaymentMethod:     method,
+				EnabledDecorators: decorators,
+				DiscountCode:      discount,
+				UseLoyaltyPoints:  points,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to compute quote: %w", err)
+			}
+
+			amounts := prepared.Result.Breakdown
+			total.Discount = amounts.DiscountAmount * rate
+			total.PointsDiscount = amounts.LoyaltyDiscount * rate
+			total.Tax = amounts.TaxAmount * rate
+			total.ServiceFee = amounts.ServiceFeeAmount * rate
+			totalAmount := prepared.Total * rate
+			total.Total = &totalAmount
+
+			if app.Config.Features.Enabled(config.FeatureTwoPhaseCheckout) {
+				total.QuoteToken = prepared.Token
+				total.QuoteExpiresAt = &prepared.ExpiresAt
+			}
+		}
+
+		if jsonOutput() {
+			return printJSON(total)
+		}
+
+		format := func(amount float64) string {
+			return currency.Format(amount, targetCurrency)
+		}
+
+		color.Cyan("Cart Total (%s):", total.Currency)
+		fmt.Printf("  Items:     %d\n", total.ItemCount)
+		fmt.Printf("  Subtotal:  %s\n", format(total.Subtotal))
+
+		if prepared == nil {
+			return nil
+		}
+
+		if total.Discount > 0 {
+			fmt.Printf("  Discount:  -%s\n", format(total.Discount))
+		}
+		if total.PointsDiscount > 0 {
+			fmt.Printf("  Points:    -%s\n", format(total.PointsDiscount))
+		}
+		if total.Tax > 0 {
+			fmt.Printf("  Tax:       %s\n", format(total.Tax))
+		}
+		if total.ServiceFee > 0 {
+			fmt.Printf("  Fee:       %s\n", format(total.ServiceFee))
+		}
+		color.Green("  Total:     %s", format(*total.Total))
+
+		if rate != 1.0 {
+			fmt.Printf("  Rate:      1 %s = %.4f %s\n", currency.DefaultCurrency, rate, total.Currency)
+		}
+
+		if total.QuoteToken == "" {
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Printf("Quote valid until %s. Confirm with:\n", prepared.ExpiresAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("  checkout -m %s -d %s --quote %s\n", method, strings.Join(decorators, ","), prepared.Token)
+
+		return nil
+	},
+}
+
+// cartTotal holds amounts converted to Currency. Total and the quote are only
+// set when decorators were requested.
+type cartTotal struct {
+	Currency       string     `json:"currency"`
+	Rate           float64    `json:"rate"`
+	ItemCount      int        `json:"item_count"`
+	Subtotal       float64    `json:"subtotal"`
+	Discount       float64    `json:"discount,omitempty"`
+	PointsDiscount float64    `json:"points_discount,omitempty"`
+	Tax            float64    `json:"tax,omitempty"`
+	ServiceFee     float64    `json:"service_fee,omitempty"`
+	Total          *float64   `json:"total,omitempty"`
+	QuoteToken     string     `json:"quote_token,omitempty"`
+	QuoteExpiresAt *time.Time `json:"quote_expires_at,omitempty"`
+}
+
+type cartView struct {
+	CartID     string `json:"cart_id"`
+	CustomerID string `json:"customer_id"`
+	domain.CartBreakdown
+}
+
+func printCartJSON(cart *domain.Cart) error {
+	return printJSON(cartView{
+		CartID:        cart.ID,
+		CustomerID:    cart.CustomerID,
+		CartBreakdown: cart.Breakdown(domain.PricingInputs{}),
+	})
+}
+
+func printUpdatedCartJSON(ctx context.Context, cartID string) error {
+	cart, err := GetApplication().CartService.GetCart(ctx, cartID)
+	if err != nil {
+		return err
+	}
+	return printCartJSON(cart)
+}
+
+// reportCurrencyError tells the user an unavailable rate can be avoided by
+// paying in the base currency. The error is still returned so the command
+// exits non-zero.
+func reportCurrencyError(cmd *cobra.Command, err error) error {
+	if jsonOutput() || !errors.HasErrorCode(err, errors.ErrCodeCurrencyUnavailable) {
+		return err
+	}
+	color.Red("✗ Currency conversion unavailable, try base currency (%s)", currency.DefaultCurrency)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return err
+}
+
 func init() {
+	cartTotalCmd.Flags().StringP("currency", "c", currency.DefaultCurrency, "Currency to show the total in")
+	cartTotalCmd.Flags().StringSliceP("decorators", "d", nil, "Decorators to include in the quote")
+	cartTotalCmd.Flags().String("discount", "", "Discount code")
+	cartTotalCmd.Flags().IntP("points", "p", 0, "Loyalty points to use")
+	cartTotalCmd.Flags().StringP("method", "m", "credit_card", "Payment method")
+
+	cartImportCmd.Flags().Bool("strict", false, "Abort the whole import if any line is invalid")
+
+	cartPurgeCmd.Flags().Duration("ttl", 0, "Abandonment TTL (defaults to cart.abandoned_ttl)")
+
 	cartCmd.AddCommand(cartViewCmd)
 	cartCmd.AddCommand(cartAddCmd)
+	cartCmd.AddCommand(cartImportCmd)
+	cartCmd.AddCommand(cartUpdateCmd)
 	cartCmd.AddCommand(cartRemoveCmd)
 	cartCmd.AddCommand(cartClearCmd)
+	cartCmd.AddCommand(cartPurgeCmd)
+	cartCmd.AddCommand(cartRepriceCmd)
+	cartCmd.AddCommand(cartTotalCmd)
 }
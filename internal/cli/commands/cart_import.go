@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type cartImportLine struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+var cartImportCmd = &cobra.Command{
+	Use:   "import [file.json]",
+	Short: "Add many items to the cart from a JSON file",
+	Long: `Add items in bulk from a JSON array of {"product_id": "...", "quantity": N}
+objects. Valid lines are added in one cart update and invalid lines (unknown
+product, non-positive quantity) are reported. With --strict any invalid line
+aborts the import and the cart is left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		lines, err := readCartImport(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read import file: %w", err)
+		}
+
+		customer, err := getCustomer(ctx, app)
+		if err != nil {
+			return err
+		}
+
+		cart, err := app.CartService.GetOrCreateCart(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		items := make([]domain.CartItem, len(lines))
+		for i, line := range lines {
+			items[i] = domain.CartItem{ProductID: line.ProductID, Quantity: line.Quantity}
+		}
+
+		rejected, err := app.CartService.AddItems(ctx, cart.ID, items, strict)
+		if err != nil {
+			if len(rejected) > 0 && !jsonOutput() {
+				printRejectedItems(rejected)
+				fmt.Println()
+			}
+			return err
+		}
+		added := len(lines) - len(rejected)
+
+		updated, err := app.CartService.GetCart(ctx, cart.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"added":    added,
+				"rejected": rejected,
+				"total":    updated.GetTotal(),
+			})
+		}
+
+		if len(rejected) > 0 {
+			printRejectedItems(rejected)
+			fmt.Println()
+		}
+		color.Green("✓ Added %d line(s) to cart, %d rejected", added, len(rejected))
+		fmt.Printf("Cart total: $%.2f\n", updated.GetTotal())
+		return nil
+	},
+}
+
+func readCartImport(path string) ([]cartImportLine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []cartImportLine
+	if err := json.Unmarshal(data, &lines); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of {product_id, quantity}: %w", err)
+	}
+	return lines, nil
+}
+
+func printRejectedItems(rejected []service.RejectedItem) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Line", "Product", "Quantity", "Reason"})
+	for _, item := range rejected {
+		table.Append([]string{
+			fmt.Sprintf("%d", item.Line),
+			item.ProductID,
+			fmt.Sprintf("%d", item.Quantity),
+			item.Reason,
+		})
+	}
+	table.Render()
+}
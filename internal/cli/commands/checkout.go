@@ -3,9 +3,16 @@ package commands
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
+	"strings"
 
+	"github.com/ecommerce/payment-system/internal/currency"
 	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/receipt"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -13,18 +20,50 @@ var (
 	paymentMethod     string
 	paymentStrategy   string
 	enabledDecorators []string
-	discountCode      string
+	discountCodes     []string
 	useLoyaltyPoints  int
+	checkoutMetadata  map[string]string
+	quoteToken        string
+	idempotencyKey    string
+	useCashback       float64
+	previewOnly       bool
+	splitEvenly       int
+	checkoutCurrency  string
+	acceptNewPrices   bool
+	savedMethodID     string
+	interactive       bool
+	billingInterval   string
 )
 
+var defaultCheckoutDecorators = []string{"tax", "fraud_detection"}
+
 var checkoutCmd = &cobra.Command{
 	Use:   "checkout",
 	Short: "Process checkout and payment",
-	Long:  `Process checkout for the current cart with selected payment method and decorators.`,
+	Long: `Process checkout for the current cart with selected payment method and decorators.
+Payment details are taken from the --card-number, --paypal-email, ... flags;
+missing ones are asked for when run interactively. --saved-method charges a
+saved payment method instead.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 		app := GetApplication()
 
+		applyConfigDefault(cmd, "method", &paymentMethod, app.Config.Payment.DefaultMethod)
+		if savedMethodID != "" && !cmd.Flags().Changed("method") {
+			paymentMethod = ""
+		}
+		applyConfigDefault(cmd, "strategy", &paymentStrategy, app.Config.Payment.DefaultStrategy)
+
+		if cmd.Flags().Changed("split-evenly") {
+			if cmd.Flags().Changed("strategy") && paymentStrategy != "split" {
+				return fmt.Errorf("--split-evenly cannot be combined with --strategy %s", paymentStrategy)
+			}
+			if splitEvenly < 2 || splitEvenly > 5 {
+				return fmt.Errorf("--split-evenly must be between 2 and 5")
+			}
+			paymentStrategy = "split"
+		}
+
 		customer, err := getCustomer(ctx, app)
 		if err != nil {
 			return fmt.Errorf("failed to get customer: %w", err)
@@ -36,129 +75,298 @@ var checkoutCmd = &cobra.Command{
 		}
 
 		if len(cart.Items) == 0 {
+			if jsonOutput() {
+				return errors.NewValidationError("cart is empty")
+			}
 			color.Yellow("⚠ Cart is empty. Add items first using 'cart add' command.")
 			return nil
 		}
 
-		printCartSummary(cart)
+		if !jsonOutput() {
+			printCheckoutSummary(cart, customer)
+		}
 
-		fmt.Println()
-		color.Cyan("Customer Information:")
-		fmt.Printf("  Name: %s\n", customer.Name)
-		fmt.Printf("  Email: %s\n", customer.Email)
-		fmt.Printf("  Loyalty Points: %d\n", customer.LoyaltyPoints)
+		options := domain.CheckoutOptions{
+			PaymentMethod:      paymentMethod,
+			PaymentStrategy:    paymentStrategy,
+			SplitParts:         splitEvenly,
+			EnabledDecorators:  enabledDecorators,
+			DiscountCodes:      discountCodes,
+			UseLoyaltyPoints:   useLoyaltyPoints,
+			UseCashback:        useCashback,
+			Currency:           checkoutCurrency,
+			IdempotencyKey:     idempotencyKey,
+			SavedMethodID:      savedMethodID,
+			PaymentDetails:     paymentDetailsFromFlags(cmd),
+			Metadata:           make(map[string]interface{}, len(checkoutMetadata)),
+			AcceptPriceChanges: acceptNewPrices,
+			BillingInterval:    billingInterval,
+		}
+		for key, value := range checkoutMetadata {
+			options.Metadata[key] = value
+		}
 
-		fmt.Println()
-		color.Cyan("Payment Options:")
-		fmt.Printf("  Payment Method: %s\n", paymentMethod)
-		fmt.Printf("  Payment Strategy: %s\n", paymentStrategy)
-		if len(enabledDecorators) > 0 {
-			fmt.Printf("  Enabled Decorators: %v\n", enabledDecorators)
+		if interactive && (jsonOutput() || !stdinIsTerminal()) {
+			if !jsonOutput() {
+				color.Yellow("⚠ --interactive needs a terminal; checking out with the flag values")
+			}
+		} else if interactive {
+			confirmed, err := runCheckoutWizard(ctx, app, customer, &options)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				color.Yellow("Checkout cancelled, nothing was charged.")
+				return nil
+			}
 		}
-		if discountCode != "" {
-			fmt.Printf("  Discount Code: %s\n", discountCode)
+
+		var schedule *domain.PaymentSchedule
+		if paymentStrategy == "deferred" {
+			schedule, err = app.CheckoutFacade.PreviewDeferredSchedule(cart)
+			if err != nil {
+				return reportFailure(err, "✗ Deferred plan unavailable: %v", err)
+			}
+			if !jsonOutput() {
+				fmt.Println()
+				printInstallmentPlan(schedule)
+			}
 		}
-		if useLoyaltyPoints > 0 {
-			fmt.Printf("  Using Loyalty Points: %d\n", useLoyaltyPoints)
+
+		if previewOnly {
+			if jsonOutput() {
+				return printJSON(map[string]interface{}{
+					"cart":     cart.Breakdown(domain.PricingInputs{}),
+					"schedule": schedule,
+				})
+			}
+			color.Yellow("Preview only, nothing was charged.")
+			return nil
 		}
 
-		options := domain.CheckoutOptions{
-			PaymentMethod:     paymentMethod,
-			PaymentStrategy:   paymentStrategy,
-			EnabledDecorators: enabledDecorators,
-			DiscountCode:      discountCode,
-			UseLoyaltyPoints:  useLoyaltyPoints,
+		if options.SavedMethodID == "" && !interactive {
+			if !jsonOutput() {
+				fmt.Println()
+			}
+			promptPaymentDetails(options.PaymentMethod, &options.PaymentDetails)
 		}
 
-		fmt.Println()
-		color.Yellow("⏳ Processing checkout...")
+		if !jsonOutput() {
+			fmt.Println()
+			color.Yellow("⏳ Processing checkout...")
+		}
+
+		process := func() (*domain.Receipt, error) {
+			if quoteToken != "" {
+				return app.CheckoutFacade.ConfirmCheckout(ctx, quoteToken, cart, customer, options)
+			}
+			return app.CheckoutFacade.ProcessOrder(ctx, cart, customer, options)
+		}
 
-		receipt, err := app.CheckoutFacade.ProcessOrder(ctx, cart, customer, options)
+		receipt, err := process()
+		if errors.IsErrorCode(err, errors.ErrCodePriceChanged) && !jsonOutput() {
+			if !confirmPriceChanges(err) {
+				color.Yellow("Checkout cancelled, nothing was charged.")
+				return nil
+			}
+			options.AcceptPriceChanges = true
+			receipt, err = process()
+		}
 		if err != nil {
+			if jsonOutput() {
+				return err
+			}
 			color.Red("✗ Checkout failed: %v", err)
-			return nil
+			printRetryHint(err)
+			// Already reported; return the error only for the exit code.
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return err
+		}
+
+		// The facade only empties its copy of the cart; save that so the
+		// purchased items do not stay in the stored cart.
+		clearErr := app.CartService.ClearCart(ctx, cart.ID)
+
+		if jsonOutput() {
+			return printReceipt(receipt)
 		}
 
 		fmt.Println()
 		printReceipt(receipt)
 
 		color.Green("✓ Checkout completed successfully!")
+		if receipt.Strategy == "recurring" {
+			fmt.Println("Subscription started; see 'subscription list' for the next billing date.")
+		}
+		if clearErr != nil {
+			color.Yellow("Failed to clear cart: %v", clearErr)
+		}
 
 		return nil
 	},
 }
 
 func init() {
-	checkoutCmd.Flags().StringVarP(&paymentMethod, "method", "m", "credit_card", "Payment method (credit_card, paypal, crypto)")
-	checkoutCmd.Flags().StringVarP(&paymentStrategy, "strategy", "s", "instant", "Payment strategy (instant, deferred, split)")
-	checkoutCmd.Flags().StringSliceVarP(&enabledDecorators, "decorators", "d", []string{"tax", "fraud_detection"}, "Enabled decorators")
-	checkoutCmd.Flags().StringVar(&discountCode, "discount", "", "Discount code")
+	checkoutCmd.Flags().StringVarP(&paymentMethod, "method", "m", "credit_card", "Payment method (credit_card, paypal, crypto, wallet); defaults to payment.default_method")
+	checkoutCmd.Flags().StringVarP(&paymentStrategy, "strategy", "s", "instant", "Payment strategy (instant, deferred, split, authorize, escrow, recurring); defaults to payment.default_strategy")
+	checkoutCmd.Flags().StringVar(&savedMethodID, "saved-method", "", "Charge a saved payment method (see 'user payment-method list') instead of entering payment details")
+	checkoutCmd.Flags().StringVar(&billingInterval, "billing-interval", "", "How often a recurring checkout charges again (weekly, monthly, yearly; default monthly)")
+	checkoutCmd.Flags().IntVar(&splitEvenly, "split-evenly", 0, "Split the charge evenly across N payments of the chosen method (2-5)")
+	checkoutCmd.Flags().StringSliceVarP(&enabledDecorators, "decorators", "d", defaultCheckoutDecorators, "Enabled decorators")
+	checkoutCmd.Flags().StringSliceVar(&discountCodes, "discount", nil, "Discount codes; repeat or comma-separate to stack them (decorators.discount stacking rules apply)")
 	checkoutCmd.Flags().IntVarP(&useLoyaltyPoints, "points", "p", 0, "Loyalty points to use")
+	checkoutCmd.Flags().StringVar(&checkoutCurrency, "currency", currency.DefaultCurrency, "Currency the order is charged in")
+	checkoutCmd.Flags().Float64Var(&useCashback, "cashback", 0, "Cashback balance to redeem against the order total")
+	checkoutCmd.Flags().BoolVar(&acceptNewPrices, "accept-price-changes", false, "Check out at current catalog prices without asking when they rose (checkout.auto_reprice)")
+	checkoutCmd.Flags().StringVar(&quoteToken, "quote", "", "Quote token from 'cart total' to confirm at the quoted price")
+	checkoutCmd.Flags().StringVar(&idempotencyKey, "idempotency-key", "", "Key that makes re-running the same checkout return the original receipt instead of charging again")
+	checkoutCmd.Flags().BoolVar(&previewOnly, "preview", false, "Show the payment plan (full installment schedule for deferred) without charging")
+	checkoutCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Choose the payment method, decorators, discount and points step by step")
+	addPaymentDetailFlags(checkoutCmd)
+	checkoutCmd.Flags().StringToStringVar(&checkoutMetadata, "meta", nil, "Checkout metadata as key=value (e.g. force_fraud=true in sandbox mode)")
+}
+
+// confirmPriceChanges shows the increases behind an ErrCodePriceChanged
+// failure and asks whether to pay the new prices.
+func confirmPriceChanges(err error) bool {
+	color.Yellow("⚠ Prices changed since these items were added to the cart:")
+	if changes, ok := errors.Detail(err, "changes"); ok {
+		if changes, ok := changes.([]domain.PriceChange); ok {
+			printPriceChanges(changes)
+		}
+	}
+
+	fmt.Print("Continue at the new prices? [y/N]: ")
+	answer, _ := stdin.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func printRetryHint(err error) {
+	if retryAfter, ok := errors.RetryAfter(err); ok {
+		color.Yellow("  Try again in %ds", int(math.Ceil(retryAfter.Seconds())))
+	}
+}
+
+func printCheckoutSummary(cart *domain.Cart, customer *domain.Customer) {
+	printCartSummary(cart)
+
+	fmt.Println()
+	color.Cyan("Customer Information:")
+	fmt.Printf("  Name: %s\n", customer.Name)
+	fmt.Printf("  Email: %s\n", customer.Email)
+	fmt.Printf("  Loyalty Points: %d\n", customer.LoyaltyPoints)
+	if customer.CashbackBalance > 0 {
+		fmt.Printf("  Cashback Balance: $%.2f\n", customer.CashbackBalance)
+	}
+
+	fmt.Println()
+	color.Cyan("Payment Options:")
+	fmt.Printf("  Payment Method: %s\n", paymentMethod)
+	fmt.Printf("  Payment Strategy: %s\n", paymentStrategy)
+	if !strings.EqualFold(checkoutCurrency, currency.DefaultCurrency) {
+		fmt.Printf("  Currency: %s\n", strings.ToUpper(checkoutCurrency))
+	}
+	if splitEvenly > 0 {
+		fmt.Printf("  Split Evenly: %d parts\n", splitEvenly)
+	}
+	if len(enabledDecorators) > 0 {
+		fmt.Printf("  Enabled Decorators: %v\n", enabledDecorators)
+	}
+	if len(discountCodes) > 0 {
+		fmt.Printf("  Discount Codes: %s\n", strings.Join(discountCodes, ", "))
+	}
+	if useLoyaltyPoints > 0 {
+		fmt.Printf("  Using Loyalty Points: %d\n", useLoyaltyPoints)
+	}
+	if useCashback > 0 {
+		fmt.Printf("  Using Cashback: $%.2f\n", useCashback)
+	}
 }
 
 func printCartSummary(cart *domain.Cart) {
+	breakdown := cart.Breakdown(domain.PricingInputs{})
+
 	color.Cyan("Cart Summary:")
-	fmt.Printf("  Items: %d\n", cart.GetItemCount())
-	fmt.Printf("  Total: $%.2f\n", cart.GetTotal())
+	fmt.Printf("  Items: %d\n", breakdown.ItemCount)
+	fmt.Printf("  Total: $%.2f\n", breakdown.Total)
 	fmt.Println()
 	fmt.Println("  Items:")
-	for _, item := range cart.Items {
+	for _, line := range breakdown.Lines {
 		fmt.Printf("    - %s x%d @ $%.2f = $%.2f\n",
-			item.Product.Name,
-			item.Quantity,
-			item.Price,
-			item.Price*float64(item.Quantity),
+			line.ProductName,
+			line.Quantity,
+			line.UnitPrice,
+			line.Total,
 		)
 	}
 }
 
-func printReceipt(receipt *domain.Receipt) {
+func printInstallmentPlan(schedule *domain.PaymentSchedule) {
+	color.Cyan("Installment Plan:")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"#", "Due Date", "Amount"})
+	for _, p := range schedule.Payments {
+		table.Append([]string{
+			fmt.Sprintf("%d", p.InstallmentNumber),
+			p.DueDate.Format("2006-01-02"),
+			fmt.Sprintf("$%.2f", p.Amount),
+		})
+	}
+	table.Render()
+
+	fmt.Printf("  Principal:     $%.2f\n", schedule.TotalAmount)
+	fmt.Printf("  Interest Rate: %.2f%%\n", schedule.InterestRate)
+	fmt.Printf("  Total:         $%.2f\n", schedule.TotalWithInterest())
+}
+
+// printReceipt renders the receipt, or emits it as JSON with --output json.
+func printReceipt(issued *domain.Receipt) error {
+	if jsonOutput() {
+		return printJSON(issued)
+	}
+
+	view := receipt.NewView(issued)
+
 	color.Cyan("═══════════════════════════════════════")
-	color.Cyan("              RECEIPT")
+	color.Cyan("%s", view.TitleLine())
 	color.Cyan("═══════════════════════════════════════")
 	fmt.Println()
 
-	fmt.Printf("Transaction ID: %s\n", receipt.TransactionID)
-	fmt.Printf("Date: %s\n", receipt.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Println()
-
-	fmt.Printf("Customer: %s\n", receipt.CustomerName)
-	fmt.Printf("Email: %s\n", receipt.CustomerEmail)
+	for _, line := range view.HeaderLines() {
+		fmt.Println(line)
+	}
 	fmt.Println()
 
-	color.Cyan("Items:")
-	for _, item := range receipt.Items {
-		fmt.Printf("  %-30s x%-3d $%8.2f\n",
-			item.ProductName,
-			item.Quantity,
-			item.Total,
-		)
+	color.Cyan("%s:", view.Labels.Items)
+	for _, item := range view.Items {
+		fmt.Println(item.String())
 	}
 	fmt.Println()
 
-	color.Cyan("Amounts:")
-	fmt.Printf("  Subtotal:          $%8.2f\n", receipt.Subtotal)
-	if receipt.Discount > 0 {
-		fmt.Printf("  Discount:          -$%8.2f\n", receipt.Discount)
+	color.Cyan("%s", view.AmountsHeading())
+	for _, line := range view.Amounts {
+		fmt.Println(line.String())
 	}
-	if receipt.Tax > 0 {
-		fmt.Printf("  Tax:               $%8.2f\n", receipt.Tax)
+	color.Green("%s", view.Total.String())
+	for _, line := range view.Base {
+		fmt.Println(line.String())
 	}
-	color.Green("  Total:             $%8.2f\n", receipt.Total)
 	fmt.Println()
 
-	if receipt.Cashback > 0 {
-		color.Yellow("  Cashback Earned:   $%8.2f\n", receipt.Cashback)
-	}
-	if receipt.LoyaltyPoints > 0 {
-		color.Yellow("  Loyalty Points:    %d points\n", receipt.LoyaltyPoints)
+	for _, line := range view.Rewards {
+		color.Yellow("%s", line.String())
 	}
 
-	if len(receipt.AppliedDecorators) > 0 {
+	if view.Features != "" {
 		fmt.Println()
-		fmt.Printf("Applied Features: %v\n", receipt.AppliedDecorators)
+		fmt.Println(view.FeaturesLine())
 	}
 
 	fmt.Println()
 	color.Cyan("═══════════════════════════════════════")
+
+	return nil
 }
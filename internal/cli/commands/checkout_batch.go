@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/app"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+type batchOrderItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type batchOrder struct {
+	Customer   string           `json:"customer"`
+	Items      []batchOrderItem `json:"items"`
+	Method     string           `json:"method"`
+	Strategy   string           `json:"strategy"`
+	Decorators []string         `json:"decorators"`
+	Discount   string           `json:"discount"`
+	// Payment holds the details the method is charged with, unless
+	// SavedMethod names a saved payment method.
+	Payment     domain.PaymentDetails `json:"payment"`
+	SavedMethod string                `json:"saved_method"`
+}
+
+type batchResult struct {
+	TransactionID string
+	Amount        float64
+	Err           error
+}
+
+var checkoutBatchCmd = &cobra.Command{
+	Use:   "batch [file]",
+	Short: "Check out many orders from a JSON file",
+	Long: `Process a JSON array of orders concurrently. Each order names a customer
+email, its items and how it pays, either "payment" details for the method or
+a "saved_method" ID; method, strategy, decorators and discount are optional:
+
+  [{"customer": "john.doe@example.com",
+    "items": [{"product_id": "prod-1", "quantity": 1}],
+    "method": "credit_card", "decorators": ["tax"],
+    "payment": {"card_number": "4532015112830366", "card_holder": "John Doe",
+                "expiry_date": "12/30", "cvv": "123"}}]
+
+Results are printed in input order once all orders finish.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		if concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+
+		orders, err := readBatchOrders(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read batch file: %w", err)
+		}
+
+		results := make([]batchResult, len(orders))
+		indexes := make(chan int)
+
+		start := time.Now()
+
+		var wg sync.WaitGroup
+		for w := 0; w < concurrency && w < len(orders); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					results[i] = processBatchOrder(ctx, app, orders[i])
+				}
+			}()
+		}
+
+		for i := range orders {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+
+		elapsed := time.Since(start)
+
+		if jsonOutput() {
+			return printBatchJSON(orders, results, elapsed, concurrency)
+		}
+
+		succeeded, failed := 0, 0
+		total := 0.0
+		for i, result := range results {
+			if result.Err != nil {
+				failed++
+				code := errors.GetErrorCode(result.Err)
+				message := strings.TrimPrefix(result.Err.Error(), code+": ")
+				color.Red("#%-4d %-28s FAILED  %s  %s", i+1, orders[i].Customer, code, message)
+				continue
+			}
+			succeeded++
+			total += result.Amount
+			color.Green("#%-4d %-28s OK      %s  $%.2f", i+1, orders[i].Customer, result.TransactionID, result.Amount)
+		}
+
+		fmt.Println()
+		color.Cyan("Batch Summary:")
+		fmt.Printf("  Orders:      %d\n", len(orders))
+		fmt.Printf("  Succeeded:   %d\n", succeeded)
+		fmt.Printf("  Failed:      %d\n", failed)
+		fmt.Printf("  Processed:   $%.2f\n", total)
+		fmt.Printf("  Elapsed:     %s\n", elapsed.Round(time.Millisecond))
+		fmt.Printf("  Concurrency: %d\n", concurrency)
+
+		return nil
+	},
+}
+
+type batchResultView struct {
+	Index         int        `json:"index"`
+	Customer      string     `json:"customer"`
+	TransactionID string     `json:"transaction_id,omitempty"`
+	Amount        float64    `json:"amount,omitempty"`
+	Error         *jsonError `json:"error,omitempty"`
+}
+
+func printBatchJSON(orders []batchOrder, results []batchResult, elapsed time.Duration, concurrency int) error {
+	views := make([]batchResultView, len(results))
+	succeeded, total := 0, 0.0
+	for i, result := range results {
+		views[i] = batchResultView{Index: i + 1, Customer: orders[i].Customer}
+		if result.Err != nil {
+			code := errors.GetErrorCode(result.Err)
+			views[i].Error = &jsonError{Code: code, Message: strings.TrimPrefix(result.Err.Error(), code+": ")}
+			continue
+		}
+		succeeded++
+		total += result.Amount
+		views[i].TransactionID = result.TransactionID
+		views[i].Amount = result.Amount
+	}
+
+	return printJSON(map[string]interface{}{
+		"results": views,
+		"summary": map[string]interface{}{
+			"orders":      len(orders),
+			"succeeded":   succeeded,
+			"failed":      len(orders) - succeeded,
+			"processed":   total,
+			"elapsed_ms":  elapsed.Milliseconds(),
+			"concurrency": concurrency,
+		},
+	})
+}
+
+func processBatchOrder(ctx context.Context, app *app.Application, order batchOrder) batchResult {
+	if len(order.Items) == 0 {
+		return batchResult{Err: errors.NewValidationError("order has no items")}
+	}
+
+	customer, err := app.Repository.GetCustomerByEmail(ctx, order.Customer)
+	if err != nil {
+		return batchResult{Err: err}
+	}
+
+	cart := &domain.Cart{ID: domain.NewID(), CustomerID: customer.ID}
+	for _, item := range order.Items {
+		if item.Quantity <= 0 {
+			return batchResult{Err: errors.NewValidationError(fmt.Sprintf("invalid quantity %d for %s", item.Quantity, item.ProductID))}
+		}
+		product, err := app.InventoryService.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return batchResult{Err: err}
+		}
+		cart.AddItem(*product, item.Quantity)
+	}
+
+	options := domain.CheckoutOptions{
+		PaymentMethod:     order.Method,
+		PaymentStrategy:   order.Strategy,
+		EnabledDecorators: order.Decorators,
+		DiscountCode:      order.Discount,
+		PaymentDetails:    order.Payment,
+		SavedMethodID:     order.SavedMethod,
+	}
+	if options.PaymentMethod == "" && options.SavedMethodID == "" {
+		options.PaymentMethod = app.Config.Payment.DefaultMethod
+	}
+	if options.PaymentStrategy == "" {
+		options.PaymentStrategy = app.Config.Payment.DefaultStrategy
+	}
+
+	receipt, err := app.CheckoutFacade.ProcessOrder(ctx, cart, customer, options)
+	if err != nil {
+		return batchResult{Err: err}
+	}
+
+	return batchResult{TransactionID: receipt.TransactionID, Amount: receipt.Total}
+}
+
+func readBatchOrders(path string) ([]batchOrder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []batchOrder
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func init() {
+	checkoutBatchCmd.Flags().Int("concurrency", 4, "Number of orders processed in parallel")
+
+	checkoutCmd.AddCommand(checkoutBatchCmd)
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var checkoutCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare quotes for several decorator combinations",
+	Long: `Run a dry-run quote of the same cart for each decorator combination and
+show the results side by side. Nothing is charged and no inventory is reserved.
+
+Combinations are separated by "|" and decorators within one by ",", e.g.
+  checkout compare --combos "tax|tax,discount|tax,discount,loyalty_points" --discount WELCOME10`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		cartID, _ := cmd.Flags().GetString("cart")
+		combos, _ := cmd.Flags().GetString("combos")
+		discount, _ := cmd.Flags().GetString("discount")
+		points, _ := cmd.Flags().GetInt("points")
+		method, _ := cmd.Flags().GetString("method")
+		applyConfigDefault(cmd, "method", &method, app.Config.Payment.DefaultMethod)
+
+		cart, customer, err := compareCart(ctx, cartID)
+		if err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 {
+			if jsonOutput() {
+				return errors.NewValidationError("cart is empty")
+			}
+			color.Yellow("Cart is empty")
+			return nil
+		}
+
+		quotes := []comboQuote{}
+		for _, combo := range parseDecoratorCombos(combos) {
+			quote := comboQuote{Decorators: combo, Subtotal: cart.GetTotal()}
+
+			prepared, err := app.CheckoutFacade.PrepareCheckout(ctx, cart, customer, domain.CheckoutOptions{
+				PaymentMethod:     method,
+				EnabledDecorators: combo,
+				DiscountCode:      discount,
+				UseLoyaltyPoints:  points,
+			})
+			if err != nil {
+				quote.Error = err.Error()
+				quotes = append(quotes, quote)
+				continue
+			}
+
+			amounts := prepared.Result.Breakdown
+			quote.Discount = amounts.DiscountAmount + amounts.LoyaltyDiscount
+			quote.Tax = amounts.TaxAmount
+			quote.Fees = amounts.ServiceFeeAmount
+			quote.Cashback = amounts.CashbackAmount
+			quote.Total = prepared.Total
+			quotes = append(quotes, quote)
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"cart_id": cart.ID, "quotes": quotes})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Decorators", "Subtotal", "Discount", "Tax", "Fees", "Cashback", "Total"})
+
+		money := func(amount float64) string {
+			return fmt.Sprintf("$%.2f", amount)
+		}
+
+		for _, quote := range quotes {
+			label := strings.Join(quote.Decorators, ",")
+			if label == "" {
+				label = "(none)"
+			}
+
+			if quote.Error != "" {
+				table.Append([]string{label, "", "", "", "", "", color.RedString("error: %s", quote.Error)})
+				continue
+			}
+
+			table.Append([]string{
+				label,
+				money(quote.Subtotal),
+				money(quote.Discount),
+				money(quote.Tax),
+				money(quote.Fees),
+				money(quote.Cashback),
+				money(quote.Total),
+			})
+		}
+
+		color.Cyan("Quote comparison for cart %s:", cart.ID)
+		table.Render()
+
+		return nil
+	},
+}
+
+type comboQuote struct {
+	Decorators []string `json:"decorators"`
+	Subtotal   float64  `json:"subtotal"`
+	Discount   float64  `json:"discount"`
+	Tax        float64  `json:"tax"`
+	Fees       float64  `json:"fees"`
+	Cashback   float64  `json:"cashback"`
+	Total      float64  `json:"total"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// compareCart loads the cart by ID together with its owner, or the current
+// customer's cart when no ID is given.
+func compareCart(ctx context.Context, cartID string) (*domain.Cart, *domain.Customer, error) {
+	app := GetApplication()
+
+	if cartID == "" {
+		customer, err := getCustomer(ctx, app)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get customer: %w", err)
+		}
+		cart, err := app.CartService.GetOrCreateCart(ctx, customer.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get cart: %w", err)
+		}
+		return cart, customer, nil
+	}
+
+	cart, err := app.CartService.GetCart(ctx, cartID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	customer, err := app.CustomerService.GetCustomer(ctx, cart.CustomerID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cart owner: %w", err)
+	}
+	return cart, customer, nil
+}
+
+func parseDecoratorCombos(combos string) [][]string {
+	result := [][]string{}
+	for _, combo := range strings.Split(combos, "|") {
+		decorators := []string{}
+		for _, name := range strings.Split(combo, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				decorators = append(decorators, name)
+			}
+		}
+		result = append(result, decorators)
+	}
+	return result
+}
+
+func init() {
+	checkoutCompareCmd.Flags().String("cart", "", "Cart ID (defaults to the current customer's cart)")
+	checkoutCompareCmd.Flags().String("combos", "tax", `Decorator combinations separated by "|"`)
+	checkoutCompareCmd.Flags().String("discount", "", "Discount code used by combinations with the discount decorator")
+	checkoutCompareCmd.Flags().IntP("points", "p", 0, "Loyalty points used by combinations with loyalty_points")
+	checkoutCompareCmd.Flags().StringP("method", "m", "credit_card", "Payment method")
+
+	checkoutCmd.AddCommand(checkoutCompareCmd)
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/app"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/fatih/color"
+)
+
+// checkoutWizard asks for the checkout options one step at a time, starting
+// from the values given as flags.
+type checkoutWizard struct {
+	app      *app.Application
+	customer *domain.Customer
+	options  *domain.CheckoutOptions
+	closed   bool
+}
+
+// runCheckoutWizard fills options in from the customer's answers. It returns
+// false when the customer does not confirm the checkout.
+func runCheckoutWizard(ctx context.Context, application *app.Application, customer *domain.Customer, options *domain.CheckoutOptions) (bool, error) {
+	w := &checkoutWizard{
+		app:      application,
+		customer: customer,
+		options:  options,
+	}
+
+	fmt.Println()
+	color.Cyan("Checkout Wizard (press Enter to keep the value in brackets)")
+
+	if err := w.askPaymentMethod(ctx); err != nil {
+		return false, err
+	}
+	w.askDecorators()
+	w.askDiscountCode()
+	w.askLoyaltyPoints()
+	if options.SavedMethodID == "" {
+		fmt.Println()
+		promptPaymentDetails(options.PaymentMethod, &options.PaymentDetails)
+	}
+
+	fmt.Println()
+	color.Cyan("Review:")
+	if options.SavedMethodID != "" {
+		fmt.Printf("  Payment Method: %s (saved %s)\n", options.PaymentMethod, options.SavedMethodID)
+	} else {
+		fmt.Printf("  Payment Method: %s\n", options.PaymentMethod)
+	}
+	fmt.Printf("  Payment Strategy: %s\n", options.PaymentStrategy)
+	fmt.Printf("  Decorators: %s\n", listOrNone(options.EnabledDecorators))
+	fmt.Printf("  Discount Codes: %s\n", listOrNone(options.DiscountCodes))
+	fmt.Printf("  Loyalty Points: %d\n", options.UseLoyaltyPoints)
+
+	answer := strings.ToLower(w.ask("Place the order? [Y/n]", ""))
+	if w.closed && answer == "" {
+		return false, nil
+	}
+	return answer == "" || answer == "y" || answer == "yes", nil
+}
+
+func (w *checkoutWizard) askPaymentMethod(ctx context.Context) error {
+	saved, err := w.app.CustomerService.ListPaymentMethods(ctx, w.customer.ID)
+	if err != nil {
+		return err
+	}
+	methods := w.app.CheckoutFacade.PaymentMethods()
+
+	fmt.Println()
+	color.Cyan("Payment method:")
+	for i, method := range saved {
+		fmt.Printf("  %d) saved %s: %s\n", i+1, method.Method, describeSavedMethod(method))
+	}
+	for i, method := range methods {
+		fmt.Printf("  %d) %s\n", len(saved)+i+1, method)
+	}
+
+	current := w.options.PaymentMethod
+	if w.options.SavedMethodID != "" {
+		current = w.options.SavedMethodID
+	}
+
+	for {
+		answer := w.ask("Choose", current)
+		if answer == current && current != "" {
+			return nil
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(saved)+len(methods) {
+			if n <= len(saved) {
+				w.options.SavedMethodID = saved[n-1].ID
+				w.options.PaymentMethod = saved[n-1].Method
+			} else {
+				w.options.SavedMethodID = ""
+				w.options.PaymentMethod = methods[n-len(saved)-1]
+			}
+			return nil
+		}
+		for _, method := range methods {
+			if answer == method {
+				w.options.SavedMethodID = ""
+				w.options.PaymentMethod = method
+				return nil
+			}
+		}
+
+		if w.closed {
+			return fmt.Errorf("no payment method chosen")
+		}
+		color.Yellow("  Choose a number or one of: %s", strings.Join(methods, ", "))
+	}
+}
+
+func (w *checkoutWizard) askDecorators() {
+	available := w.app.CheckoutFacade.AvailableDecorators()
+
+	fmt.Println()
+	color.Cyan("Decorators (comma-separated names or numbers, \"none\" for none):")
+	for i, decorator := range available {
+		fmt.Printf("  %d) %s\n", i+1, decorator)
+	}
+
+	for {
+		answer := w.ask("Enable", strings.Join(w.options.EnabledDecorators, ","))
+		if strings.EqualFold(answer, "none") {
+			w.options.EnabledDecorators = nil
+			return
+		}
+
+		var chosen []string
+		valid := true
+		for _, part := range strings.Split(answer, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if n, err := strconv.Atoi(part); err == nil && n >= 1 && n <= len(available) {
+				part = available[n-1]
+			}
+			if !containsString(available, part) {
+				color.Yellow("  Unknown decorator %q; choose from: %s", part, strings.Join(available, ", "))
+				valid = false
+				break
+			}
+			if !containsString(chosen, part) {
+				chosen = append(chosen, part)
+			}
+		}
+		if valid {
+			w.options.EnabledDecorators = chosen
+			return
+		}
+		if w.closed {
+			return
+		}
+	}
+}
+
+func (w *checkoutWizard) askDiscountCode() {
+	fmt.Println()
+	answer := w.ask("Discount code (\"none\" for none)", strings.Join(w.options.DiscountCodes, ","))
+	if strings.EqualFold(answer, "none") {
+		answer = ""
+	}
+
+	// The discount decorator applies the codes, so keep the two in step.
+	hasDiscount := containsString(w.options.EnabledDecorators, "discount")
+	if answer == "" {
+		w.options.DiscountCodes = nil
+		if hasDiscount {
+			w.options.EnabledDecorators = removeString(w.options.EnabledDecorators, "discount")
+			color.Yellow("  No discount code, so the discount decorator is disabled")
+		}
+		return
+	}
+
+	w.options.DiscountCodes = strings.Split(answer, ",")
+	if !hasDiscount {
+		w.options.EnabledDecorators = append(w.options.EnabledDecorators, "discount")
+		color.Yellow("  Enabled the discount decorator to apply the code")
+	}
+}
+
+func (w *checkoutWizard) askLoyaltyPoints() {
+	if w.customer.LoyaltyPoints == 0 {
+		return
+	}
+
+	fmt.Println()
+	for {
+		answer := w.ask(
+			fmt.Sprintf("Loyalty points to use (balance %d)", w.customer.LoyaltyPoints),
+			strconv.Itoa(w.options.UseLoyaltyPoints),
+		)
+		points, err := strconv.Atoi(answer)
+		if err == nil && points >= 0 && points <= w.customer.LoyaltyPoints {
+			w.options.UseLoyaltyPoints = points
+			return
+		}
+		if w.closed {
+			return
+		}
+		color.Yellow("  Enter a number from 0 to %d", w.customer.LoyaltyPoints)
+	}
+}
+
+// ask prints a prompt and returns the trimmed answer, or defaultValue when
+// the answer is empty. Once stdin is closed the wizard stops re-asking.
+func (w *checkoutWizard) ask(label, defaultValue string) string {
+	if defaultValue != "" {
+		fmt.Printf("%s [%s]: ", label, defaultValue)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil {
+		w.closed = true
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue
+	}
+	return answer
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+func removeString(values []string, value string) []string {
+	kept := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -7,18 +7,11 @@ import (
 	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-var exchangeRates = map[string]float64{
-	"USD": 538.0,
-	"EUR": 580.0,
-	"RUB": 5.8,
-	"CNY": 75.0,
-	"KZT": 1.0,
-}
-
 var (
 	fromCurrency string
 	toCurrency   string
@@ -44,28 +37,22 @@ var debitCmd = &cobra.Command{
 		}
 
 		if len(cart.Items) == 0 {
+			if jsonOutput() {
+				return errors.NewValidationError("cart is empty")
+			}
 			color.Yellow("  Cart is empty. Add items first using 'cart add' command.")
 			return nil
 		}
 
-		originalAmount := cart.GetTotal()
-		convertedAmount := convertCurrency(originalAmount, fromCurrency, toCurrency)
-
-		color.Cyan("Cart Summary:")
-		fmt.Printf("  Items: %d\n", cart.GetItemCount())
-		fmt.Printf("  Total (%s): %.2f %s\n", fromCurrency, originalAmount, fromCurrency)
-		if fromCurrency != toCurrency {
-			var rate float64
-			if toCurrency != "" {
-				rate = exchangeRates[fromCurrency] / exchangeRates[toCurrency]
-			} else {
-				rate = exchangeRates[fromCurrency]
-			}
-			fmt.Printf("  Exchange Rate: 1 %s = %.4f %s\n", fromCurrency, rate, toCurrency)
+		rate, err := app.CurrencyConverter.Rate(fromCurrency, toCurrency)
+		if err != nil {
+			return reportCurrencyError(cmd, err)
 		}
-		color.Green("  Total (%s): %.2f %s\n", toCurrency, convertedAmount, toCurrency)
 
-		fmt.Println()
+		breakdown := cart.Breakdown(domain.PricingInputs{})
+		originalAmount := breakdown.Total
+		convertedAmount := originalAmount * rate
+
 		transaction := &domain.Transaction{
 			ID:            domain.NewID(),
 			CustomerID:    customer.ID,
@@ -81,9 +68,39 @@ var debitCmd = &cobra.Command{
 			ProcessedAt: time.Now(),
 			CreatedAt:   time.Now(),
 		}
+		amoundDebited := float64(number) * rate
+		clearErr := app.CartService.ClearCart(ctx, cart.ID)
+
+		if jsonOutput() {
+			result := map[string]interface{}{
+				"transaction_id":     transaction.ID,
+				"item_count":         breakdown.ItemCount,
+				"original_amount":    originalAmount,
+				"original_currency":  fromCurrency,
+				"converted_amount":   convertedAmount,
+				"converted_currency": toCurrency,
+				"rate":               rate,
+				"amount_debited":     amoundDebited,
+				"sufficient_funds":   amoundDebited >= convertedAmount,
+				"cart_cleared":       clearErr == nil,
+			}
+			if amoundDebited >= convertedAmount {
+				result["remaining_balance"] = amoundDebited - convertedAmount
+			}
+			return printJSON(result)
+		}
+
+		color.Cyan("Cart Summary:")
+		fmt.Printf("  Items: %d\n", breakdown.ItemCount)
+		fmt.Printf("  Total (%s): %.2f %s\n", fromCurrency, originalAmount, fromCurrency)
+		if fromCurrency != toCurrency {
+			fmt.Printf("  Exchange Rate: 1 %s = %.4f %s\n", fromCurrency, rate, toCurrency)
+		}
+		color.Green("  Total (%s): %.2f %s\n", toCurrency, convertedAmount, toCurrency)
+
+		fmt.Println()
 		color.Green("  Debit payment processed successfully!")
 		fmt.Printf("  Transaction ID: %s\n", transaction.ID)
-		amoundDebited := convertCurrency(float64(number), fromCurrency, toCurrency)
 		fmt.Printf("  Amount debited: %.2f %s\n", amoundDebited, toCurrency)
 		if amoundDebited < convertedAmount {
 			color.Red("  Insufficient fund")
@@ -95,8 +112,8 @@ var debitCmd = &cobra.Command{
 			fmt.Println()
 		}
 
-		if err := app.CartService.ClearCart(ctx, cart.ID); err != nil {
-			color.Yellow("  Failed to clear cart: %v", err)
+		if clearErr != nil {
+			color.Yellow("  Failed to clear cart: %v", clearErr)
 		} else {
 			color.Green("  Cart cleared after successful payment")
 		}
@@ -105,18 +122,6 @@ var debitCmd = &cobra.Command{
 	},
 }
 
-func convertCurrency(amount float64, from, to string) float64 {
-	from = strings.ToUpper(from)
-	to = strings.ToUpper(to)
-
-	if from == to {
-		return amount
-	}
-
-	amountInKZT := amount * exchangeRates[from]
-	return amountInKZT / exchangeRates[to]
-}
-
 func init() {
 	debitCmd.Flags().StringVarP(&fromCurrency, "from", "f", "USD", "Source currency")
 	debitCmd.Flags().StringVarP(&toCurrency, "to", "t", "KZT", "Target currency")
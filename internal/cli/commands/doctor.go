@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/app"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the installation for setup problems",
+	Long: `Check that the configuration loads, storage is reachable and writable, enabled notifiers can initialize and the default decorators are enabled.
+Enabled payment methods and decorators are listed, and --show-config prints the
+effective configuration with secrets redacted. Exits non-zero when a critical
+check fails.`,
+	// Runs without the application so it can report why initialization fails.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		checks := app.Diagnose(configPath, defaultCheckoutDecorators)
+
+		showConfig, _ := cmd.Flags().GetBool("show-config")
+		var settings map[string]interface{}
+		if showConfig {
+			// A config that fails to load is already reported by the checks.
+			if cfg, err := config.Load(configPath); err == nil {
+				settings = cfg.Settings()
+			}
+		}
+
+		critical := 0
+		for _, check := range checks {
+			if !check.Passed && check.Critical {
+				critical++
+			}
+		}
+
+		if jsonOutput() {
+			report := map[string]interface{}{"checks": checks, "critical_failures": critical}
+			if settings != nil {
+				report["config"] = settings
+			}
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			printChecks(checks)
+			if settings != nil {
+				printSettings(settings)
+			}
+		}
+
+		if critical > 0 {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			return fmt.Errorf("%d critical check(s) failed", critical)
+		}
+
+		if !jsonOutput() {
+			color.Green("✓ All critical checks passed")
+		}
+		return nil
+	},
+}
+
+func printSettings(settings map[string]interface{}) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	color.Cyan("Effective Configuration:")
+	for _, key := range keys {
+		fmt.Printf("  %s = %v\n", key, settings[key])
+	}
+	fmt.Println()
+}
+
+func printChecks(checks []app.Check) {
+	color.Cyan("Installation Check:")
+
+	for _, check := range checks {
+		switch {
+		case check.Passed:
+			color.Green("  ✓ %s: %s", check.Name, check.Detail)
+		case check.Critical:
+			color.Red("  ✗ %s: %s", check.Name, check.Detail)
+		default:
+			color.Yellow("  ⚠ %s: %s", check.Name, check.Detail)
+		}
+		if !check.Passed && check.Hint != "" {
+			fmt.Printf("      → %s\n", check.Hint)
+		}
+	}
+
+	fmt.Println()
+}
+
+func init() {
+	doctorCmd.Flags().Bool("show-config", false, "Also print the effective configuration, secrets redacted")
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var escrowCmd = &cobra.Command{
+	Use:   "escrow",
+	Short: "Settle payments held in escrow",
+	Long: `Checkouts with --strategy escrow charge the customer but hold the funds
+until delivery is confirmed. Release them to complete the sale, or refund
+them to the customer.`,
+}
+
+var escrowReleaseCmd = &cobra.Command{
+	Use:   "release [transaction-id]",
+	Short: "Release held funds after delivery is confirmed",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		transaction, err := app.EscrowService.Release(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(transaction)
+		}
+
+		color.Green("✓ Released %s%.2f held for %s",
+			currency.Symbol(transaction.DisplayCurrency), transaction.Financials().Total, transaction.ID)
+		return nil
+	},
+}
+
+var escrowRefundCmd = &cobra.Command{
+	Use:   "refund [transaction-id]",
+	Short: "Return held funds to the customer",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		refund, err := app.EscrowService.Refund(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(refund)
+		}
+
+		color.Green("✓ Refunded %s%.2f held in escrow", currency.Symbol(refund.DisplayCurrency), refund.Amount)
+		fmt.Printf("  Refund ID: %s\n", refund.ID)
+		return nil
+	},
+}
+
+func init() {
+	escrowCmd.AddCommand(escrowReleaseCmd)
+	escrowCmd.AddCommand(escrowRefundCmd)
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var featuresCmd = &cobra.Command{
+	Use:   "features",
+	Short: "Inspect feature flags",
+}
+
+var featuresListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the state of each feature flag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := GetApplication()
+
+		if jsonOutput() {
+			states := make(map[string]bool)
+			for _, name := range config.KnownFeatures() {
+				states[name] = app.Config.Features.Enabled(name)
+			}
+			return printJSON(states)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Feature", "State"})
+
+		for _, name := range config.KnownFeatures() {
+			state := "off"
+			if app.Config.Features.Enabled(name) {
+				state = "on"
+			}
+			table.Append([]string{name, state})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+func init() {
+	featuresCmd.AddCommand(featuresListCmd)
+}
@@ -21,13 +21,28 @@ var historyCmd = &cobra.Command{
 			return err
 		}
 
+		const pageSize = 50
+		page, offset, err := pageOffset(cmd, pageSize)
+		if err != nil {
+			return err
+		}
+
 		transactionService := app.Repository
-		transactions, err := transactionService.ListTransactionsByCustomer(ctx, customer.ID, 50, 0)
+		transactions, err := transactionService.ListTransactionsByCustomer(ctx, customer.ID, pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(transactions)
+		}
+
+		total, err := transactionService.CountTransactionsByCustomer(ctx, customer.ID)
 		if err != nil {
 			return err
 		}
 
-		if len(transactions) == 0 {
+		if total == 0 {
 			fmt.Println("No transaction history found")
 			return nil
 		}
@@ -47,8 +62,12 @@ var historyCmd = &cobra.Command{
 
 		table.Render()
 
-		fmt.Printf("\nTotal Transactions: %d\n", len(transactions))
+		printPageFooter("Transactions", total, page, pageSize)
 
 		return nil
 	},
 }
+
+func init() {
+	historyCmd.Flags().Int("page", 1, "Page of transactions to show (50 per page)")
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show payment metrics",
+	Long:  `Show payment counters collected by the metrics observer, broken down by method, strategy and decorator.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := GetApplication()
+
+		if app.MetricsCollector == nil {
+			if jsonOutput() {
+				return errors.NewFeatureDisabledError("metrics")
+			}
+			color.Yellow("⚠ Metrics collection is disabled (metrics.enabled: false)")
+			return nil
+		}
+
+		metrics := app.MetricsCollector.GetMetrics()
+
+		if jsonOutput() {
+			return printJSON(metrics)
+		}
+
+		total := metrics.SuccessCount + metrics.FailureCount
+		successRate := metrics.SuccessRate() * 100.0
+
+		color.Cyan("Payment Metrics:")
+		fmt.Printf("  Total Payments:   %d\n", total)
+		fmt.Printf("  Successful:       %d\n", metrics.SuccessCount)
+		fmt.Printf("  Failed:           %d\n", metrics.FailureCount)
+		fmt.Printf("  Fraud Blocks:     %d\n", metrics.FraudBlockCount)
+		fmt.Printf("  Success Rate:     %.1f%%\n", successRate)
+		fmt.Printf("  Total Amount:     $%.2f\n", metrics.TotalAmount)
+
+		printCountTable("By Payment Method", "Method", metrics.PaymentMethodCounts, metrics.SuccessCount)
+		printCountTable("By Strategy", "Strategy", metrics.StrategyCounts, metrics.SuccessCount)
+		printCountTable("By Decorator", "Decorator", metrics.DecoratorCounts, metrics.SuccessCount)
+
+		return nil
+	},
+}
+
+func printCountTable(title, label string, counts map[string]int64, total int64) {
+	if len(counts) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Println()
+	color.Cyan("%s:", title)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{label, "Count", "Share"})
+
+	for _, key := range keys {
+		share := 0.0
+		if total > 0 {
+			share = float64(counts[key]) / float64(total) * 100.0
+		}
+		table.Append([]string{
+			key,
+			fmt.Sprintf("%d", counts[key]),
+			fmt.Sprintf("%.1f%%", share),
+		})
+	}
+
+	table.Render()
+}
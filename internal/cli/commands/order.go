@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var orderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Manage orders",
+}
+
+var orderShowCmd = &cobra.Command{
+	Use:   "show [order-id]",
+	Short: "Show order details and lifecycle status",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		order, err := app.OrderService.GetOrder(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(order)
+		}
+
+		color.Cyan("Order %s", order.ID)
+		fmt.Printf("  Status: %s\n", order.Status)
+		fmt.Printf("  Customer: %s\n", order.CustomerID)
+		fmt.Printf("  Transaction: %s\n", order.TransactionID)
+		fmt.Printf("  Created: %s\n", order.CreatedAt.Format("2006-01-02 15:04"))
+		fmt.Printf("  Updated: %s\n", order.UpdatedAt.Format("2006-01-02 15:04"))
+		fmt.Println()
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Product", "Quantity", "Price", "Total"})
+
+		for _, item := range order.Items {
+			table.Append([]string{
+				item.Product.Name,
+				fmt.Sprintf("%d", item.Quantity),
+				fmt.Sprintf("$%.2f", item.Price),
+				fmt.Sprintf("$%.2f", item.Price*float64(item.Quantity)),
+			})
+		}
+
+		table.Render()
+
+		fmt.Printf("\nSubtotal: $%.2f\n", order.Subtotal)
+		color.Green("Total: $%.2f", order.Total)
+
+		if len(order.Fulfillments) > 0 {
+			fmt.Println()
+			color.Cyan("Fulfillments:")
+			for _, f := range order.Fulfillments {
+				fmt.Printf("  %s  %s %s\n", f.CreatedAt.Format("2006-01-02 15:04"), f.Carrier, f.TrackingNumber)
+			}
+		}
+
+		return nil
+	},
+}
+
+var orderFulfillCmd = &cobra.Command{
+	Use:   "fulfill [order-id]",
+	Short: "Record a shipment for a paid order",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		carrier, _ := cmd.Flags().GetString("carrier")
+		tracking, _ := cmd.Flags().GetString("tracking")
+		if strings.TrimSpace(carrier) == "" || strings.TrimSpace(tracking) == "" {
+			return errors.NewValidationError("--carrier and --tracking are required")
+		}
+
+		order, err := app.OrderService.Fulfill(ctx, args[0], strings.TrimSpace(carrier), strings.TrimSpace(tracking))
+		if err != nil {
+			return fmt.Errorf("failed to fulfill order: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(order)
+		}
+
+		color.Green("✓ Order %s fulfilled", order.ID)
+		fmt.Printf("  Carrier: %s\n", carrier)
+		fmt.Printf("  Tracking: %s\n", tracking)
+		return nil
+	},
+}
+
+var orderCompleteCmd = &cobra.Command{
+	Use:   "complete [order-id]",
+	Short: "Mark a fulfilled order as delivered",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		order, err := app.OrderService.Complete(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to complete order: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(order)
+		}
+
+		color.Green("✓ Order %s completed", order.ID)
+		return nil
+	},
+}
+
+func init() {
+	orderCmd.AddCommand(orderShowCmd)
+	orderCmd.AddCommand(orderFulfillCmd)
+	orderCmd.AddCommand(orderCompleteCmd)
+
+	orderFulfillCmd.Flags().String("carrier", "", "Shipping carrier")
+	orderFulfillCmd.Flags().String("tracking", "", "Tracking number")
+}
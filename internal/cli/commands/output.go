@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var outputFormat = outputTable
+
+// outputFlag rejects unknown --output values while flags are parsed.
+type outputFlag struct {
+	value *string
+}
+
+func (f outputFlag) String() string {
+	return *f.value
+}
+
+func (f outputFlag) Set(value string) error {
+	switch value {
+	case outputTable, outputJSON:
+		*f.value = value
+		return nil
+	default:
+		return fmt.Errorf("expected %s or %s", outputTable, outputJSON)
+	}
+}
+
+func (f outputFlag) Type() string {
+	return "format"
+}
+
+func jsonOutput() bool {
+	return outputFormat == outputJSON
+}
+
+// applyOutputFormat runs once flags are parsed. JSON mode turns color off
+// and leaves usage text out of failures so stdout carries only JSON; Execute
+// reports the error itself.
+func applyOutputFormat() {
+	if jsonOutput() {
+		color.NoColor = true
+		rootCmd.SilenceUsage = true
+		rootCmd.SilenceErrors = true
+	}
+}
+
+// pageOffset reads the 1-based --page flag and returns the page together
+// with the list offset it starts at.
+func pageOffset(cmd *cobra.Command, pageSize int) (int, int, error) {
+	page, _ := cmd.Flags().GetInt("page")
+	if page < 1 {
+		return 0, 0, errors.NewValidationError("--page must be 1 or more")
+	}
+	return page, (page - 1) * pageSize, nil
+}
+
+// printPageFooter prints the list total and which page of it was shown.
+func printPageFooter(label string, total, page, pageSize int) {
+	pages := (total + pageSize - 1) / pageSize
+	if pages < 1 {
+		pages = 1
+	}
+	fmt.Printf("\nTotal %s: %d (page %d of %d)\n", label, total, page, pages)
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+type jsonError struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+func printJSONError(err error) {
+	code := errors.GetErrorCode(err)
+	details := errors.AllDetails(err)
+	for key, value := range details {
+		if duration, ok := value.(time.Duration); ok {
+			details[key] = duration.Seconds()
+		}
+	}
+	printJSON(map[string]jsonError{
+		"error": {Code: code, Message: strings.TrimPrefix(err.Error(), code+": "), Details: details},
+	})
+}
+
+// exitCodes gives scripts a distinct exit status per kind of failure;
+// anything not listed exits 1.
+var exitCodes = map[string]int{
+	errors.ErrCodeValidation:          2,
+	errors.ErrCodeInvalidPayment:      2,
+	errors.ErrCodeFraudDetected:       3,
+	errors.ErrCodePaymentFailed:       4,
+	errors.ErrCodeInsufficientFunds:   4,
+	errors.ErrCodeNotFound:            5,
+	errors.ErrCodeAlreadyExists:       6,
+	errors.ErrCodeConflict:            6,
+	errors.ErrCodeInventoryError:      6,
+	errors.ErrCodePriceChanged:        6,
+	errors.ErrCodeQuoteExpired:        6,
+	errors.ErrCodeTimeout:             7,
+	errors.ErrCodeRateLimited:         7,
+	errors.ErrCodeTaxUnavailable:      7,
+	errors.ErrCodeCurrencyUnavailable: 7,
+	errors.ErrCodeUnauthorized:        8,
+	errors.ErrCodeFeatureDisabled:     8,
+}
+
+// ExitCode maps a failed command's error to the process exit status.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if code, ok := exitCodes[errors.GetErrorCode(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// reportFailure shows a failure the interactive way, in red with a nil
+// error so the command still exits cleanly. In JSON mode the error is
+// returned instead, so scripts get a JSON error object and a non-zero exit.
+func reportFailure(err error, format string, args ...interface{}) error {
+	if jsonOutput() {
+		return err
+	}
+	color.Red(format, args...)
+	return nil
+}
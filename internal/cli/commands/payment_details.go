@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// addPaymentDetailFlags registers the flags a customer enters payment
+// details with.
+func addPaymentDetailFlags(cmd *cobra.Command) {
+	cmd.Flags().String("card-number", "", "Card number")
+	cmd.Flags().String("card-holder", "", "Card holder name")
+	cmd.Flags().String("expiry", "", "Card expiry date (MM/YY)")
+	cmd.Flags().String("cvv", "", "Card CVV")
+	cmd.Flags().String("paypal-email", "", "PayPal account email")
+	cmd.Flags().String("paypal-password", "", "PayPal password")
+	cmd.Flags().String("wallet-address", "", "Crypto wallet address")
+	cmd.Flags().String("crypto-type", "BTC", "Cryptocurrency")
+	cmd.Flags().String("wallet-token", "", "Mobile wallet device token")
+	cmd.Flags().String("wallet-provider", "", "Mobile wallet provider (apple_pay, google_pay)")
+	cmd.Flags().String("gift-card-code", "", "Gift card code (XXXX-XXXX-XXXX-XXXX)")
+	cmd.Flags().Float64("gift-card-balance", 0, "Gift card balance")
+}
+
+func paymentDetailsFromFlags(cmd *cobra.Command) domain.PaymentDetails {
+	var details domain.PaymentDetails
+	details.CardNumber, _ = cmd.Flags().GetString("card-number")
+	details.CardHolder, _ = cmd.Flags().GetString("card-holder")
+	details.ExpiryDate, _ = cmd.Flags().GetString("expiry")
+	details.CVV, _ = cmd.Flags().GetString("cvv")
+	details.PayPalEmail, _ = cmd.Flags().GetString("paypal-email")
+	details.PayPalPassword, _ = cmd.Flags().GetString("paypal-password")
+	details.WalletAddress, _ = cmd.Flags().GetString("wallet-address")
+	details.CryptoType, _ = cmd.Flags().GetString("crypto-type")
+	details.WalletToken, _ = cmd.Flags().GetString("wallet-token")
+	details.WalletProvider, _ = cmd.Flags().GetString("wallet-provider")
+	details.GiftCardCode, _ = cmd.Flags().GetString("gift-card-code")
+	details.GiftCardBalance, _ = cmd.Flags().GetFloat64("gift-card-balance")
+	return details
+}
+
+// promptPaymentDetails asks for the method's required details that were not
+// given as flags. It only prompts on an interactive terminal in table output;
+// otherwise missing details are reported by checkout.
+func promptPaymentDetails(method string, details *domain.PaymentDetails) {
+	if jsonOutput() || !stdinIsTerminal() {
+		return
+	}
+
+	var fields []struct {
+		label string
+		value *string
+	}
+	add := func(label string, value *string) {
+		fields = append(fields, struct {
+			label string
+			value *string
+		}{label, value})
+	}
+
+	switch method {
+	case "credit_card":
+		add("Card number", &details.CardNumber)
+		add("Card holder", &details.CardHolder)
+		add("Expiry (MM/YY)", &details.ExpiryDate)
+		add("CVV", &details.CVV)
+	case "paypal":
+		add("PayPal email", &details.PayPalEmail)
+		add("PayPal password", &details.PayPalPassword)
+	case "crypto":
+		add("Wallet address", &details.WalletAddress)
+	case "gift_card":
+		add("Gift card code", &details.GiftCardCode)
+	}
+
+	for _, field := range fields {
+		if *field.value != "" {
+			continue
+		}
+		fmt.Printf("%s: ", field.label)
+		answer, _ := stdin.ReadString('\n')
+		*field.value = strings.TrimSpace(answer)
+	}
+}
+
+// stdin is shared by all prompts so input buffered by one is not lost to the
+// next.
+var stdin = bufio.NewReader(os.Stdin)
+
+func stdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) || isatty.IsCygwinTerminal(os.Stdin.Fd())
+}
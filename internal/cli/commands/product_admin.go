@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var productAddCmd = &cobra.Command{
+	Use:     "add",
+	Short:   "Add a product to the catalog",
+	Example: `  product add --name "USB Hub" --sku HUB-001 --price 24.99 --stock 40 --category Accessories`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		name, _ := cmd.Flags().GetString("name")
+		sku, _ := cmd.Flags().GetString("sku")
+		price, _ := cmd.Flags().GetFloat64("price")
+		stock, _ := cmd.Flags().GetInt("stock")
+		category, _ := cmd.Flags().GetString("category")
+		description, _ := cmd.Flags().GetString("description")
+		taxExempt, _ := cmd.Flags().GetBool("tax-exempt")
+
+		product := &domain.Product{
+			Name:        name,
+			SKU:         sku,
+			Price:       price,
+			Stock:       stock,
+			Category:    category,
+			Description: description,
+			TaxExempt:   taxExempt,
+		}
+		if err := app.InventoryService.CreateProduct(ctx, product); err != nil {
+			return fmt.Errorf("failed to add product: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(product)
+		}
+
+		color.Green("✓ Product %s added", product.ID)
+		renderProducts([]*domain.Product{product})
+		return nil
+	},
+}
+
+var productUpdateCmd = &cobra.Command{
+	Use:     "update [product-id]",
+	Short:   "Change a product's price, stock, name, category or tax exemption",
+	Example: `  product update prod-2 --price 27.99 --stock 80`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		var update service.ProductUpdate
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			name, _ := flags.GetString("name")
+			update.Name = &name
+		}
+		if flags.Changed("price") {
+			price, _ := flags.GetFloat64("price")
+			update.Price = &price
+		}
+		if flags.Changed("stock") {
+			stock, _ := flags.GetInt("stock")
+			update.Stock = &stock
+		}
+		if flags.Changed("category") {
+			category, _ := flags.GetString("category")
+			update.Category = &category
+		}
+		if flags.Changed("tax-exempt") {
+			taxExempt, _ := flags.GetBool("tax-exempt")
+			update.TaxExempt = &taxExempt
+		}
+		if update == (service.ProductUpdate{}) {
+			return fmt.Errorf("nothing to update: pass --name, --price, --stock, --category or --tax-exempt")
+		}
+
+		product, err := app.InventoryService.UpdateProduct(ctx, args[0], update)
+		if err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(product)
+		}
+
+		color.Green("✓ Product %s updated", product.ID)
+		renderProducts([]*domain.Product{product})
+		return nil
+	},
+}
+
+var productDeleteCmd = &cobra.Command{
+	Use:   "delete [product-id]",
+	Short: "Remove a product from the catalog",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		if err := app.InventoryService.DeleteProduct(ctx, args[0]); err != nil {
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"product_id": args[0], "deleted": true})
+		}
+
+		color.Green("✓ Product %s deleted", args[0])
+		return nil
+	},
+}
+
+func init() {
+	productAddCmd.Flags().String("name", "", "Product name (required)")
+	productAddCmd.Flags().String("sku", "", "Unique stock keeping unit (required)")
+	productAddCmd.Flags().Float64("price", 0, "Unit price, must be positive (required)")
+	productAddCmd.Flags().Int("stock", 0, "Units in stock")
+	productAddCmd.Flags().String("category", "", "Catalog category")
+	productAddCmd.Flags().String("description", "", "Product description")
+	productAddCmd.Flags().Bool("tax-exempt", false, "Never charge tax on this product")
+
+	productUpdateCmd.Flags().String("name", "", "New product name")
+	productUpdateCmd.Flags().Float64("price", 0, "New unit price")
+	productUpdateCmd.Flags().Int("stock", 0, "New stock level")
+	productUpdateCmd.Flags().String("category", "", "New catalog category")
+	productUpdateCmd.Flags().Bool("tax-exempt", false, "Never charge tax on this product (--tax-exempt=false to undo)")
+
+	productCmd.AddCommand(productAddCmd)
+	productCmd.AddCommand(productUpdateCmd)
+	productCmd.AddCommand(productDeleteCmd)
+}
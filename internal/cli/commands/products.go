@@ -5,40 +5,159 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
-var productsCmd = &cobra.Command{
-	Use:   "products",
+var productCmd = &cobra.Command{
+	Use:     "product",
+	Aliases: []string{"products"},
+	Short:   "Browse and manage the product catalog",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return productListCmd.RunE(cmd, args)
+	},
+}
+
+var productListCmd = &cobra.Command{
+	Use:   "list",
 	Short: "List available products",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 		app := GetApplication()
 
-		products, err := app.Repository.ListProducts(ctx, 100, 0)
+		const pageSize = 100
+		page, offset, err := pageOffset(cmd, pageSize)
 		if err != nil {
 			return err
 		}
 
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"ID", "Name", "SKU", "Price", "Stock", "Category"})
+		products, err := app.Repository.ListProducts(ctx, pageSize, offset)
+		if err != nil {
+			return err
+		}
 
-		for _, product := range products {
-			table.Append([]string{
-				product.ID,
-				product.Name,
-				product.SKU,
-				fmt.Sprintf("$%.2f", product.Price),
-				fmt.Sprintf("%d", product.Stock),
-				product.Category,
-			})
+		if jsonOutput() {
+			return printJSON(products)
 		}
 
-		table.Render()
+		total, err := app.Repository.CountProducts(ctx)
+		if err != nil {
+			return err
+		}
 
-		fmt.Printf("\nTotal Products: %d\n", len(products))
+		renderProducts(products)
+
+		printPageFooter("Products", total, page, pageSize)
 
 		return nil
 	},
 }
+
+var productShowCmd = &cobra.Command{
+	Use:   "show [product-id]",
+	Short: "Show product details",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		product, err := app.Repository.GetProduct(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
+		}
+
+		available, err := app.InventoryService.AvailableStock(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get available stock: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(struct {
+				*domain.Product
+				Available int `json:"available"`
+			}{product, available})
+		}
+
+		color.Cyan("Product %s", product.ID)
+		fmt.Printf("  Name: %s\n", product.Name)
+		fmt.Printf("  SKU: %s\n", product.SKU)
+		fmt.Printf("  Category: %s\n", product.Category)
+		if product.TaxExempt {
+			fmt.Println("  Tax: exempt")
+		}
+		fmt.Printf("  Price: $%.2f\n", product.Price)
+		fmt.Printf("  Stock: %d\n", product.Stock)
+		if available != product.Stock {
+			fmt.Printf("  Available: %d (rest held by checkouts in progress)\n", available)
+		}
+		if product.Description != "" {
+			fmt.Printf("  Description: %s\n", product.Description)
+		}
+
+		return nil
+	},
+}
+
+var productSearchCmd = &cobra.Command{
+	Use:   "search [term]",
+	Short: "Search products by name, description or category",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+
+		products, err := app.Repository.SearchProducts(ctx, args[0], limit, 0)
+		if err != nil {
+			return fmt.Errorf("failed to search products: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(products)
+		}
+
+		if len(products) == 0 {
+			color.Yellow("No products match %q", args[0])
+			return nil
+		}
+
+		renderProducts(products)
+
+		fmt.Printf("\nMatching Products: %d\n", len(products))
+
+		return nil
+	},
+}
+
+func renderProducts(products []*domain.Product) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Name", "SKU", "Price", "Stock", "Category"})
+
+	for _, product := range products {
+		table.Append([]string{
+			product.ID,
+			product.Name,
+			product.SKU,
+			fmt.Sprintf("$%.2f", product.Price),
+			fmt.Sprintf("%d", product.Stock),
+			product.Category,
+		})
+	}
+
+	table.Render()
+}
+
+func init() {
+	productListCmd.Flags().Int("page", 1, "Page of products to show (100 per page)")
+	productSearchCmd.Flags().Int("limit", 50, "Maximum number of results")
+
+	productCmd.AddCommand(productListCmd)
+	productCmd.AddCommand(productShowCmd)
+	productCmd.AddCommand(productSearchCmd)
+}
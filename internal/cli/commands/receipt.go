@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ecommerce/payment-system/internal/receipt"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var receiptCmd = &cobra.Command{
+	Use:   "receipt",
+	Short: "Show and export checkout receipts",
+}
+
+var receiptShowCmd = &cobra.Command{
+	Use:   "show [transaction-or-receipt-id]",
+	Short: "Print a stored receipt again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		issued, err := app.CheckoutFacade.Receipt(ctx, args[0])
+		if errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+			issued, err = app.TransactionService.GetReceipt(ctx, args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get receipt: %w", err)
+		}
+
+		return printReceipt(issued)
+	},
+}
+
+var receiptExportCmd = &cobra.Command{
+	Use:   "export [transaction-id]",
+	Short: "Export a transaction's receipt as HTML, text or PDF",
+	Long: `Export the receipt issued for a transaction, laid out as checkout prints it.
+Receipts of transactions charged before receipts were stored are rebuilt from
+the transaction and its order. --template replaces the built-in HTML template.`,
+	Example: `  receipt export 6f1c... --format html --out receipt.html
+  receipt export 6f1c... --format pdf --out receipt.pdf`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		formatValue, _ := cmd.Flags().GetString("format")
+		out, _ := cmd.Flags().GetString("out")
+		templatePath, _ := cmd.Flags().GetString("template")
+
+		format, err := receipt.ParseFormat(formatValue)
+		if err != nil {
+			return err
+		}
+		renderer, err := receipt.NewReceiptRenderer(templatePath)
+		if err != nil {
+			return err
+		}
+
+		issued, err := app.CheckoutFacade.Receipt(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get receipt: %w", err)
+		}
+
+		var w io.Writer = os.Stdout
+		if out != "" {
+			file, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", out, err)
+			}
+			defer file.Close()
+			w = file
+		}
+		buffered := bufio.NewWriter(w)
+
+		if err := renderer.Render(buffered, issued, format); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+
+		if out != "" && jsonOutput() {
+			return printJSON(map[string]interface{}{"transaction_id": issued.TransactionID, "format": format, "file": out})
+		}
+		if out != "" {
+			color.Green("✓ Exported receipt for %s to %s", issued.TransactionID, out)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	receiptExportCmd.Flags().String("format", string(receipt.FormatHTML), "Output format (html, text, pdf)")
+	receiptExportCmd.Flags().String("out", "", "Output file (defaults to stdout)")
+	receiptExportCmd.Flags().String("template", "", "HTML template to use instead of the built-in one")
+
+	receiptCmd.AddCommand(receiptShowCmd)
+	receiptCmd.AddCommand(receiptExportCmd)
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var refundCmd = &cobra.Command{
+	Use:   "refund [transaction-id]",
+	Short: "Refund a completed transaction in full or in part",
+	Long: `Refund a completed transaction. Without --amount whatever has not been
+refunded yet is returned; --amount refunds part of it and may be repeated until
+the original is fully refunded. Pending and failed transactions cannot be
+refunded. The customer is notified through the configured channels.`,
+	Example: `  refund 6f1c... --reason defective
+  refund 6f1c... --amount 10.50 --reason customer-remorse`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRefund,
+}
+
+func addRefundFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64("amount", 0, "Amount to refund (defaults to the full refundable amount)")
+	cmd.Flags().String("reason", "", "Refund reason: "+refundReasonList()+" (required)")
+}
+
+func runRefund(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	app := GetApplication()
+
+	amount, _ := cmd.Flags().GetFloat64("amount")
+	reasonFlag, _ := cmd.Flags().GetString("reason")
+
+	reason, ok := domain.ParseRefundReason(reasonFlag)
+	if !ok {
+		return fmt.Errorf("invalid --reason %q (expected one of: %s)", reasonFlag, refundReasonList())
+	}
+	if cmd.Flags().Changed("amount") && amount <= 0 {
+		return fmt.Errorf("--amount must be positive")
+	}
+	cmd.SilenceUsage = true
+
+	if !cmd.Flags().Changed("amount") {
+		remaining, err := app.CheckoutFacade.RefundableAmount(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		amount = remaining
+	}
+
+	refund, err := app.CheckoutFacade.RefundOrder(ctx, args[0], amount, reason)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput() {
+		return printJSON(refund)
+	}
+
+	remaining, err := app.CheckoutFacade.RefundableAmount(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	symbol := currency.Symbol(refund.DisplayCurrency)
+	color.Green("✓ Refunded %s%.2f (%s)", symbol, refund.Amount, reason)
+	fmt.Printf("  Refund ID:   %s\n", refund.ID)
+	fmt.Printf("  Original:    %s\n", args[0])
+	if remaining > 0 {
+		fmt.Printf("  Refundable:  %s%.2f remaining\n", symbol, remaining)
+	} else {
+		fmt.Println("  Fully refunded")
+	}
+
+	return nil
+}
+
+func init() {
+	addRefundFlags(refundCmd)
+}
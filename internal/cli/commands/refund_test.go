@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/app"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedTransaction reads a transaction back from the workspace store; an
+// empty id returns the default customer's only transaction.
+func storedTransaction(t *testing.T, id string) *domain.Transaction {
+	t.Helper()
+
+	application, err := app.Initialize("config")
+	require.NoError(t, err)
+	defer application.Shutdown()
+
+	ctx := context.Background()
+	if id != "" {
+		transaction, err := application.TransactionService.GetTransaction(ctx, id)
+		require.NoError(t, err)
+		return transaction
+	}
+	transactions, err := application.TransactionService.GetCustomerTransactions(ctx, "cust-default", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	return transactions[0]
+}
+
+func TestRefundCommand(t *testing.T) {
+	cliWorkspace(t)
+
+	var output bytes.Buffer
+	rootCmd.SetOut(&output)
+	rootCmd.SetErr(&output)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+	})
+
+	t.Run("Business Errors Skip Usage", func(t *testing.T) {
+		output.Reset()
+
+		err := runCLI("refund", "missing-transaction", "--reason", "defective")
+		require.Error(t, err)
+		assert.NotContains(t, output.String(), "Usage:")
+	})
+
+	t.Run("Refunds The Taxed Remainder By Default", func(t *testing.T) {
+		require.NoError(t, runCLI("cart", "add", "prod-2", "1"))
+		require.NoError(t, runCLI("checkout", "--method", "credit_card",
+			"--card-number", "4532015112830366", "--card-holder", "John Doe",
+			"--expiry", "12/30", "--cvv", "123"))
+
+		charged := storedTransaction(t, "")
+		require.Greater(t, charged.Metadata["charged_amount"], charged.Amount)
+
+		require.NoError(t, runCLI("refund", charged.ID, "--reason", "defective"))
+
+		refunded := storedTransaction(t, charged.ID)
+		assert.Equal(t, domain.TransactionStatusRefunded, refunded.Status)
+		assert.Equal(t, charged.Metadata["charged_amount"], refunded.Metadata["refunded_amount"])
+	})
+}
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Financial reports",
+}
+
+var reportRefundsCmd = &cobra.Command{
+	Use:   "refunds",
+	Short: "Summarize refunds by reason and payment method",
+	Long: `Summarize refunds issued in a date range by refund reason and by the
+payment method of the original transaction. Dates are YYYY-MM-DD; --to is inclusive.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+
+		createdAfter, createdBefore, err := parseDateRange(from, to)
+		if err != nil {
+			return err
+		}
+
+		report, err := app.TransactionService.RefundReport(ctx, createdAfter, createdBefore)
+		if err != nil {
+			return fmt.Errorf("failed to build refund report: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(report)
+		}
+
+		if report.Total.Count == 0 {
+			color.Yellow("No refunds in this period")
+			return nil
+		}
+
+		color.Cyan("Refunds:")
+		fmt.Printf("  Count:  %d\n", report.Total.Count)
+		fmt.Printf("  Amount: $%.2f\n", report.Total.Amount)
+
+		printRefundTable("By Reason", "Reason", report.ByReason)
+		printRefundTable("By Payment Method", "Method", report.ByMethod)
+
+		return nil
+	},
+}
+
+var reportSalesCmd = &cobra.Command{
+	Use:   "sales",
+	Short: "Summarize a day's completed sales",
+	Long: `Summarize the transactions completed on one day: revenue, order count,
+average order value, tax, discounts and cashback, and revenue by payment method.
+--date is YYYY-MM-DD in local time and defaults to today.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		dateValue, _ := cmd.Flags().GetString("date")
+
+		date := time.Now()
+		if dateValue != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", dateValue, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --date %q: %w", dateValue, err)
+			}
+			date = parsed
+		}
+
+		report, err := app.ReportService.DailySalesReport(ctx, date)
+		if err != nil {
+			return fmt.Errorf("failed to build sales report: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(report)
+		}
+
+		if report.Count == 0 {
+			color.Yellow("No completed sales on %s", report.Date)
+			return nil
+		}
+
+		color.Cyan("Sales for %s:", report.Date)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Metric", "Value"})
+		for _, row := range [][2]string{
+			{"Orders", fmt.Sprintf("%d", report.Count)},
+			{"Revenue", fmt.Sprintf("$%.2f", report.Revenue)},
+			{"Average Order", fmt.Sprintf("$%.2f", report.AverageOrderValue)},
+			{"Tax", fmt.Sprintf("$%.2f", report.Tax)},
+			{"Discounts", fmt.Sprintf("$%.2f", report.Discount)},
+			{"Cashback Redeemed", fmt.Sprintf("$%.2f", report.CashbackRedeemed)},
+			{"Cashback Earned", fmt.Sprintf("$%.2f", report.CashbackEarned)},
+			{"Refunded", fmt.Sprintf("$%.2f", report.Refunded)},
+		} {
+			table.Append(row[:])
+		}
+		table.Render()
+
+		methods := make([]string, 0, len(report.ByMethod))
+		for method := range report.ByMethod {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		fmt.Println()
+		color.Cyan("By Payment Method:")
+		table = tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Method", "Orders", "Revenue"})
+		for _, method := range methods {
+			table.Append([]string{
+				method,
+				fmt.Sprintf("%d", report.ByMethod[method].Count),
+				fmt.Sprintf("$%.2f", report.ByMethod[method].Revenue),
+			})
+		}
+		table.Render()
+
+		return nil
+	},
+}
+
+func printRefundTable(title, label string, totals map[string]*service.RefundTotals) {
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Println()
+	color.Cyan("%s:", title)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{label, "Count", "Amount"})
+	for _, key := range keys {
+		table.Append([]string{
+			key,
+			fmt.Sprintf("%d", totals[key].Count),
+			fmt.Sprintf("$%.2f", totals[key].Amount),
+		})
+	}
+	table.Render()
+}
+
+func init() {
+	reportRefundsCmd.Flags().String("from", "", "Start date (YYYY-MM-DD), inclusive")
+	reportRefundsCmd.Flags().String("to", "", "End date (YYYY-MM-DD), inclusive")
+
+	reportSalesCmd.Flags().String("date", "", "Day to report (YYYY-MM-DD), defaults to today")
+
+	reportCmd.AddCommand(reportRefundsCmd)
+	reportCmd.AddCommand(reportSalesCmd)
+}
@@ -27,28 +27,64 @@ Factory, and Facade patterns.`,
 		}
 		return nil
 	},
-	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
-
-		if application != nil {
-			return application.Shutdown()
-		}
-		return nil
-	},
 }
 
+// Execute runs the CLI. With --output json, failures are also written to
+// stdout as {"error": {"code", "message", "details"}} unless the command
+// already reported them itself. ExitCode turns the returned error into the
+// process exit status. The application is shut down whether or not the
+// command succeeded, so queued notifications and audit entries are flushed.
 func Execute() error {
-	return rootCmd.Execute()
+	cobra.OnInitialize(applyOutputFormat)
+
+	cmd, err := rootCmd.ExecuteC()
+	if shutdownErr := shutdown(); err == nil {
+		err = shutdownErr
+	}
+	if err != nil && jsonOutput() && !cmd.SilenceErrors {
+		printJSONError(err)
+	}
+	return err
+}
+
+func shutdown() error {
+	if application == nil {
+		return nil
+	}
+	err := application.Shutdown()
+	application = nil
+	return err
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&configPath, "config", "./config", "config file directory")
+	rootCmd.PersistentFlags().VarP(outputFlag{&outputFormat}, "output", "o", "Output format: table or json")
 
 	rootCmd.AddCommand(checkoutCmd)
 	rootCmd.AddCommand(cartCmd)
-	rootCmd.AddCommand(productsCmd)
+	rootCmd.AddCommand(productCmd)
 	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(userCmd)
 	rootCmd.AddCommand(debitCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(orderCmd)
+	rootCmd.AddCommand(transactionCmd)
+	rootCmd.AddCommand(escrowCmd)
+	rootCmd.AddCommand(refundCmd)
+	rootCmd.AddCommand(receiptCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(subscriptionCmd)
+	rootCmd.AddCommand(featuresCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+func applyConfigDefault(cmd *cobra.Command, flag string, target *string, configured string) {
+	if !cmd.Flags().Changed(flag) && configured != "" {
+		*target = configured
+	}
 }
 
 func GetApplication() *app.Application {
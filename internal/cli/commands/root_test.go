@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cliWorkspace runs the CLI from a scratch directory holding a copy of the
+// repository config, so file-backed data and logs stay out of the tree.
+func cliWorkspace(t *testing.T) string {
+	t.Helper()
+
+	config, err := os.ReadFile(filepath.Join("..", "..", "..", "config", "config.yaml"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	for _, sub := range []string{"config", "logs", "data"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config", "config.yaml"), config, 0o644))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	return dir
+}
+
+func runCLI(args ...string) error {
+	rootCmd.SetArgs(append([]string{"--config", "config"}, args...))
+	return Execute()
+}
+
+func TestExecuteShutsDownAfterFailedCommand(t *testing.T) {
+	dir := cliWorkspace(t)
+
+	require.NoError(t, runCLI("cart", "add", "prod-2", "1"))
+
+	err := runCLI("checkout", "--method", "credit_card",
+		"--card-number", "4532015112830367", "--card-holder", "John Doe",
+		"--expiry", "12/30", "--cvv", "123")
+	require.Error(t, err)
+	assert.Nil(t, GetApplication())
+
+	audit, err := os.ReadFile(filepath.Join(dir, "logs", "audit.log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(audit), `"event_type":"payment_failed"`)
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage deferred payment schedules",
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deferred payment schedules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		schedules, err := app.ScheduleService.ListSchedules(ctx, limit, 0)
+		if err != nil {
+			return fmt.Errorf("failed to list schedules: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(schedules)
+		}
+
+		if len(schedules) == 0 {
+			color.Yellow("No payment schedules found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Customer", "Method", "Total", "Paid", "Remaining", "Next Due"})
+
+		for _, s := range schedules {
+			nextDue := "-"
+			if next := s.NextInstallment(); next != nil {
+				nextDue = next.DueDate.Format("2006-01-02")
+			}
+
+			table.Append([]string{
+				s.ID,
+				s.CustomerID,
+				s.PaymentMethod,
+				fmt.Sprintf("$%.2f", s.TotalAmount),
+				fmt.Sprintf("%d/%d", s.PaidCount(), len(s.Payments)),
+				fmt.Sprintf("$%.2f", s.RemainingAmount()),
+				nextDue,
+			})
+		}
+
+		table.Render()
+		return nil
+	},
+}
+
+var scheduleChargeCmd = &cobra.Command{
+	Use:   "charge [schedule-id]",
+	Short: "Charge the next pending installment of a schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		schedule, err := app.ScheduleService.ChargeNextInstallment(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to charge installment: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(schedule)
+		}
+
+		color.Green("✓ Installment charged")
+		printSchedule(schedule)
+
+		return nil
+	},
+}
+
+func printSchedule(schedule *domain.PaymentSchedule) {
+	fmt.Printf("  Schedule: %s\n", schedule.ID)
+	fmt.Printf("  Paid: %d/%d\n", schedule.PaidCount(), len(schedule.Payments))
+	fmt.Printf("  Remaining: $%.2f\n", schedule.RemainingAmount())
+	fmt.Println()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"#", "Amount", "Due", "Status", "Transaction"})
+
+	for _, p := range schedule.Payments {
+		table.Append([]string{
+			fmt.Sprintf("%d", p.InstallmentNumber),
+			fmt.Sprintf("$%.2f", p.Amount),
+			p.DueDate.Format("2006-01-02"),
+			string(p.Status),
+			p.TransactionID,
+		})
+	}
+
+	table.Render()
+}
+
+func init() {
+	scheduleListCmd.Flags().Int("limit", 20, "Maximum number of schedules to show")
+
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleChargeCmd)
+}
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var subscriptionCmd = &cobra.Command{
+	Use:   "subscription",
+	Short: "Manage subscriptions started with the recurring strategy",
+}
+
+var subscriptionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List subscriptions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		subscriptions, err := app.BillingService.ListSubscriptions(ctx, limit, 0)
+		if err != nil {
+			return fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(subscriptions)
+		}
+
+		if len(subscriptions) == 0 {
+			color.Yellow("No subscriptions found")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Customer", "Method", "Amount", "Interval", "Status", "Charges", "Next Billing"})
+
+		for _, s := range subscriptions {
+			table.Append([]string{
+				s.ID,
+				s.CustomerID,
+				s.PaymentMethod,
+				fmt.Sprintf("$%.2f", s.Amount),
+				string(s.Interval),
+				string(s.Status),
+				fmt.Sprintf("%d", s.ChargeCount),
+				s.NextBillingDate.Format("2006-01-02"),
+			})
+		}
+
+		table.Render()
+		return nil
+	},
+}
+
+var subscriptionCancelCmd = &cobra.Command{
+	Use:   "cancel [subscription-id]",
+	Short: "Stop charging a subscription",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		subscription, err := app.BillingService.CancelSubscription(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to cancel subscription: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(subscription)
+		}
+
+		color.Green("✓ Subscription %s canceled", subscription.ID)
+		return nil
+	},
+}
+
+var subscriptionBillCmd = &cobra.Command{
+	Use:   "bill",
+	Short: "Charge every subscription whose billing date has passed",
+	Long: `Charge all due subscriptions once. A failed charge leaves the subscription
+past due so the next run retries it; after 3 failures in a row it is canceled.
+Meant to be run periodically, e.g. from cron.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		outcomes, err := app.BillingService.RunDueCharges(ctx)
+		if err != nil {
+			return fmt.Errorf("billing run failed: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(outcomes)
+		}
+
+		if len(outcomes) == 0 {
+			color.Yellow("No subscriptions are due")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Subscription", "Amount", "Result", "Status", "Next Billing"})
+
+		failed := 0
+		for _, o := range outcomes {
+			result := o.TransactionID
+			if o.Error != "" {
+				result = o.Error
+				failed++
+			}
+			table.Append([]string{
+				o.SubscriptionID,
+				fmt.Sprintf("$%.2f", o.Amount),
+				result,
+				string(o.Status),
+				o.NextBillingDate.Format("2006-01-02"),
+			})
+		}
+		table.Render()
+
+		if failed > 0 {
+			color.Yellow("⚠ Charged %d of %d due subscription(s)", len(outcomes)-failed, len(outcomes))
+			return nil
+		}
+		color.Green("✓ Charged %d subscription(s)", len(outcomes))
+		return nil
+	},
+}
+
+func init() {
+	subscriptionListCmd.Flags().Int("limit", 20, "Maximum number of subscriptions to show")
+
+	subscriptionCmd.AddCommand(subscriptionListCmd)
+	subscriptionCmd.AddCommand(subscriptionCancelCmd)
+	subscriptionCmd.AddCommand(subscriptionBillCmd)
+}
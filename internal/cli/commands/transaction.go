@@ -0,0 +1,378 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var transactionCmd = &cobra.Command{
+	Use:   "transaction",
+	Short: "Manage transactions",
+}
+
+var transactionExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export transactions in a date range for accounting",
+	Long: `Export all transactions created in a date range as CSV or JSON, including
+the financial breakdown (amount, discounts, tax, fees, total, refunds, net).
+Dates are YYYY-MM-DD; --to is inclusive.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		format, _ := cmd.Flags().GetString("format")
+		if jsonOutput() && !cmd.Flags().Changed("format") {
+			format = "json"
+		}
+		out, _ := cmd.Flags().GetString("out")
+
+		createdAfter, createdBefore, err := parseDateRange(from, to)
+		if err != nil {
+			return err
+		}
+		filter := repository.TransactionFilter{
+			CreatedAfter:  createdAfter,
+			CreatedBefore: createdBefore,
+		}
+
+		var writer transactionWriter
+		var w io.Writer = os.Stdout
+		if out != "" {
+			file, err := os.Create(out)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", out, err)
+			}
+			defer file.Close()
+			w = file
+		}
+		buffered := bufio.NewWriter(w)
+
+		switch format {
+		case "csv":
+			writer = newCSVTransactionWriter(buffered)
+		case "json":
+			writer = newJSONTransactionWriter(buffered)
+		default:
+			return fmt.Errorf("unsupported format %q (expected csv or json)", format)
+		}
+
+		exported := 0
+		err = app.TransactionService.ExportTransactions(ctx, filter, func(tx *domain.Transaction) error {
+			exported++
+			return writer.Write(tx)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to export transactions: %w", err)
+		}
+
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+
+		if out != "" && jsonOutput() {
+			return printJSON(map[string]interface{}{"exported": exported, "file": out})
+		}
+		if out != "" {
+			color.Green("✓ Exported %d transactions to %s", exported, out)
+		}
+
+		return nil
+	},
+}
+
+var transactionShowCmd = &cobra.Command{
+	Use:   "show [transaction-id]",
+	Short: "Show transaction details",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		tx, err := app.TransactionService.GetTransaction(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get transaction: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(tx)
+		}
+
+		f := tx.Financials()
+
+		color.Cyan("Transaction %s", tx.ID)
+		fmt.Printf("  Status: %s\n", tx.Status)
+		fmt.Printf("  Customer: %s\n", tx.CustomerID)
+		fmt.Printf("  Payment Method: %s\n", tx.PaymentMethod)
+		if tx.Strategy != "" {
+			fmt.Printf("  Strategy: %s\n", tx.Strategy)
+		}
+		fmt.Printf("  Created: %s\n", tx.CreatedAt.Format("2006-01-02 15:04"))
+		if !tx.ProcessedAt.IsZero() {
+			fmt.Printf("  Processed: %s\n", tx.ProcessedAt.Format("2006-01-02 15:04"))
+		}
+		if tx.ErrorMessage != "" {
+			color.Red("  Error: %s", tx.ErrorMessage)
+		}
+		fmt.Println()
+
+		symbol := currency.Symbol(tx.DisplayCurrency)
+		fmt.Printf("Total: %s%.2f\n", symbol, f.Total)
+		if tx.IsConverted() {
+			fmt.Printf("  Base Amount: %s%.2f\n", currency.Symbol(tx.BaseCurrency), tx.BaseAmount)
+			fmt.Printf("  Locked Rate: 1 %s = %.4f %s\n", tx.DisplayCurrency, tx.ExchangeRate, tx.BaseCurrency)
+		}
+		if f.Refunded > 0 {
+			fmt.Printf("  Refunded: %s%.2f\n", symbol, f.Refunded)
+		}
+
+		return nil
+	},
+}
+
+var transactionRefundCmd = &cobra.Command{
+	Use:   "refund [transaction-id]",
+	Short: "Refund a completed transaction",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRefund,
+}
+
+var transactionCaptureCmd = &cobra.Command{
+	Use:   "capture [transaction-id]",
+	Short: "Capture an authorized transaction in full or in part",
+	Long: `Capture a transaction checked out with --strategy authorize. Without
+--amount the whole authorization is captured; a smaller --amount releases the
+rest. Loyalty points and cashback are paid out on the captured amount.`,
+	Example: `  transaction capture 6f1c...
+  transaction capture 6f1c... --amount 80`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		amount, _ := cmd.Flags().GetFloat64("amount")
+		if !cmd.Flags().Changed("amount") {
+			authorized, err := app.CheckoutFacade.AuthorizedAmount(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			amount = authorized
+		} else if amount <= 0 {
+			return fmt.Errorf("--amount must be positive")
+		}
+
+		tx, err := app.CheckoutFacade.CaptureTransaction(ctx, args[0], amount)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(tx)
+		}
+
+		symbol := currency.Symbol(tx.DisplayCurrency)
+		color.Green("✓ Captured %s%.2f of %s", symbol, tx.Amount, tx.ID)
+		if released, _ := tx.Metadata["released_amount"].(float64); released > 0 {
+			fmt.Printf("  Released: %s%.2f\n", symbol, released)
+		}
+
+		return nil
+	},
+}
+
+func refundReasonList() string {
+	reasons := make([]string, 0, len(domain.RefundReasons()))
+	for _, reason := range domain.RefundReasons() {
+		reasons = append(reasons, string(reason))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// parseDateRange turns YYYY-MM-DD flags into a [from, to) range where --to is
+// inclusive. Empty flags leave that side open.
+func parseDateRange(from, to string) (time.Time, time.Time, error) {
+	var after, before time.Time
+	if from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return after, before, fmt.Errorf("invalid --from date %q: %w", from, err)
+		}
+		after = t
+	}
+	if to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return after, before, fmt.Errorf("invalid --to date %q: %w", to, err)
+		}
+		before = t.AddDate(0, 0, 1)
+	}
+	return after, before, nil
+}
+
+type transactionWriter interface {
+	Write(tx *domain.Transaction) error
+	Close() error
+}
+
+var transactionCSVHeader = []string{
+	"id", "created_at", "processed_at", "customer_id", "status", "payment_method", "strategy",
+	"amount", "discount", "loyalty_discount", "cashback_redeemed", "tax", "fees",
+	"total", "refunded", "net",
+}
+
+type csvTransactionWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVTransactionWriter(w io.Writer) *csvTransactionWriter {
+	return &csvTransactionWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvTransactionWriter) Write(tx *domain.Transaction) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	f := tx.Financials()
+	money := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	return c.w.Write([]string{
+		tx.ID,
+		tx.CreatedAt.Format(time.RFC3339),
+		formatOptionalTime(tx.ProcessedAt),
+		tx.CustomerID,
+		string(tx.Status),
+		tx.PaymentMethod,
+		tx.Strategy,
+		money(f.Amount),
+		money(f.Discount),
+		money(f.LoyaltyDiscount),
+		money(f.CashbackRedeemed),
+		money(f.Tax),
+		money(f.Fees),
+		money(f.Total),
+		money(f.Refunded),
+		money(f.Net),
+	})
+}
+
+func (c *csvTransactionWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(transactionCSVHeader)
+}
+
+// Close writes the header if no rows were written, so an empty export is
+// still a valid CSV file.
+func (c *csvTransactionWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type exportedTransaction struct {
+	ID            string                   `json:"id"`
+	CreatedAt     time.Time                `json:"created_at"`
+	ProcessedAt   *time.Time               `json:"processed_at,omitempty"`
+	CustomerID    string                   `json:"customer_id"`
+	Status        domain.TransactionStatus `json:"status"`
+	PaymentMethod string                   `json:"payment_method"`
+	Strategy      string                   `json:"strategy,omitempty"`
+	domain.TransactionFinancials
+}
+
+type jsonTransactionWriter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONTransactionWriter(w io.Writer) *jsonTransactionWriter {
+	return &jsonTransactionWriter{w: w}
+}
+
+func (j *jsonTransactionWriter) Write(tx *domain.Transaction) error {
+	record := exportedTransaction{
+		ID:                    tx.ID,
+		CreatedAt:             tx.CreatedAt,
+		CustomerID:            tx.CustomerID,
+		Status:                tx.Status,
+		PaymentMethod:         tx.PaymentMethod,
+		Strategy:              tx.Strategy,
+		TransactionFinancials: tx.Financials(),
+	}
+	if !tx.ProcessedAt.IsZero() {
+		processedAt := tx.ProcessedAt
+		record.ProcessedAt = &processedAt
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	prefix := ",\n  "
+	if j.count == 0 {
+		prefix = "[\n  "
+	}
+	j.count++
+
+	if _, err := io.WriteString(j.w, prefix); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonTransactionWriter) Close() error {
+	closing := "\n]\n"
+	if j.count == 0 {
+		closing = "[]\n"
+	}
+	_, err := io.WriteString(j.w, closing)
+	return err
+}
+
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func init() {
+	transactionExportCmd.Flags().String("from", "", "Start date (YYYY-MM-DD), inclusive")
+	transactionExportCmd.Flags().String("to", "", "End date (YYYY-MM-DD), inclusive")
+	transactionExportCmd.Flags().String("format", "csv", "Output format (csv, json)")
+	transactionExportCmd.Flags().String("out", "", "Output file (defaults to stdout)")
+
+	addRefundFlags(transactionRefundCmd)
+	transactionCaptureCmd.Flags().Float64("amount", 0, "Amount to capture (defaults to the full authorization)")
+
+	transactionCmd.AddCommand(transactionExportCmd)
+	transactionCmd.AddCommand(transactionShowCmd)
+	transactionCmd.AddCommand(transactionRefundCmd)
+	transactionCmd.AddCommand(transactionCaptureCmd)
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"time"
+	"strconv"
 
 	"github.com/ecommerce/payment-system/internal/domain"
-	"github.com/ecommerce/payment-system/pkg/validator"
+	"github.com/ecommerce/payment-system/internal/i18n"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -35,35 +36,13 @@ var userRegisterCmd = &cobra.Command{
 		state, _ := cmd.Flags().GetString("state")
 		postalCode, _ := cmd.Flags().GetString("postal-code")
 		country, _ := cmd.Flags().GetString("country")
-
-		if email == "" || name == "" {
-			return fmt.Errorf("email and name are required")
-		}
-
-		emailValidator := validator.NewEmailValidator()
-		if err := emailValidator.Validate(email); err != nil {
-			return fmt.Errorf("invalid email: %w", err)
-		}
-
-		if phone != "" {
-			phoneValidator := validator.NewPhoneValidator()
-			if err := phoneValidator.Validate(phone); err != nil {
-				return fmt.Errorf("invalid phone: %w", err)
-			}
-		}
-
-		_, err := app.Repository.GetCustomerByEmail(ctx, email)
-		if err == nil {
-			color.Yellow("⚠ Customer with email %s already exists", email)
-			return nil
-		}
+		locale, _ := cmd.Flags().GetString("locale")
 
 		customer := &domain.Customer{
-			ID:            domain.NewID(),
-			Email:         email,
-			Name:          name,
-			Phone:         phone,
-			LoyaltyPoints: 0,
+			Email:  email,
+			Name:   name,
+			Phone:  phone,
+			Locale: locale,
 			Address: domain.Address{
 				Street:     street,
 				City:       city,
@@ -71,12 +50,18 @@ var userRegisterCmd = &cobra.Command{
 				PostalCode: postalCode,
 				Country:    country,
 			},
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
 		}
 
-		if err := app.Repository.CreateCustomer(ctx, customer); err != nil {
-			return fmt.Errorf("failed to create customer: %w", err)
+		if err := app.CustomerService.Register(ctx, customer); err != nil {
+			if errors.IsErrorCode(err, errors.ErrCodeAlreadyExists) && !jsonOutput() {
+				color.Yellow("⚠ Customer with email %s already exists", email)
+				return nil
+			}
+			return fmt.Errorf("failed to register customer: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(customer)
 		}
 
 		color.Green("\n✓ Customer registered successfully!")
@@ -100,12 +85,27 @@ var userListCmd = &cobra.Command{
 		ctx := context.Background()
 		app := GetApplication()
 
-		customers, err := app.Repository.ListCustomers(ctx, 50, 0)
+		const pageSize = 50
+		page, offset, err := pageOffset(cmd, pageSize)
+		if err != nil {
+			return err
+		}
+
+		customers, err := app.Repository.ListCustomers(ctx, pageSize, offset)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(customers)
+		}
+
+		total, err := app.Repository.CountCustomers(ctx)
 		if err != nil {
 			return err
 		}
 
-		if len(customers) == 0 {
+		if total == 0 {
 			fmt.Println("No customers found")
 			return nil
 		}
@@ -130,7 +130,7 @@ var userListCmd = &cobra.Command{
 		}
 
 		table.Render()
-		fmt.Printf("\nTotal Customers: %d\n", len(customers))
+		printPageFooter("Customers", total, page, pageSize)
 
 		return nil
 	},
@@ -148,8 +148,11 @@ var userInfoCmd = &cobra.Command{
 
 		customer, err := app.Repository.GetCustomerByEmail(ctx, email)
 		if err != nil {
-			color.Red("✗ Customer not found: %s", email)
-			return nil
+			return reportFailure(err, "✗ Customer not found: %s", email)
+		}
+
+		if jsonOutput() {
+			return printJSON(customer)
 		}
 
 		color.Cyan("\n═══════════════════════════════════════")
@@ -162,7 +165,14 @@ var userInfoCmd = &cobra.Command{
 		if customer.Phone != "" {
 			fmt.Printf("Phone:          %s\n", customer.Phone)
 		}
+		if customer.Locale != "" {
+			fmt.Printf("Locale:         %s\n", customer.Locale)
+		}
 		fmt.Printf("Loyalty Points: %d points\n", customer.LoyaltyPoints)
+		fmt.Printf("Cashback:       $%.2f\n", customer.CashbackBalance)
+		if customer.SpendingLimit > 0 {
+			fmt.Printf("Spending Limit: $%.2f\n", customer.SpendingLimit)
+		}
 		fmt.Printf("Member Since:   %s\n", customer.CreatedAt.Format("2006-01-02"))
 
 		if customer.Address.Street != "" {
@@ -178,6 +188,279 @@ var userInfoCmd = &cobra.Command{
 	},
 }
 
+var userSetLimitCmd = &cobra.Command{
+	Use:   "set-limit [email] [amount]",
+	Short: "Set a customer's rolling spending limit (0 restores the default)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		limit, err := strconv.ParseFloat(args[1], 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount: %s", args[1])
+		}
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		if err := app.CustomerService.SetSpendingLimit(ctx, customer.ID, limit); err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"customer_id":    customer.ID,
+				"email":          customer.Email,
+				"spending_limit": limit,
+			})
+		}
+
+		if limit == 0 {
+			color.Green("✓ Spending limit for %s reset to the default", customer.Email)
+		} else {
+			color.Green("✓ Spending limit for %s set to $%.2f", customer.Email, limit)
+		}
+
+		return nil
+	},
+}
+
+var userSetLocaleCmd = &cobra.Command{
+	Use:   "set-locale [email] [locale]",
+	Short: "Set the language of a customer's receipts and emails (empty restores the default)",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		if err := app.CustomerService.SetLocale(ctx, customer.ID, args[1]); err != nil {
+			return err
+		}
+
+		updated, err := app.CustomerService.GetCustomer(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"customer_id": updated.ID,
+				"email":       updated.Email,
+				"locale":      updated.Locale,
+			})
+		}
+
+		if updated.Locale == "" {
+			color.Green("✓ Locale for %s reset to the default (%s)", updated.Email, i18n.DefaultLocale)
+		} else {
+			color.Green("✓ Locale for %s set to %s", updated.Email, updated.Locale)
+		}
+
+		return nil
+	},
+}
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete [email]",
+	Short: "Delete a customer, or anonymize one with transaction history",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		force, _ := cmd.Flags().GetBool("force")
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		anonymized, err := app.CustomerService.DeleteCustomer(ctx, customer.ID, force)
+		if errors.IsErrorCode(err, errors.ErrCodeValidation) && !jsonOutput() {
+			color.Red("✗ Cannot delete %s: %v", customer.Email, err)
+			color.Yellow("  Use --force to anonymize the customer instead")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"customer_id": customer.ID,
+				"deleted":     !anonymized,
+				"anonymized":  anonymized,
+			})
+		}
+
+		if anonymized {
+			color.Green("✓ Customer %s anonymized; transaction history retained", customer.ID)
+		} else {
+			color.Green("✓ Customer %s deleted", customer.Email)
+		}
+
+		return nil
+	},
+}
+
+var userCashbackCmd = &cobra.Command{
+	Use:   "cashback [email]",
+	Short: "View the cashback balance and its ledger",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		var customer *domain.Customer
+		var err error
+		if len(args) == 1 {
+			customer, err = app.Repository.GetCustomerByEmail(ctx, args[0])
+		} else {
+			customer, err = getCustomer(ctx, app)
+		}
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found")
+		}
+
+		entries, err := app.CustomerService.CashbackHistory(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"customer_id":    customer.ID,
+				"email":          customer.Email,
+				"balance":        customer.CashbackBalance,
+				"loyalty_points": customer.LoyaltyPoints,
+				"payout_mode":    app.Config.Decorators.Cashback.Payout,
+				"entries":        entries,
+			})
+		}
+
+		color.Cyan("Cashback for %s:", customer.Email)
+		fmt.Printf("  Balance:        $%.2f\n", customer.CashbackBalance)
+		fmt.Printf("  Loyalty Points: %d\n", customer.LoyaltyPoints)
+		fmt.Printf("  Payout Mode:    %s\n", app.Config.Decorators.Cashback.Payout)
+		fmt.Println()
+
+		if len(entries) == 0 {
+			fmt.Println("No cashback movements yet")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Date", "Reason", "Change", "Balance", "Transaction ID"})
+
+		for _, entry := range entries {
+			transactionID := entry.TransactionID
+			if transactionID == "" {
+				transactionID = "-"
+			} else if len(transactionID) > 8 {
+				transactionID = transactionID[:8] + "..."
+			}
+			table.Append([]string{
+				entry.CreatedAt.Local().Format("2006-01-02 15:04"),
+				entry.Reason,
+				fmt.Sprintf("%+.2f", entry.Delta),
+				fmt.Sprintf("$%.2f", entry.Balance),
+				transactionID,
+			})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
+var userPointsCmd = &cobra.Command{
+	Use:   "points [email]",
+	Short: "View the loyalty points ledger, optionally adjusting the balance",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+		adjust, _ := cmd.Flags().GetInt("adjust")
+
+		var customer *domain.Customer
+		var err error
+		if len(args) == 1 {
+			customer, err = app.Repository.GetCustomerByEmail(ctx, args[0])
+		} else {
+			customer, err = getCustomer(ctx, app)
+		}
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found")
+		}
+
+		if cmd.Flags().Changed("adjust") {
+			if err := app.CustomerService.AdjustLoyaltyPoints(ctx, customer.ID, adjust); err != nil {
+				return reportFailure(err, "✗ Adjustment failed: %v", err)
+			}
+			if customer, err = app.CustomerService.GetCustomer(ctx, customer.ID); err != nil {
+				return err
+			}
+		}
+
+		entries, err := app.CustomerService.LoyaltyHistory(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"customer_id":    customer.ID,
+				"email":          customer.Email,
+				"loyalty_points": customer.LoyaltyPoints,
+				"entries":        entries,
+			})
+		}
+
+		if adjust != 0 {
+			color.Green("✓ Adjusted loyalty points by %+d", adjust)
+		}
+		color.Cyan("Loyalty points for %s: %d", customer.Email, customer.LoyaltyPoints)
+		fmt.Println()
+
+		if len(entries) == 0 {
+			fmt.Println("No loyalty point movements yet")
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Date", "Reason", "Change", "Balance", "Transaction ID"})
+
+		for _, entry := range entries {
+			transactionID := entry.TransactionID
+			if transactionID == "" {
+				transactionID = "-"
+			} else if len(transactionID) > 8 {
+				transactionID = transactionID[:8] + "..."
+			}
+			table.Append([]string{
+				entry.CreatedAt.Local().Format("2006-01-02 15:04"),
+				entry.Reason,
+				fmt.Sprintf("%+d", entry.Delta),
+				fmt.Sprintf("%d", entry.Balance),
+				transactionID,
+			})
+		}
+
+		table.Render()
+
+		return nil
+	},
+}
+
 func init() {
 	userRegisterCmd.Flags().String("email", "", "Customer email (required)")
 	userRegisterCmd.Flags().String("name", "", "Customer name (required)")
@@ -187,8 +470,20 @@ func init() {
 	userRegisterCmd.Flags().String("state", "", "State/Province")
 	userRegisterCmd.Flags().String("postal-code", "", "Postal/ZIP code")
 	userRegisterCmd.Flags().String("country", "USA", "Country")
+	userRegisterCmd.Flags().String("locale", "", "Language for receipts and emails (e.g. en, es; defaults to en)")
+
+	userImportCmd.Flags().Bool("dry-run", false, "Validate rows without saving any customers")
+	userListCmd.Flags().Int("page", 1, "Page of customers to show (50 per page)")
+	userDeleteCmd.Flags().Bool("force", false, "Anonymize customers that still have active transactions")
+	userPointsCmd.Flags().Int("adjust", 0, "Manually add (or with a negative value, remove) loyalty points")
 
 	userCmd.AddCommand(userRegisterCmd)
 	userCmd.AddCommand(userListCmd)
 	userCmd.AddCommand(userInfoCmd)
+	userCmd.AddCommand(userImportCmd)
+	userCmd.AddCommand(userCashbackCmd)
+	userCmd.AddCommand(userPointsCmd)
+	userCmd.AddCommand(userSetLimitCmd)
+	userCmd.AddCommand(userSetLocaleCmd)
+	userCmd.AddCommand(userDeleteCmd)
 }
@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type customerImportRecord struct {
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	Phone      string `json:"phone"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	Locale     string `json:"locale"`
+}
+
+func (r customerImportRecord) toCustomer() *domain.Customer {
+	country := r.Country
+	if country == "" {
+		country = "USA"
+	}
+
+	return &domain.Customer{
+		Email:  r.Email,
+		Name:   r.Name,
+		Phone:  r.Phone,
+		Locale: r.Locale,
+		Address: domain.Address{
+			Street:     r.Street,
+			City:       r.City,
+			State:      r.State,
+			PostalCode: r.PostalCode,
+			Country:    country,
+		},
+	}
+}
+
+var userImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import customers from a CSV or JSON file",
+	Long: `Import customers in bulk. CSV files need a header row with the columns
+email,name,phone,street,city,state,postal_code,country and optionally locale.
+JSON files hold an array of objects using the same keys. Each row is validated independently.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		records, err := readCustomerImport(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read import file: %w", err)
+		}
+
+		customers := make([]*domain.Customer, len(records))
+		for i, record := range records {
+			customers[i] = record.toCustomer()
+		}
+		results := app.CustomerService.ImportCustomers(ctx, customers, dryRun)
+
+		imported, skipped, failed := 0, 0, 0
+		for _, result := range results {
+			switch result.Result {
+			case service.CustomerImportSkipped:
+				skipped++
+			case service.CustomerImportFailed:
+				failed++
+			default:
+				imported++
+			}
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{
+				"dry_run":  dryRun,
+				"imported": imported,
+				"skipped":  skipped,
+				"failed":   failed,
+				"rows":     results,
+			})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Row", "Email", "Result"})
+		for _, result := range results {
+			outcome := result.Result
+			if result.Reason != "" {
+				outcome += ": " + result.Reason
+			}
+			table.Append([]string{fmt.Sprintf("%d", result.Row), result.Email, outcome})
+		}
+		table.Render()
+
+		fmt.Println()
+		if dryRun {
+			color.Cyan("Dry run: %d valid, %d skipped, %d failed (nothing was saved)", imported, skipped, failed)
+		} else {
+			color.Green("✓ Imported %d customer(s), %d skipped, %d failed", imported, skipped, failed)
+		}
+
+		return nil
+	},
+}
+
+func readCustomerImport(path string) ([]customerImportRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var records []customerImportRecord
+		if err := json.NewDecoder(file).Decode(&records); err != nil {
+			return nil, err
+		}
+		return records, nil
+	}
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	records := []customerImportRecord{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, customerImportRecord{
+			Email:      field(row, "email"),
+			Name:       field(row, "name"),
+			Phone:      field(row, "phone"),
+			Street:     field(row, "street"),
+			City:       field(row, "city"),
+			State:      field(row, "state"),
+			PostalCode: field(row, "postal_code"),
+			Country:    field(row, "country"),
+			Locale:     field(row, "locale"),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var userPaymentMethodCmd = &cobra.Command{
+	Use:     "payment-method",
+	Aliases: []string{"pm"},
+	Short:   "Manage a customer's saved payment methods",
+	Long: `Save payment methods to a customer's vault so checkout can charge them with
+--saved-method. Only a vault token and masked details are stored.`,
+}
+
+var userPaymentMethodAddCmd = &cobra.Command{
+	Use:   "add [email]",
+	Short: "Save a payment method",
+	Example: `  user payment-method add john.doe@example.com --method credit_card \
+    --card-number 4532015112830366 --card-holder "John Doe" --expiry 12/30 --cvv 123
+  user payment-method add john.doe@example.com --method paypal --paypal-email john@example.com --paypal-password secret`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		method, _ := cmd.Flags().GetString("method")
+		details := paymentDetailsFromFlags(cmd)
+		promptPaymentDetails(method, &details)
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		saved, err := app.CustomerService.AddPaymentMethod(ctx, customer.ID, method, details)
+		if err != nil {
+			return reportFailure(err, "✗ Failed to save payment method: %v", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(saved)
+		}
+
+		color.Green("✓ Saved %s for %s", describeSavedMethod(saved), customer.Email)
+		fmt.Printf("Payment method ID: %s\n", saved.ID)
+		fmt.Printf("Use it with: checkout --saved-method %s\n", saved.ID)
+		return nil
+	},
+}
+
+var userPaymentMethodListCmd = &cobra.Command{
+	Use:   "list [email]",
+	Short: "List saved payment methods",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		methods, err := app.CustomerService.ListPaymentMethods(ctx, customer.ID)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput() {
+			return printJSON(methods)
+		}
+
+		if len(methods) == 0 {
+			fmt.Printf("No saved payment methods for %s\n", customer.Email)
+			return nil
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"ID", "Method", "Details", "Added"})
+		for _, method := range methods {
+			table.Append([]string{
+				method.ID,
+				method.Method,
+				describeSavedMethod(method),
+				method.CreatedAt.Format("2006-01-02"),
+			})
+		}
+		table.Render()
+
+		return nil
+	},
+}
+
+var userPaymentMethodRemoveCmd = &cobra.Command{
+	Use:   "remove [email] [payment-method-id]",
+	Short: "Remove a saved payment method",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		customer, err := app.Repository.GetCustomerByEmail(ctx, args[0])
+		if err != nil {
+			return reportFailure(err, "✗ Customer not found: %s", args[0])
+		}
+
+		if err := app.CustomerService.RemovePaymentMethod(ctx, customer.ID, args[1]); err != nil {
+			return reportFailure(err, "✗ Failed to remove payment method: %v", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(map[string]interface{}{"customer_id": customer.ID, "removed": args[1]})
+		}
+
+		color.Green("✓ Removed payment method %s", args[1])
+		return nil
+	},
+}
+
+func describeSavedMethod(method *domain.SavedPaymentMethod) string {
+	var parts []string
+	switch method.Method {
+	case "credit_card":
+		parts = []string{fmt.Sprint(method.Details["card_brand"]), fmt.Sprint(method.Details["last_4_digits"])}
+		if holder, ok := method.Details["card_holder"].(string); ok {
+			parts = append(parts, "("+holder+")")
+		}
+	default:
+		keys := make([]string, 0, len(method.Details))
+		for key := range method.Details {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			parts = append(parts, fmt.Sprint(method.Details[key]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	userPaymentMethodAddCmd.Flags().String("method", "credit_card", "Payment method (credit_card, paypal, crypto, wallet)")
+	addPaymentDetailFlags(userPaymentMethodAddCmd)
+
+	userPaymentMethodCmd.AddCommand(userPaymentMethodAddCmd)
+	userPaymentMethodCmd.AddCommand(userPaymentMethodListCmd)
+	userPaymentMethodCmd.AddCommand(userPaymentMethodRemoveCmd)
+	userCmd.AddCommand(userPaymentMethodCmd)
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var webhookCmd = &cobra.Command{
+	Use:   "webhook",
+	Short: "Inspect and replay failed webhook deliveries",
+}
+
+var webhookReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Re-send webhook events from the dead-letter file",
+	Long: `Webhook events that fail every retry are appended to the dead-letter file
+(notifications.webhook.dlq_path). Replay sends each of them once more with its
+original event ID; delivered events are removed and the rest stay for the
+next replay.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		app := GetApplication()
+
+		if app.WebhookNotifier == nil {
+			return fmt.Errorf("webhook notifications are not enabled")
+		}
+
+		result, err := app.WebhookNotifier.Replay(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to replay webhooks: %w", err)
+		}
+
+		if jsonOutput() {
+			return printJSON(result)
+		}
+
+		if len(result.Delivered) == 0 && len(result.Remaining) == 0 {
+			color.Yellow("No dead-lettered webhooks in %s", app.WebhookNotifier.DLQPath())
+			return nil
+		}
+
+		color.Green("✓ Delivered %d webhook(s)", len(result.Delivered))
+		if len(result.Remaining) > 0 {
+			color.Yellow("%d webhook(s) still failing:", len(result.Remaining))
+			printDeadLetters(result.Remaining)
+		}
+		return nil
+	},
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List webhook events waiting in the dead-letter file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		app := GetApplication()
+
+		if app.WebhookNotifier == nil || app.WebhookNotifier.DLQPath() == "" {
+			return fmt.Errorf("no webhook dead-letter file configured")
+		}
+
+		letters, err := observer.ReadWebhookDLQ(app.WebhookNotifier.DLQPath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read dead-letter file: %w", err)
+		}
+
+		if jsonOutput() {
+			if letters == nil {
+				letters = []observer.WebhookDeadLetter{}
+			}
+			return printJSON(letters)
+		}
+
+		if len(letters) == 0 {
+			color.Yellow("No dead-lettered webhooks")
+			return nil
+		}
+
+		printDeadLetters(letters)
+		return nil
+	},
+}
+
+func printDeadLetters(letters []observer.WebhookDeadLetter) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Event ID", "Type", "Transaction", "Attempts", "Failed At", "Error"})
+	for _, letter := range letters {
+		table.Append([]string{
+			letter.EventID,
+			string(letter.EventType),
+			letter.TransactionID,
+			fmt.Sprintf("%d", letter.Attempts),
+			letter.FailedAt.Format("2006-01-02 15:04:05"),
+			letter.Error,
+		})
+	}
+	table.Render()
+}
+
+func init() {
+	webhookCmd.AddCommand(webhookReplayCmd)
+	webhookCmd.AddCommand(webhookListCmd)
+}
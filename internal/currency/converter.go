@@ -0,0 +1,38 @@
+package currency
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+// Converter is the shared conversion service for the payment path and the
+// CLI, so limits, quotes and debits all use the same rates.
+type Converter struct {
+	rates RateProvider
+}
+
+func NewConverter(rates RateProvider) *Converter {
+	return &Converter{rates: rates}
+}
+
+func NewDefaultConverter() *Converter {
+	return NewConverter(NewStaticRateProvider(DefaultRates))
+}
+
+func (c *Converter) Rate(from, to string) (float64, error) {
+	return c.rates.GetRate(from, to)
+}
+
+func (c *Converter) Convert(amount float64, from, to string) (float64, error) {
+	return Convert(c.rates, amount, from, to)
+}
+
+// Validate rejects currencies the converter has no rate for.
+func (c *Converter) Validate(code string) error {
+	if _, err := c.rates.GetRate(code, BaseCurrency); err != nil {
+		return errors.NewValidationError(fmt.Sprintf("unsupported currency %q", strings.ToUpper(code)))
+	}
+	return nil
+}
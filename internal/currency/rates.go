@@ -0,0 +1,109 @@
+package currency
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+const BaseCurrency = "KZT"
+
+var DefaultRates = map[string]float64{
+	"USD": 538.0,
+	"EUR": 580.0,
+	"RUB": 5.8,
+	"CNY": 75.0,
+	"KZT": 1.0,
+}
+
+type RateProvider interface {
+	GetRate(from, to string) (float64, error)
+}
+
+type StaticRateProvider struct {
+	rates map[string]float64
+}
+
+func NewStaticRateProvider(rates map[string]float64) *StaticRateProvider {
+	normalized := make(map[string]float64, len(rates))
+	for code, rate := range rates {
+		normalized[strings.ToUpper(code)] = rate
+	}
+
+	return &StaticRateProvider{rates: normalized}
+}
+
+func (p *StaticRateProvider) GetRate(from, to string) (float64, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	if from == to {
+		return 1.0, nil
+	}
+
+	fromRate, ok := p.rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, errors.NewCurrencyError(from, to)
+	}
+
+	toRate, ok := p.rates[to]
+	if !ok || toRate <= 0 {
+		return 0, errors.NewCurrencyError(from, to)
+	}
+
+	return fromRate / toRate, nil
+}
+
+func Convert(provider RateProvider, amount float64, from, to string) (float64, error) {
+	if provider == nil {
+		return 0, errors.NewCurrencyError(from, to)
+	}
+
+	rate, err := provider.GetRate(from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate, nil
+}
+
+const DefaultCurrency = "USD"
+
+var symbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"RUB": "₽",
+	"CNY": "¥",
+	"KZT": "₸",
+}
+
+// Symbol returns the currency sign, or the code followed by a space for
+// currencies without one. An empty code means DefaultCurrency.
+func Symbol(code string) string {
+	if code == "" {
+		code = DefaultCurrency
+	}
+	code = strings.ToUpper(code)
+
+	if symbol, ok := symbols[code]; ok {
+		return symbol
+	}
+	return code + " "
+}
+
+func Format(amount float64, code string) string {
+	code = strings.ToUpper(code)
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if symbol, ok := symbols[code]; ok {
+		return fmt.Sprintf("%s%s%.2f", sign, symbol, amount)
+	}
+
+	return fmt.Sprintf("%s%.2f %s", sign, amount, code)
+}
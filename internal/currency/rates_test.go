@@ -0,0 +1,61 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		amount float64
+		code   string
+		want   string
+	}{
+		{"Dollar Sign", 1059.97, "USD", "$1059.97"},
+		{"Lowercase Code", 12.5, "eur", "€12.50"},
+		{"Negative Amount", -3.1, "KZT", "-₸3.10"},
+		{"Code Without Symbol", 42, "CHF", "42.00 CHF"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Format(tc.amount, tc.code))
+		})
+	}
+}
+
+func TestSymbol(t *testing.T) {
+	assert.Equal(t, "$", Symbol(""))
+	assert.Equal(t, "₽", Symbol("rub"))
+	assert.Equal(t, "CHF ", Symbol("CHF"))
+}
+
+func TestConverter(t *testing.T) {
+	converter := NewDefaultConverter()
+
+	t.Run("Converts Through The Base Currency", func(t *testing.T) {
+		rate, err := converter.Rate(DefaultCurrency, "EUR")
+		require.NoError(t, err)
+		assert.InDelta(t, 538.0/580.0, rate, 1e-12)
+
+		amount, err := converter.Convert(100, "usd", "KZT")
+		require.NoError(t, err)
+		assert.InDelta(t, 53800.0, amount, 1e-9)
+	})
+
+	t.Run("Same Currency Is Free", func(t *testing.T) {
+		rate, err := converter.Rate("EUR", "eur")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, rate)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		_, err := converter.Rate(DefaultCurrency, "XYZ")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeCurrencyUnavailable))
+
+		err = converter.Validate("xyz")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
@@ -3,8 +3,10 @@ package decorator
 import (
 	"context"
 
+	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
@@ -13,12 +15,19 @@ type CashbackDecorator struct {
 	tier1Threshold  float64
 	tier1Percentage float64
 	tier2Percentage float64
+	customerTier    domain.CustomerTier
+	tierPercentages map[domain.CustomerTier]float64
 }
 
+// CashbackConfig configures the cashback decorator. Tier1 and Tier2 pick a
+// percentage by amount; CustomerTier's entry in TierPercentages is used
+// instead when it is higher.
 type CashbackConfig struct {
 	Tier1Threshold  float64
 	Tier1Percentage float64
 	Tier2Percentage float64
+	CustomerTier    domain.CustomerTier
+	TierPercentages map[domain.CustomerTier]float64
 }
 
 func NewCashbackDecorator(wrapped payment.Payment, config CashbackConfig) *CashbackDecorator {
@@ -27,10 +36,14 @@ func NewCashbackDecorator(wrapped payment.Payment, config CashbackConfig) *Cashb
 		tier1Threshold:  config.Tier1Threshold,
 		tier1Percentage: config.Tier1Percentage,
 		tier2Percentage: config.Tier2Percentage,
+		customerTier:    config.CustomerTier,
+		tierPercentages: config.TierPercentages,
 	}
 }
 
-func (d *CashbackDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
+func (d *CashbackDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Applying cashback decorator",
 		zap.Float64("amount", amount),
 	)
@@ -42,7 +55,7 @@ func (d *CashbackDecorator) Process(ctx context.Context, amount float64) (*payme
 		zap.Float64("cashback_amount", cashbackAmount),
 	)
 
-	result, err := d.wrapped.Process(ctx, amount)
+	result, err := d.wrapped.Process(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -53,19 +66,27 @@ func (d *CashbackDecorator) Process(ctx context.Context, amount float64) (*payme
 		result.Metadata = make(map[string]interface{})
 	}
 	result.Metadata["cashback_amount"] = cashbackAmount
+	result.Breakdown.CashbackAmount = cashbackAmount
 	result.Metadata["cashback_percentage"] = d.getCashbackPercentage(amount)
+	if d.customerTier != "" {
+		result.Metadata["customer_tier"] = string(d.customerTier)
+	}
 
 	return result, nil
 }
 
 func (d *CashbackDecorator) calculateCashback(amount float64) float64 {
 	percentage := d.getCashbackPercentage(amount)
-	return amount * (percentage / 100.0)
+	return money.Percent(amount, percentage)
 }
 
 func (d *CashbackDecorator) getCashbackPercentage(amount float64) float64 {
+	percentage := d.tier1Percentage
 	if amount >= d.tier1Threshold {
-		return d.tier2Percentage
+		percentage = d.tier2Percentage
+	}
+	if tierPercentage := d.tierPercentages[d.customerTier]; tierPercentage > percentage {
+		return tierPercentage
 	}
-	return d.tier1Percentage
+	return percentage
 }
@@ -0,0 +1,63 @@
+package decorator
+
+import (
+	"context"
+	"math"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
+	"go.uber.org/zap"
+)
+
+// CashbackRedemptionDecorator deducts cashback that the facade has already
+// reserved from the customer's balance, capped at the amount due.
+type CashbackRedemptionDecorator struct {
+	*BaseDecorator
+	amountToRedeem float64
+}
+
+type CashbackRedemptionConfig struct {
+	AmountToRedeem float64
+}
+
+func NewCashbackRedemptionDecorator(wrapped payment.Payment, config CashbackRedemptionConfig) (*CashbackRedemptionDecorator, error) {
+	if config.AmountToRedeem < 0 {
+		return nil, errors.NewValidationError("cashback to redeem cannot be negative")
+	}
+
+	return &CashbackRedemptionDecorator{
+		BaseDecorator:  NewBaseDecorator(wrapped),
+		amountToRedeem: config.AmountToRedeem,
+	}, nil
+}
+
+func (d *CashbackRedemptionDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Applying cashback redemption decorator",
+		zap.Float64("amount", amount),
+		zap.Float64("cashback_to_redeem", d.amountToRedeem),
+	)
+
+	redeemed := math.Min(d.amountToRedeem, amount)
+	redeemed = money.Round(redeemed)
+	finalAmount := money.Round(amount - redeemed)
+
+	result, err := d.wrapped.Process(ctx, request.WithAmount(finalAmount))
+	if err != nil {
+		return nil, err
+	}
+
+	result.OriginalAmount = amount
+	result.AppliedDecorators = append(result.AppliedDecorators, "cashback_redemption")
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["cashback_redeemed"] = redeemed
+	result.Breakdown.CashbackRedeemed = redeemed
+
+	return result, nil
+}
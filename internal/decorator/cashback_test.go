@@ -0,0 +1,61 @@
+package decorator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCashbackDecoratorCustomerTier(t *testing.T) {
+	basePayment, err := payment.NewCreditCardPayment(
+		"4532015112830366",
+		"John Doe",
+		"12/30",
+		"123",
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	config := CashbackConfig{
+		Tier1Threshold:  100.00,
+		Tier1Percentage: 2.0,
+		Tier2Percentage: 5.0,
+		TierPercentages: map[domain.CustomerTier]float64{
+			domain.TierSilver: 4.0,
+			domain.TierGold:   10.0,
+		},
+	}
+
+	t.Run("Gold Customer Gets Higher Percentage", func(t *testing.T) {
+		config := config
+		config.CustomerTier = domain.TierGold
+
+		result, err := NewCashbackDecorator(basePayment, config).Process(ctx, payment.PaymentRequest{Amount: 200.00})
+		require.NoError(t, err)
+
+		assert.Equal(t, 10.0, result.Metadata["cashback_percentage"])
+		assert.Equal(t, "gold", result.Metadata["customer_tier"])
+	})
+
+	t.Run("Amount Percentage Wins When Higher", func(t *testing.T) {
+		config := config
+		config.CustomerTier = domain.TierSilver
+
+		result, err := NewCashbackDecorator(basePayment, config).Process(ctx, payment.PaymentRequest{Amount: 200.00})
+		require.NoError(t, err)
+
+		assert.Equal(t, 5.0, result.Metadata["cashback_percentage"])
+	})
+
+	t.Run("Tier From Loyalty Points", func(t *testing.T) {
+		thresholds := domain.TierThresholds{Silver: 1000, Gold: 5000}
+
+		assert.Equal(t, domain.TierBronze, (&domain.Customer{LoyaltyPoints: 999}).Tier(thresholds))
+		assert.Equal(t, domain.TierSilver, (&domain.Customer{LoyaltyPoints: 1000}).Tier(thresholds))
+		assert.Equal(t, domain.TierGold, (&domain.Customer{LoyaltyPoints: 5000}).Tier(thresholds))
+	})
+}
@@ -0,0 +1,105 @@
+package decorator
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
+	"go.uber.org/zap"
+)
+
+// Every code is computed against the same pre-discount amount, so stacked
+// percentages add up rather than compound.
+type CombinedDiscountDecorator struct {
+	*BaseDecorator
+	discounts          []DiscountConfig
+	maxTotalPercentage float64
+}
+
+type CombinedDiscountConfig struct {
+	Discounts          []DiscountConfig
+	MaxTotalPercentage float64
+}
+
+func NewCombinedDiscountDecorator(wrapped payment.Payment, config CombinedDiscountConfig) (*CombinedDiscountDecorator, error) {
+	if len(config.Discounts) == 0 {
+		return nil, errors.NewValidationError("at least one discount is required")
+	}
+	for _, discount := range config.Discounts {
+		if err := discount.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.MaxTotalPercentage < 0 || config.MaxTotalPercentage > 100 {
+		return nil, errors.NewValidationError("max total discount must be between 0 and 100%")
+	}
+
+	return &CombinedDiscountDecorator{
+		BaseDecorator:      NewBaseDecorator(wrapped),
+		discounts:          config.Discounts,
+		maxTotalPercentage: config.MaxTotalPercentage,
+	}, nil
+}
+
+func (d *CombinedDiscountDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	codes := make([]string, 0, len(d.discounts))
+	var discountAmount float64
+	for _, discount := range d.discounts {
+		if err := discount.checkApplies(amount); err != nil {
+			return nil, err
+		}
+		discountAmount += discount.calculate(amount)
+		codes = append(codes, discount.DiscountCode)
+	}
+
+	capped := false
+	if d.maxTotalPercentage > 0 {
+		if limit := money.Percent(amount, d.maxTotalPercentage); discountAmount > limit {
+			discountAmount = limit
+			capped = true
+		}
+	}
+	if discountAmount > amount {
+		discountAmount = amount
+	}
+	discountAmount = money.Round(discountAmount)
+	finalAmount := money.Round(amount - discountAmount)
+
+	logger.Info("Combined discount applied",
+		zap.Strings("codes", codes),
+		zap.Float64("original_amount", amount),
+		zap.Float64("discount_amount", discountAmount),
+		zap.Float64("final_amount", finalAmount),
+		zap.Bool("capped", capped),
+	)
+
+	result, err := d.wrapped.Process(ctx, request.WithAmount(finalAmount))
+	if err != nil {
+		return nil, err
+	}
+
+	result.OriginalAmount = amount
+	result.AppliedDecorators = append(result.AppliedDecorators, "discount")
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	if len(d.discounts) == 1 {
+		result.Metadata["discount_type"] = d.discounts[0].DiscountType
+		result.Metadata["discount_value"] = d.discounts[0].DiscountValue
+	}
+	result.Metadata["discount_amount"] = discountAmount
+	result.Breakdown.DiscountAmount = discountAmount
+	result.Metadata["discount_code"] = strings.Join(codes, ",")
+	result.Metadata["discount_codes"] = codes
+	if capped {
+		result.Metadata["discount_capped"] = true
+	}
+
+	return result, nil
+}
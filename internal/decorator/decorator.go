@@ -31,6 +31,6 @@ func (d *BaseDecorator) GetDetails() map[string]interface{} {
 	return d.wrapped.GetDetails()
 }
 
-func (d *BaseDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
-	return d.wrapped.Process(ctx, amount)
+func (d *BaseDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	return d.wrapped.Process(ctx, request)
 }
@@ -8,17 +8,13 @@ import (
 	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
 type DiscountDecorator struct {
 	*BaseDecorator
-	discountType  string
-	discountValue float64
-	minAmount     float64
-	maxDiscount   float64
-	expiryDate    time.Time
-	discountCode  string
+	config DiscountConfig
 }
 
 type DiscountConfig struct {
@@ -31,44 +27,31 @@ type DiscountConfig struct {
 }
 
 func NewDiscountDecorator(wrapped payment.Payment, config DiscountConfig) (*DiscountDecorator, error) {
-	if config.DiscountValue <= 0 {
-		return nil, errors.NewValidationError("discount value must be positive")
-	}
-
-	if config.DiscountType == "percentage" && config.DiscountValue > 100 {
-		return nil, errors.NewValidationError("percentage discount cannot exceed 100%")
+	if err := config.validate(); err != nil {
+		return nil, err
 	}
 
 	return &DiscountDecorator{
 		BaseDecorator: NewBaseDecorator(wrapped),
-		discountType:  config.DiscountType,
-		discountValue: config.DiscountValue,
-		minAmount:     config.MinAmount,
-		maxDiscount:   config.MaxDiscount,
-		expiryDate:    config.ExpiryDate,
-		discountCode:  config.DiscountCode,
+		config:        config,
 	}, nil
 }
 
-func (d *DiscountDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
+func (d *DiscountDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Applying discount decorator",
-		zap.String("type", d.discountType),
-		zap.Float64("value", d.discountValue),
+		zap.String("type", d.config.DiscountType),
+		zap.Float64("value", d.config.DiscountValue),
 		zap.Float64("original_amount", amount),
 	)
 
-	if !d.expiryDate.IsZero() && time.Now().After(d.expiryDate) {
-		return nil, errors.NewValidationError("discount code has expired")
-	}
-
-	if amount < d.minAmount {
-		return nil, errors.NewValidationError(
-			fmt.Sprintf("minimum amount for discount is $%.2f", d.minAmount),
-		)
+	if err := d.config.checkApplies(amount); err != nil {
+		return nil, err
 	}
 
-	discountAmount := d.calculateDiscount(amount)
-	finalAmount := amount - discountAmount
+	discountAmount := d.config.calculate(amount)
+	finalAmount := money.Round(amount - discountAmount)
 
 	if finalAmount < 0 {
 		finalAmount = 0
@@ -80,43 +63,68 @@ func (d *DiscountDecorator) Process(ctx context.Context, amount float64) (*payme
 		zap.Float64("final_amount", finalAmount),
 	)
 
-	result, err := d.wrapped.Process(ctx, finalAmount)
+	result, err := d.wrapped.Process(ctx, request.WithAmount(finalAmount))
 	if err != nil {
 		return nil, err
 	}
 
 	result.OriginalAmount = amount
-	result.ProcessedAmount = finalAmount
-	result.Amount = finalAmount
 	result.AppliedDecorators = append(result.AppliedDecorators, "discount")
 
 	if result.Metadata == nil {
 		result.Metadata = make(map[string]interface{})
 	}
-	result.Metadata["discount_type"] = d.discountType
-	result.Metadata["discount_value"] = d.discountValue
+	result.Metadata["discount_type"] = d.config.DiscountType
+	result.Metadata["discount_value"] = d.config.DiscountValue
 	result.Metadata["discount_amount"] = discountAmount
-	result.Metadata["discount_code"] = d.discountCode
+	result.Breakdown.DiscountAmount = discountAmount
+	result.Metadata["discount_code"] = d.config.DiscountCode
 
 	return result, nil
 }
 
-func (d *DiscountDecorator) calculateDiscount(amount float64) float64 {
+func (c DiscountConfig) validate() error {
+	if c.DiscountValue <= 0 {
+		return errors.NewValidationError("discount value must be positive")
+	}
+
+	if c.DiscountType == "percentage" && c.DiscountValue > 100 {
+		return errors.NewValidationError("percentage discount cannot exceed 100%")
+	}
+
+	return nil
+}
+
+func (c DiscountConfig) checkApplies(amount float64) error {
+	if !c.ExpiryDate.IsZero() && time.Now().After(c.ExpiryDate) {
+		return errors.NewValidationError("discount code has expired")
+	}
+
+	if amount < c.MinAmount {
+		return errors.NewValidationError(
+			fmt.Sprintf("minimum amount for discount is $%.2f", c.MinAmount),
+		)
+	}
+
+	return nil
+}
+
+func (c DiscountConfig) calculate(amount float64) float64 {
 	var discount float64
 
-	if d.discountType == "percentage" {
-		discount = amount * (d.discountValue / 100.0)
+	if c.DiscountType == "percentage" {
+		discount = money.Percent(amount, c.DiscountValue)
 	} else {
-		discount = d.discountValue
+		discount = c.DiscountValue
 	}
 
-	if d.maxDiscount > 0 && discount > d.maxDiscount {
-		discount = d.maxDiscount
+	if c.MaxDiscount > 0 && discount > c.MaxDiscount {
+		discount = c.MaxDiscount
 	}
 
 	if discount > amount {
 		discount = amount
 	}
 
-	return discount
+	return money.Round(discount)
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/strategy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -14,7 +15,7 @@ func TestDiscountDecorator(t *testing.T) {
 	basePayment, _ := payment.NewCreditCardPayment(
 		"4532015112830366",
 		"John Doe",
-		"12/25",
+		"12/30",
 		"123",
 	)
 
@@ -32,12 +33,13 @@ func TestDiscountDecorator(t *testing.T) {
 		require.NoError(t, err)
 
 		ctx := context.Background()
-		result, err := decorator.Process(ctx, 100.00)
+		result, err := decorator.Process(ctx, payment.PaymentRequest{Amount: 100.00})
 		require.NoError(t, err)
 
 		assert.True(t, result.Success)
 		assert.Equal(t, 100.00, result.OriginalAmount)
 		assert.Equal(t, 90.00, result.ProcessedAmount)
+		assert.Equal(t, 10.00, result.Breakdown.DiscountAmount)
 		assert.Contains(t, result.AppliedDecorators, "discount")
 	})
 
@@ -55,7 +57,7 @@ func TestDiscountDecorator(t *testing.T) {
 		require.NoError(t, err)
 
 		ctx := context.Background()
-		result, err := decorator.Process(ctx, 100.00)
+		result, err := decorator.Process(ctx, payment.PaymentRequest{Amount: 100.00})
 		require.NoError(t, err)
 
 		assert.Equal(t, 80.00, result.ProcessedAmount)
@@ -72,7 +74,47 @@ func TestDiscountDecorator(t *testing.T) {
 		require.NoError(t, err)
 
 		ctx := context.Background()
-		_, err = decorator.Process(ctx, 100.00)
+		_, err = decorator.Process(ctx, payment.PaymentRequest{Amount: 100.00})
 		assert.Error(t, err)
 	})
 }
+
+func TestDiscountRoundsToCents(t *testing.T) {
+	newCard := func() payment.Payment {
+		p, err := payment.NewCreditCardPayment("4532015112830366", "John Doe", "12/30", "123")
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("Percentage Of Odd Amount", func(t *testing.T) {
+		decorator, err := NewDiscountDecorator(newCard(), DiscountConfig{DiscountType: "percentage", DiscountValue: 10})
+		require.NoError(t, err)
+
+		result, err := decorator.Process(context.Background(), payment.PaymentRequest{Amount: 33.33})
+		require.NoError(t, err)
+		assert.Equal(t, 3.33, result.Breakdown.DiscountAmount)
+		assert.Equal(t, 30.00, result.ProcessedAmount)
+	})
+
+	t.Run("Three Way Split Of Discounted Total", func(t *testing.T) {
+		split, err := strategy.NewSplitPaymentStrategy([]strategy.SplitPaymentItem{
+			{Payment: newCard(), Amount: 33.33},
+			{Payment: newCard(), Amount: 33.33},
+			{Payment: newCard(), Amount: 33.34},
+		})
+		require.NoError(t, err)
+
+		// 10% off 111.11 is 11.111; unrounded, the split would be charged
+		// 99.999 and the last leg 33.339.
+		decorator, err := NewDiscountDecorator(strategy.NewSplitPayment(split), DiscountConfig{DiscountType: "percentage", DiscountValue: 10})
+		require.NoError(t, err)
+
+		result, err := decorator.Process(context.Background(), payment.PaymentRequest{Amount: 111.11})
+		require.NoError(t, err)
+		assert.Equal(t, 100.00, result.ProcessedAmount)
+
+		details := result.Metadata["split_details"].([]map[string]interface{})
+		assert.Equal(t, 33.33, details[0]["amount"])
+		assert.Equal(t, 33.34, details[2]["amount"])
+	})
+}
@@ -3,7 +3,6 @@ package decorator
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
 
@@ -18,8 +17,10 @@ type FraudDetectionDecorator struct {
 	maxRiskScore             int
 	velocityCheckWindow      time.Duration
 	maxTransactionsPerWindow int
-	transactionHistory       map[string][]time.Time
-	mu                       sync.RWMutex
+	customerID               string
+	scorer                   RiskScorer
+	profile                  *CustomerRiskProfile
+	history                  *TransactionHistory
 }
 
 type FraudDetectionConfig struct {
@@ -27,24 +28,103 @@ type FraudDetectionConfig struct {
 	VelocityCheckWindow      time.Duration
 	MaxTransactionsPerWindow int
 	CustomerID               string
+	Scorer                   RiskScorer
+	Profile                  *CustomerRiskProfile
+	// History is shared between decorators so velocity counts a customer's
+	// earlier checkouts. A nil History starts an empty one.
+	History *TransactionHistory
+}
+
+// TransactionHistory records when each customer's payments went through. It
+// is safe for concurrent use.
+type TransactionHistory struct {
+	mu         sync.Mutex
+	byCustomer map[string][]time.Time
+}
+
+func NewTransactionHistory() *TransactionHistory {
+	return &TransactionHistory{byCustomer: make(map[string][]time.Time)}
+}
+
+// Recent returns the customer's payments within window of now, oldest first.
+func (h *TransactionHistory) Recent(customerID string, window time.Duration) []time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.prune(window)
+
+	return append([]time.Time(nil), h.byCustomer[customerID]...)
+}
+
+func (h *TransactionHistory) Record(customerID string, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.byCustomer[customerID] = append(h.byCustomer[customerID], at)
+}
+
+// prune drops timestamps that fell out of the window so the history only
+// holds customers with recent activity. Callers hold h.mu.
+func (h *TransactionHistory) prune(window time.Duration) {
+	cutoff := time.Now().Add(-window)
+
+	for customerID, transactions := range h.byCustomer {
+		recent := transactions[:0]
+		for _, tx := range transactions {
+			if tx.After(cutoff) {
+				recent = append(recent, tx)
+			}
+		}
+
+		if len(recent) == 0 {
+			delete(h.byCustomer, customerID)
+		} else {
+			h.byCustomer[customerID] = recent
+		}
+	}
 }
 
 func NewFraudDetectionDecorator(wrapped payment.Payment, config FraudDetectionConfig) *FraudDetectionDecorator {
+	customerID := config.CustomerID
+	if customerID == "" {
+		customerID = "default"
+	}
+
+	scorer := config.Scorer
+	if scorer == nil {
+		scorer = NewDefaultRiskScorer()
+	}
+
+	history := config.History
+	if history == nil {
+		history = NewTransactionHistory()
+	}
+
 	return &FraudDetectionDecorator{
 		BaseDecorator:            NewBaseDecorator(wrapped),
 		maxRiskScore:             config.MaxRiskScore,
 		velocityCheckWindow:      config.VelocityCheckWindow,
 		maxTransactionsPerWindow: config.MaxTransactionsPerWindow,
-		transactionHistory:       make(map[string][]time.Time),
+		customerID:               customerID,
+		scorer:                   scorer,
+		profile:                  config.Profile,
+		history:                  history,
 	}
 }
 
-func (d *FraudDetectionDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
+func (d *FraudDetectionDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Applying fraud detection decorator",
 		zap.Float64("amount", amount),
 	)
 
-	riskScore := d.calculateRiskScore(amount)
+	riskScore := d.scorer.Score(RiskSignals{
+		Amount:             amount,
+		RecentTransactions: len(d.history.Recent(d.customerID, d.velocityCheckWindow)),
+		CustomerID:         d.customerID,
+		Profile:            d.profile,
+	})
 
 	logger.Info("Fraud risk calculated",
 		zap.Int("risk_score", riskScore),
@@ -65,7 +145,7 @@ func (d *FraudDetectionDecorator) Process(ctx context.Context, amount float64) (
 		return nil, err
 	}
 
-	result, err := d.wrapped.Process(ctx, amount)
+	result, err := d.wrapped.Process(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -87,54 +167,25 @@ func (d *FraudDetectionDecorator) Process(ctx context.Context, amount float64) (
 	return result, nil
 }
 
-func (d *FraudDetectionDecorator) calculateRiskScore(amount float64) int {
-
-	score := 0
-
-	if amount > 1000 {
-		score += 20
-	}
-	if amount > 5000 {
-		score += 30
-	}
-
-	score += rand.Intn(30)
-
-	return score
-}
-
 func (d *FraudDetectionDecorator) velocityCheck() error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	customerID := "default"
-
-	transactions, exists := d.transactionHistory[customerID]
-	if !exists {
-		return nil
-	}
-
-	cutoff := time.Now().Add(-d.velocityCheckWindow)
-	recent := []time.Time{}
-	for _, tx := range transactions {
-		if tx.After(cutoff) {
-			recent = append(recent, tx)
-		}
-	}
+	recent := d.history.Recent(d.customerID, d.velocityCheckWindow)
 
 	if len(recent) >= d.maxTransactionsPerWindow {
+		retryAfter := d.velocityCheckWindow
+		if n := len(recent) - d.maxTransactionsPerWindow; n >= 0 && n < len(recent) {
+			retryAfter = time.Until(recent[n].Add(d.velocityCheckWindow))
+		}
 		return errors.NewFraudDetectedError(
 			fmt.Sprintf("transaction velocity exceeded: %d transactions in %v",
 				len(recent), d.velocityCheckWindow),
-		)
+		).WithRetryAfter(retryAfter)
 	}
 
 	return nil
 }
 
 func (d *FraudDetectionDecorator) geolocationCheck() error {
-
-	if rand.Intn(100) < 5 {
+	if d.profile != nil && d.profile.GeolocationMismatch {
 		return errors.NewFraudDetectedError("geolocation validation failed")
 	}
 
@@ -142,16 +193,5 @@ func (d *FraudDetectionDecorator) geolocationCheck() error {
 }
 
 func (d *FraudDetectionDecorator) recordTransaction() {
-	d.mu.Lock()
-	defer d.mu.Unlock()
-
-	customerID := "default"
-
-	transactions, exists := d.transactionHistory[customerID]
-	if !exists {
-		transactions = []time.Time{}
-	}
-
-	transactions = append(transactions, time.Now())
-	d.transactionHistory[customerID] = transactions
+	d.history.Record(d.customerID, time.Now())
 }
@@ -0,0 +1,113 @@
+package decorator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFraudDetectionVelocityRetryAfter(t *testing.T) {
+	history := NewTransactionHistory()
+	decorator := NewFraudDetectionDecorator(nil, FraudDetectionConfig{
+		MaxRiskScore:             100,
+		VelocityCheckWindow:      10 * time.Minute,
+		MaxTransactionsPerWindow: 2,
+		History:                  history,
+	})
+
+	now := time.Now()
+	history.Record("default", now.Add(-8*time.Minute))
+	history.Record("default", now.Add(-1*time.Minute))
+
+	err := decorator.velocityCheck()
+	require.Error(t, err)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+
+	wrapped := errors.Wrap(err, errors.ErrCodePaymentFailed, "payment processing failed")
+	retryAfter, ok := errors.RetryAfter(wrapped)
+	require.True(t, ok)
+	assert.InDelta(t, (2 * time.Minute).Seconds(), retryAfter.Seconds(), 1)
+}
+
+func TestFraudDetectionVelocityPerCustomer(t *testing.T) {
+	history := NewTransactionHistory()
+	newDecorator := func(customerID string) *FraudDetectionDecorator {
+		return NewFraudDetectionDecorator(nil, FraudDetectionConfig{
+			MaxRiskScore:             100,
+			VelocityCheckWindow:      10 * time.Minute,
+			MaxTransactionsPerWindow: 2,
+			CustomerID:               customerID,
+			History:                  history,
+		})
+	}
+
+	now := time.Now()
+	history.Record("cust-a", now.Add(-2*time.Minute))
+	history.Record("cust-a", now.Add(-1*time.Minute))
+	history.Record("cust-b", now.Add(-20*time.Minute))
+
+	t.Run("Burst Blocks Only That Customer", func(t *testing.T) {
+		assert.Error(t, newDecorator("cust-a").velocityCheck())
+		assert.NoError(t, newDecorator("cust-b").velocityCheck())
+	})
+
+	t.Run("Stale Entries Are Pruned", func(t *testing.T) {
+		_, exists := history.byCustomer["cust-b"]
+		assert.False(t, exists)
+		assert.Len(t, history.byCustomer["cust-a"], 2)
+	})
+}
+
+func TestDefaultRiskScorer(t *testing.T) {
+	scorer := NewDefaultRiskScorer()
+
+	tests := []struct {
+		name    string
+		signals RiskSignals
+		want    int
+	}{
+		{"Small Amount", RiskSignals{Amount: 50}, 0},
+		{"High Amount", RiskSignals{Amount: 1500}, 20},
+		{"Very High Amount", RiskSignals{Amount: 6000}, 50},
+		{"Velocity", RiskSignals{Amount: 50, RecentTransactions: 3}, 30},
+		{"Very High Amount With High Velocity", RiskSignals{Amount: 6000, RecentTransactions: 4}, 90},
+		{"Risk Profile", RiskSignals{Amount: 1500, Profile: &CustomerRiskProfile{BaseScore: 15}}, 35},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scorer.Score(tt.signals))
+		})
+	}
+
+	t.Run("Injected Jitter", func(t *testing.T) {
+		jittered := &DefaultRiskScorer{Jitter: func() int { return 7 }}
+		assert.Equal(t, 27, jittered.Score(RiskSignals{Amount: 1500}))
+	})
+}
+
+func TestFraudDetectionBlocksHighRisk(t *testing.T) {
+	history := NewTransactionHistory()
+	decorator := NewFraudDetectionDecorator(nil, FraudDetectionConfig{
+		MaxRiskScore:             80,
+		VelocityCheckWindow:      time.Hour,
+		MaxTransactionsPerWindow: 5,
+		CustomerID:               "cust-risky",
+		History:                  history,
+	})
+
+	now := time.Now()
+	for i := 4; i >= 1; i-- {
+		history.Record("cust-risky", now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	_, err := decorator.Process(context.Background(), payment.PaymentRequest{Amount: 6000})
+	require.Error(t, err)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+	assert.Contains(t, err.Error(), "score: 90")
+}
@@ -3,10 +3,12 @@ package decorator
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +18,7 @@ type LoyaltyPointsDecorator struct {
 	pointsToRedeem          int
 	pointsToCurrencyRatio   float64
 	maxRedemptionPercentage float64
+	earnRate                float64
 }
 
 type LoyaltyPointsConfig struct {
@@ -23,6 +26,8 @@ type LoyaltyPointsConfig struct {
 	PointsToRedeem          int
 	PointsToCurrencyRatio   float64
 	MaxRedemptionPercentage float64
+	// EarnRate is the points earned per currency unit of the amount.
+	EarnRate float64
 }
 
 func NewLoyaltyPointsDecorator(wrapped payment.Payment, config LoyaltyPointsConfig) (*LoyaltyPointsDecorator, error) {
@@ -34,25 +39,32 @@ func NewLoyaltyPointsDecorator(wrapped payment.Payment, config LoyaltyPointsConf
 		return nil, errors.NewValidationError("points to redeem cannot be negative")
 	}
 
+	if config.EarnRate < 0 {
+		return nil, errors.NewValidationError("loyalty earn rate cannot be negative")
+	}
+
 	return &LoyaltyPointsDecorator{
 		BaseDecorator:           NewBaseDecorator(wrapped),
 		availablePoints:         config.AvailablePoints,
 		pointsToRedeem:          config.PointsToRedeem,
 		pointsToCurrencyRatio:   config.PointsToCurrencyRatio,
 		maxRedemptionPercentage: config.MaxRedemptionPercentage,
+		earnRate:                config.EarnRate,
 	}, nil
 }
 
-func (d *LoyaltyPointsDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
+func (d *LoyaltyPointsDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Applying loyalty points decorator",
 		zap.Float64("amount", amount),
 		zap.Int("points_to_redeem", d.pointsToRedeem),
 		zap.Int("available_points", d.availablePoints),
 	)
 
-	discount := float64(d.pointsToRedeem) / d.pointsToCurrencyRatio
+	discount := money.Round(float64(d.pointsToRedeem) / d.pointsToCurrencyRatio)
 
-	maxRedemption := amount * (d.maxRedemptionPercentage / 100.0)
+	maxRedemption := money.Percent(amount, d.maxRedemptionPercentage)
 	if discount > maxRedemption {
 		return nil, errors.NewValidationError(
 			fmt.Sprintf("loyalty points redemption exceeds maximum (%.2f%% of purchase)",
@@ -60,12 +72,12 @@ func (d *LoyaltyPointsDecorator) Process(ctx context.Context, amount float64) (*
 		)
 	}
 
-	finalAmount := amount - discount
+	finalAmount := money.Round(amount - discount)
 	if finalAmount < 0 {
 		finalAmount = 0
 	}
 
-	pointsEarned := int(amount)
+	pointsEarned := PointsEarned(amount, d.earnRate)
 
 	logger.Info("Loyalty points processed",
 		zap.Float64("original_amount", amount),
@@ -75,16 +87,12 @@ func (d *LoyaltyPointsDecorator) Process(ctx context.Context, amount float64) (*
 		zap.Int("points_earned", pointsEarned),
 	)
 
-	result, err := d.wrapped.Process(ctx, finalAmount)
+	result, err := d.wrapped.Process(ctx, request.WithAmount(finalAmount))
 	if err != nil {
 		return nil, err
 	}
 
-	if result.OriginalAmount == 0 {
-		result.OriginalAmount = amount
-	}
-	result.ProcessedAmount = finalAmount
-	result.Amount = finalAmount
+	result.OriginalAmount = amount
 	result.AppliedDecorators = append(result.AppliedDecorators, "loyalty_points")
 
 	if result.Metadata == nil {
@@ -94,6 +102,18 @@ func (d *LoyaltyPointsDecorator) Process(ctx context.Context, amount float64) (*
 	result.Metadata["loyalty_points_earned"] = pointsEarned
 	result.Metadata["loyalty_discount"] = discount
 	result.Metadata["loyalty_balance_after"] = d.availablePoints - d.pointsToRedeem + pointsEarned
+	result.Breakdown.LoyaltyPointsRedeemed = d.pointsToRedeem
+	result.Breakdown.LoyaltyPointsEarned = pointsEarned
+	result.Breakdown.LoyaltyDiscount = discount
 
 	return result, nil
 }
+
+// PointsEarned is the whole points a purchase of amount earns at earnRate
+// points per currency unit; fractions of a point are dropped.
+func PointsEarned(amount, earnRate float64) int {
+	if amount <= 0 || earnRate <= 0 {
+		return 0
+	}
+	return int(math.Floor(money.Round(amount * earnRate)))
+}
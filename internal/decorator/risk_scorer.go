@@ -0,0 +1,61 @@
+package decorator
+
+// RiskSignals are the inputs a RiskScorer uses to rate a transaction.
+type RiskSignals struct {
+	Amount             float64
+	RecentTransactions int
+	CustomerID         string
+	Profile            *CustomerRiskProfile
+}
+
+// CustomerRiskProfile carries optional per-customer risk information.
+type CustomerRiskProfile struct {
+	BaseScore           int
+	GeolocationMismatch bool
+}
+
+type RiskScorer interface {
+	Score(signals RiskSignals) int
+}
+
+const (
+	highAmountThreshold     = 1000.0
+	veryHighAmountThreshold = 5000.0
+	highAmountScore         = 20
+	veryHighAmountScore     = 30
+	velocityScorePerTx      = 10
+)
+
+// DefaultRiskScorer scores amount tiers, recent transaction velocity and the
+// customer's risk profile. Jitter, when set, is added on top so production
+// scores are harder to probe; tests leave it nil for exact scores.
+type DefaultRiskScorer struct {
+	Jitter func() int
+}
+
+func NewDefaultRiskScorer() *DefaultRiskScorer {
+	return &DefaultRiskScorer{}
+}
+
+func (s *DefaultRiskScorer) Score(signals RiskSignals) int {
+	score := 0
+
+	if signals.Amount > highAmountThreshold {
+		score += highAmountScore
+	}
+	if signals.Amount > veryHighAmountThreshold {
+		score += veryHighAmountScore
+	}
+
+	score += signals.RecentTransactions * velocityScorePerTx
+
+	if signals.Profile != nil {
+		score += signals.Profile.BaseScore
+	}
+
+	if s.Jitter != nil {
+		score += s.Jitter()
+	}
+
+	return score
+}
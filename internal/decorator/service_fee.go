@@ -0,0 +1,94 @@
+package decorator
+
+import (
+	"context"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
+	"go.uber.org/zap"
+)
+
+type ServiceFeeDecorator struct {
+	*BaseDecorator
+	feeType     string
+	feeValue    float64
+	exemptAbove float64
+}
+
+type ServiceFeeConfig struct {
+	FeeType     string
+	FeeValue    float64
+	ExemptAbove float64
+}
+
+func NewServiceFeeDecorator(wrapped payment.Payment, config ServiceFeeConfig) (*ServiceFeeDecorator, error) {
+	if config.FeeType != "flat" && config.FeeType != "percentage" {
+		return nil, errors.NewValidationError("service fee type must be flat or percentage")
+	}
+
+	if config.FeeValue < 0 {
+		return nil, errors.NewValidationError("service fee cannot be negative")
+	}
+
+	return &ServiceFeeDecorator{
+		BaseDecorator: NewBaseDecorator(wrapped),
+		feeType:       config.FeeType,
+		feeValue:      config.FeeValue,
+		exemptAbove:   config.ExemptAbove,
+	}, nil
+}
+
+func (d *ServiceFeeDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Applying service fee decorator",
+		zap.Float64("amount", amount),
+		zap.String("fee_type", d.feeType),
+		zap.Float64("fee_value", d.feeValue),
+	)
+
+	exempt := d.isExempt(amount)
+
+	fee := 0.0
+	if !exempt {
+		fee = d.calculateFee(amount)
+	}
+	totalAmount := money.Round(amount + fee)
+
+	logger.Info("Service fee calculated",
+		zap.Float64("amount", amount),
+		zap.Float64("service_fee", fee),
+		zap.Bool("exempt", exempt),
+	)
+
+	result, err := d.wrapped.Process(ctx, request.WithAmount(totalAmount))
+	if err != nil {
+		return nil, err
+	}
+
+	result.OriginalAmount = amount
+	result.AppliedDecorators = append(result.AppliedDecorators, "service_fee")
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["service_fee_amount"] = fee
+	result.Breakdown.ServiceFeeAmount = fee
+	result.Metadata["service_fee_type"] = d.feeType
+	result.Metadata["service_fee_exempt"] = exempt
+
+	return result, nil
+}
+
+func (d *ServiceFeeDecorator) isExempt(amount float64) bool {
+	return d.exemptAbove > 0 && amount > d.exemptAbove
+}
+
+func (d *ServiceFeeDecorator) calculateFee(amount float64) float64 {
+	if d.feeType == "percentage" {
+		return money.Percent(amount, d.feeValue)
+	}
+	return d.feeValue
+}
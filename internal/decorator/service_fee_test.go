@@ -0,0 +1,62 @@
+package decorator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceFeeDecorator(t *testing.T) {
+	basePayment, err := payment.NewCreditCardPayment(
+		"4532015112830366",
+		"John Doe",
+		"12/30",
+		"123",
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("Flat Fee", func(t *testing.T) {
+		decorator, err := NewServiceFeeDecorator(basePayment, ServiceFeeConfig{
+			FeeType:  "flat",
+			FeeValue: 2.50,
+		})
+		require.NoError(t, err)
+
+		result, err := decorator.Process(ctx, payment.PaymentRequest{Amount: 40.00})
+		require.NoError(t, err)
+
+		assert.Equal(t, 42.50, result.ProcessedAmount)
+		assert.Equal(t, 2.50, result.Metadata["service_fee_amount"])
+		assert.Contains(t, result.AppliedDecorators, "service_fee")
+	})
+
+	t.Run("Exemption Boundary", func(t *testing.T) {
+		decorator, err := NewServiceFeeDecorator(basePayment, ServiceFeeConfig{
+			FeeType:     "percentage",
+			FeeValue:    2.0,
+			ExemptAbove: 100.00,
+		})
+		require.NoError(t, err)
+
+		atThreshold, err := decorator.Process(ctx, payment.PaymentRequest{Amount: 100.00})
+		require.NoError(t, err)
+		assert.Equal(t, 102.00, atThreshold.ProcessedAmount)
+		assert.Equal(t, false, atThreshold.Metadata["service_fee_exempt"])
+
+		aboveThreshold, err := decorator.Process(ctx, payment.PaymentRequest{Amount: 100.01})
+		require.NoError(t, err)
+		assert.Equal(t, 100.01, aboveThreshold.ProcessedAmount)
+		assert.Equal(t, 0.0, aboveThreshold.Metadata["service_fee_amount"])
+		assert.Equal(t, true, aboveThreshold.Metadata["service_fee_exempt"])
+	})
+
+	t.Run("Invalid Fee Type", func(t *testing.T) {
+		_, err := NewServiceFeeDecorator(basePayment, ServiceFeeConfig{FeeType: "tiered", FeeValue: 1})
+		assert.Error(t, err)
+	})
+}
@@ -2,52 +2,122 @@ package decorator
 
 import (
 	"context"
+	"strings"
 
+	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
 type TaxDecorator struct {
 	*BaseDecorator
-	region      string
-	taxRate     float64
-	taxRates    map[string]float64
-	defaultRate float64
+	region        string
+	category      string
+	provider      TaxRateProvider
+	components    []TaxComponent
+	categoryRates map[string]float64
 }
 
+// TaxComponent is one jurisdiction's rate, in percent, within a compound tax.
+type TaxComponent struct {
+	Name string
+	Rate float64
+}
+
+// TaxConfig configures the tax decorator. Components, when set, are summed
+// into the rate and itemized in the result; otherwise the single rate for
+// Region comes from Provider, which defaults to a static provider over
+// TaxRates and DefaultRate. CategoryRates, keyed by product category,
+// replace the regional rate for cart lines in that category; a zero rate
+// exempts them.
 type TaxConfig struct {
-	Region      string
-	TaxRates    map[string]float64
-	DefaultRate float64
+	Region        string
+	Category      string
+	TaxRates      map[string]float64
+	DefaultRate   float64
+	Provider      TaxRateProvider
+	Components    []TaxComponent
+	CategoryRates map[string]float64
 }
 
 func NewTaxDecorator(wrapped payment.Payment, config TaxConfig) *TaxDecorator {
-	decorator := &TaxDecorator{
+	provider := config.Provider
+	if provider == nil {
+		provider = NewStaticTaxRateProvider(config.TaxRates, config.DefaultRate)
+	}
+
+	return &TaxDecorator{
 		BaseDecorator: NewBaseDecorator(wrapped),
 		region:        config.Region,
-		taxRates:      config.TaxRates,
-		defaultRate:   config.DefaultRate,
+		category:      config.Category,
+		provider:      provider,
+		components:    config.Components,
+		categoryRates: normalizeTaxCategories(config.CategoryRates),
 	}
+}
 
-	if rate, exists := config.TaxRates[config.Region]; exists {
-		decorator.taxRate = rate
-	} else {
-		decorator.taxRate = config.DefaultRate
+// Process taxes the amount per product category when the request carries
+// cart lines, splitting it across the lines in proportion to their value.
+// Without them the whole amount is taxed at the decorator's category.
+func (d *TaxDecorator) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	var taxAmount, exemptAmount float64
+	var lines []domain.TaxLine
+	lineIndex := make(map[string]int)
+	rates := make(map[float64]bool)
+
+	for _, part := range d.taxParts(amount, request.Items) {
+		if part.exempt {
+			exemptAmount += part.amount
+			continue
+		}
+
+		components, err := d.resolveComponents(ctx, part.category)
+		if err != nil {
+			// Charging without tax would under-collect, so the checkout fails.
+			return nil, errors.NewTaxError(d.region, err)
+		}
+
+		var partRate float64
+		for _, component := range components {
+			componentAmount := money.Percent(part.amount, component.Rate)
+			partRate += component.Rate
+			taxAmount += componentAmount
+			if i, ok := lineIndex[component.Name]; ok {
+				lines[i].Amount = money.Round(lines[i].Amount + componentAmount)
+				continue
+			}
+			lineIndex[component.Name] = len(lines)
+			lines = append(lines, domain.TaxLine{Name: component.Name, Rate: component.Rate, Amount: componentAmount})
+		}
+		rates[partRate] = true
 	}
 
-	return decorator
-}
+	// A single rate over the whole amount is reported as is; mixed rates or
+	// exempt lines are reported as the effective rate.
+	var taxRate float64
+	if len(rates) == 1 && exemptAmount == 0 {
+		for rate := range rates {
+			taxRate = rate
+		}
+	} else if amount > 0 {
+		taxRate = money.Round(taxAmount / amount * 100)
+	}
 
-func (d *TaxDecorator) Process(ctx context.Context, amount float64) (*payment.PaymentResult, error) {
 	logger.Info("Applying tax decorator",
 		zap.Float64("amount", amount),
 		zap.String("region", d.region),
-		zap.Float64("tax_rate", d.taxRate),
+		zap.Float64("tax_rate", taxRate),
+		zap.Float64("exempt_amount", exemptAmount),
+		zap.Int("components", len(lines)),
 	)
 
-	taxAmount := amount * (d.taxRate / 100.0)
-	totalAmount := amount + taxAmount
+	taxAmount = money.Round(taxAmount)
+	totalAmount := money.Round(amount + taxAmount)
 
 	logger.Info("Tax calculated",
 		zap.Float64("subtotal", amount),
@@ -55,16 +125,12 @@ func (d *TaxDecorator) Process(ctx context.Context, amount float64) (*payment.Pa
 		zap.Float64("total_amount", totalAmount),
 	)
 
-	result, err := d.wrapped.Process(ctx, totalAmount)
+	result, err := d.wrapped.Process(ctx, request.WithAmount(totalAmount))
 	if err != nil {
 		return nil, err
 	}
 
-	if result.OriginalAmount == 0 {
-		result.OriginalAmount = amount
-	}
-	result.ProcessedAmount = totalAmount
-	result.Amount = totalAmount
+	result.OriginalAmount = amount
 	result.AppliedDecorators = append(result.AppliedDecorators, "tax")
 
 	if result.Metadata == nil {
@@ -72,8 +138,87 @@ func (d *TaxDecorator) Process(ctx context.Context, amount float64) (*payment.Pa
 	}
 	result.Metadata["subtotal"] = amount
 	result.Metadata["tax_amount"] = taxAmount
-	result.Metadata["tax_rate"] = d.taxRate
+	result.Breakdown.TaxAmount = taxAmount
+	result.Breakdown.TaxLines = lines
+	result.Metadata["tax_rate"] = taxRate
 	result.Metadata["tax_region"] = d.region
+	result.Metadata["tax_components"] = lines
+	if exemptAmount > 0 {
+		result.Metadata["tax_exempt_amount"] = money.Round(exemptAmount)
+	}
 
 	return result, nil
 }
+
+type taxPart struct {
+	category string
+	exempt   bool
+	amount   float64
+}
+
+// taxParts groups amount by category and exemption. The last group takes
+// the remainder so the parts always add up to amount exactly.
+func (d *TaxDecorator) taxParts(amount float64, items []payment.LineItem) []taxPart {
+	var itemsTotal float64
+	for _, item := range items {
+		itemsTotal += item.Amount
+	}
+	if itemsTotal <= 0 {
+		return []taxPart{{category: d.category, amount: amount}}
+	}
+
+	var parts []taxPart
+	index := make(map[taxPart]int)
+	for _, item := range items {
+		category := item.Category
+		if category == "" {
+			category = d.category
+		}
+		key := taxPart{category: taxCategoryKey(category), exempt: item.TaxExempt}
+		i, ok := index[key]
+		if !ok {
+			i = len(parts)
+			index[key] = i
+			parts = append(parts, taxPart{category: category, exempt: item.TaxExempt})
+		}
+		parts[i].amount += amount * item.Amount / itemsTotal
+	}
+
+	allocated := 0.0
+	for i := range parts[:len(parts)-1] {
+		allocated += parts[i].amount
+	}
+	parts[len(parts)-1].amount = amount - allocated
+	return parts
+}
+
+func (d *TaxDecorator) resolveComponents(ctx context.Context, category string) ([]TaxComponent, error) {
+	if rate, ok := d.categoryRates[taxCategoryKey(category)]; ok {
+		if rate == 0 {
+			return nil, nil
+		}
+		return []TaxComponent{{Name: strings.TrimSpace(category) + " Tax", Rate: rate}}, nil
+	}
+
+	if len(d.components) > 0 {
+		return d.components, nil
+	}
+
+	rate, err := d.provider.Rate(ctx, d.region, category)
+	if err != nil {
+		return nil, err
+	}
+	return []TaxComponent{{Name: "Sales Tax", Rate: rate}}, nil
+}
+
+func taxCategoryKey(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+func normalizeTaxCategories(rates map[string]float64) map[string]float64 {
+	normalized := make(map[string]float64, len(rates))
+	for category, rate := range rates {
+		normalized[taxCategoryKey(category)] = rate
+	}
+	return normalized
+}
@@ -0,0 +1,176 @@
+package decorator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TaxRateProvider returns the tax rate, in percent, for a region and product
+// category. An empty category means the general rate.
+type TaxRateProvider interface {
+	Rate(ctx context.Context, region, category string) (float64, error)
+}
+
+// StaticTaxRateProvider serves rates from configuration, falling back to the
+// default rate for regions without an entry. Regions match case-insensitively,
+// since configuration loading lower-cases map keys.
+type StaticTaxRateProvider struct {
+	rates       map[string]float64
+	localRates  map[string]float64
+	defaultRate float64
+}
+
+func NewStaticTaxRateProvider(rates map[string]float64, defaultRate float64) *StaticTaxRateProvider {
+	return &StaticTaxRateProvider{
+		rates:       normalizeTaxRegions(rates),
+		defaultRate: defaultRate,
+	}
+}
+
+// SetLocalRates adds city and postal code rates levied on top of the state
+// rate, keyed "STATE/City" or "STATE/postal-code".
+func (p *StaticTaxRateProvider) SetLocalRates(rates map[string]float64) {
+	p.localRates = normalizeTaxRegions(rates)
+}
+
+func (p *StaticTaxRateProvider) Rate(ctx context.Context, region, category string) (float64, error) {
+	if rate, ok := p.rates[taxRegionKey(region)]; ok {
+		return rate, nil
+	}
+	return p.defaultRate, nil
+}
+
+// Components splits the tax for an address into its jurisdictions: the state
+// rate, or the default rate when the state has none, followed by any local
+// rates for the city and postal code.
+func (p *StaticTaxRateProvider) Components(state, city, postalCode string) []TaxComponent {
+	state = taxRegionKey(state)
+
+	var components []TaxComponent
+	if rate, ok := p.rates[state]; ok && state != "" {
+		components = append(components, TaxComponent{Name: state + " State Tax", Rate: rate})
+	} else {
+		components = append(components, TaxComponent{Name: "Sales Tax", Rate: p.defaultRate})
+	}
+
+	if state == "" {
+		return components
+	}
+	if rate, ok := p.localRates[taxRegionKey(state+"/"+city)]; ok && city != "" {
+		components = append(components, TaxComponent{Name: strings.TrimSpace(city) + " City Tax", Rate: rate})
+	}
+	if rate, ok := p.localRates[taxRegionKey(state+"/"+postalCode)]; ok && postalCode != "" {
+		components = append(components, TaxComponent{Name: strings.TrimSpace(postalCode) + " District Tax", Rate: rate})
+	}
+	return components
+}
+
+func taxRegionKey(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+func normalizeTaxRegions(rates map[string]float64) map[string]float64 {
+	normalized := make(map[string]float64, len(rates))
+	for region, rate := range rates {
+		normalized[taxRegionKey(region)] = rate
+	}
+	return normalized
+}
+
+type cachedTaxRate struct {
+	rate      float64
+	expiresAt time.Time
+}
+
+// HTTPTaxRateProvider looks rates up from an external tax service with
+// GET <url>?region=..&category=.., expecting {"rate": <percent>}. Successful
+// lookups are cached for the TTL; failures are never cached.
+type HTTPTaxRateProvider struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedTaxRate
+}
+
+func NewHTTPTaxRateProvider(serviceURL string, timeout, ttl time.Duration) *HTTPTaxRateProvider {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &HTTPTaxRateProvider{
+		url:    serviceURL,
+		client: &http.Client{Timeout: timeout},
+		ttl:    ttl,
+		now:    time.Now,
+		cache:  make(map[string]cachedTaxRate),
+	}
+}
+
+func (p *HTTPTaxRateProvider) Rate(ctx context.Context, region, category string) (float64, error) {
+	key := region + "|" + category
+
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && p.now().Before(cached.expiresAt) {
+		return cached.rate, nil
+	}
+
+	rate, err := p.fetch(ctx, region, category)
+	if err != nil {
+		return 0, err
+	}
+
+	if p.ttl > 0 {
+		p.mu.Lock()
+		p.cache[key] = cachedTaxRate{rate: rate, expiresAt: p.now().Add(p.ttl)}
+		p.mu.Unlock()
+	}
+
+	return rate, nil
+}
+
+func (p *HTTPTaxRateProvider) fetch(ctx context.Context, region, category string) (float64, error) {
+	query := url.Values{}
+	query.Set("region", region)
+	if category != "" {
+		query.Set("category", category)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "ECommerce-Payment-System/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("tax service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("tax service returned status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Rate *float64 `json:"rate"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("invalid tax service response: %w", err)
+	}
+	if body.Rate == nil || *body.Rate < 0 {
+		return 0, fmt.Errorf("tax service response has no valid rate")
+	}
+
+	return *body.Rate, nil
+}
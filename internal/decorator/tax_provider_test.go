@@ -0,0 +1,155 @@
+package decorator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTaxRateProvider(t *testing.T) {
+	ctx := context.Background()
+
+	var requests int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		rate := 6.25
+		if r.URL.Query().Get("category") == "groceries" {
+			rate = 0
+		}
+		fmt.Fprintf(w, `{"rate": %v}`, rate)
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewHTTPTaxRateProvider(server.URL, time.Second, time.Hour)
+	provider.now = func() time.Time { return now }
+
+	t.Run("Caches Rates Until TTL Expires", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			rate, err := provider.Rate(ctx, "TX", "")
+			require.NoError(t, err)
+			assert.Equal(t, 6.25, rate)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+		rate, err := provider.Rate(ctx, "TX", "groceries")
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, rate)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+		now = now.Add(61 * time.Minute)
+		_, err = provider.Rate(ctx, "TX", "")
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("Provider Failure Fails Checkout", func(t *testing.T) {
+		failing.Store(true)
+
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 500)
+		require.NoError(t, err)
+		decorator := NewTaxDecorator(base, TaxConfig{Region: "CA", Provider: provider})
+
+		_, err = decorator.Process(ctx, payment.PaymentRequest{Amount: 100})
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeTaxUnavailable))
+	})
+}
+
+func TestCompoundTax(t *testing.T) {
+	ctx := context.Background()
+
+	provider := NewStaticTaxRateProvider(map[string]float64{"ca": 7.25}, 8.5)
+	provider.SetLocalRates(map[string]float64{"ca/san francisco": 1.25, "CA/94103": 0.5})
+
+	t.Run("Sums State City And Postal Components", func(t *testing.T) {
+		components := provider.Components("CA", "San Francisco", "94103")
+		assert.Equal(t, []TaxComponent{
+			{Name: "CA State Tax", Rate: 7.25},
+			{Name: "San Francisco City Tax", Rate: 1.25},
+			{Name: "94103 District Tax", Rate: 0.5},
+		}, components)
+
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 500)
+		require.NoError(t, err)
+		result, err := NewTaxDecorator(base, TaxConfig{Region: "CA", Provider: provider, Components: components}).Process(ctx, payment.PaymentRequest{Amount: 100})
+		require.NoError(t, err)
+
+		assert.InDelta(t, 9.0, result.Breakdown.TaxAmount, 0.0001)
+		assert.InDelta(t, 9.0, result.Metadata["tax_rate"], 0.0001)
+		require.Len(t, result.Breakdown.TaxLines, 3)
+		assert.Equal(t, "San Francisco City Tax", result.Breakdown.TaxLines[1].Name)
+		assert.InDelta(t, 1.25, result.Breakdown.TaxLines[1].Amount, 0.0001)
+	})
+
+	t.Run("Unknown State Falls Back To Default Rate", func(t *testing.T) {
+		assert.Equal(t, []TaxComponent{{Name: "Sales Tax", Rate: 8.5}}, provider.Components("OR", "Portland", "97201"))
+	})
+
+	t.Run("Rate Lookup Ignores Case", func(t *testing.T) {
+		rate, err := provider.Rate(ctx, "CA", "")
+		require.NoError(t, err)
+		assert.Equal(t, 7.25, rate)
+	})
+}
+
+func TestTaxByCategory(t *testing.T) {
+	provider := NewStaticTaxRateProvider(map[string]float64{"CA": 10}, 8.5)
+	config := TaxConfig{Region: "CA", Provider: provider, CategoryRates: map[string]float64{"groceries": 0, "clothing": 5}}
+
+	process := func(t *testing.T, amount float64, items []payment.LineItem) *payment.PaymentResult {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 500)
+		require.NoError(t, err)
+		result, err := NewTaxDecorator(base, config).Process(context.Background(), payment.PaymentRequest{Amount: amount, Items: items})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("Taxes Each Category At Its Rate", func(t *testing.T) {
+		result := process(t, 100, []payment.LineItem{
+			{ProductID: "laptop", Category: "Electronics", Amount: 50},
+			{ProductID: "bread", Category: "Groceries", Amount: 20},
+			{ProductID: "shirt", Category: "Clothing", Amount: 30},
+		})
+
+		assert.InDelta(t, 5.0+1.5, result.Breakdown.TaxAmount, 0.0001)
+		assert.InDelta(t, 106.5, result.Amount, 0.0001)
+		assert.InDelta(t, 6.5, result.Metadata["tax_rate"], 0.0001)
+		assert.Equal(t, []domain.TaxLine{
+			{Name: "Sales Tax", Rate: 10, Amount: 5},
+			{Name: "Clothing Tax", Rate: 5, Amount: 1.5},
+		}, result.Breakdown.TaxLines)
+	})
+
+	t.Run("Exempt Products Are Not Taxed", func(t *testing.T) {
+		result := process(t, 80, []payment.LineItem{
+			{ProductID: "laptop", Category: "Electronics", Amount: 60},
+			{ProductID: "insulin", Category: "Medical", TaxExempt: true, Amount: 40},
+		})
+
+		assert.InDelta(t, 4.8, result.Breakdown.TaxAmount, 0.0001)
+		assert.InDelta(t, 32.0, result.Metadata["tax_exempt_amount"], 0.0001)
+	})
+
+	t.Run("Without Line Items Taxes The Whole Amount", func(t *testing.T) {
+		result := process(t, 100, nil)
+
+		assert.InDelta(t, 10.0, result.Breakdown.TaxAmount, 0.0001)
+		assert.InDelta(t, 10.0, result.Metadata["tax_rate"], 0.0001)
+	})
+}
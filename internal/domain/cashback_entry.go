@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// CashbackEntry is one movement in a customer's cashback ledger. It is
+// written together with the balance change, so Balance is the balance right
+// after this movement.
+type CashbackEntry struct {
+	ID            string    `json:"id"`
+	CustomerID    string    `json:"customer_id"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Delta         float64   `json:"delta"`
+	Reason        string    `json:"reason"`
+	Balance       float64   `json:"balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const (
+	CashbackReasonPayout   = "payout"
+	CashbackReasonRedeem   = "redeem"
+	CashbackReasonRestore  = "restore"
+	CashbackReasonClawback = "clawback"
+)
@@ -0,0 +1,30 @@
+package domain
+
+// CustomerTier ranks customers by their loyalty points for tier benefits
+// such as a higher cashback percentage.
+type CustomerTier string
+
+const (
+	TierBronze CustomerTier = "bronze"
+	TierSilver CustomerTier = "silver"
+	TierGold   CustomerTier = "gold"
+)
+
+// TierThresholds are the loyalty points needed to reach each tier above
+// bronze. A zero threshold leaves that tier out.
+type TierThresholds struct {
+	Silver int
+	Gold   int
+}
+
+// Tier ranks the customer against the thresholds.
+func (c *Customer) Tier(thresholds TierThresholds) CustomerTier {
+	switch {
+	case thresholds.Gold > 0 && c.LoyaltyPoints >= thresholds.Gold:
+		return TierGold
+	case thresholds.Silver > 0 && c.LoyaltyPoints >= thresholds.Silver:
+		return TierSilver
+	default:
+		return TierBronze
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "github.com/ecommerce/payment-system/pkg/money"
+
+// TransactionFinancials breaks a transaction into the amounts an accounting
+// ledger needs. Amount is the cart subtotal; Total is what was charged.
+type TransactionFinancials struct {
+	Amount           float64 `json:"amount"`
+	Discount         float64 `json:"discount"`
+	LoyaltyDiscount  float64 `json:"loyalty_discount"`
+	CashbackRedeemed float64 `json:"cashback_redeemed"`
+	Tax              float64 `json:"tax"`
+	Fees             float64 `json:"fees"`
+	Total            float64 `json:"total"`
+	Refunded         float64 `json:"refunded"`
+	Net              float64 `json:"net"`
+}
+
+func (t *Transaction) Financials() TransactionFinancials {
+	f := TransactionFinancials{
+		Amount:           t.Amount,
+		Discount:         metadataNumber(t.PaymentDetails, "discount_amount"),
+		LoyaltyDiscount:  metadataNumber(t.PaymentDetails, "loyalty_discount"),
+		CashbackRedeemed: metadataNumber(t.PaymentDetails, "cashback_redeemed"),
+		Tax:              metadataNumber(t.PaymentDetails, "tax_amount"),
+		Fees:             metadataNumber(t.PaymentDetails, "service_fee_amount"),
+		Refunded:         metadataNumber(t.Metadata, "refunded_amount"),
+	}
+
+	switch {
+	case hasMetadata(t.Metadata, "captured_amount"):
+		f.Total = metadataNumber(t.Metadata, "captured_amount")
+	case hasMetadata(t.Metadata, "charged_amount"):
+		f.Total = metadataNumber(t.Metadata, "charged_amount")
+	default:
+		f.Total = f.Amount - f.Discount - f.LoyaltyDiscount - f.CashbackRedeemed + f.Tax + f.Fees
+	}
+
+	if t.Status == TransactionStatusFailed {
+		f.Total = 0
+	}
+
+	f.Net = f.Total - f.Refunded
+
+	for _, amount := range []*float64{
+		&f.Amount, &f.Discount, &f.LoyaltyDiscount, &f.CashbackRedeemed,
+		&f.Tax, &f.Fees, &f.Total, &f.Refunded, &f.Net,
+	} {
+		*amount = money.Round(*amount)
+	}
+
+	return f
+}
+
+func hasMetadata(metadata map[string]interface{}, key string) bool {
+	_, ok := metadata[key]
+	return ok
+}
+
+// metadataNumber reads a numeric metadata value, which is an int or float64
+// in memory and always a float64 after a JSON roundtrip.
+func metadataNumber(metadata map[string]interface{}, key string) float64 {
+	switch v := metadata[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
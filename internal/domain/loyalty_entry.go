@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// LoyaltyEntry is one movement in a customer's loyalty ledger. Entries are
+// recorded from loyalty change events, so Balance is the balance right after
+// this movement.
+type LoyaltyEntry struct {
+	ID            string    `json:"id"`
+	CustomerID    string    `json:"customer_id"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	Delta         int       `json:"delta"`
+	Reason        string    `json:"reason"`
+	Balance       int       `json:"balance"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -1,20 +1,25 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
+	"github.com/ecommerce/payment-system/pkg/money"
 	"github.com/google/uuid"
 )
 
 type Customer struct {
-	ID            string    `json:"id"`
-	Email         string    `json:"email"`
-	Name          string    `json:"name"`
-	Phone         string    `json:"phone"`
-	LoyaltyPoints int       `json:"loyalty_points"`
-	Address       Address   `json:"address"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID              string    `json:"id"`
+	Email           string    `json:"email"`
+	Name            string    `json:"name"`
+	Phone           string    `json:"phone"`
+	LoyaltyPoints   int       `json:"loyalty_points"`
+	CashbackBalance float64   `json:"cashback_balance"`
+	SpendingLimit   float64   `json:"spending_limit,omitempty"`
+	Locale          string    `json:"locale,omitempty"`
+	Address         Address   `json:"address"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type Address struct {
@@ -33,6 +38,7 @@ type Product struct {
 	SKU         string    `json:"sku"`
 	Stock       int       `json:"stock"`
 	Category    string    `json:"category"`
+	TaxExempt   bool      `json:"tax_exempt,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -50,22 +56,74 @@ type Cart struct {
 	Items      []CartItem `json:"items"`
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
+	// Version is bumped by every successful UpdateCart; an update carrying
+	// an older version is rejected as a conflict.
+	Version int `json:"version"`
 }
 
-func (c *Cart) GetTotal() float64 {
-	total := 0.0
+type PricingInputs struct {
+	LineAdjustments map[string]float64
+	Discount        float64
+	Tax             float64
+}
+
+type CartBreakdown struct {
+	Lines     []LineBreakdown `json:"lines"`
+	ItemCount int             `json:"item_count"`
+	Subtotal  float64         `json:"subtotal"`
+	Discount  float64         `json:"discount"`
+	Tax       float64         `json:"tax"`
+	Total     float64         `json:"total"`
+}
+
+type LineBreakdown struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	SKU         string  `json:"sku"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Gross       float64 `json:"gross"`
+	Adjustment  float64 `json:"adjustment,omitempty"`
+	Total       float64 `json:"total"`
+}
+
+func (c *Cart) Breakdown(pricing PricingInputs) CartBreakdown {
+	breakdown := CartBreakdown{
+		Lines:    make([]LineBreakdown, 0, len(c.Items)),
+		Discount: pricing.Discount,
+		Tax:      pricing.Tax,
+	}
+
 	for _, item := range c.Items {
-		total += item.Price * float64(item.Quantity)
+		gross := item.Price * float64(item.Quantity)
+		adjustment := pricing.LineAdjustments[item.ProductID]
+
+		breakdown.Lines = append(breakdown.Lines, LineBreakdown{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			SKU:         item.Product.SKU,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			Gross:       gross,
+			Adjustment:  adjustment,
+			Total:       gross + adjustment,
+		})
+
+		breakdown.ItemCount += item.Quantity
+		breakdown.Subtotal += gross + adjustment
 	}
-	return total
+
+	breakdown.Total = breakdown.Subtotal - breakdown.Discount + breakdown.Tax
+
+	return breakdown
+}
+
+func (c *Cart) GetTotal() float64 {
+	return c.Breakdown(PricingInputs{}).Subtotal
 }
 
 func (c *Cart) GetItemCount() int {
-	count := 0
-	for _, item := range c.Items {
-		count += item.Quantity
-	}
-	return count
+	return c.Breakdown(PricingInputs{}).ItemCount
 }
 
 func (c *Cart) AddItem(product Product, quantity int) {
@@ -118,11 +176,48 @@ type Transaction struct {
 	Amount         float64                `json:"amount"`
 	Status         TransactionStatus      `json:"status"`
 	PaymentMethod  string                 `json:"payment_method"`
+	Strategy       string                 `json:"strategy,omitempty"`
 	PaymentDetails map[string]interface{} `json:"payment_details"`
 	Metadata       map[string]interface{} `json:"metadata"`
 	ErrorMessage   string                 `json:"error_message,omitempty"`
 	ProcessedAt    time.Time              `json:"processed_at"`
 	CreatedAt      time.Time              `json:"created_at"`
+
+	// Set when the charge was converted; the rate is frozen at checkout so
+	// the display amount can always be reconciled with the base amount.
+	ExchangeRate    float64 `json:"exchange_rate,omitempty"`
+	BaseAmount      float64 `json:"base_amount,omitempty"`
+	BaseCurrency    string  `json:"base_currency,omitempty"`
+	DisplayAmount   float64 `json:"display_amount,omitempty"`
+	DisplayCurrency string  `json:"display_currency,omitempty"`
+}
+
+func (t *Transaction) IsConverted() bool {
+	return t.ExchangeRate > 0
+}
+
+// LockExchangeRate records a display-currency amount together with its value
+// in the base currency at rate.
+func (t *Transaction) LockExchangeRate(rate, displayAmount float64, displayCurrency, baseCurrency string) {
+	t.ExchangeRate = rate
+	t.DisplayAmount = displayAmount
+	t.DisplayCurrency = displayCurrency
+	t.BaseAmount = money.Round(displayAmount * rate)
+	t.BaseCurrency = baseCurrency
+}
+
+func (t *Transaction) IdempotencyKey() string {
+	key, _ := t.Metadata["idempotency_key"].(string)
+	return key
+}
+
+// Abandon fails a checkout that never finished and frees its idempotency key
+// for a retry, keeping the key under abandoned_idempotency_key.
+func (t *Transaction) Abandon() {
+	t.Status = TransactionStatusFailed
+	t.ErrorMessage = "checkout abandoned before completing"
+	t.Metadata["abandoned_idempotency_key"] = t.IdempotencyKey()
+	delete(t.Metadata, "idempotency_key")
 }
 
 type TransactionStatus string
@@ -130,30 +225,57 @@ type TransactionStatus string
 const (
 	TransactionStatusPending    TransactionStatus = "pending"
 	TransactionStatusProcessing TransactionStatus = "processing"
+	TransactionStatusAuthorized TransactionStatus = "authorized"
+	TransactionStatusHeld       TransactionStatus = "held"
 	TransactionStatusCompleted  TransactionStatus = "completed"
 	TransactionStatusFailed     TransactionStatus = "failed"
 	TransactionStatusRefunded   TransactionStatus = "refunded"
 )
 
+// Escrow states, recorded in a transaction's PaymentDetails["escrow_status"].
+const (
+	EscrowStatusHeld     = "held"
+	EscrowStatusReleased = "released"
+	EscrowStatusRefunded = "refunded"
+)
+
 type Receipt struct {
 	ID                string                 `json:"id"`
 	TransactionID     string                 `json:"transaction_id"`
+	OrderID           string                 `json:"order_id,omitempty"`
 	CustomerID        string                 `json:"customer_id"`
 	CustomerName      string                 `json:"customer_name"`
 	CustomerEmail     string                 `json:"customer_email"`
+	Locale            string                 `json:"locale,omitempty"`
 	Items             []ReceiptItem          `json:"items"`
 	Subtotal          float64                `json:"subtotal"`
 	Discount          float64                `json:"discount"`
 	Tax               float64                `json:"tax"`
+	TaxLines          []TaxLine              `json:"tax_lines,omitempty"`
+	ServiceFee        float64                `json:"service_fee"`
 	Cashback          float64                `json:"cashback"`
+	CashbackRedeemed  float64                `json:"cashback_redeemed,omitempty"`
 	LoyaltyPoints     int                    `json:"loyalty_points_earned"`
 	Total             float64                `json:"total"`
+	Currency          string                 `json:"currency,omitempty"`
+	ExchangeRate      float64                `json:"exchange_rate,omitempty"`
+	BaseAmount        float64                `json:"base_amount,omitempty"`
+	BaseCurrency      string                 `json:"base_currency,omitempty"`
 	PaymentMethod     string                 `json:"payment_method"`
+	Strategy          string                 `json:"strategy"`
 	PaymentDetails    map[string]interface{} `json:"payment_details"`
 	AppliedDecorators []string               `json:"applied_decorators"`
 	CreatedAt         time.Time              `json:"created_at"`
 }
 
+// TaxLine is one jurisdiction's part of the tax charged, e.g. a state tax and
+// a city tax levied on the same order.
+type TaxLine struct {
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Amount float64 `json:"amount"`
+}
+
 type ReceiptItem struct {
 	ProductID   string  `json:"product_id"`
 	ProductName string  `json:"product_name"`
@@ -183,6 +305,11 @@ const (
 	DiscountTypeFixed      DiscountType = "fixed"
 )
 
+// NormalizeDiscountCode makes code lookups case-insensitive.
+func NormalizeDiscountCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
 func (d *Discount) IsValid() bool {
 	if !d.IsActive {
 		return false
@@ -219,10 +346,59 @@ func (d *Discount) Calculate(amount float64) float64 {
 type CheckoutOptions struct {
 	PaymentMethod     string                 `json:"payment_method"`
 	PaymentStrategy   string                 `json:"payment_strategy"`
+	SplitParts        int                    `json:"split_parts,omitempty"`
 	EnabledDecorators []string               `json:"enabled_decorators"`
 	DiscountCode      string                 `json:"discount_code,omitempty"`
+	DiscountCodes     []string               `json:"discount_codes,omitempty"`
 	UseLoyaltyPoints  int                    `json:"use_loyalty_points,omitempty"`
+	UseCashback       float64                `json:"use_cashback,omitempty"`
+	Currency          string                 `json:"currency,omitempty"`
+	IdempotencyKey    string                 `json:"idempotency_key,omitempty"`
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	// PaymentDetails are what the customer entered for PaymentMethod.
+	PaymentDetails PaymentDetails `json:"payment_details"`
+	// SavedMethodID charges a vault method; PaymentMethod may then be empty.
+	SavedMethodID string `json:"saved_method_id,omitempty"`
+	// AcceptPriceChanges lets an auto-repriced checkout go ahead at higher
+	// catalog prices.
+	AcceptPriceChanges bool `json:"accept_price_changes,omitempty"`
+	// BillingInterval is weekly, monthly or yearly; empty means monthly.
+	BillingInterval string `json:"billing_interval,omitempty"`
+}
+
+// PaymentDetails carry the card, account or wallet a customer pays with.
+// Only the fields of the chosen payment method are used.
+type PaymentDetails struct {
+	CardNumber string `json:"card_number,omitempty"`
+	CardHolder string `json:"card_holder,omitempty"`
+	ExpiryDate string `json:"expiry_date,omitempty"`
+	CVV        string `json:"cvv,omitempty"`
+
+	PayPalEmail    string `json:"paypal_email,omitempty"`
+	PayPalPassword string `json:"paypal_password,omitempty"`
+
+	WalletAddress string `json:"wallet_address,omitempty"`
+	CryptoType    string `json:"crypto_type,omitempty"`
+
+	WalletToken    string `json:"wallet_token,omitempty"`
+	WalletProvider string `json:"wallet_provider,omitempty"`
+
+	GiftCardCode    string  `json:"gift_card_code,omitempty"`
+	GiftCardBalance float64 `json:"gift_card_balance,omitempty"`
+}
+
+func (o CheckoutOptions) AllDiscountCodes() []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, code := range append([]string{o.DiscountCode}, o.DiscountCodes...) {
+		code = NormalizeDiscountCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
 }
 
 func NewID() string {
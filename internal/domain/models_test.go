@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCartBreakdown(t *testing.T) {
+	cart := &Cart{ID: "cart-1", CustomerID: "cust-1"}
+	cart.AddItem(Product{ID: "prod-laptop", Name: "Laptop", SKU: "LAP-001", Price: 999.99}, 1)
+	cart.AddItem(Product{ID: "prod-mouse", Name: "Mouse", SKU: "MOU-001", Price: 29.99}, 2)
+
+	t.Run("Lines And Subtotal Without Pricing", func(t *testing.T) {
+		breakdown := cart.Breakdown(PricingInputs{})
+
+		require.Len(t, breakdown.Lines, 2)
+		assert.Equal(t, LineBreakdown{
+			ProductID:   "prod-mouse",
+			ProductName: "Mouse",
+			SKU:         "MOU-001",
+			Quantity:    2,
+			UnitPrice:   29.99,
+			Gross:       59.98,
+			Total:       59.98,
+		}, breakdown.Lines[1])
+		assert.Equal(t, 3, breakdown.ItemCount)
+		assert.InDelta(t, 1059.97, breakdown.Subtotal, 1e-9)
+		assert.InDelta(t, 1059.97, breakdown.Total, 1e-9)
+	})
+
+	t.Run("Applies Line Adjustments, Discount And Tax", func(t *testing.T) {
+		breakdown := cart.Breakdown(PricingInputs{
+			LineAdjustments: map[string]float64{"prod-laptop": -99.99},
+			Discount:        10,
+			Tax:             72.00,
+		})
+
+		assert.InDelta(t, -99.99, breakdown.Lines[0].Adjustment, 1e-9)
+		assert.InDelta(t, 900.00, breakdown.Lines[0].Total, 1e-9)
+		assert.InDelta(t, 959.98, breakdown.Subtotal, 1e-9)
+		assert.InDelta(t, 959.98-10+72.00, breakdown.Total, 1e-9)
+	})
+
+	t.Run("Totals Agree With GetTotal And GetItemCount", func(t *testing.T) {
+		breakdown := cart.Breakdown(PricingInputs{})
+		assert.Equal(t, breakdown.Subtotal, cart.GetTotal())
+		assert.Equal(t, breakdown.ItemCount, cart.GetItemCount())
+	})
+
+	t.Run("Empty Cart", func(t *testing.T) {
+		breakdown := (&Cart{}).Breakdown(PricingInputs{Tax: 5})
+		assert.Empty(t, breakdown.Lines)
+		assert.Equal(t, 0, breakdown.ItemCount)
+		assert.Equal(t, 5.0, breakdown.Total)
+	})
+}
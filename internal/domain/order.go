@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusCreated   OrderStatus = "created"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusCompleted OrderStatus = "completed"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusCreated:   {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusFulfilled: {OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusCompleted: {OrderStatusRefunded},
+}
+
+type Order struct {
+	ID            string        `json:"id"`
+	CustomerID    string        `json:"customer_id"`
+	TransactionID string        `json:"transaction_id"`
+	CartID        string        `json:"cart_id"`
+	Status        OrderStatus   `json:"status"`
+	Items         []CartItem    `json:"items"`
+	Subtotal      float64       `json:"subtotal"`
+	Total         float64       `json:"total"`
+	Fulfillments  []Fulfillment `json:"fulfillments"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type Fulfillment struct {
+	ID             string    `json:"id"`
+	Carrier        string    `json:"carrier"`
+	TrackingNumber string    `json:"tracking_number"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func NewOrder(cart *Cart, transactionID string) *Order {
+	items := make([]CartItem, len(cart.Items))
+	copy(items, cart.Items)
+
+	now := time.Now()
+	return &Order{
+		ID:            NewID(),
+		CustomerID:    cart.CustomerID,
+		TransactionID: transactionID,
+		CartID:        cart.ID,
+		Status:        OrderStatusCreated,
+		Items:         items,
+		Subtotal:      cart.GetTotal(),
+		Fulfillments:  []Fulfillment{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (o *Order) CanTransitionTo(status OrderStatus) bool {
+	for _, allowed := range orderTransitions[o.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
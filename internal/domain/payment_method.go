@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// Details only holds masked fields such as the card brand and last four digits.
+type SavedPaymentMethod struct {
+	ID         string                 `json:"id"`
+	CustomerID string                 `json:"customer_id"`
+	Method     string                 `json:"method"`
+	Token      string                 `json:"token"`
+	Details    map[string]interface{} `json:"details"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
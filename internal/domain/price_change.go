@@ -0,0 +1,61 @@
+package domain
+
+import "github.com/ecommerce/payment-system/pkg/money"
+
+// PriceChange is a cart line whose snapshot price no longer matches the
+// catalog.
+type PriceChange struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	OldPrice    float64 `json:"old_price"`
+	NewPrice    float64 `json:"new_price"`
+	// Delta is the change in unit price, LineDelta the change in line total.
+	Delta     float64 `json:"delta"`
+	LineDelta float64 `json:"line_delta"`
+}
+
+func (p PriceChange) Increased() bool {
+	return p.Delta > 0
+}
+
+func PricesIncreased(changes []PriceChange) bool {
+	for _, change := range changes {
+		if change.Increased() {
+			return true
+		}
+	}
+	return false
+}
+
+// Reprice refreshes each line from the current catalog entry and returns the
+// lines whose price changed. Lines without a catalog entry are left alone.
+func (c *Cart) Reprice(products map[string]*Product) []PriceChange {
+	changes := []PriceChange{}
+
+	for i, item := range c.Items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			continue
+		}
+		c.Items[i].Product = *product
+
+		if money.Equal(product.Price, item.Price) {
+			continue
+		}
+
+		delta := product.Price - item.Price
+		changes = append(changes, PriceChange{
+			ProductID:   item.ProductID,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			OldPrice:    item.Price,
+			NewPrice:    product.Price,
+			Delta:       money.Round(delta),
+			LineDelta:   money.Round(delta * float64(item.Quantity)),
+		})
+		c.Items[i].Price = product.Price
+	}
+
+	return changes
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/money"
+)
+
+// Canonical returns a copy of the receipt suitable for snapshot comparison:
+// items are sorted by SKU, amounts are rounded to cents, and identifiers,
+// timestamps and processor-specific payment details are cleared.
+func (r *Receipt) Canonical() *Receipt {
+	canonical := *r
+	canonical.ID = ""
+	canonical.TransactionID = ""
+	canonical.OrderID = ""
+	canonical.PaymentDetails = nil
+	canonical.CreatedAt = time.Time{}
+
+	for _, amount := range []*float64{
+		&canonical.Subtotal, &canonical.Discount, &canonical.Tax, &canonical.ServiceFee,
+		&canonical.Cashback, &canonical.CashbackRedeemed, &canonical.Total,
+	} {
+		*amount = money.Round(*amount)
+	}
+
+	if r.TaxLines != nil {
+		canonical.TaxLines = make([]TaxLine, len(r.TaxLines))
+		for i, line := range r.TaxLines {
+			line.Amount = money.Round(line.Amount)
+			canonical.TaxLines[i] = line
+		}
+	}
+
+	canonical.Items = make([]ReceiptItem, len(r.Items))
+	for i, item := range r.Items {
+		item.UnitPrice = money.Round(item.UnitPrice)
+		item.Total = money.Round(item.Total)
+		canonical.Items[i] = item
+	}
+	sort.SliceStable(canonical.Items, func(i, j int) bool {
+		if canonical.Items[i].SKU != canonical.Items[j].SKU {
+			return canonical.Items[i].SKU < canonical.Items[j].SKU
+		}
+		return canonical.Items[i].ProductID < canonical.Items[j].ProductID
+	})
+
+	canonical.AppliedDecorators = append([]string(nil), r.AppliedDecorators...)
+
+	return &canonical
+}
+
+// ReceiptHash is a stable SHA-256 of the receipt's financial content. Two
+// receipts with the same hash charged the same customer the same amounts for
+// the same items, regardless of IDs, timestamps or item order.
+func (r *Receipt) ReceiptHash() string {
+	canonical := r.Canonical()
+
+	type hashItem struct {
+		ProductID string `json:"product_id"`
+		SKU       string `json:"sku"`
+		Quantity  int    `json:"quantity"`
+		UnitPrice string `json:"unit_price"`
+		Total     string `json:"total"`
+	}
+
+	items := make([]hashItem, 0, len(canonical.Items))
+	for _, item := range canonical.Items {
+		items = append(items, hashItem{
+			ProductID: item.ProductID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: cents(item.UnitPrice),
+			Total:     cents(item.Total),
+		})
+	}
+
+	content := struct {
+		CustomerID        string     `json:"customer_id"`
+		Items             []hashItem `json:"items"`
+		Subtotal          string     `json:"subtotal"`
+		Discount          string     `json:"discount"`
+		Tax               string     `json:"tax"`
+		ServiceFee        string     `json:"service_fee"`
+		Cashback          string     `json:"cashback"`
+		CashbackRedeemed  string     `json:"cashback_redeemed"`
+		LoyaltyPoints     int        `json:"loyalty_points_earned"`
+		Total             string     `json:"total"`
+		PaymentMethod     string     `json:"payment_method"`
+		Strategy          string     `json:"strategy"`
+		AppliedDecorators []string   `json:"applied_decorators"`
+	}{
+		CustomerID:        canonical.CustomerID,
+		Items:             items,
+		Subtotal:          cents(canonical.Subtotal),
+		Discount:          cents(canonical.Discount),
+		Tax:               cents(canonical.Tax),
+		ServiceFee:        cents(canonical.ServiceFee),
+		Cashback:          cents(canonical.Cashback),
+		CashbackRedeemed:  cents(canonical.CashbackRedeemed),
+		LoyaltyPoints:     canonical.LoyaltyPoints,
+		Total:             cents(canonical.Total),
+		PaymentMethod:     canonical.PaymentMethod,
+		Strategy:          canonical.Strategy,
+		AppliedDecorators: canonical.AppliedDecorators,
+	}
+
+	data, _ := json.Marshal(content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cents formats amounts at two decimals so float noise does not change the hash.
+func cents(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "strings"
+
+type RefundReason string
+
+const (
+	RefundReasonDefective       RefundReason = "defective"
+	RefundReasonCustomerRemorse RefundReason = "customer-remorse"
+	RefundReasonFraud           RefundReason = "fraud"
+	RefundReasonDuplicate       RefundReason = "duplicate"
+)
+
+// RefundReasons lists the reasons a refund can be recorded with.
+func RefundReasons() []RefundReason {
+	return []RefundReason{
+		RefundReasonDefective,
+		RefundReasonCustomerRemorse,
+		RefundReasonFraud,
+		RefundReasonDuplicate,
+	}
+}
+
+func (r RefundReason) IsValid() bool {
+	for _, reason := range RefundReasons() {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRefundReason normalizes user input; ok is false for unknown reasons.
+func ParseRefundReason(s string) (RefundReason, bool) {
+	reason := RefundReason(strings.ToLower(strings.TrimSpace(s)))
+	return reason, reason.IsValid()
+}
+
+// RefundOf returns the original transaction ID when t is a refund.
+func (t *Transaction) RefundOf() string {
+	id, _ := t.Metadata["refund_of"].(string)
+	return id
+}
+
+func (t *Transaction) RefundReason() RefundReason {
+	reason, _ := t.Metadata["refund_reason"].(string)
+	return RefundReason(reason)
+}
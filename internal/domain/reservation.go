@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+type Reservation struct {
+	ID            string    `json:"id"`
+	ProductID     string    `json:"product_id"`
+	Quantity      int       `json:"quantity"`
+	CartID        string    `json:"cart_id"`
+	TransactionID string    `json:"transaction_id"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (r *Reservation) IsExpired(at time.Time) bool {
+	return !at.Before(r.ExpiresAt)
+}
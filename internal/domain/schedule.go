@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/money"
+)
+
+type InstallmentStatus string
+
+const (
+	InstallmentStatusPending InstallmentStatus = "pending"
+	InstallmentStatusPaid    InstallmentStatus = "paid"
+	InstallmentStatusFailed  InstallmentStatus = "failed"
+)
+
+// PaymentSchedule is the installment plan of a deferred payment. The first
+// installment is charged at checkout; the rest are charged later by ID.
+type PaymentSchedule struct {
+	ID            string                 `json:"id"`
+	TransactionID string                 `json:"transaction_id"`
+	CustomerID    string                 `json:"customer_id"`
+	PaymentMethod string                 `json:"payment_method"`
+	SavedMethodID string                 `json:"saved_method_id,omitempty"`
+	TotalAmount   float64                `json:"total_amount"`
+	Installments  int                    `json:"installments"`
+	InterestRate  float64                `json:"interest_rate"`
+	Payments      []ScheduledInstallment `json:"payments"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+type ScheduledInstallment struct {
+	InstallmentNumber int               `json:"installment_number"`
+	Amount            float64           `json:"amount"`
+	DueDate           time.Time         `json:"due_date"`
+	Status            InstallmentStatus `json:"status"`
+	TransactionID     string            `json:"transaction_id,omitempty"`
+	PaidAt            *time.Time        `json:"paid_at,omitempty"`
+}
+
+// NextInstallment returns the first installment that has not been paid, or
+// nil when the schedule is complete. Failed installments are retried.
+func (s *PaymentSchedule) NextInstallment() *ScheduledInstallment {
+	for i := range s.Payments {
+		if s.Payments[i].Status != InstallmentStatusPaid {
+			return &s.Payments[i]
+		}
+	}
+	return nil
+}
+
+func (s *PaymentSchedule) PaidCount() int {
+	paid := 0
+	for _, p := range s.Payments {
+		if p.Status == InstallmentStatusPaid {
+			paid++
+		}
+	}
+	return paid
+}
+
+func (s *PaymentSchedule) RemainingAmount() float64 {
+	remaining := 0.0
+	for _, p := range s.Payments {
+		if p.Status != InstallmentStatusPaid {
+			remaining += p.Amount
+		}
+	}
+	return money.Round(remaining)
+}
+
+// TotalWithInterest is the sum of all installments, i.e. what the customer
+// pays over the life of the plan.
+func (s *PaymentSchedule) TotalWithInterest() float64 {
+	total := 0.0
+	for _, p := range s.Payments {
+		total += p.Amount
+	}
+	return money.Round(total)
+}
+
+func (s *PaymentSchedule) IsComplete() bool {
+	return s.NextInstallment() == nil
+}
+
+func (i *ScheduledInstallment) MarkPaid(transactionID string, at time.Time) {
+	i.Status = InstallmentStatusPaid
+	i.TransactionID = transactionID
+	i.PaidAt = &at
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BillingInterval string
+
+const (
+	BillingIntervalWeekly  BillingInterval = "weekly"
+	BillingIntervalMonthly BillingInterval = "monthly"
+	BillingIntervalYearly  BillingInterval = "yearly"
+)
+
+func ParseBillingInterval(value string) (BillingInterval, error) {
+	switch interval := BillingInterval(strings.ToLower(strings.TrimSpace(value))); interval {
+	case "":
+		return BillingIntervalMonthly, nil
+	case BillingIntervalWeekly, BillingIntervalMonthly, BillingIntervalYearly:
+		return interval, nil
+	default:
+		return "", fmt.Errorf("unsupported billing interval %q (expected weekly, monthly or yearly)", value)
+	}
+}
+
+func (i BillingInterval) Next(from time.Time) time.Time {
+	switch i {
+	case BillingIntervalWeekly:
+		return from.AddDate(0, 0, 7)
+	case BillingIntervalYearly:
+		return from.AddDate(1, 0, 0)
+	default:
+		return from.AddDate(0, 1, 0)
+	}
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	SubscriptionStatusPastDue  SubscriptionStatus = "past_due"
+	SubscriptionStatusCanceled SubscriptionStatus = "canceled"
+)
+
+type Subscription struct {
+	ID                string             `json:"id"`
+	TransactionID     string             `json:"transaction_id"`
+	CustomerID        string             `json:"customer_id"`
+	PaymentMethod     string             `json:"payment_method"`
+	SavedMethodID     string             `json:"saved_method_id,omitempty"`
+	Amount            float64            `json:"amount"`
+	Interval          BillingInterval    `json:"interval"`
+	Status            SubscriptionStatus `json:"status"`
+	NextBillingDate   time.Time          `json:"next_billing_date"`
+	ChargeCount       int                `json:"charge_count"`
+	FailedAttempts    int                `json:"failed_attempts,omitempty"`
+	LastTransactionID string             `json:"last_transaction_id,omitempty"`
+	LastChargedAt     *time.Time         `json:"last_charged_at,omitempty"`
+	CanceledAt        *time.Time         `json:"canceled_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// Past-due subscriptions stay due so failed charges are retried.
+func (s *Subscription) IsDue(now time.Time) bool {
+	return s.Status != SubscriptionStatusCanceled && !now.Before(s.NextBillingDate)
+}
+
+// MarkCharged skips missed periods, so a late run charges only once.
+func (s *Subscription) MarkCharged(transactionID string, now time.Time) {
+	s.Status = SubscriptionStatusActive
+	s.ChargeCount++
+	s.FailedAttempts = 0
+	s.LastTransactionID = transactionID
+	s.LastChargedAt = &now
+	if s.NextBillingDate.IsZero() {
+		s.NextBillingDate = now
+	}
+	for !s.NextBillingDate.After(now) {
+		s.NextBillingDate = s.Interval.Next(s.NextBillingDate)
+	}
+	s.UpdatedAt = now
+}
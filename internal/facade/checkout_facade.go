@@ -2,22 +2,34 @@ package facade
 
 import (
 	"context"
+	"crypto/rand"
+	stderrors "errors"
 	"fmt"
+	"math"
+	mathrand "math/rand"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/internal/decorator"
 	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/factory"
 	"github.com/ecommerce/payment-system/internal/observer"
 	"github.com/ecommerce/payment-system/internal/payment"
-	"github.com/ecommerce/payment-system/internal/repository"
 	"github.com/ecommerce/payment-system/internal/service"
 	"github.com/ecommerce/payment-system/internal/strategy"
 	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
+// defaultNotificationTimeout applies when notifications.timeout is unset.
+const defaultNotificationTimeout = 30 * time.Second
+
 type CheckoutFacade struct {
 	config             *config.Config
 	paymentFactory     *factory.PaymentFactory
@@ -26,24 +38,135 @@ type CheckoutFacade struct {
 	inventoryService   *service.InventoryService
 	customerService    *service.CustomerService
 	transactionService *service.TransactionService
+	loyaltyService     *service.LoyaltyService
+	orderService       *service.OrderService
+	scheduleService    *service.ScheduleService
+	billingService     *service.BillingService
+	paymentLimits      map[string]payment.AmountLimits
+	strategyLimits     map[string]payment.AmountLimits
+	converter          *currency.Converter
+	restrictions       *service.RestrictionPolicy
+	quoteSecret        []byte
+	now                func() time.Time
+	newID              func() string
+	sleep              func(ctx context.Context, d time.Duration) error
 	eventSubject       *observer.Subject
+
+	// settleMu serializes refunds and captures, which read, adjust and write
+	// back the original transaction.
+	settleMu sync.Mutex
+	// notifications tracks deliveries still running so Close can wait.
+	notifications sync.WaitGroup
 }
 
 func NewCheckoutFacade(
 	cfg *config.Config,
-	repo repository.Repository,
+	inventoryService *service.InventoryService,
+	customerService *service.CustomerService,
+	transactionService *service.TransactionService,
+	loyaltyService *service.LoyaltyService,
+	orderService *service.OrderService,
+	scheduleService *service.ScheduleService,
+	billingService *service.BillingService,
+	discountService *service.DiscountService,
+	restrictions *service.RestrictionPolicy,
+	converter *currency.Converter,
 	eventSubject *observer.Subject,
-) *CheckoutFacade {
+) (*CheckoutFacade, error) {
+	quoteSecret, err := newQuoteSecret(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &CheckoutFacade{
 		config:             cfg,
 		paymentFactory:     factory.NewPaymentFactory(),
-		decoratorFactory:   factory.NewDecoratorFactory(cfg),
-		strategyFactory:    factory.NewStrategyFactory(),
-		inventoryService:   service.NewInventoryService(repo),
-		customerService:    service.NewCustomerService(repo),
-		transactionService: service.NewTransactionService(repo),
+		decoratorFactory:   factory.NewDecoratorFactory(cfg, discountService),
+		strategyFactory:    factory.NewStrategyFactory().WithSplitTolerance(cfg.Payment.SplitTolerance),
+		inventoryService:   inventoryService,
+		customerService:    customerService,
+		transactionService: transactionService,
+		loyaltyService:     loyaltyService,
+		orderService:       orderService,
+		scheduleService:    scheduleService,
+		billingService:     billingService,
+		paymentLimits:      toDefaultCurrency(cfg, converter, methodLimits(cfg)),
+		strategyLimits:     toDefaultCurrency(cfg, converter, strategyLimits(cfg)),
+		converter:          converter,
+		restrictions:       restrictions,
+		quoteSecret:        quoteSecret,
+		now:                time.Now,
+		newID:              domain.NewID,
+		sleep:              sleepContext,
 		eventSubject:       eventSubject,
+	}, nil
+}
+
+func newQuoteSecret(cfg *config.Config) ([]byte, error) {
+	if cfg.Checkout.QuoteSecret != "" {
+		return []byte(cfg.Checkout.QuoteSecret), nil
+	}
+
+	logger.Warn("checkout.quote_secret is not set; quote tokens are only valid for this process")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate quote secret: %w", err)
+	}
+	return secret, nil
+}
+
+// toDefaultCurrency converts configured limits into currency.DefaultCurrency,
+// the currency payments and strategies check amounts in. Limits that cannot
+// be converted are dropped, leaving the built-in ones in place.
+func toDefaultCurrency(cfg *config.Config, converter *currency.Converter, limits map[string]payment.AmountLimits) map[string]payment.AmountLimits {
+	from := cfg.Payment.LimitCurrency
+	if from == "" || from == currency.DefaultCurrency {
+		return limits
+	}
+
+	rate, err := converter.Rate(from, currency.DefaultCurrency)
+	if err != nil {
+		logger.Warn("Configured payment limits not applied",
+			zap.String("limit_currency", from),
+			zap.Error(err),
+		)
+		return map[string]payment.AmountLimits{}
+	}
+
+	for key, l := range limits {
+		limits[key] = payment.AmountLimits{Min: l.Min * rate, Max: l.Max * rate}
+	}
+	return limits
+}
+
+// methodLimits returns the configured limits, in payment.limit_currency, of
+// the methods that set a maximum.
+func methodLimits(cfg *config.Config) map[string]payment.AmountLimits {
+	limits := map[string]payment.AmountLimits{}
+
+	if cfg.Payment.CreditCard.MaxAmount > 0 {
+		limits["credit_card"] = payment.AmountLimits{Min: cfg.Payment.CreditCard.MinAmount, Max: cfg.Payment.CreditCard.MaxAmount}
+	}
+	if cfg.Payment.PayPal.MaxAmount > 0 {
+		limits["paypal"] = payment.AmountLimits{Min: cfg.Payment.PayPal.MinAmount, Max: cfg.Payment.PayPal.MaxAmount}
+	}
+	if cfg.Payment.Crypto.MaxAmount > 0 {
+		limits["crypto"] = payment.AmountLimits{Min: cfg.Payment.Crypto.MinAmount, Max: cfg.Payment.Crypto.MaxAmount}
+	}
+
+	return limits
+}
+
+// strategyLimits returns the configured limits, in payment.limit_currency, of
+// strategies with bounds of their own.
+func strategyLimits(cfg *config.Config) map[string]payment.AmountLimits {
+	limits := map[string]payment.AmountLimits{}
+
+	if cfg.Payment.Escrow.MaxAmount > 0 {
+		limits["escrow"] = payment.AmountLimits{Min: cfg.Payment.Escrow.MinAmount, Max: cfg.Payment.Escrow.MaxAmount}
 	}
+
+	return limits
 }
 
 func (f *CheckoutFacade) ProcessOrder(
@@ -58,90 +181,1021 @@ func (f *CheckoutFacade) ProcessOrder(
 		zap.Float64("amount", cart.GetTotal()),
 	)
 
+	if timeout := f.config.Checkout.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := f.decoratorFactory.ValidateDecorators(options.EnabledDecorators); err != nil {
+		return nil, err
+	}
+
+	savedMethod, err := f.resolveSavedMethod(ctx, customer, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	currencyCode, err := f.chargeCurrency(options)
+	if err != nil {
+		return nil, err
+	}
+	options.Currency = currencyCode
+	ctx = payment.WithCurrency(ctx, currencyCode, f.converter)
+
+	if err := f.repriceCart(ctx, cart, options); err != nil {
+		return nil, err
+	}
+
 	transaction := &domain.Transaction{
-		ID:             domain.NewID(),
+		ID:             f.newID(),
 		CustomerID:     customer.ID,
 		Amount:         cart.GetTotal(),
 		Status:         domain.TransactionStatusPending,
 		PaymentMethod:  options.PaymentMethod,
 		PaymentDetails: make(map[string]interface{}),
 		Metadata:       options.Metadata,
-		CreatedAt:      time.Now(),
+		CreatedAt:      f.now(),
+	}
+
+	if options.IdempotencyKey != "" {
+		receipt, err := f.replayCheckout(ctx, options.IdempotencyKey)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+
+		if transaction.Metadata == nil {
+			transaction.Metadata = make(map[string]interface{})
+		}
+		transaction.Metadata["idempotency_key"] = options.IdempotencyKey
+
+		if err := f.transactionService.CreateTransaction(ctx, transaction); err != nil {
+			if errors.IsErrorCode(err, errors.ErrCodeAlreadyExists) {
+				return nil, errors.New(errors.ErrCodeAlreadyExists, "checkout with this idempotency key is already in progress")
+			}
+			return nil, err
+		}
 	}
 
 	f.notifyEvent(ctx, observer.Event{
 		Type:          observer.EventPaymentStarted,
 		TransactionID: transaction.ID,
 		CustomerID:    customer.ID,
+		CustomerEmail: customer.Email,
+		CustomerName:  customer.Name,
+		Locale:        customer.Locale,
+		CartID:        cart.ID,
 		Amount:        cart.GetTotal(),
 		PaymentMethod: options.PaymentMethod,
-		Timestamp:     time.Now().Format(time.RFC3339),
+		Order:         observer.NewOrderSnapshot(cart, customer, options.Metadata),
+		Timestamp:     f.now().Format(time.RFC3339),
 	})
 
+	if err := f.restrictions.CheckCart(customer, cart); err != nil {
+		return nil, f.handleError(ctx, transaction, err, "product restriction check failed")
+	}
+
+	if err := f.checkSpendingLimit(ctx, customer, cart.GetTotal()); err != nil {
+		return nil, f.handleError(ctx, transaction, err, "spending limit check failed")
+	}
+
 	if err := f.validateInventory(ctx, cart); err != nil {
 		return nil, f.handleError(ctx, transaction, err, "inventory validation failed")
 	}
 
-	if err := f.reserveInventory(ctx, cart); err != nil {
-		return nil, f.handleError(ctx, transaction, err, "inventory reservation failed")
+	if err := f.reserveInventory(ctx, transaction.ID, cart); err != nil {
+		return nil, f.handleError(ctx, transaction, err, "inventory reservation failed")
+	}
+
+	var loyaltyHold *service.LoyaltyHold
+	if f.redeemsLoyaltyPoints(options) {
+		hold, err := f.loyaltyService.Hold(ctx, customer.ID, options.UseLoyaltyPoints)
+		if err != nil {
+			f.rollbackInventory(ctx, transaction.ID, cart)
+			return nil, f.handleError(ctx, transaction, err, "loyalty points reservation failed")
+		}
+		loyaltyHold = hold
+	}
+
+	if options.UseCashback > 0 {
+		if err := f.customerService.DebitCashback(ctx, customer.ID, transaction.ID, options.UseCashback); err != nil {
+			f.releaseLoyaltyHold(loyaltyHold)
+			f.rollbackInventory(ctx, transaction.ID, cart)
+			return nil, f.handleError(ctx, transaction, err, "cashback reservation failed")
+		}
+		options.EnabledDecorators = withCashbackRedemption(options.EnabledDecorators)
+	}
+
+	order, err := f.orderService.CreateOrder(ctx, cart, transaction.ID)
+	if err != nil {
+		f.restoreCashback(ctx, customer.ID, transaction.ID, options.UseCashback)
+		f.releaseLoyaltyHold(loyaltyHold)
+		f.rollbackInventory(ctx, transaction.ID, cart)
+		return nil, f.handleError(ctx, transaction, err, "order creation failed")
+	}
+
+	abort := func(err error, message string) error {
+		f.cancelOrder(ctx, order)
+		f.restoreCashback(ctx, customer.ID, transaction.ID, options.UseCashback)
+		f.releaseLoyaltyHold(loyaltyHold)
+		f.rollbackInventory(ctx, transaction.ID, cart)
+		return f.handleError(ctx, transaction, err, message)
+	}
+
+	// Steps that ignore the context may have run past the deadline; do not
+	// start charging once it has passed.
+	if err := ctx.Err(); err != nil {
+		return nil, abort(err, "checkout deadline exceeded before payment")
+	}
+
+	paymentInstance, err := f.createPayment(options, savedMethod)
+	if err != nil {
+		return nil, abort(err, "payment creation failed")
+	}
+
+	decoratedPayment, err := f.applyDecorators(ctx, paymentInstance, options, customer)
+	if err != nil {
+		return nil, abort(err, "decorator application failed")
+	}
+
+	result, err := f.executePaymentStrategy(ctx, decoratedPayment, paymentRequest(ctx, cart, customer, options), options)
+	if err != nil {
+		return nil, abort(err, "payment processing failed")
+	}
+
+	// The customer has been charged, so recording the order must not be cut
+	// short by time the checkout already spent; it gets a deadline of its own.
+	ctx, cancelRecord := f.recordContext(ctx)
+	defer cancelRecord()
+
+	f.commitInventory(ctx, transaction.ID, cart)
+
+	schedule, _ := result.Metadata[strategy.ScheduleMetadataKey].(*domain.PaymentSchedule)
+	delete(result.Metadata, strategy.ScheduleMetadataKey)
+	subscription, _ := result.Metadata[strategy.SubscriptionMetadataKey].(*domain.Subscription)
+	delete(result.Metadata, strategy.SubscriptionMetadataKey)
+
+	transaction.Status = domain.TransactionStatusCompleted
+	if pending, _ := result.Metadata["capture_pending"].(bool); pending {
+		transaction.Status = domain.TransactionStatusAuthorized
+	}
+	if escrow, _ := result.Metadata["escrow_status"].(string); escrow == domain.EscrowStatusHeld {
+		transaction.Status = domain.TransactionStatusHeld
+	}
+	transaction.Strategy = result.Strategy
+	transaction.ProcessedAt = f.now()
+	transaction.PaymentDetails = result.Metadata
+
+	if transaction.Metadata == nil {
+		transaction.Metadata = make(map[string]interface{})
+	}
+	transaction.Metadata["charged_amount"] = result.Amount
+	if rate, ok := payment.ExchangeRateFromContext(ctx); ok {
+		transaction.LockExchangeRate(rate, result.Amount, options.Currency, currency.DefaultCurrency)
+	}
+
+	if freeOrder, _ := result.Metadata["free_order"].(bool); freeOrder {
+		transaction.Metadata["free_order"] = true
+	}
+
+	if err := f.updateLoyaltyPoints(ctx, customer, transaction, result, loyaltyHold); err != nil {
+		logger.Warn("Failed to update loyalty points",
+			zap.Error(err),
+			zap.String("customer_id", customer.ID),
+		)
+	}
+
+	if options.UseCashback > 0 {
+		redeemed := result.Breakdown.CashbackRedeemed
+		f.restoreCashback(ctx, customer.ID, transaction.ID, money.Round(options.UseCashback-redeemed))
+		if transaction.Metadata == nil {
+			transaction.Metadata = make(map[string]interface{})
+		}
+		transaction.Metadata["cashback_redeemed"] = redeemed
+	}
+
+	if transaction.Status == domain.TransactionStatusAuthorized {
+		// Cashback is paid out on the captured amount at capture.
+		if cashback := money.Round(result.Breakdown.CashbackAmount); cashback > 0 {
+			transaction.Metadata["cashback_pending"] = cashback
+		}
+	} else if err := f.payoutCashback(ctx, customer.ID, result.Breakdown.CashbackAmount, transaction); err != nil {
+		// Saved with the transaction below, so the payout owed is on record.
+		transaction.Metadata["cashback_pending"] = money.Round(result.Breakdown.CashbackAmount)
+		logger.Error("Failed to pay out cashback",
+			zap.Error(err),
+			zap.String("customer_id", customer.ID),
+		)
+	}
+
+	if err := f.orderService.MarkPaid(ctx, order, result.Amount); err != nil {
+		logger.Error("Failed to mark order paid",
+			zap.Error(err),
+			zap.String("order_id", order.ID),
+		)
+	}
+
+	receipt := f.generateReceipt(transaction, cart, customer, result)
+	receipt.OrderID = order.ID
+	receipt.Currency = options.Currency
+	lockReceiptRate(receipt, transaction)
+
+	if transaction.IdempotencyKey() != "" {
+		if err := f.transactionService.UpdateTransaction(ctx, transaction); err != nil {
+			logger.Error("Failed to save transaction",
+				zap.Error(err),
+				zap.String("transaction_id", transaction.ID),
+			)
+		}
+	} else if err := f.transactionService.CreateTransaction(ctx, transaction); err != nil {
+		logger.Error("Failed to save transaction",
+			zap.Error(err),
+			zap.String("transaction_id", transaction.ID),
+		)
+	}
+
+	if err := f.transactionService.CreateReceipt(ctx, receipt); err != nil {
+		logger.Error("Failed to save receipt",
+			zap.Error(err),
+			zap.String("transaction_id", transaction.ID),
+		)
+	}
+
+	if schedule != nil {
+		f.saveSchedule(ctx, schedule, transaction, options.SavedMethodID)
+	}
+	if subscription != nil {
+		f.saveSubscription(ctx, subscription, transaction, options.SavedMethodID)
+	}
+
+	cart.Clear()
+
+	f.notifyEvent(ctx, observer.Event{
+		Type:          observer.EventPaymentSuccess,
+		TransactionID: transaction.ID,
+		CustomerID:    customer.ID,
+		CustomerEmail: customer.Email,
+		CustomerName:  customer.Name,
+		Locale:        customer.Locale,
+		Amount:        result.Amount,
+		PaymentMethod: result.PaymentMethod,
+		Result:        result,
+		Timestamp:     f.now().Format(time.RFC3339),
+	})
+
+	logger.Info("Checkout completed successfully",
+		zap.String("transaction_id", transaction.ID),
+		zap.Float64("amount", result.Amount),
+	)
+
+	return receipt, nil
+}
+
+// replayCheckout returns the stored receipt when a checkout with the same
+// idempotency key already completed, and nil when the key is unused or was
+// held by a checkout pending past checkout.idempotency_ttl.
+func (f *CheckoutFacade) replayCheckout(ctx context.Context, key string) (*domain.Receipt, error) {
+	existing, err := f.transactionService.GetTransactionByIdempotencyKey(ctx, key)
+	if errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch existing.Status {
+	case domain.TransactionStatusPending, domain.TransactionStatusProcessing:
+		return nil, f.abandonStaleCheckout(ctx, existing, key)
+	case domain.TransactionStatusFailed:
+		return nil, errors.NewPaymentError(
+			fmt.Sprintf("checkout with this idempotency key already failed: %s", existing.ErrorMessage),
+		)
+	}
+
+	receipt, err := f.Receipt(ctx, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Returning receipt for repeated checkout",
+		zap.String("transaction_id", existing.ID),
+		zap.String("idempotency_key", key),
+	)
+
+	return receipt, nil
+}
+
+func (f *CheckoutFacade) abandonStaleCheckout(ctx context.Context, existing *domain.Transaction, key string) error {
+	inProgress := errors.New(errors.ErrCodeAlreadyExists, "checkout with this idempotency key is already in progress")
+
+	ttl := f.config.Checkout.IdempotencyTTL
+	if ttl <= 0 {
+		return inProgress
+	}
+	err := f.transactionService.AbandonStaleTransaction(ctx, key, f.now().Add(-ttl))
+	if errors.IsErrorCode(err, errors.ErrCodeConflict) || errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+		return inProgress
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Warn("Abandoned stale checkout holding idempotency key",
+		zap.String("transaction_id", existing.ID),
+		zap.String("idempotency_key", key),
+	)
+	return nil
+}
+
+// Receipt returns the receipt issued for a transaction. Transactions charged
+// before receipts were stored get one rebuilt from the transaction and its
+// order.
+func (f *CheckoutFacade) Receipt(ctx context.Context, transactionID string) (*domain.Receipt, error) {
+	receipt, err := f.transactionService.GetReceiptByTransaction(ctx, transactionID)
+	if !errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+		return receipt, err
+	}
+
+	transaction, err := f.transactionService.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.rebuildReceipt(ctx, transaction)
+}
+
+func (f *CheckoutFacade) rebuildReceipt(ctx context.Context, transaction *domain.Transaction) (*domain.Receipt, error) {
+	switch transaction.Status {
+	case domain.TransactionStatusCompleted, domain.TransactionStatusAuthorized, domain.TransactionStatusHeld, domain.TransactionStatusRefunded:
+	default:
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("transaction %s has no receipt in status %s", transaction.ID, transaction.Status),
+		)
+	}
+	if _, ok := transaction.Metadata["refund_of"]; ok {
+		return nil, errors.NewValidationError(fmt.Sprintf("transaction %s is a refund and has no receipt", transaction.ID))
+	}
+
+	customer, err := f.customerService.GetCustomer(ctx, transaction.CustomerID)
+	if err != nil {
+		customer = &domain.Customer{ID: transaction.CustomerID}
+	}
+
+	order, err := f.orderService.FindByTransaction(ctx, transaction.CustomerID, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := transaction.PaymentDetails
+	total := transaction.Amount
+	if _, ok := transaction.Metadata["charged_amount"]; ok {
+		total = metadataFloat(transaction.Metadata, "charged_amount")
+	}
+	result := &payment.PaymentResult{
+		Amount:        total,
+		PaymentMethod: transaction.PaymentMethod,
+		Strategy:      transaction.Strategy,
+		Metadata:      details,
+		Breakdown: payment.ResultBreakdown{
+			DiscountAmount:      metadataFloat(details, "discount_amount"),
+			TaxAmount:           metadataFloat(details, "tax_amount"),
+			ServiceFeeAmount:    metadataFloat(details, "service_fee_amount"),
+			CashbackAmount:      metadataFloat(details, "cashback_amount"),
+			CashbackRedeemed:    metadataFloat(transaction.Metadata, "cashback_redeemed"),
+			LoyaltyPointsEarned: int(metadataFloat(details, "loyalty_points_earned")),
+		},
+	}
+
+	receipt := f.generateReceipt(transaction, &domain.Cart{Items: order.Items}, customer, result)
+	receipt.OrderID = order.ID
+	receipt.Currency = transaction.DisplayCurrency
+	if receipt.Currency == "" {
+		receipt.Currency = currency.DefaultCurrency
+	}
+	receipt.CreatedAt = transaction.ProcessedAt
+	lockReceiptRate(receipt, transaction)
+
+	return receipt, nil
+}
+
+func lockReceiptRate(receipt *domain.Receipt, transaction *domain.Transaction) {
+	if transaction.IsConverted() {
+		receipt.ExchangeRate = transaction.ExchangeRate
+		receipt.BaseAmount = transaction.BaseAmount
+		receipt.BaseCurrency = transaction.BaseCurrency
+	}
+}
+
+func (f *CheckoutFacade) Quote(
+	ctx context.Context,
+	cart *domain.Cart,
+	customer *domain.Customer,
+	options domain.CheckoutOptions,
+) (*payment.PaymentResult, error) {
+	quoteOptions := options
+	quoteOptions.EnabledDecorators = make([]string, 0, len(options.EnabledDecorators))
+	for _, name := range options.EnabledDecorators {
+		if name != "fraud_detection" {
+			quoteOptions.EnabledDecorators = append(quoteOptions.EnabledDecorators, name)
+		}
+	}
+
+	currencyCode, err := f.chargeCurrency(options)
+	if err != nil {
+		return nil, err
+	}
+	ctx = payment.WithCurrency(ctx, currencyCode, f.converter)
+
+	quotePayment := payment.NewQuotePayment(options.PaymentMethod, currencyCode)
+
+	decorated, err := f.applyDecorators(ctx, quotePayment, quoteOptions, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	return decorated.Process(ctx, paymentRequest(ctx, cart, customer, options))
+}
+
+// chargeCurrency normalizes the order currency, defaulting to
+// currency.DefaultCurrency, and rejects currencies without a rate.
+func (f *CheckoutFacade) chargeCurrency(options domain.CheckoutOptions) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(options.Currency))
+	if code == "" {
+		return currency.DefaultCurrency, nil
+	}
+	if err := f.converter.Validate(code); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+type CheckoutQuote struct {
+	Token     string
+	Total     float64
+	ExpiresAt time.Time
+	Result    *payment.PaymentResult
+}
+
+func (f *CheckoutFacade) PrepareCheckout(
+	ctx context.Context,
+	cart *domain.Cart,
+	customer *domain.Customer,
+	options domain.CheckoutOptions,
+) (*CheckoutQuote, error) {
+	result, err := f.Quote(ctx, cart, customer, options)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := QuoteClaims{
+		CartID:     cart.ID,
+		CustomerID: customer.ID,
+		Lines:      quoteLines(cart),
+		Total:      chargeAmount(result),
+		IssuedAt:   f.now(),
+	}
+
+	token, err := signQuoteToken(f.quoteSecret, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckoutQuote{
+		Token:     token,
+		Total:     claims.Total,
+		ExpiresAt: claims.IssuedAt.Add(f.config.Checkout.QuoteTTL),
+		Result:    result,
+	}, nil
+}
+
+func (f *CheckoutFacade) ConfirmCheckout(
+	ctx context.Context,
+	token string,
+	cart *domain.Cart,
+	customer *domain.Customer,
+	options domain.CheckoutOptions,
+) (*domain.Receipt, error) {
+	if !f.config.Features.Enabled(config.FeatureTwoPhaseCheckout) {
+		return nil, errors.NewFeatureDisabledError(config.FeatureTwoPhaseCheckout)
+	}
+
+	claims, err := parseQuoteToken(f.quoteSecret, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.CustomerID != customer.ID || claims.CartID != cart.ID {
+		return nil, errors.NewUnauthorizedError("quote token does not belong to this cart")
+	}
+
+	if ttl := f.config.Checkout.QuoteTTL; ttl > 0 && f.now().Sub(claims.IssuedAt) > ttl {
+		return nil, errors.New(errors.ErrCodeQuoteExpired, "quote expired, please re-quote").
+			WithDetails("issued_at", claims.IssuedAt)
+	}
+
+	if err := f.checkQuoteDrift(ctx, claims, cart, customer, options); err != nil {
+		return nil, err
+	}
+
+	return f.ProcessOrder(ctx, cart, customer, options)
+}
+
+func (f *CheckoutFacade) checkQuoteDrift(
+	ctx context.Context,
+	claims *QuoteClaims,
+	cart *domain.Cart,
+	customer *domain.Customer,
+	options domain.CheckoutOptions,
+) error {
+	current := quoteLines(cart)
+	if len(current) != len(claims.Lines) {
+		return errors.New(errors.ErrCodeConflict, "cart changed since quote, please re-quote")
+	}
+
+	for i, line := range current {
+		quoted := claims.Lines[i]
+		if line.ProductID != quoted.ProductID || line.Quantity != quoted.Quantity {
+			return errors.New(errors.ErrCodeConflict, "cart changed since quote, please re-quote")
+		}
+
+		product, err := f.inventoryService.GetProduct(ctx, line.ProductID)
+		if err != nil {
+			return err
+		}
+		if !money.Equal(product.Price, quoted.UnitPrice) || !money.Equal(line.UnitPrice, quoted.UnitPrice) {
+			return errors.New(errors.ErrCodeConflict, "quoted prices changed, please re-quote").
+				WithDetails("product_id", line.ProductID).
+				WithDetails("quoted_price", quoted.UnitPrice).
+				WithDetails("current_price", product.Price)
+		}
+	}
+
+	result, err := f.Quote(ctx, cart, customer, options)
+	if err != nil {
+		return err
+	}
+
+	if total := chargeAmount(result); !money.Equal(total, claims.Total) {
+		return errors.New(errors.ErrCodeConflict, "quoted total changed, please re-quote").
+			WithDetails("quoted_total", claims.Total).
+			WithDetails("current_total", total)
+	}
+
+	return nil
+}
+
+func chargeAmount(result *payment.PaymentResult) float64 {
+	if val, ok := result.Metadata["charge_amount"].(float64); ok {
+		return val
+	}
+	return result.Amount
+}
+
+func (f *CheckoutFacade) RefundOrder(ctx context.Context, transactionID string, amount float64, reason domain.RefundReason) (*domain.Transaction, error) {
+	if !reason.IsValid() {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid refund reason: %q", reason)).
+			WithDetails("allowed_reasons", domain.RefundReasons())
+	}
+
+	f.settleMu.Lock()
+	defer f.settleMu.Unlock()
+
+	original, err := f.transactionService.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Status == domain.TransactionStatusRefunded {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("transaction %s is already refunded", transactionID),
+		)
+	}
+	if original.Status != domain.TransactionStatusCompleted {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("transaction %s cannot be refunded in status %s", transactionID, original.Status),
+		)
+	}
+
+	alreadyRefunded := metadataFloat(original.Metadata, "refunded_amount")
+	remaining := refundableAmount(original)
+
+	if amount <= 0 || money.Round(amount) > remaining {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("refund amount must be between 0 and %.2f", remaining),
+		)
+	}
+	amount = money.Round(amount)
+
+	now := f.now()
+	refund := &domain.Transaction{
+		ID:            f.newID(),
+		CustomerID:    original.CustomerID,
+		Amount:        amount,
+		Status:        domain.TransactionStatusRefunded,
+		PaymentMethod: original.PaymentMethod,
+		Strategy:      original.Strategy,
+		Metadata: map[string]interface{}{
+			"refund_of":       original.ID,
+			"refunded_amount": amount,
+			"refund_reason":   string(reason),
+		},
+		ProcessedAt: now,
+		CreatedAt:   now,
+	}
+	if original.IsConverted() {
+		refund.LockExchangeRate(original.ExchangeRate, amount, original.DisplayCurrency, original.BaseCurrency)
+	}
+
+	if err := f.transactionService.CreateTransaction(ctx, refund); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to record refund")
+	}
+
+	if original.Metadata == nil {
+		original.Metadata = make(map[string]interface{})
+	}
+	original.Metadata["refunded_amount"] = money.Round(alreadyRefunded + amount)
+	original.Metadata["refund_transaction_ids"] = append(
+		metadataStrings(original.Metadata, "refund_transaction_ids"), refund.ID,
+	)
+	fullRefund := amount >= remaining
+	if fullRefund {
+		original.Status = domain.TransactionStatusRefunded
+	}
+	clawback, restore := cashbackReversal(original, amount, fullRefund)
+
+	if err := f.transactionService.UpdateTransaction(ctx, original); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to update refunded transaction")
+	}
+
+	if err := f.reverseLoyaltyPoints(ctx, original, refund.ID, amount); err != nil {
+		logger.Warn("Failed to reverse loyalty points",
+			zap.Error(err),
+			zap.String("transaction_id", original.ID),
+		)
+	}
+
+	if err := f.reverseCashback(ctx, original, refund.ID, clawback, restore); err != nil {
+		logger.Warn("Failed to reverse cashback",
+			zap.Error(err),
+			zap.String("transaction_id", original.ID),
+		)
+	}
+
+	if fullRefund {
+		f.markOrderRefunded(ctx, original, reason)
+	}
+
+	f.notifyEvent(ctx, observer.Event{
+		Type:          observer.EventRefundIssued,
+		TransactionID: refund.ID,
+		CustomerID:    refund.CustomerID,
+		Amount:        amount,
+		PaymentMethod: refund.PaymentMethod,
+		Metadata: map[string]interface{}{
+			"refund_of":       original.ID,
+			"refunded_amount": amount,
+			"refund_reason":   string(reason),
+		},
+		Timestamp: now.Format(time.RFC3339),
+	})
+
+	logger.Info("Refund issued",
+		zap.String("transaction_id", original.ID),
+		zap.String("refund_id", refund.ID),
+		zap.Float64("amount", amount),
+		zap.String("reason", string(reason)),
+	)
+
+	return refund, nil
+}
+
+// RefundableAmount is how much of a transaction can still be refunded.
+func (f *CheckoutFacade) RefundableAmount(ctx context.Context, transactionID string) (float64, error) {
+	original, err := f.transactionService.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return 0, err
+	}
+	return refundableAmount(original), nil
+}
+
+func refundableAmount(original *domain.Transaction) float64 {
+	return money.Round(capturedAmount(original) - metadataFloat(original.Metadata, "refunded_amount"))
+}
+
+// AuthorizedAmount is how much of an authorized transaction can be captured.
+func (f *CheckoutFacade) AuthorizedAmount(ctx context.Context, transactionID string) (float64, error) {
+	transaction, err := f.transactionService.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return 0, err
+	}
+	return authorizedAmount(transaction), nil
+}
+
+func authorizedAmount(transaction *domain.Transaction) float64 {
+	if authorized := metadataFloat(transaction.PaymentDetails, "authorized_amount"); authorized > 0 {
+		return authorized
+	}
+	return transaction.Amount
+}
+
+func (f *CheckoutFacade) CaptureTransaction(ctx context.Context, transactionID string, amount float64) (*domain.Transaction, error) {
+	f.settleMu.Lock()
+	defer f.settleMu.Unlock()
+
+	transaction, err := f.transactionService.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != domain.TransactionStatusAuthorized {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("transaction %s cannot be captured in status %s", transactionID, transaction.Status),
+		)
+	}
+
+	authorized := authorizedAmount(transaction)
+	amount = money.Round(amount)
+	if amount <= 0 || amount > money.Round(authorized) {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("capture amount must be between 0 and %.2f", authorized),
+		)
+	}
+
+	if transaction.Metadata == nil {
+		transaction.Metadata = make(map[string]interface{})
+	}
+	transaction.Metadata["authorized_amount"] = authorized
+	transaction.Metadata["captured_amount"] = amount
+	transaction.Metadata["released_amount"] = money.Round(authorized - amount)
+	transaction.Amount = amount
+	transaction.Status = domain.TransactionStatusCompleted
+	if transaction.IsConverted() {
+		transaction.LockExchangeRate(transaction.ExchangeRate, amount, transaction.DisplayCurrency, transaction.BaseCurrency)
+	}
+	transaction.ProcessedAt = f.now()
+
+	f.payoutCaptured(ctx, transaction, amount/authorized)
+
+	if err := f.transactionService.UpdateTransaction(ctx, transaction); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to record capture")
+	}
+
+	logger.Info("Authorization captured",
+		zap.String("transaction_id", transaction.ID),
+		zap.Float64("authorized", authorized),
+		zap.Float64("captured", amount),
+		zap.Float64("released", money.Round(authorized-amount)),
+	)
+
+	return transaction, nil
+}
+
+// payoutCaptured pays the loyalty points and cashback an authorization held
+// back, scaled to the captured share of it.
+func (f *CheckoutFacade) payoutCaptured(ctx context.Context, transaction *domain.Transaction, share float64) {
+	if earned := metadataFloat(transaction.PaymentDetails, "loyalty_points_earned"); earned > 0 {
+		points := int(math.Round(earned * share))
+		transaction.PaymentDetails["loyalty_points_earned"] = points
+
+		if points > 0 {
+			err := f.customerService.UpdateLoyaltyPoints(ctx, transaction.CustomerID, transaction.ID, observer.LoyaltyChange{
+				Delta:  points,
+				Reason: observer.LoyaltyReasonEarn,
+			})
+			if err != nil {
+				logger.Warn("Failed to update loyalty points",
+					zap.Error(err),
+					zap.String("customer_id", transaction.CustomerID),
+				)
+			}
+		}
+	}
+
+	if pending := metadataFloat(transaction.Metadata, "cashback_pending"); pending > 0 {
+		delete(transaction.Metadata, "cashback_pending")
+		if err := f.payoutCashback(ctx, transaction.CustomerID, pending*share, transaction); err != nil {
+			transaction.Metadata["cashback_pending"] = money.Round(pending * share)
+			logger.Error("Failed to pay out cashback",
+				zap.Error(err),
+				zap.String("customer_id", transaction.CustomerID),
+			)
+		}
+	}
+}
+
+// repriceCart brings the cart up to current catalog prices when
+// checkout.auto_reprice is on. Price drops are applied silently; an increase
+// fails with ErrCodePriceChanged until the buyer accepts it.
+func (f *CheckoutFacade) repriceCart(ctx context.Context, cart *domain.Cart, options domain.CheckoutOptions) error {
+	if !f.config.Checkout.AutoReprice {
+		return nil
+	}
+
+	products := make(map[string]*domain.Product, len(cart.Items))
+	for _, item := range cart.Items {
+		product, err := f.inventoryService.GetProduct(ctx, item.ProductID)
+		if err != nil {
+			return err
+		}
+		products[item.ProductID] = product
+	}
+
+	changes := cart.Reprice(products)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	logger.Info("Cart repriced at checkout",
+		zap.String("cart_id", cart.ID),
+		zap.Int("changed_items", len(changes)),
+	)
+
+	if domain.PricesIncreased(changes) && !options.AcceptPriceChanges {
+		return errors.New(errors.ErrCodePriceChanged, "cart prices increased, please confirm the new prices").
+			WithDetails("changes", changes)
+	}
+
+	return nil
+}
+
+// checkSpendingLimit rejects an order that would push the customer's spend in
+// the rolling window past their own limit or the configured default.
+func (f *CheckoutFacade) checkSpendingLimit(ctx context.Context, customer *domain.Customer, amount float64) error {
+	cfg := f.config.Checkout.SpendingLimit
+
+	limit := cfg.Amount
+	if customer.SpendingLimit > 0 {
+		limit = customer.SpendingLimit
+	}
+	if limit <= 0 || cfg.Window <= 0 {
+		return nil
+	}
+
+	spent, err := f.transactionService.CustomerSpend(ctx, customer.ID, f.now().Add(-cfg.Window))
+	if err != nil {
+		return err
+	}
+
+	if money.Round(spent+amount) > money.Round(limit) {
+		return errors.NewValidationError(fmt.Sprintf(
+			"order of $%.2f exceeds spending limit: $%.2f of $%.2f already spent in the last %s",
+			amount, spent, limit, cfg.Window,
+		)).
+			WithDetails("spending_limit", limit).
+			WithDetails("spent", money.Round(spent)).
+			WithDetails("remaining", money.Round(math.Max(limit-spent, 0)))
+	}
+
+	return nil
+}
+
+// capturedAmount is what the customer was actually charged, tax and fees
+// included, falling back the same way Transaction.Financials does.
+func capturedAmount(transaction *domain.Transaction) float64 {
+	for _, key := range []string{"captured_amount", "charged_amount"} {
+		if _, ok := transaction.Metadata[key]; ok {
+			return metadataFloat(transaction.Metadata, key)
+		}
+	}
+	return transaction.Amount
+}
+
+func (f *CheckoutFacade) reverseLoyaltyPoints(ctx context.Context, original *domain.Transaction, refundID string, amount float64) error {
+	earned := metadataFloat(original.PaymentDetails, "loyalty_points_earned")
+	captured := capturedAmount(original)
+	if earned <= 0 || captured <= 0 {
+		return nil
+	}
+
+	points := int(math.Round(earned * amount / captured))
+	if points <= 0 {
+		return nil
 	}
 
-	paymentInstance, err := f.createPayment(options)
+	customer, err := f.customerService.GetCustomer(ctx, original.CustomerID)
 	if err != nil {
-		f.rollbackInventory(ctx, cart)
-		return nil, f.handleError(ctx, transaction, err, "payment creation failed")
+		return err
+	}
+	if points > customer.LoyaltyPoints {
+		points = customer.LoyaltyPoints
 	}
 
-	decoratedPayment, err := f.applyDecorators(ctx, paymentInstance, options, customer)
-	if err != nil {
-		f.rollbackInventory(ctx, cart)
-		return nil, f.handleError(ctx, transaction, err, "decorator application failed")
+	return f.customerService.UpdateLoyaltyPoints(ctx, customer.ID, refundID, observer.LoyaltyChange{
+		Delta:  -points,
+		Reason: observer.LoyaltyReasonRefund,
+	})
+}
+
+// cashbackReversal works out how much of the cashback the order paid out a
+// refund of amount takes back, and how much of the cashback it redeemed is
+// given back, recording both on the original. The refund that completes the
+// order settles whatever is left so rounding strands nothing.
+func cashbackReversal(original *domain.Transaction, amount float64, full bool) (clawback, restore float64) {
+	captured := capturedAmount(original)
+	if captured <= 0 {
+		return 0, 0
 	}
 
-	result, err := f.executePaymentStrategy(ctx, decoratedPayment, cart.GetTotal(), options)
-	if err != nil {
-		f.rollbackInventory(ctx, cart)
-		return nil, f.handleError(ctx, transaction, err, "payment processing failed")
+	settle := func(total float64, key string) float64 {
+		done := metadataFloat(original.Metadata, key)
+		part := money.Round(total * amount / captured)
+		if full || part > total-done {
+			part = money.Round(total - done)
+		}
+		if part <= 0 {
+			return 0
+		}
+		original.Metadata[key] = money.Round(done + part)
+		return part
 	}
 
-	transaction.Status = domain.TransactionStatusCompleted
-	transaction.ProcessedAt = time.Now()
-	transaction.PaymentDetails = result.Metadata
+	paidOut := metadataFloat(original.Metadata, "cashback_credited")
+	if original.Metadata["cashback_payout"] == config.CashbackPayoutLoyaltyPoints {
+		paidOut = metadataFloat(original.Metadata, "cashback_points")
+	}
 
-	if err := f.updateLoyaltyPoints(ctx, customer, result); err != nil {
-		logger.Warn("Failed to update loyalty points",
-			zap.Error(err),
-			zap.String("customer_id", customer.ID),
-		)
+	return settle(paidOut, "cashback_clawed_back"), settle(metadataFloat(original.Metadata, "cashback_redeemed"), "cashback_restored")
+}
+
+// reverseCashback takes back clawback, in the unit the order's cashback was
+// paid out in, and returns restore to the customer's cashback balance.
+func (f *CheckoutFacade) reverseCashback(ctx context.Context, original *domain.Transaction, refundID string, clawback, restore float64) error {
+	if clawback > 0 {
+		if original.Metadata["cashback_payout"] == config.CashbackPayoutLoyaltyPoints {
+			customer, err := f.customerService.GetCustomer(ctx, original.CustomerID)
+			if err != nil {
+				return err
+			}
+			points := int(math.Min(math.Round(clawback), float64(customer.LoyaltyPoints)))
+			if points > 0 {
+				err := f.customerService.UpdateLoyaltyPoints(ctx, customer.ID, refundID, observer.LoyaltyChange{
+					Delta:  -points,
+					Reason: observer.LoyaltyReasonRefund,
+				})
+				if err != nil {
+					return err
+				}
+			}
+		} else if err := f.customerService.ClawBackCashback(ctx, original.CustomerID, refundID, clawback); err != nil {
+			return err
+		}
 	}
 
-	receipt := f.generateReceipt(transaction, cart, customer, result)
+	if restore > 0 {
+		return f.customerService.CreditCashback(ctx, original.CustomerID, refundID, domain.CashbackReasonRestore, restore)
+	}
+	return nil
+}
 
-	if err := f.transactionService.CreateTransaction(ctx, transaction); err != nil {
-		logger.Error("Failed to save transaction",
+// markOrderRefunded also restocks the order's items, except defective ones.
+func (f *CheckoutFacade) markOrderRefunded(ctx context.Context, original *domain.Transaction, reason domain.RefundReason) {
+	order, err := f.orderService.FindByTransaction(ctx, original.CustomerID, original.ID)
+	if err == nil {
+		err = f.orderService.MarkRefunded(ctx, order)
+	}
+	if err != nil {
+		logger.Warn("Failed to mark order refunded",
 			zap.Error(err),
-			zap.String("transaction_id", transaction.ID),
+			zap.String("transaction_id", original.ID),
 		)
+		return
 	}
 
-	cart.Clear()
-
-	f.notifyEvent(ctx, observer.Event{
-		Type:          observer.EventPaymentSuccess,
-		TransactionID: transaction.ID,
-		CustomerID:    customer.ID,
-		Amount:        result.Amount,
-		PaymentMethod: result.PaymentMethod,
-		Result:        result,
-		Timestamp:     time.Now().Format(time.RFC3339),
-	})
+	if reason == domain.RefundReasonDefective {
+		return
+	}
+	if err := f.inventoryService.RestockItems(ctx, original.ID, order.Items); err != nil {
+		logger.Warn("Failed to restock refunded order",
+			zap.Error(err),
+			zap.String("order_id", order.ID),
+		)
+	}
+}
 
-	logger.Info("Checkout completed successfully",
-		zap.String("transaction_id", transaction.ID),
-		zap.Float64("amount", result.Amount),
-	)
+func metadataFloat(metadata map[string]interface{}, key string) float64 {
+	switch v := metadata[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
 
-	return receipt, nil
+func metadataStrings(metadata map[string]interface{}, key string) []string {
+	switch v := metadata[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
 }
 
 func (f *CheckoutFacade) validateInventory(ctx context.Context, cart *domain.Cart) error {
@@ -163,53 +1217,191 @@ func (f *CheckoutFacade) validateInventory(ctx context.Context, cart *domain.Car
 	return nil
 }
 
-func (f *CheckoutFacade) reserveInventory(ctx context.Context, cart *domain.Cart) error {
+func (f *CheckoutFacade) reserveInventory(ctx context.Context, transactionID string, cart *domain.Cart) error {
 	logger.Debug("Reserving inventory")
 
-	for _, item := range cart.Items {
-		if err := f.inventoryService.ReserveStock(ctx, item.ProductID, item.Quantity); err != nil {
-			return errors.Wrap(err, errors.ErrCodeInventoryError, "failed to reserve inventory")
-		}
+	if err := f.inventoryService.ReserveItems(ctx, transactionID, cart.ID, cart.Items); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInventoryError, "failed to reserve inventory")
 	}
 
 	return nil
 }
 
-func (f *CheckoutFacade) rollbackInventory(ctx context.Context, cart *domain.Cart) {
+func (f *CheckoutFacade) commitInventory(ctx context.Context, transactionID string, cart *domain.Cart) {
+	if err := f.inventoryService.CommitItems(ctx, transactionID, cart.Items); err != nil {
+		logger.Error("Failed to commit inventory",
+			zap.Error(err),
+			zap.String("transaction_id", transactionID),
+		)
+	}
+}
+
+func (f *CheckoutFacade) rollbackInventory(ctx context.Context, transactionID string, cart *domain.Cart) {
 	logger.Warn("Rolling back inventory reservations")
+	ctx = context.WithoutCancel(ctx)
 
-	for _, item := range cart.Items {
-		if err := f.inventoryService.ReleaseStock(ctx, item.ProductID, item.Quantity); err != nil {
-			logger.Error("Failed to rollback inventory",
-				zap.Error(err),
-				zap.String("product_id", item.ProductID),
-			)
-		}
+	if err := f.inventoryService.ReleaseReservations(ctx, transactionID); err != nil {
+		logger.Error("Failed to rollback inventory",
+			zap.Error(err),
+			zap.String("transaction_id", transactionID),
+		)
+	}
+}
+
+func (f *CheckoutFacade) resolveSavedMethod(
+	ctx context.Context,
+	customer *domain.Customer,
+	options *domain.CheckoutOptions,
+) (*domain.SavedPaymentMethod, error) {
+	if options.SavedMethodID == "" {
+		return nil, nil
+	}
+
+	saved, err := f.customerService.GetPaymentMethod(ctx, customer.ID, options.SavedMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.PaymentMethod == "" {
+		options.PaymentMethod = saved.Method
+	} else if options.PaymentMethod != saved.Method {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("saved payment method %s is %s, not %s", saved.ID, saved.Method, options.PaymentMethod),
+		)
 	}
+
+	return saved, nil
 }
 
-func (f *CheckoutFacade) createPayment(options domain.CheckoutOptions) (payment.Payment, error) {
+func (f *CheckoutFacade) createPayment(options domain.CheckoutOptions, saved *domain.SavedPaymentMethod) (payment.Payment, error) {
 	logger.Debug("Creating payment instance",
 		zap.String("payment_method", options.PaymentMethod),
 	)
 
-	config := payment.PaymentConfig{}
+	if options.SplitParts > 0 {
+		return f.createEvenSplitPayment(options, saved)
+	}
+
+	config := payment.ConfigFromDetails(options.PaymentDetails)
+	config.Limits = f.paymentLimits[options.PaymentMethod]
+
+	if saved != nil {
+		config.SavedToken = saved.Token
+		config.SavedDetails = saved.Details
+	}
+	if options.PaymentMethod == "wallet" && config.WalletToken == "" {
+		// Mobile clients pass the device token in the checkout metadata.
+		config.WalletToken, _ = options.Metadata["wallet_token"].(string)
+		config.WalletProvider, _ = options.Metadata["wallet_provider"].(string)
+	}
+
+	paymentInstance, err := f.paymentFactory.CreatePayment(options.PaymentMethod, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.config.Payment.Sandbox {
+		paymentInstance = payment.NewSandboxPayment(paymentInstance, options.Metadata)
+	}
+
+	return payment.NewFreeOrderPayment(paymentInstance, f.config.Payment.FreeOrderMax), nil
+}
+
+// PaymentMethods lists the payment methods checkout can charge, sorted.
+func (f *CheckoutFacade) PaymentMethods() []string {
+	methods := f.paymentFactory.GetSupportedTypes()
+	sort.Strings(methods)
+	return methods
+}
+
+// AvailableDecorators lists the decorators enabled in config.
+func (f *CheckoutFacade) AvailableDecorators() []string {
+	return f.decoratorFactory.GetAvailableDecorators()
+}
+
+// NewPayment charges the saved method behind installments and renewals.
+func (f *CheckoutFacade) NewPayment(ctx context.Context, customerID, method, savedMethodID string) (payment.Payment, error) {
+	if savedMethodID == "" {
+		return nil, errors.NewValidationError("no saved payment method to charge")
+	}
+
+	saved, err := f.customerService.GetPaymentMethod(ctx, customerID, savedMethodID)
+	if err != nil {
+		return nil, err
+	}
+	if saved.Method != method {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("saved payment method %s is %s, not %s", saved.ID, saved.Method, method),
+		)
+	}
+
+	return f.createPayment(domain.CheckoutOptions{PaymentMethod: method}, saved)
+}
+
+func (f *CheckoutFacade) saveSchedule(ctx context.Context, schedule *domain.PaymentSchedule, transaction *domain.Transaction, savedMethodID string) {
+	schedule.TransactionID = transaction.ID
+	schedule.CustomerID = transaction.CustomerID
+	schedule.PaymentMethod = transaction.PaymentMethod
+	schedule.SavedMethodID = savedMethodID
+	for i := range schedule.Payments {
+		if schedule.Payments[i].Status == domain.InstallmentStatusPaid {
+			schedule.Payments[i].TransactionID = transaction.ID
+		}
+	}
+
+	if err := f.scheduleService.CreateSchedule(ctx, schedule); err != nil {
+		logger.Error("Failed to save payment schedule",
+			zap.Error(err),
+			zap.String("schedule_id", schedule.ID),
+			zap.String("transaction_id", transaction.ID),
+		)
+	}
+}
+
+func (f *CheckoutFacade) saveSubscription(ctx context.Context, subscription *domain.Subscription, transaction *domain.Transaction, savedMethodID string) {
+	subscription.TransactionID = transaction.ID
+	subscription.LastTransactionID = transaction.ID
+	subscription.CustomerID = transaction.CustomerID
+	subscription.PaymentMethod = transaction.PaymentMethod
+	subscription.SavedMethodID = savedMethodID
+
+	if err := f.billingService.CreateSubscription(ctx, subscription); err != nil {
+		logger.Error("Failed to save subscription",
+			zap.Error(err),
+			zap.String("subscription_id", subscription.ID),
+			zap.String("transaction_id", transaction.ID),
+		)
+	}
+}
+
+// createEvenSplitPayment charges the order in equal parts, each with its own
+// instance of the payment method. The split sits under the decorators, so
+// tax, discounts and loyalty apply once to the whole order.
+func (f *CheckoutFacade) createEvenSplitPayment(options domain.CheckoutOptions, saved *domain.SavedPaymentMethod) (payment.Payment, error) {
+	if options.PaymentStrategy != "split" {
+		return nil, errors.NewValidationError("split_parts requires the split payment strategy")
+	}
+	if options.SplitParts < 2 {
+		return nil, errors.NewValidationError("an even split needs at least 2 parts")
+	}
+
+	legOptions := options
+	legOptions.SplitParts = 0
 
-	switch options.PaymentMethod {
-	case "credit_card":
-		config.CardNumber = "4532015112830366"
-		config.CardHolder = "John Doe"
-		config.ExpiryDate = "12/25"
-		config.CVV = "123"
-	case "paypal":
-		config.PayPalEmail = "user@example.com"
-		config.PayPalPassword = "password"
-	case "crypto":
-		config.WalletAddress = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
-		config.CryptoType = "BTC"
+	legs := make([]payment.Payment, 0, options.SplitParts)
+	for i := 0; i < options.SplitParts; i++ {
+		leg, err := f.createPayment(legOptions, saved)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
 	}
 
-	return f.paymentFactory.CreatePayment(options.PaymentMethod, config)
+	split, err := strategy.NewEvenSplitPaymentStrategy(legs)
+	if err != nil {
+		return nil, err
+	}
+	return strategy.NewSplitPayment(split), nil
 }
 
 func (f *CheckoutFacade) applyDecorators(
@@ -223,6 +1415,7 @@ func (f *CheckoutFacade) applyDecorators(
 	)
 
 	return f.decoratorFactory.CreateDecoratorChain(
+		ctx,
 		paymentInstance,
 		options.EnabledDecorators,
 		options,
@@ -230,15 +1423,31 @@ func (f *CheckoutFacade) applyDecorators(
 	)
 }
 
+// PreviewDeferredSchedule returns the installment plan a deferred checkout of
+// the cart would create, without reserving stock or charging.
+func (f *CheckoutFacade) PreviewDeferredSchedule(cart *domain.Cart) (*domain.PaymentSchedule, error) {
+	paymentStrategy, err := f.strategyFactory.CreateStrategy("deferred", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	deferred, ok := paymentStrategy.(*strategy.DeferredPaymentStrategy)
+	if !ok {
+		return nil, errors.NewInternalError("deferred strategy does not support previews")
+	}
+
+	return deferred.Preview(cart.GetTotal())
+}
+
 func (f *CheckoutFacade) executePaymentStrategy(
 	ctx context.Context,
 	paymentInstance payment.Payment,
-	amount float64,
+	request payment.PaymentRequest,
 	options domain.CheckoutOptions,
 ) (*payment.PaymentResult, error) {
 	logger.Debug("Executing payment strategy",
 		zap.String("strategy", options.PaymentStrategy),
-		zap.Float64("amount", amount),
+		zap.Float64("amount", request.Amount),
 	)
 
 	ctx, cancel := context.WithTimeout(ctx, f.config.Payment.Timeout)
@@ -249,39 +1458,75 @@ func (f *CheckoutFacade) executePaymentStrategy(
 		strategyType = "instant"
 	}
 
-	paymentStrategy, err := f.strategyFactory.CreateStrategy(strategyType, nil)
+	// An even split is already inside the payment chain (see
+	// createEvenSplitPayment); it only needs to be charged once.
+	split := strategyType == "split" && options.SplitParts > 0
+	if split {
+		strategyType = "instant"
+	}
+
+	paymentStrategy, err := f.strategyFactory.CreateStrategy(strategyType, f.strategyParams(strategyType, options))
 	if err != nil {
 		return nil, err
 	}
 
-	return f.executeWithRetry(ctx, paymentStrategy, paymentInstance, amount)
+	result, err := f.executeWithRetry(ctx, paymentStrategy, paymentInstance, request)
+	if err != nil || !split {
+		return result, err
+	}
+
+	// Every leg used the same method, so report it rather than "split".
+	result.PaymentMethod = options.PaymentMethod
+	result.Strategy = "split"
+	result.Metadata["payment_strategy"] = result.Strategy
+	return result, nil
+}
+
+// strategyParams prefers the strategy's configured limits over the method's.
+func (f *CheckoutFacade) strategyParams(strategyType string, options domain.CheckoutOptions) map[string]interface{} {
+	params := make(map[string]interface{})
+	if limits, ok := f.strategyLimits[strategyType]; ok {
+		params["min_amount"] = limits.Min
+		params["max_amount"] = limits.Max
+	} else if limits, ok := f.paymentLimits[options.PaymentMethod]; ok {
+		params["max_amount"] = limits.Max
+	}
+
+	if strategyType == "recurring" && options.BillingInterval != "" {
+		params["interval"] = options.BillingInterval
+	}
+	return params
 }
 
 func (f *CheckoutFacade) executeWithRetry(
 	ctx context.Context,
 	paymentStrategy strategy.PaymentStrategy,
 	paymentInstance payment.Payment,
-	amount float64,
+	request payment.PaymentRequest,
 ) (*payment.PaymentResult, error) {
 	var lastErr error
 
 	for attempt := 0; attempt <= f.config.Payment.RetryAttempts; attempt++ {
 		if attempt > 0 {
+			delay := f.retryDelay(attempt)
 			logger.Info("Retrying payment",
 				zap.Int("attempt", attempt),
+				zap.Duration("delay", delay),
 			)
-			time.Sleep(f.config.Payment.RetryDelay)
+			if err := f.sleep(ctx, delay); err != nil {
+				return nil, errors.Wrap(lastErr, errors.ErrCodeTimeout, "checkout cancelled while waiting to retry payment")
+			}
 		}
 
-		result, err := paymentStrategy.Execute(ctx, paymentInstance, amount)
+		result, err := paymentStrategy.Execute(ctx, paymentInstance, request)
 		if err == nil {
 			return result, nil
 		}
 
 		lastErr = err
 
-		if errors.IsErrorCode(err, errors.ErrCodeFraudDetected) ||
-			errors.IsErrorCode(err, errors.ErrCodeInvalidPayment) {
+		if errors.HasErrorCode(err, errors.ErrCodeFraudDetected) ||
+			errors.HasErrorCode(err, errors.ErrCodeInvalidPayment) {
 			break
 		}
 	}
@@ -289,31 +1534,203 @@ func (f *CheckoutFacade) executeWithRetry(
 	return nil, lastErr
 }
 
+// retryDelay is the wait before retry number attempt: payment.retry_delay
+// doubled for each earlier retry and capped at payment.retry_max_delay. With
+// payment.retry_jitter it is a random point in the upper half of that.
+func (f *CheckoutFacade) retryDelay(attempt int) time.Duration {
+	cfg := f.config.Payment
+
+	delay := cfg.RetryDelay
+	for i := 1; i < attempt && (cfg.RetryMaxDelay <= 0 || delay < cfg.RetryMaxDelay); i++ {
+		delay *= 2
+	}
+	if cfg.RetryMaxDelay > 0 && delay > cfg.RetryMaxDelay {
+		delay = cfg.RetryMaxDelay
+	}
+
+	if cfg.RetryJitter && delay > 1 {
+		delay = delay/2 + time.Duration(mathrand.Int63n(int64(delay/2)+1))
+	}
+	return delay
+}
+
+// sleepContext waits for d, returning early with ctx's error if ctx is done
+// first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *CheckoutFacade) redeemsLoyaltyPoints(options domain.CheckoutOptions) bool {
+	if options.UseLoyaltyPoints <= 0 || !f.config.Decorators.LoyaltyPoints.Enabled {
+		return false
+	}
+
+	for _, name := range options.EnabledDecorators {
+		if name == "loyalty_points" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *CheckoutFacade) cancelOrder(ctx context.Context, order *domain.Order) {
+	ctx = context.WithoutCancel(ctx)
+	if err := f.orderService.Cancel(ctx, order); err != nil {
+		logger.Error("Failed to cancel order",
+			zap.Error(err),
+			zap.String("order_id", order.ID),
+		)
+	}
+}
+
+func (f *CheckoutFacade) releaseLoyaltyHold(hold *service.LoyaltyHold) {
+	if hold != nil {
+		f.loyaltyService.Release(hold.ID)
+	}
+}
+
 func (f *CheckoutFacade) updateLoyaltyPoints(
 	ctx context.Context,
 	customer *domain.Customer,
+	transaction *domain.Transaction,
 	result *payment.PaymentResult,
+	hold *service.LoyaltyHold,
 ) error {
 
-	pointsEarned := 0
-	if val, ok := result.Metadata["loyalty_points_earned"].(int); ok {
-		pointsEarned = val
+	// The loyalty decorator is only in the chain when points are redeemed;
+	// otherwise the points the order earns are worked out here.
+	if !appliedDecorator(result, "loyalty_points") && f.config.Decorators.LoyaltyPoints.Enabled {
+		earned := decorator.PointsEarned(transaction.Amount, f.config.Decorators.LoyaltyPoints.EarnRate)
+		if earned > 0 {
+			if result.Metadata == nil {
+				result.Metadata = make(map[string]interface{})
+				transaction.PaymentDetails = result.Metadata
+			}
+			result.Metadata["loyalty_points_earned"] = earned
+			result.Breakdown.LoyaltyPointsEarned = earned
+		}
+	}
+
+	pointsEarned := result.Breakdown.LoyaltyPointsEarned
+	if transaction.Status == domain.TransactionStatusAuthorized {
+		// An authorization earns its points on the captured amount at capture.
+		pointsEarned = 0
+	}
+
+	if hold != nil {
+		return f.loyaltyService.Commit(ctx, hold.ID, transaction.ID, pointsEarned)
+	}
+
+	pointsRedeemed := result.Breakdown.LoyaltyPointsRedeemed
+
+	return f.customerService.UpdateLoyaltyPoints(ctx, customer.ID, transaction.ID,
+		observer.LoyaltyChange{Delta: -pointsRedeemed, Reason: observer.LoyaltyReasonRedeem},
+		observer.LoyaltyChange{Delta: pointsEarned, Reason: observer.LoyaltyReasonEarn},
+	)
+}
+
+func appliedDecorator(result *payment.PaymentResult, name string) bool {
+	for _, applied := range result.AppliedDecorators {
+		if applied == name {
+			return true
+		}
+	}
+	return false
+}
+
+// paymentRequest charges the cart total in the ctx currency, with the cart
+// lines and customer for decorators that price per item or per customer.
+func paymentRequest(ctx context.Context, cart *domain.Cart, customer *domain.Customer, options domain.CheckoutOptions) payment.PaymentRequest {
+	items := make([]payment.LineItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, payment.LineItem{
+			ProductID: item.ProductID,
+			Category:  item.Product.Category,
+			TaxExempt: item.Product.TaxExempt,
+			Amount:    item.Price * float64(item.Quantity),
+		})
+	}
+
+	return payment.PaymentRequest{
+		Amount:   cart.GetTotal(),
+		Currency: payment.CurrencyFromContext(ctx),
+		Items:    items,
+		Customer: customer,
+		Metadata: options.Metadata,
 	}
+}
 
-	pointsRedeemed := 0
-	if val, ok := result.Metadata["loyalty_points_redeemed"].(int); ok {
-		pointsRedeemed = val
+func withCashbackRedemption(decorators []string) []string {
+	for _, name := range decorators {
+		if name == "cashback_redemption" {
+			return decorators
+		}
 	}
+	return append(append([]string(nil), decorators...), "cashback_redemption")
+}
 
-	if pointsEarned > 0 || pointsRedeemed > 0 {
-		return f.customerService.UpdateLoyaltyPoints(
-			ctx,
-			customer.ID,
-			pointsEarned,
-			pointsRedeemed,
+// restoreCashback returns reserved cashback that was not spent.
+func (f *CheckoutFacade) restoreCashback(ctx context.Context, customerID, transactionID string, amount float64) {
+	if amount <= 0 {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	if err := f.customerService.CreditCashback(ctx, customerID, transactionID, domain.CashbackReasonRestore, amount); err != nil {
+		logger.Error("Failed to restore reserved cashback",
+			zap.Error(err),
+			zap.String("customer_id", customerID),
+			zap.Float64("amount", amount),
 		)
 	}
+}
+
+func (f *CheckoutFacade) payoutCashback(
+	ctx context.Context,
+	customerID string,
+	cashback float64,
+	transaction *domain.Transaction,
+) error {
+	cashback = money.Round(cashback)
+	if cashback <= 0 {
+		return nil
+	}
+
+	if transaction.Metadata == nil {
+		transaction.Metadata = make(map[string]interface{})
+	}
+
+	cfg := f.config.Decorators.Cashback
+	if cfg.Payout == config.CashbackPayoutLoyaltyPoints {
+		points := int(math.Round(cashback * cfg.PointsPerUnit))
+		if points <= 0 {
+			return nil
+		}
+		err := f.customerService.UpdateLoyaltyPoints(ctx, customerID, transaction.ID, observer.LoyaltyChange{
+			Delta:  points,
+			Reason: observer.LoyaltyReasonCashback,
+		})
+		if err != nil {
+			return err
+		}
+		transaction.Metadata["cashback_payout"] = config.CashbackPayoutLoyaltyPoints
+		transaction.Metadata["cashback_points"] = points
+		return nil
+	}
 
+	if err := f.customerService.CreditCashback(ctx, customerID, transaction.ID, domain.CashbackReasonPayout, cashback); err != nil {
+		return err
+	}
+	transaction.Metadata["cashback_payout"] = config.CashbackPayoutBalance
+	transaction.Metadata["cashback_credited"] = cashback
 	return nil
 }
 
@@ -324,55 +1741,54 @@ func (f *CheckoutFacade) generateReceipt(
 	result *payment.PaymentResult,
 ) *domain.Receipt {
 
-	items := make([]domain.ReceiptItem, 0, len(cart.Items))
-	for _, item := range cart.Items {
-		items = append(items, domain.ReceiptItem{
-			ProductID:   item.ProductID,
-			ProductName: item.Product.Name,
-			SKU:         item.Product.SKU,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.Price,
-			Total:       item.Price * float64(item.Quantity),
-		})
+	pricing := domain.PricingInputs{
+		Discount: result.Breakdown.DiscountAmount,
+		Tax:      result.Breakdown.TaxAmount,
 	}
 
-	subtotal := cart.GetTotal()
-	discount := 0.0
-	tax := 0.0
-	cashback := 0.0
-	loyaltyPoints := 0
+	breakdown := cart.Breakdown(pricing)
 
-	if val, ok := result.Metadata["discount_amount"].(float64); ok {
-		discount = val
-	}
-	if val, ok := result.Metadata["tax_amount"].(float64); ok {
-		tax = val
-	}
-	if val, ok := result.Metadata["cashback_amount"].(float64); ok {
-		cashback = val
-	}
-	if val, ok := result.Metadata["loyalty_points_earned"].(int); ok {
-		loyaltyPoints = val
+	items := make([]domain.ReceiptItem, 0, len(breakdown.Lines))
+	for _, line := range breakdown.Lines {
+		items = append(items, domain.ReceiptItem{
+			ProductID:   line.ProductID,
+			ProductName: line.ProductName,
+			SKU:         line.SKU,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			Total:       line.Total,
+		})
 	}
 
-	return &domain.Receipt{
-		ID:                domain.NewID(),
+	receipt := &domain.Receipt{
+		ID:                f.newID(),
 		TransactionID:     transaction.ID,
 		CustomerID:        customer.ID,
 		CustomerName:      customer.Name,
 		CustomerEmail:     customer.Email,
+		Locale:            customer.Locale,
 		Items:             items,
-		Subtotal:          subtotal,
-		Discount:          discount,
-		Tax:               tax,
-		Cashback:          cashback,
-		LoyaltyPoints:     loyaltyPoints,
+		Subtotal:          breakdown.Subtotal,
+		Discount:          breakdown.Discount,
+		Tax:               breakdown.Tax,
+		ServiceFee:        result.Breakdown.ServiceFeeAmount,
+		Cashback:          result.Breakdown.CashbackAmount,
+		CashbackRedeemed:  result.Breakdown.CashbackRedeemed,
+		LoyaltyPoints:     result.Breakdown.LoyaltyPointsEarned,
 		Total:             result.Amount,
 		PaymentMethod:     result.PaymentMethod,
+		Strategy:          result.Strategy,
 		PaymentDetails:    result.Metadata,
 		AppliedDecorators: result.AppliedDecorators,
-		CreatedAt:         time.Now(),
+		CreatedAt:         f.now(),
+	}
+
+	// Only compound taxes are itemized; a single rate is just the tax line.
+	if len(result.Breakdown.TaxLines) > 1 {
+		receipt.TaxLines = result.Breakdown.TaxLines
 	}
+
+	return receipt
 }
 
 func (f *CheckoutFacade) handleError(
@@ -381,6 +1797,13 @@ func (f *CheckoutFacade) handleError(
 	err error,
 	message string,
 ) error {
+	// A checkout past its deadline fails as a timeout whatever step noticed.
+	timedOut := stderrors.Is(ctx.Err(), context.DeadlineExceeded)
+	if timedOut {
+		message = "checkout timed out: " + message
+	}
+	ctx = context.WithoutCancel(ctx)
+
 	logger.Error(message,
 		zap.Error(err),
 		zap.String("transaction_id", transaction.ID),
@@ -389,6 +1812,15 @@ func (f *CheckoutFacade) handleError(
 	transaction.Status = domain.TransactionStatusFailed
 	transaction.ErrorMessage = err.Error()
 
+	if transaction.IdempotencyKey() != "" {
+		if updateErr := f.transactionService.UpdateTransaction(ctx, transaction); updateErr != nil {
+			logger.Error("Failed to record failed transaction",
+				zap.Error(updateErr),
+				zap.String("transaction_id", transaction.ID),
+			)
+		}
+	}
+
 	f.notifyEvent(ctx, observer.Event{
 		Type:          observer.EventPaymentFailed,
 		TransactionID: transaction.ID,
@@ -396,14 +1828,45 @@ func (f *CheckoutFacade) handleError(
 		Amount:        transaction.Amount,
 		PaymentMethod: transaction.PaymentMethod,
 		Error:         err,
-		Timestamp:     time.Now().Format(time.RFC3339),
+		Timestamp:     f.now().Format(time.RFC3339),
 	})
 
-	return errors.Wrap(err, errors.ErrCodePaymentFailed, message)
+	// Keep the most specific code, e.g. FRAUD_DETECTED rather than the
+	// PAYMENT_FAILED a strategy wrapped it in, so callers can act on it.
+	code := errors.ErrCodePaymentFailed
+	if cause := errors.Cause(err); cause != nil {
+		code = cause.Code
+	}
+	if timedOut {
+		code = errors.ErrCodeTimeout
+	}
+	return errors.Wrap(err, code, message)
+}
+
+// recordContext is used once the customer has been charged. It keeps the
+// caller's values but not its deadline, and is bounded by checkout.timeout
+// anew so a stuck repository still cannot hang the checkout.
+func (f *CheckoutFacade) recordContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if timeout := f.config.Checkout.Timeout; timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
 }
 
+// notifyEvent delivers event in the background. The caller's deadline is
+// dropped since delivery outlives the checkout call; notifications.timeout
+// bounds it instead.
 func (f *CheckoutFacade) notifyEvent(ctx context.Context, event observer.Event) {
+	ctx = context.WithoutCancel(ctx)
+
+	f.notifications.Add(1)
 	go func() {
+		defer f.notifications.Done()
+
+		ctx, cancel := context.WithTimeout(ctx, f.notificationTimeout())
+		defer cancel()
+
 		defer func() {
 			if r := recover(); r != nil {
 				logger.Error("Event notification panic",
@@ -412,6 +1875,27 @@ func (f *CheckoutFacade) notifyEvent(ctx context.Context, event observer.Event)
 			}
 		}()
 
-		f.eventSubject.Notify(context.Background(), event)
+		// Failure and refund paths only know the customer ID.
+		if event.CustomerEmail == "" && event.CustomerID != "" {
+			if customer, err := f.customerService.GetCustomer(ctx, event.CustomerID); err == nil {
+				event.CustomerEmail = customer.Email
+			}
+		}
+
+		f.eventSubject.Notify(ctx, event)
 	}()
 }
+
+// Close waits for event deliveries still in flight. Call it before closing
+// the observers.
+func (f *CheckoutFacade) Close() error {
+	f.notifications.Wait()
+	return nil
+}
+
+func (f *CheckoutFacade) notificationTimeout() time.Duration {
+	if timeout := f.config.Notifications.Timeout; timeout > 0 {
+		return timeout
+	}
+	return defaultNotificationTimeout
+}
@@ -0,0 +1,1602 @@
+package facade
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/ecommerce/payment-system/internal/strategy"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+type checkoutFixture struct {
+	facade   *CheckoutFacade
+	repo     *repository.MemoryRepository
+	subject  *observer.Subject
+	customer *domain.Customer
+	product  *domain.Product
+}
+
+func newCheckoutFixture(t *testing.T, cfg *config.Config) *checkoutFixture {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+
+	customer := &domain.Customer{
+		ID:            "cust-checkout",
+		Email:         "checkout@example.com",
+		Name:          "Checkout Tester",
+		LoyaltyPoints: 10000,
+	}
+	require.NoError(t, repo.CreateCustomer(ctx, customer))
+
+	product := &domain.Product{
+		ID:    "prod-checkout",
+		Name:  "Test Product",
+		SKU:   "TEST-001",
+		Price: 50.00,
+		Stock: 10,
+	}
+	require.NoError(t, repo.CreateProduct(ctx, product))
+
+	subject := observer.NewSubject()
+	customerService := service.NewCustomerService(repo, subject)
+
+	checkoutFacade, err := NewCheckoutFacade(
+		cfg,
+		service.NewInventoryService(repo, nil, service.InventoryOptions{}),
+		customerService,
+		service.NewTransactionService(repo),
+		service.NewLoyaltyService(customerService),
+		service.NewOrderService(repo),
+		service.NewScheduleService(repo),
+		service.NewBillingService(repo),
+		service.NewDiscountService(repo),
+		service.NewRestrictionPolicy(nil),
+		currency.NewDefaultConverter(),
+		subject,
+	)
+	require.NoError(t, err)
+
+	return &checkoutFixture{
+		facade:   checkoutFacade,
+		repo:     repo,
+		subject:  subject,
+		customer: customer,
+		product:  product,
+	}
+}
+
+var testCard = domain.PaymentDetails{
+	CardNumber: "4532015112830366",
+	CardHolder: "Checkout Tester",
+	ExpiryDate: "12/30",
+	CVV:        "123",
+}
+
+func newTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Payment.Timeout = 5 * time.Second
+	cfg.Payment.LimitCurrency = "USD"
+	cfg.Checkout.QuoteTTL = 15 * time.Minute
+	cfg.Checkout.QuoteSecret = "test-quote-secret"
+	cfg.Features = config.FeatureFlags{config.FeatureTwoPhaseCheckout: true}
+	cfg.Payment.CreditCard = config.CreditCardConfig{Enabled: true, MinAmount: 1, MaxAmount: 10000}
+	cfg.Decorators.LoyaltyPoints = config.LoyaltyPointsConfig{
+		Enabled:                 true,
+		PointsToCurrencyRatio:   100,
+		MaxRedemptionPercentage: 100,
+		EarnRate:                1,
+	}
+	return cfg
+}
+
+type slowObserver struct {
+	mu     sync.Mutex
+	events []observer.EventType
+}
+
+func (o *slowObserver) Notify(ctx context.Context, event observer.Event) error {
+	time.Sleep(20 * time.Millisecond)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event.Type)
+	return nil
+}
+
+func (o *slowObserver) GetName() string {
+	return "slow"
+}
+
+func TestCheckoutFacadeCloseWaitsForNotifications(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+	recorder := &slowObserver{}
+	f.subject.AttachFiltered(recorder, observer.EventPaymentSuccess)
+
+	cart := &domain.Cart{ID: "cart-close", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 1)
+	_, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:   "credit_card",
+		PaymentDetails:  testCard,
+		PaymentStrategy: "instant",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, f.facade.Close())
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, []observer.EventType{observer.EventPaymentSuccess}, recorder.events)
+}
+
+func TestCheckoutFacadeQuote(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	cart := &domain.Cart{ID: "cart-quote", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 2)
+
+	t.Run("Quotes In The Requested Currency Without Side Effects", func(t *testing.T) {
+		result, err := f.facade.Quote(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:     "credit_card",
+			EnabledDecorators: []string{"loyalty_points", "fraud_detection"},
+			UseLoyaltyPoints:  1000,
+			Currency:          "eur",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", result.Currency)
+		assert.InDelta(t, 90.00, result.Amount, 1e-9)
+		assert.NotContains(t, result.AppliedDecorators, "fraud_detection")
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, product.Stock)
+
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10000, customer.LoyaltyPoints)
+
+		transactions, err := f.repo.ListTransactionsByCustomer(ctx, f.customer.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, transactions)
+	})
+
+	t.Run("Rejects An Unknown Currency", func(t *testing.T) {
+		_, err := f.facade.Quote(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod: "credit_card",
+			Currency:      "XYZ",
+		})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
+
+func TestCheckoutFacadeFreeOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	cart := &domain.Cart{ID: "cart-free", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 1)
+
+	receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:     "credit_card",
+		PaymentDetails:    testCard,
+		PaymentStrategy:   "instant",
+		EnabledDecorators: []string{"loyalty_points"},
+		UseLoyaltyPoints:  5000,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0.0, receipt.Total)
+	assert.Equal(t, 50.00, receipt.Subtotal)
+	assert.Equal(t, 50, receipt.LoyaltyPoints)
+	assert.Equal(t, true, receipt.PaymentDetails["free_order"])
+
+	transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionStatusCompleted, transaction.Status)
+	assert.Equal(t, true, transaction.Metadata["free_order"])
+
+	product, err := f.repo.GetProduct(ctx, f.product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 9, product.Stock)
+
+	customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5000+50, customer.LoyaltyPoints)
+}
+
+func TestCheckoutFacadeEarnsLoyaltyPoints(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Decorators.LoyaltyPoints.EarnRate = 2
+	f := newCheckoutFixture(t, cfg)
+	startingPoints := f.customer.LoyaltyPoints
+
+	cart := &domain.Cart{ID: "cart-earn", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 1)
+
+	receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:   "credit_card",
+		PaymentDetails:  testCard,
+		PaymentStrategy: "instant",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 100, receipt.LoyaltyPoints)
+
+	transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, transaction.PaymentDetails["loyalty_points_earned"])
+
+	customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, startingPoints+100, customer.LoyaltyPoints)
+}
+
+func TestCheckoutFacadeRefundOrder(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	original := &domain.Transaction{
+		ID:             "tx-refund",
+		CustomerID:     f.customer.ID,
+		Amount:         100.00,
+		Status:         domain.TransactionStatusCompleted,
+		PaymentMethod:  "credit_card",
+		PaymentDetails: map[string]interface{}{"loyalty_points_earned": 100},
+		CreatedAt:      time.Now(),
+	}
+	require.NoError(t, f.repo.CreateTransaction(ctx, original))
+
+	t.Run("Partial Refund", func(t *testing.T) {
+		refund, err := f.facade.RefundOrder(ctx, original.ID, 40.00, domain.RefundReasonDefective)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusRefunded, refund.Status)
+		assert.Equal(t, original.ID, refund.RefundOf())
+		assert.Equal(t, domain.RefundReasonDefective, refund.RefundReason())
+
+		stored, err := f.repo.GetTransaction(ctx, original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusCompleted, stored.Status)
+		assert.Equal(t, 40.00, stored.Metadata["refunded_amount"])
+
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10000-40, customer.LoyaltyPoints)
+	})
+
+	t.Run("Rejects Unknown Reason", func(t *testing.T) {
+		_, err := f.facade.RefundOrder(ctx, original.ID, 10.00, domain.RefundReason("changed-mind"))
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Rejects Amount Above Remaining", func(t *testing.T) {
+		_, err := f.facade.RefundOrder(ctx, original.ID, 60.01, domain.RefundReasonDefective)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Refundable Amount Excludes Earlier Refunds", func(t *testing.T) {
+		remaining, err := f.facade.RefundableAmount(ctx, original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 60.00, remaining)
+	})
+
+	t.Run("Remaining Refund Completes Transaction", func(t *testing.T) {
+		_, err := f.facade.RefundOrder(ctx, original.ID, 60.00, domain.RefundReasonCustomerRemorse)
+		require.NoError(t, err)
+
+		stored, err := f.repo.GetTransaction(ctx, original.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusRefunded, stored.Status)
+		assert.Equal(t, 100.00, stored.Metadata["refunded_amount"])
+
+		_, err = f.facade.RefundOrder(ctx, original.ID, 1.00, domain.RefundReasonDefective)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
+
+func TestCheckoutFacadeRefundReversesCheckout(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Decorators.Cashback = config.CashbackConfig{
+		Enabled:         true,
+		Tier1Threshold:  1000,
+		Tier1Percentage: 10,
+		Tier2Percentage: 10,
+		Payout:          config.CashbackPayoutBalance,
+	}
+
+	checkout := func(t *testing.T, f *checkoutFixture, useCashback float64) *domain.Receipt {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 2)
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:     "credit_card",
+			PaymentDetails:    testCard,
+			PaymentStrategy:   "instant",
+			EnabledDecorators: []string{"cashback"},
+			UseCashback:       useCashback,
+		})
+		require.NoError(t, err)
+		return receipt
+	}
+
+	balance := func(t *testing.T, f *checkoutFixture) float64 {
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		return customer.CashbackBalance
+	}
+
+	t.Run("Full Refund Settles Cashback And Order", func(t *testing.T) {
+		f := newCheckoutFixture(t, cfg)
+		f.customer.CashbackBalance = 10.00
+
+		receipt := checkout(t, f, 10.00)
+		assert.Greater(t, balance(t, f), 0.0, "cashback is paid out")
+
+		remaining, err := f.facade.RefundableAmount(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		_, err = f.facade.RefundOrder(ctx, receipt.TransactionID, remaining/2, domain.RefundReasonDefective)
+		require.NoError(t, err)
+		_, err = f.facade.RefundOrder(ctx, receipt.TransactionID, remaining-money.Round(remaining/2), domain.RefundReasonDefective)
+		require.NoError(t, err)
+
+		assert.Equal(t, 10.00, balance(t, f), "payout clawed back and redeemed cashback restored")
+
+		entries, err := f.repo.ListCashbackEntries(ctx, f.customer.ID)
+		require.NoError(t, err)
+		reasons := []string{}
+		for _, entry := range entries {
+			reasons = append(reasons, entry.Reason)
+		}
+		assert.Contains(t, reasons, domain.CashbackReasonRedeem)
+		assert.Contains(t, reasons, domain.CashbackReasonClawback)
+		assert.Contains(t, reasons, domain.CashbackReasonRestore)
+		assert.Equal(t, 10.00, entries[len(entries)-1].Balance, "the ledger ends at the balance")
+
+		order, err := f.repo.GetOrder(ctx, receipt.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusRefunded, order.Status)
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 8, product.Stock, "defective items are not restocked")
+	})
+
+	t.Run("Refunds Tax Charged At Checkout", func(t *testing.T) {
+		taxed := *cfg
+		taxed.Decorators.Tax = config.TaxConfig{Enabled: true, DefaultRate: 10}
+		f := newCheckoutFixture(t, &taxed)
+
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 2)
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:     "credit_card",
+			PaymentDetails:    testCard,
+			PaymentStrategy:   "instant",
+			EnabledDecorators: []string{"tax"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 110.00, receipt.Total)
+
+		_, err = f.facade.RefundOrder(ctx, receipt.TransactionID, 10.00, domain.RefundReasonCustomerRemorse)
+		require.NoError(t, err)
+
+		remaining, err := f.facade.RefundableAmount(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, 100.00, remaining)
+
+		_, err = f.facade.RefundOrder(ctx, receipt.TransactionID, remaining, domain.RefundReasonCustomerRemorse)
+		require.NoError(t, err)
+
+		stored, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusRefunded, stored.Status)
+		assert.Equal(t, 110.00, stored.Metadata["refunded_amount"])
+	})
+
+	t.Run("Full Refund Restocks The Order", func(t *testing.T) {
+		f := newCheckoutFixture(t, cfg)
+		receipt := checkout(t, f, 0)
+
+		remaining, err := f.facade.RefundableAmount(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		_, err = f.facade.RefundOrder(ctx, receipt.TransactionID, remaining, domain.RefundReasonCustomerRemorse)
+		require.NoError(t, err)
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, product.Stock)
+	})
+
+	t.Run("Concurrent Refunds Never Over-Refund", func(t *testing.T) {
+		f := newCheckoutFixture(t, cfg)
+		receipt := checkout(t, f, 0)
+
+		remaining, err := f.facade.RefundableAmount(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		part := money.Round(remaining / 4)
+
+		var wg sync.WaitGroup
+		var refunded atomic.Int32
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := f.facade.RefundOrder(ctx, receipt.TransactionID, part, domain.RefundReasonDefective); err == nil {
+					refunded.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(4), refunded.Load())
+		stored, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, money.Round(part*4), stored.Metadata["refunded_amount"])
+	})
+}
+
+func TestCheckoutFacadeConfirmCheckout(t *testing.T) {
+	ctx := context.Background()
+	options := domain.CheckoutOptions{PaymentMethod: "credit_card", PaymentDetails: testCard, PaymentStrategy: "instant"}
+
+	setup := func(t *testing.T) (*checkoutFixture, *domain.Cart, *CheckoutQuote) {
+		f := newCheckoutFixture(t, newTestConfig())
+		cart := &domain.Cart{ID: "cart-quote", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 2)
+
+		quote, err := f.facade.PrepareCheckout(ctx, cart, f.customer, options)
+		require.NoError(t, err)
+		assert.Equal(t, 100.00, quote.Total)
+		return f, cart, quote
+	}
+
+	t.Run("Expired Token", func(t *testing.T) {
+		f, cart, quote := setup(t)
+		f.facade.now = func() time.Time { return time.Now().Add(16 * time.Minute) }
+
+		_, err := f.facade.ConfirmCheckout(ctx, quote.Token, cart, f.customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeQuoteExpired))
+		assert.Contains(t, err.Error(), "quote expired, please re-quote")
+	})
+
+	t.Run("Price Drift", func(t *testing.T) {
+		f, cart, quote := setup(t)
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		product.Price = 55.00
+		require.NoError(t, f.repo.UpdateProduct(ctx, product))
+
+		_, err = f.facade.ConfirmCheckout(ctx, quote.Token, cart, f.customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeConflict))
+
+		stored, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, stored.Stock)
+	})
+
+	t.Run("Forged Token", func(t *testing.T) {
+		f, cart, quote := setup(t)
+
+		forged, err := signQuoteToken([]byte("other-secret"), QuoteClaims{
+			CartID:     cart.ID,
+			CustomerID: f.customer.ID,
+			Lines:      quoteLines(cart),
+			Total:      1.00,
+			IssuedAt:   time.Now(),
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, quote.Token, forged)
+
+		_, err = f.facade.ConfirmCheckout(ctx, forged, cart, f.customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeUnauthorized))
+	})
+
+	t.Run("Feature Disabled", func(t *testing.T) {
+		f, cart, quote := setup(t)
+		f.facade.config.Features = config.FeatureFlags{}
+
+		_, err := f.facade.ConfirmCheckout(ctx, quote.Token, cart, f.customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFeatureDisabled))
+
+		stored, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, stored.Stock)
+	})
+}
+
+func TestCheckoutFacadePartialCapture(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Decorators.Cashback = config.CashbackConfig{
+		Enabled:         true,
+		Tier1Threshold:  1000,
+		Tier1Percentage: 10,
+		Tier2Percentage: 10,
+		Payout:          config.CashbackPayoutBalance,
+	}
+	f := newCheckoutFixture(t, cfg)
+
+	cart := &domain.Cart{ID: "cart-authorize", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 2)
+
+	receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:     "credit_card",
+		PaymentDetails:    testCard,
+		PaymentStrategy:   "authorize",
+		EnabledDecorators: []string{"cashback"},
+	})
+	require.NoError(t, err)
+
+	authorization, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionStatusAuthorized, authorization.Status)
+	assert.Equal(t, true, authorization.PaymentDetails["authorization_hold"])
+
+	customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10000, customer.LoyaltyPoints, "points wait for the capture")
+	assert.Equal(t, 0.0, customer.CashbackBalance, "cashback waits for the capture")
+
+	_, err = f.facade.RefundOrder(ctx, authorization.ID, 10.00, domain.RefundReasonDefective)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation), "uncaptured authorization cannot be refunded")
+
+	captured, err := f.facade.CaptureTransaction(ctx, authorization.ID, 80.00)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionStatusCompleted, captured.Status)
+	assert.Equal(t, 100.00, captured.Metadata["authorized_amount"])
+	assert.Equal(t, 80.00, captured.Metadata["captured_amount"])
+	assert.Equal(t, 20.00, captured.Metadata["released_amount"])
+
+	customer, err = f.repo.GetCustomer(ctx, f.customer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10000+80, customer.LoyaltyPoints, "points are earned on the captured amount")
+	assert.Equal(t, 8.00, customer.CashbackBalance, "cashback is paid on the captured amount")
+
+	_, err = f.facade.CaptureTransaction(ctx, authorization.ID, 80.00)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation), "an authorization is captured once")
+
+	_, err = f.facade.RefundOrder(ctx, authorization.ID, 30.00, domain.RefundReasonDefective)
+	require.NoError(t, err)
+
+	_, err = f.facade.RefundOrder(ctx, authorization.ID, 50.01, domain.RefundReasonDefective)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation), "refund max is based on the captured amount")
+
+	_, err = f.facade.RefundOrder(ctx, authorization.ID, 50.00, domain.RefundReasonDefective)
+	require.NoError(t, err)
+
+	stored, err := f.repo.GetTransaction(ctx, authorization.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TransactionStatusRefunded, stored.Status)
+	assert.Equal(t, 80.00, stored.Metadata["refunded_amount"])
+}
+
+func TestCheckoutFacadeCashbackPayout(t *testing.T) {
+	ctx := context.Background()
+
+	newCashbackConfig := func(payout string) *config.Config {
+		cfg := newTestConfig()
+		cfg.Decorators.Cashback = config.CashbackConfig{
+			Enabled:         true,
+			Tier1Threshold:  1000,
+			Tier1Percentage: 10,
+			Tier2Percentage: 10,
+			Payout:          payout,
+			PointsPerUnit:   100,
+		}
+		return cfg
+	}
+
+	checkout := func(t *testing.T, f *checkoutFixture) *domain.Transaction {
+		cart := &domain.Cart{ID: "cart-cashback", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:     "credit_card",
+			PaymentDetails:    testCard,
+			PaymentStrategy:   "instant",
+			EnabledDecorators: []string{"cashback"},
+		})
+		require.NoError(t, err)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		return transaction
+	}
+
+	t.Run("Balance", func(t *testing.T) {
+		f := newCheckoutFixture(t, newCashbackConfig(config.CashbackPayoutBalance))
+		transaction := checkout(t, f)
+
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 5.00, customer.CashbackBalance)
+		assert.Equal(t, config.CashbackPayoutBalance, transaction.Metadata["cashback_payout"])
+		assert.Equal(t, 5.00, transaction.Metadata["cashback_credited"])
+
+		entries, err := f.repo.ListCashbackEntries(ctx, f.customer.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, domain.CashbackReasonPayout, entries[0].Reason)
+		assert.Equal(t, transaction.ID, entries[0].TransactionID)
+		assert.Equal(t, 5.00, entries[0].Delta)
+		assert.Equal(t, 5.00, entries[0].Balance)
+	})
+
+	t.Run("Loyalty Points", func(t *testing.T) {
+		f := newCheckoutFixture(t, newCashbackConfig(config.CashbackPayoutLoyaltyPoints))
+		transaction := checkout(t, f)
+
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, customer.CashbackBalance)
+		// 50 points earned by the order itself, 500 paid out as cashback.
+		assert.Equal(t, 10000+50+500, customer.LoyaltyPoints)
+		assert.Equal(t, 500, transaction.Metadata["cashback_points"])
+	})
+}
+
+func TestCheckoutFacadeIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Checkout.IdempotencyTTL = 15 * time.Minute
+	f := newCheckoutFixture(t, cfg)
+
+	checkout := func(key string) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: "cart-idempotent", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+			IdempotencyKey:  key,
+		})
+	}
+
+	t.Run("Repeated Checkout Returns Original Receipt", func(t *testing.T) {
+		first, err := checkout("key-1")
+		require.NoError(t, err)
+
+		second, err := checkout("key-1")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Equal(t, first.TransactionID, second.TransactionID)
+		assert.Equal(t, first.Total, second.Total)
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 9, product.Stock, "inventory is only reserved once")
+
+		transactions, err := f.repo.ListTransactionsByCustomer(ctx, f.customer.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 1)
+	})
+
+	t.Run("Pending Transaction Is Rejected", func(t *testing.T) {
+		require.NoError(t, f.repo.CreateTransaction(ctx, &domain.Transaction{
+			ID:         "tx-in-flight",
+			CustomerID: f.customer.ID,
+			Amount:     50.00,
+			Status:     domain.TransactionStatusPending,
+			Metadata:   map[string]interface{}{"idempotency_key": "key-2"},
+			CreatedAt:  time.Now(),
+		}))
+
+		_, err := checkout("key-2")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeAlreadyExists))
+	})
+
+	t.Run("Stale Pending Transaction Is Abandoned", func(t *testing.T) {
+		require.NoError(t, f.repo.CreateTransaction(ctx, &domain.Transaction{
+			ID:         "tx-crashed",
+			CustomerID: f.customer.ID,
+			Amount:     50.00,
+			Status:     domain.TransactionStatusPending,
+			Metadata:   map[string]interface{}{"idempotency_key": "key-3"},
+			CreatedAt:  time.Now().Add(-time.Hour),
+		}))
+
+		receipt, err := checkout("key-3")
+		require.NoError(t, err)
+		assert.NotEqual(t, "tx-crashed", receipt.TransactionID)
+
+		crashed, err := f.repo.GetTransaction(ctx, "tx-crashed")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusFailed, crashed.Status)
+
+		replayed, err := checkout("key-3")
+		require.NoError(t, err)
+		assert.Equal(t, receipt.ID, replayed.ID)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.NotContains(t, transaction.Metadata, "receipt")
+	})
+}
+
+func TestCheckoutFacadeCashbackRedemption(t *testing.T) {
+	ctx := context.Background()
+
+	checkout := func(f *checkoutFixture, useCashback float64) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: "cart-redeem", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+			UseCashback:     useCashback,
+		})
+	}
+
+	balance := func(t *testing.T, f *checkoutFixture) float64 {
+		customer, err := f.repo.GetCustomer(ctx, f.customer.ID)
+		require.NoError(t, err)
+		return customer.CashbackBalance
+	}
+
+	t.Run("Partial Redemption", func(t *testing.T) {
+		f := newCheckoutFixture(t, newTestConfig())
+		f.customer.CashbackBalance = 30.00
+
+		receipt, err := checkout(f, 20.00)
+		require.NoError(t, err)
+		assert.Equal(t, 20.00, receipt.CashbackRedeemed)
+		assert.Equal(t, 30.00, receipt.Total)
+		assert.Equal(t, 10.00, balance(t, f))
+	})
+
+	t.Run("Capped At Order Total", func(t *testing.T) {
+		f := newCheckoutFixture(t, newTestConfig())
+		f.customer.CashbackBalance = 80.00
+
+		receipt, err := checkout(f, 80.00)
+		require.NoError(t, err)
+		assert.Equal(t, 50.00, receipt.CashbackRedeemed)
+		assert.Equal(t, 0.0, receipt.Total)
+		assert.Equal(t, 30.00, balance(t, f), "unspent cashback is returned to the balance")
+	})
+
+	t.Run("Insufficient Balance", func(t *testing.T) {
+		f := newCheckoutFixture(t, newTestConfig())
+		f.customer.CashbackBalance = 5.00
+
+		_, err := checkout(f, 10.00)
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation))
+		assert.Equal(t, 5.00, balance(t, f))
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, product.Stock)
+	})
+}
+
+func TestCheckoutFacadeCanonicalReceipt(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := newTestConfig()
+	cfg.Decorators.Tax = config.TaxConfig{Enabled: true, DefaultRate: 10}
+	cfg.Decorators.Cashback = config.CashbackConfig{
+		Enabled:         true,
+		Tier1Threshold:  1000,
+		Tier1Percentage: 2,
+		Tier2Percentage: 5,
+	}
+
+	second := &domain.Product{ID: "prod-cable", Name: "Cable", SKU: "CAB-001", Price: 12.50, Stock: 10}
+
+	checkout := func(t *testing.T, reversed bool) *domain.Receipt {
+		f := newCheckoutFixture(t, cfg)
+		require.NoError(t, f.repo.CreateProduct(ctx, second))
+
+		ids := 0
+		f.facade.newID = func() string {
+			ids++
+			return fmt.Sprintf("id-%d", ids)
+		}
+		f.facade.now = func() time.Time {
+			return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		}
+
+		cart := &domain.Cart{ID: "cart-canonical", CustomerID: f.customer.ID}
+		if reversed {
+			cart.AddItem(*second, 2)
+			cart.AddItem(*f.product, 1)
+		} else {
+			cart.AddItem(*f.product, 1)
+			cart.AddItem(*second, 2)
+		}
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:     "credit_card",
+			PaymentDetails:    testCard,
+			PaymentStrategy:   "instant",
+			EnabledDecorators: []string{"cashback", "tax"},
+		})
+		require.NoError(t, err)
+		return receipt
+	}
+
+	first := checkout(t, false)
+	reordered := checkout(t, true)
+
+	assert.Equal(t, first.ReceiptHash(), reordered.ReceiptHash())
+	assert.Equal(t, first.Canonical(), reordered.Canonical())
+
+	changed := *first
+	changed.Total += 0.01
+	assert.NotEqual(t, first.ReceiptHash(), changed.ReceiptHash())
+
+	actual, err := json.MarshalIndent(first.Canonical(), "", "  ")
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "canonical_receipt.golden.json")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(golden, append(actual, '\n'), 0644))
+	}
+
+	expected, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+type timestampObserver struct {
+	mu         sync.Mutex
+	timestamps []string
+}
+
+func (o *timestampObserver) Notify(ctx context.Context, event observer.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.timestamps = append(o.timestamps, event.Timestamp)
+	return nil
+}
+
+func (o *timestampObserver) GetName() string {
+	return "timestamps"
+}
+
+func TestCheckoutFacadeInjectedClock(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+	recorder := &timestampObserver{}
+	f.subject.AttachFiltered(recorder,
+		observer.EventPaymentStarted, observer.EventPaymentSuccess,
+		observer.EventPaymentFailed, observer.EventRefundIssued)
+
+	fixed := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	f.facade.now = func() time.Time { return fixed }
+	ids := 0
+	f.facade.newID = func() string {
+		ids++
+		return fmt.Sprintf("id-%d", ids)
+	}
+
+	cart := &domain.Cart{ID: "cart-clock", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 1)
+	receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:   "credit_card",
+		PaymentDetails:  testCard,
+		PaymentStrategy: "authorize",
+	})
+	require.NoError(t, err)
+
+	captured, err := f.facade.CaptureTransaction(ctx, receipt.TransactionID, 50.00)
+	require.NoError(t, err)
+	assert.True(t, fixed.Equal(captured.ProcessedAt))
+
+	before := ids
+	refund, err := f.facade.RefundOrder(ctx, receipt.TransactionID, 10.00, domain.RefundReasonDefective)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("id-%d", before+1), refund.ID)
+	assert.True(t, fixed.Equal(refund.CreatedAt))
+	assert.True(t, fixed.Equal(refund.ProcessedAt))
+
+	declined := testCard
+	declined.CardNumber = "4532015112830367"
+	failedCart := &domain.Cart{ID: "cart-clock-failed", CustomerID: f.customer.ID}
+	failedCart.AddItem(*f.product, 1)
+	_, err = f.facade.ProcessOrder(ctx, failedCart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:   "credit_card",
+		PaymentDetails:  declined,
+		PaymentStrategy: "instant",
+	})
+	require.Error(t, err)
+
+	require.NoError(t, f.facade.Close())
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Len(t, recorder.timestamps, 5)
+	for _, timestamp := range recorder.timestamps {
+		assert.Equal(t, fixed.Format(time.RFC3339), timestamp)
+	}
+}
+
+func TestCheckoutFacadeSpendingLimit(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Checkout.SpendingLimit = config.SpendingLimitConfig{Amount: 150, Window: 30 * 24 * time.Hour}
+	f := newCheckoutFixture(t, cfg)
+
+	require.NoError(t, f.repo.CreateTransaction(ctx, &domain.Transaction{
+		ID:         "tx-previous",
+		CustomerID: f.customer.ID,
+		Amount:     100,
+		Status:     domain.TransactionStatusCompleted,
+		CreatedAt:  time.Now().Add(-24 * time.Hour),
+	}))
+	require.NoError(t, f.repo.CreateTransaction(ctx, &domain.Transaction{
+		ID:         "tx-outside-window",
+		CustomerID: f.customer.ID,
+		Amount:     500,
+		Status:     domain.TransactionStatusCompleted,
+		CreatedAt:  time.Now().Add(-60 * 24 * time.Hour),
+	}))
+
+	checkout := func(customer *domain.Customer) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: customer.ID}
+		cart.AddItem(*f.product, 1)
+		return f.facade.ProcessOrder(ctx, cart, customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+		})
+	}
+
+	t.Run("Allows Order Reaching The Cap", func(t *testing.T) {
+		receipt, err := checkout(f.customer)
+		require.NoError(t, err)
+		assert.Equal(t, 50.00, receipt.Total)
+	})
+
+	t.Run("Rejects Order Past The Cap", func(t *testing.T) {
+		_, err := checkout(f.customer)
+		require.Error(t, err)
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation))
+		assert.Contains(t, err.Error(), "exceeds spending limit")
+	})
+
+	t.Run("Customer Override Takes Precedence", func(t *testing.T) {
+		customer := *f.customer
+		customer.SpendingLimit = 200
+		_, err := checkout(&customer)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCheckoutFacadePaymentLimits(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Payment.CreditCard.MaxAmount = 20000
+	f := newCheckoutFixture(t, cfg)
+
+	product := &domain.Product{ID: "prod-expensive", Name: "Server", SKU: "SRV-001", Price: 12000, Stock: 5}
+	require.NoError(t, f.repo.CreateProduct(ctx, product))
+
+	checkout := func(quantity int) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*product, quantity)
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+		})
+	}
+
+	t.Run("Charges Above The Built-in Limit", func(t *testing.T) {
+		receipt, err := checkout(1)
+		require.NoError(t, err)
+		assert.Equal(t, 12000.00, receipt.Total)
+	})
+
+	t.Run("Rejects Above The Configured Limit", func(t *testing.T) {
+		_, err := checkout(2)
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation))
+		assert.ErrorContains(t, err, "exceeds maximum 20000.00")
+	})
+}
+
+func TestCheckoutFacadeEscrow(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Payment.Escrow = config.EscrowConfig{MinAmount: 100, MaxAmount: 50000}
+	f := newCheckoutFixture(t, cfg)
+
+	checkout := func(quantity int) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, quantity)
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "escrow",
+		})
+	}
+
+	t.Run("Stores The Transaction As Held", func(t *testing.T) {
+		receipt, err := checkout(3)
+		require.NoError(t, err)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusHeld, transaction.Status)
+		assert.Equal(t, "escrow", transaction.Strategy)
+		assert.Equal(t, domain.EscrowStatusHeld, transaction.PaymentDetails["escrow_status"])
+	})
+
+	t.Run("Rejects Orders Below The Escrow Minimum", func(t *testing.T) {
+		_, err := checkout(1)
+		assert.ErrorContains(t, err, "below minimum 100.00")
+	})
+}
+
+func TestCheckoutFacadePaymentDetails(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	checkout := func(details domain.PaymentDetails) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentStrategy: "instant",
+			PaymentDetails:  details,
+		})
+	}
+
+	t.Run("Charges The Entered Card", func(t *testing.T) {
+		card := testCard
+		card.CardNumber = "5425233430109903"
+		card.CardHolder = "Jane Roe"
+
+		receipt, err := checkout(card)
+		require.NoError(t, err)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Roe", transaction.PaymentDetails["card_holder"])
+		assert.Equal(t, "****9903", transaction.PaymentDetails["last_4_digits"])
+	})
+
+	t.Run("Rejects Missing Details", func(t *testing.T) {
+		_, err := checkout(domain.PaymentDetails{})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation))
+		assert.ErrorContains(t, err, "card number is required")
+	})
+
+	t.Run("Rejects An Invalid Card", func(t *testing.T) {
+		card := testCard
+		card.CardNumber = "4532015112830367"
+
+		_, err := checkout(card)
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeInvalidPayment))
+	})
+}
+
+func TestCheckoutFacadeGiftCard(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	checkout := func(details domain.PaymentDetails) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+		return f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "gift_card",
+			PaymentStrategy: "instant",
+			PaymentDetails:  details,
+		})
+	}
+
+	t.Run("Charges The Gift Card", func(t *testing.T) {
+		receipt, err := checkout(domain.PaymentDetails{GiftCardCode: "abcd-efgh-jklm-1234", GiftCardBalance: 80})
+		require.NoError(t, err)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, "gift_card", transaction.PaymentMethod)
+		assert.Equal(t, "************1234", transaction.PaymentDetails["gift_card"])
+		assert.Equal(t, 30.00, transaction.PaymentDetails["remaining_balance"])
+	})
+
+	t.Run("Rejects An Insufficient Balance", func(t *testing.T) {
+		_, err := checkout(domain.PaymentDetails{GiftCardCode: "ABCD-EFGH-JKLM-1234", GiftCardBalance: 20})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeInsufficientFunds))
+	})
+
+	t.Run("Rejects A Missing Code", func(t *testing.T) {
+		_, err := checkout(domain.PaymentDetails{GiftCardBalance: 80})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation))
+		assert.ErrorContains(t, err, "gift card code is required")
+	})
+}
+
+func TestCheckoutFacadeSavedPaymentMethod(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+	customers := service.NewCustomerService(f.repo, nil)
+
+	saved, err := customers.AddPaymentMethod(ctx, f.customer.ID, "paypal", domain.PaymentDetails{
+		PayPalEmail:    "saved@example.com",
+		PayPalPassword: "secret-password",
+	})
+	require.NoError(t, err)
+
+	checkout := func(customer *domain.Customer, options domain.CheckoutOptions) (*domain.Receipt, error) {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: customer.ID}
+		cart.AddItem(*f.product, 1)
+		options.PaymentStrategy = "instant"
+		return f.facade.ProcessOrder(ctx, cart, customer, options)
+	}
+
+	t.Run("Charges The Saved Method", func(t *testing.T) {
+		receipt, err := checkout(f.customer, domain.CheckoutOptions{SavedMethodID: saved.ID})
+		require.NoError(t, err)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, "paypal", transaction.PaymentMethod)
+		assert.Equal(t, "saved@example.com", transaction.PaymentDetails["paypal_email"])
+		assert.Equal(t, true, transaction.PaymentDetails["saved_method"])
+	})
+
+	t.Run("Rejects A Conflicting Payment Method", func(t *testing.T) {
+		_, err := checkout(f.customer, domain.CheckoutOptions{PaymentMethod: "credit_card", SavedMethodID: saved.ID})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Rejects Another Customer's Method", func(t *testing.T) {
+		other := &domain.Customer{ID: "cust-other", Email: "other@example.com", Name: "Other"}
+		require.NoError(t, f.repo.CreateCustomer(ctx, other))
+
+		_, err := checkout(other, domain.CheckoutOptions{SavedMethodID: saved.ID})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	})
+}
+
+func TestCheckoutFacadeInstallmentsChargeSavedMethod(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+	customers := service.NewCustomerService(f.repo, nil)
+	schedules := service.NewScheduleService(f.repo)
+	schedules.SetPaymentProvider(f.facade.NewPayment)
+
+	saved, err := customers.AddPaymentMethod(ctx, f.customer.ID, "paypal", domain.PaymentDetails{
+		PayPalEmail:    "saved@example.com",
+		PayPalPassword: "secret-password",
+	})
+	require.NoError(t, err)
+
+	deferredCheckout := func(t *testing.T, options domain.CheckoutOptions) *domain.PaymentSchedule {
+		cart := &domain.Cart{ID: domain.NewID(), CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 2)
+		options.PaymentStrategy = "deferred"
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, options)
+		require.NoError(t, err)
+
+		all, err := schedules.ListSchedules(ctx, 10, 0)
+		require.NoError(t, err)
+		for _, schedule := range all {
+			if schedule.TransactionID == receipt.TransactionID {
+				return schedule
+			}
+		}
+		t.Fatalf("no schedule saved for transaction %s", receipt.TransactionID)
+		return nil
+	}
+
+	t.Run("Charges The Saved Method", func(t *testing.T) {
+		schedule := deferredCheckout(t, domain.CheckoutOptions{SavedMethodID: saved.ID})
+		assert.Equal(t, saved.ID, schedule.SavedMethodID)
+
+		updated, err := schedules.ChargeNextInstallment(ctx, schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.PaidCount())
+
+		transaction, err := f.repo.GetTransaction(ctx, updated.Payments[1].TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, "saved@example.com", transaction.PaymentDetails["paypal_email"])
+		assert.Equal(t, true, transaction.PaymentDetails["saved_method"])
+	})
+
+	t.Run("Fails Without A Saved Method", func(t *testing.T) {
+		schedule := deferredCheckout(t, domain.CheckoutOptions{
+			PaymentMethod:  "credit_card",
+			PaymentDetails: testCard,
+		})
+		assert.Empty(t, schedule.SavedMethodID)
+
+		_, err := schedules.ChargeNextInstallment(ctx, schedule.ID)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+
+		unchanged, err := schedules.GetSchedule(ctx, schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, unchanged.PaidCount())
+	})
+}
+
+// flakyPayment fails with err for its first failures calls.
+type flakyPayment struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (p *flakyPayment) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, p.err
+	}
+	return &payment.PaymentResult{Success: true, TransactionID: "tx-flaky", Amount: request.Amount, Metadata: map[string]interface{}{}}, nil
+}
+
+func (p *flakyPayment) GetType() string { return "credit_card" }
+
+func (p *flakyPayment) GetDetails() map[string]interface{} { return map[string]interface{}{} }
+
+func TestCheckoutFacadeRetryBackoff(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Payment.RetryAttempts = 4
+	cfg.Payment.RetryDelay = 100 * time.Millisecond
+	cfg.Payment.RetryMaxDelay = 300 * time.Millisecond
+	f := newCheckoutFixture(t, cfg)
+
+	var delays []time.Duration
+	f.facade.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	instant := strategy.NewInstantPaymentStrategy(1, 10000)
+
+	t.Run("Backs Off Exponentially Up To The Cap", func(t *testing.T) {
+		delays = nil
+		flaky := &flakyPayment{failures: 4, err: errors.NewPaymentError("gateway unavailable")}
+
+		result, err := f.facade.executeWithRetry(ctx, instant, flaky, payment.PaymentRequest{Amount: 50})
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, 5, flaky.calls)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}, delays)
+	})
+
+	t.Run("Stops On Fraud", func(t *testing.T) {
+		delays = nil
+		flaky := &flakyPayment{failures: 4, err: errors.NewFraudDetectedError("velocity check failed")}
+
+		_, err := f.facade.executeWithRetry(ctx, instant, flaky, payment.PaymentRequest{Amount: 50})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeFraudDetected))
+		assert.Equal(t, 1, flaky.calls)
+		assert.Empty(t, delays)
+	})
+
+	t.Run("Cancelled Context Stops Retrying", func(t *testing.T) {
+		f.facade.sleep = sleepContext
+		f.facade.config.Payment.RetryDelay = time.Hour
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		flaky := &flakyPayment{failures: 4, err: errors.NewPaymentError("gateway unavailable")}
+
+		start := time.Now()
+		_, err := f.facade.executeWithRetry(cancelled, instant, flaky, payment.PaymentRequest{Amount: 50})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeTimeout))
+		assert.Equal(t, 1, flaky.calls)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Jitter Stays In The Upper Half", func(t *testing.T) {
+		f.facade.config.Payment.RetryDelay = 100 * time.Millisecond
+		f.facade.config.Payment.RetryJitter = true
+		for attempt := 1; attempt <= 4; attempt++ {
+			delay := f.facade.retryDelay(attempt)
+			assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+			assert.LessOrEqual(t, delay, 300*time.Millisecond)
+		}
+	})
+}
+
+func TestCheckoutFacadeConcurrentOrders(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	const orders = 12
+	errs := make([]error, orders)
+
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cart := &domain.Cart{ID: fmt.Sprintf("cart-concurrent-%d", i), CustomerID: f.customer.ID}
+			cart.AddItem(*f.product, 1)
+			_, errs[i] = f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+				PaymentMethod:   "credit_card",
+				PaymentDetails:  testCard,
+				PaymentStrategy: "instant",
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeInventoryError), "unexpected error: %v", err)
+	}
+	assert.Equal(t, 10, succeeded)
+
+	product, err := f.repo.GetProduct(ctx, f.product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, product.Stock)
+}
+
+func TestCheckoutFacadeEvenSplit(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	t.Run("Charges Equal Parts", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-split", CustomerID: f.customer.ID}
+		cart.AddItem(domain.Product{ID: f.product.ID, Name: f.product.Name, Price: 10.00, Stock: 10}, 1)
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "split",
+			SplitParts:      3,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "split", receipt.Strategy)
+		assert.Equal(t, "credit_card", receipt.PaymentMethod)
+
+		transaction, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		details := transaction.PaymentDetails["split_details"].([]map[string]interface{})
+		require.Len(t, details, 3)
+		assert.InDelta(t, 3.34, details[0]["amount"].(float64), 1e-9)
+		assert.InDelta(t, 3.33, details[1]["amount"].(float64), 1e-9)
+		assert.InDelta(t, 3.33, details[2]["amount"].(float64), 1e-9)
+	})
+
+	t.Run("Requires Split Strategy", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-split-instant", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		_, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+			SplitParts:      2,
+		})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeValidation), "unexpected error: %v", err)
+	})
+}
+
+func TestCheckoutFacadeCurrency(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	t.Run("Receipt Shows Order Currency", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-eur", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+			Currency:        "eur",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", receipt.Currency)
+
+		rate := 580.0 / 538.0
+		assert.InDelta(t, rate, receipt.ExchangeRate, 1e-9)
+		assert.Equal(t, 53.90, receipt.BaseAmount)
+		assert.Equal(t, "USD", receipt.BaseCurrency)
+
+		stored, err := f.repo.GetTransaction(ctx, receipt.TransactionID)
+		require.NoError(t, err)
+		assert.InDelta(t, rate, stored.ExchangeRate, 1e-9)
+		assert.Equal(t, 50.00, stored.DisplayAmount)
+		assert.Equal(t, "EUR", stored.DisplayCurrency)
+		assert.Equal(t, 53.90, stored.BaseAmount)
+
+		refund, err := f.facade.RefundOrder(ctx, receipt.TransactionID, 10, domain.RefundReasonDefective)
+		require.NoError(t, err)
+		assert.Equal(t, stored.ExchangeRate, refund.ExchangeRate)
+		assert.Equal(t, "EUR", refund.DisplayCurrency)
+		assert.Equal(t, 10.78, refund.BaseAmount)
+	})
+
+	t.Run("Rejects Unsupported Currency", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-xyz", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+
+		_, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+			Currency:        "XYZ",
+		})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation), "unexpected error: %v", err)
+
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 9, product.Stock)
+	})
+}
+
+func TestCheckoutFacadeAutoReprice(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Checkout.AutoReprice = true
+	f := newCheckoutFixture(t, cfg)
+
+	setPrice := func(t *testing.T, price float64) {
+		product, err := f.repo.GetProduct(ctx, f.product.ID)
+		require.NoError(t, err)
+		product.Price = price
+		require.NoError(t, f.repo.UpdateProduct(ctx, product))
+	}
+
+	t.Run("Price Increase Needs Confirmation", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-raised", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+		setPrice(t, 60.00)
+
+		options := domain.CheckoutOptions{PaymentMethod: "credit_card", PaymentDetails: testCard, PaymentStrategy: "instant"}
+		_, err := f.facade.ProcessOrder(ctx, cart, f.customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodePriceChanged), "unexpected error: %v", err)
+
+		changes, ok := errors.Detail(err, "changes")
+		require.True(t, ok)
+		assert.Equal(t, 10.00, changes.([]domain.PriceChange)[0].Delta)
+
+		options.AcceptPriceChanges = true
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, options)
+		require.NoError(t, err)
+		assert.Equal(t, 60.00, receipt.Total)
+	})
+
+	t.Run("Price Drop Applies Silently", func(t *testing.T) {
+		cart := &domain.Cart{ID: "cart-dropped", CustomerID: f.customer.ID}
+		cart.AddItem(*f.product, 1)
+		setPrice(t, 40.00)
+
+		receipt, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+			PaymentMethod:   "credit_card",
+			PaymentDetails:  testCard,
+			PaymentStrategy: "instant",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 40.00, receipt.Total)
+	})
+}
+
+func TestCheckoutFacadeReceipt(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Decorators.Tax = config.TaxConfig{Enabled: true, DefaultRate: 10}
+	f := newCheckoutFixture(t, cfg)
+
+	cart := &domain.Cart{ID: "cart-receipt", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 2)
+
+	issued, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:     "credit_card",
+		PaymentDetails:    testCard,
+		PaymentStrategy:   "instant",
+		EnabledDecorators: []string{"tax"},
+	})
+	require.NoError(t, err)
+
+	t.Run("Returns The Stored Receipt", func(t *testing.T) {
+		stored, err := f.facade.Receipt(ctx, issued.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, issued.ID, stored.ID)
+		assert.Equal(t, issued.ReceiptHash(), stored.ReceiptHash())
+	})
+
+	t.Run("Rebuilds A Receipt From The Transaction", func(t *testing.T) {
+		transaction, err := f.repo.GetTransaction(ctx, issued.TransactionID)
+		require.NoError(t, err)
+
+		rebuilt, err := f.facade.rebuildReceipt(ctx, transaction)
+		require.NoError(t, err)
+		assert.Equal(t, issued.OrderID, rebuilt.OrderID)
+		assert.Equal(t, issued.CustomerName, rebuilt.CustomerName)
+		assert.Equal(t, issued.Canonical().Items, rebuilt.Canonical().Items)
+		assert.InDelta(t, issued.Subtotal, rebuilt.Subtotal, 0.001)
+		assert.InDelta(t, issued.Tax, rebuilt.Tax, 0.001)
+		assert.InDelta(t, issued.Total, rebuilt.Total, 0.001)
+	})
+
+	t.Run("Unknown Transaction", func(t *testing.T) {
+		_, err := f.facade.Receipt(ctx, "missing")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	})
+}
+
+func TestCheckoutFacadeHandleErrorKeepsCause(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(t, newTestConfig())
+
+	t.Run("Surfaces The Innermost Code", func(t *testing.T) {
+		cause := errors.NewFraudDetectedError("velocity check failed").WithDetails("risk_score", 95)
+		strategyErr := errors.Wrap(cause, errors.ErrCodePaymentFailed, "instant payment processing failed")
+
+		err := f.facade.handleError(ctx, &domain.Transaction{ID: "txn-1"}, strategyErr, "payment processing failed")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+		assert.Equal(t, 95, errors.AllDetails(err)["risk_score"])
+	})
+
+	t.Run("Plain Errors Fail The Payment", func(t *testing.T) {
+		err := f.facade.handleError(ctx, &domain.Transaction{ID: "txn-2"}, fmt.Errorf("gateway closed"), "payment processing failed")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodePaymentFailed))
+	})
+}
+
+type slowOrderRepository struct {
+	repository.Repository
+	delay time.Duration
+}
+
+func (r *slowOrderRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	select {
+	case <-time.After(r.delay):
+		return r.Repository.CreateOrder(ctx, order)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestCheckoutFacadeTimeout(t *testing.T) {
+	ctx := context.Background()
+	cfg := newTestConfig()
+	cfg.Checkout.Timeout = 50 * time.Millisecond
+	f := newCheckoutFixture(t, cfg)
+	f.facade.orderService = service.NewOrderService(&slowOrderRepository{Repository: f.repo, delay: 5 * time.Second})
+
+	cart := &domain.Cart{ID: "cart-timeout", CustomerID: f.customer.ID}
+	cart.AddItem(*f.product, 2)
+
+	started := time.Now()
+	_, err := f.facade.ProcessOrder(ctx, cart, f.customer, domain.CheckoutOptions{
+		PaymentMethod:   "credit_card",
+		PaymentDetails:  testCard,
+		PaymentStrategy: "instant",
+	})
+	require.Error(t, err)
+	assert.True(t, errors.HasErrorCode(err, errors.ErrCodeTimeout))
+	assert.Less(t, time.Since(started), time.Second)
+
+	product, err := f.repo.GetProduct(ctx, f.product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, product.Stock)
+
+	reserved, err := f.repo.ReservedQuantity(ctx, f.product.ID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, reserved)
+}
@@ -0,0 +1,74 @@
+package facade
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+type QuoteLine struct {
+	ProductID string  `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+type QuoteClaims struct {
+	CartID     string      `json:"cart_id"`
+	CustomerID string      `json:"customer_id"`
+	Lines      []QuoteLine `json:"lines"`
+	Total      float64     `json:"total"`
+	IssuedAt   time.Time   `json:"issued_at"`
+}
+
+func quoteLines(cart *domain.Cart) []QuoteLine {
+	lines := make([]QuoteLine, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lines = append(lines, QuoteLine{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+		})
+	}
+	return lines
+}
+
+func signQuoteToken(secret []byte, claims QuoteClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodeInternalError, "failed to encode quote token")
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + quoteSignature(secret, encoded), nil
+}
+
+func parseQuoteToken(secret []byte, token string) (*QuoteClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(quoteSignature(secret, encoded))) {
+		return nil, errors.NewUnauthorizedError("invalid quote token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError("invalid quote token")
+	}
+
+	var claims QuoteClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.NewUnauthorizedError("invalid quote token")
+	}
+
+	return &claims, nil
+}
+
+func quoteSignature(secret []byte, encoded string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -1,7 +1,9 @@
 package factory
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ecommerce/payment-system/config"
@@ -13,17 +15,53 @@ import (
 	"go.uber.org/zap"
 )
 
+var knownDecorators = map[string]bool{
+	"discount":            true,
+	"cashback":            true,
+	"fraud_detection":     true,
+	"tax":                 true,
+	"loyalty_points":      true,
+	"service_fee":         true,
+	"cashback_redemption": true,
+}
+
+// DiscountLookup resolves a discount code to an applicable discount,
+// returning an error for unknown, inactive or expired codes.
+type DiscountLookup interface {
+	GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error)
+}
+
 type DecoratorFactory struct {
-	config *config.Config
+	config       *config.Config
+	discounts    DiscountLookup
+	taxRates     decorator.TaxRateProvider
+	fraudHistory *decorator.TransactionHistory
 }
 
-func NewDecoratorFactory(cfg *config.Config) *DecoratorFactory {
+// NewDecoratorFactory keeps one fraud transaction history for all the chains
+// it builds, so velocity checks span checkouts.
+func NewDecoratorFactory(cfg *config.Config, discounts DiscountLookup) *DecoratorFactory {
 	return &DecoratorFactory{
-		config: cfg,
+		config:       cfg,
+		discounts:    discounts,
+		taxRates:     newTaxRateProvider(cfg.Decorators.Tax),
+		fraudHistory: decorator.NewTransactionHistory(),
+	}
+}
+
+// newTaxRateProvider is built once per factory so the HTTP provider's cache
+// is shared across checkouts.
+func newTaxRateProvider(cfg config.TaxConfig) decorator.TaxRateProvider {
+	if cfg.Provider == "http" {
+		return decorator.NewHTTPTaxRateProvider(cfg.URL, cfg.Timeout, cfg.CacheTTL)
 	}
+	provider := decorator.NewStaticTaxRateProvider(cfg.Rates, cfg.DefaultRate)
+	provider.SetLocalRates(cfg.LocalRates)
+	return provider
 }
 
 func (f *DecoratorFactory) CreateDecoratorChain(
+	ctx context.Context,
 	basePayment payment.Payment,
 	features []string,
 	options domain.CheckoutOptions,
@@ -34,11 +72,17 @@ func (f *DecoratorFactory) CreateDecoratorChain(
 		zap.String("payment_type", basePayment.GetType()),
 	)
 
+	if err := f.ValidateDecorators(features); err != nil {
+		return nil, err
+	}
+
+	ordered := OrderDecorators(features)
 	current := basePayment
 
-	for _, feature := range features {
+	for i := len(ordered) - 1; i >= 0; i-- {
+		feature := ordered[i]
 		var err error
-		current, err = f.createDecorator(feature, current, options, customer)
+		current, err = f.createDecorator(ctx, feature, current, options, customer)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create decorator %s: %w", feature, err)
 		}
@@ -51,6 +95,7 @@ func (f *DecoratorFactory) CreateDecoratorChain(
 }
 
 func (f *DecoratorFactory) createDecorator(
+	ctx context.Context,
 	feature string,
 	wrapped payment.Payment,
 	options domain.CheckoutOptions,
@@ -58,49 +103,96 @@ func (f *DecoratorFactory) createDecorator(
 ) (payment.Payment, error) {
 	switch feature {
 	case "discount":
-		return f.createDiscountDecorator(wrapped, options)
+		return f.createDiscountDecorator(ctx, wrapped, options)
 	case "cashback":
-		return f.createCashbackDecorator(wrapped)
+		return f.createCashbackDecorator(wrapped, customer)
 	case "fraud_detection":
-		return f.createFraudDetectionDecorator(wrapped, customer)
+		return f.createFraudDetectionDecorator(wrapped, options, customer)
 	case "tax":
 		return f.createTaxDecorator(wrapped, customer)
 	case "loyalty_points":
 		return f.createLoyaltyPointsDecorator(wrapped, options, customer)
+	case "service_fee":
+		return f.createServiceFeeDecorator(wrapped)
+	case "cashback_redemption":
+		return f.createCashbackRedemptionDecorator(wrapped, options)
 	default:
 		return nil, errors.NewValidationError(fmt.Sprintf("unsupported decorator: %s", feature))
 	}
 }
 
 func (f *DecoratorFactory) createDiscountDecorator(
+	ctx context.Context,
 	wrapped payment.Payment,
 	options domain.CheckoutOptions,
 ) (payment.Payment, error) {
-	if !f.config.Decorators.Discount.Enabled {
-		return wrapped, nil
+	codes := options.AllDiscountCodes()
+	if len(codes) == 0 {
+		return nil, errors.NewValidationError("discount decorator requires a discount code")
 	}
-
-	config := decorator.DiscountConfig{
-		DiscountType:  "percentage",
-		DiscountValue: 10.0,
-		MinAmount:     0,
-		MaxDiscount:   f.config.Decorators.Discount.MaxFixedAmount,
-		ExpiryDate:    time.Now().Add(30 * 24 * time.Hour),
-		DiscountCode:  options.DiscountCode,
+	if f.discounts == nil {
+		return nil, errors.NewInternalError("discount codes are not available")
 	}
 
-	return decorator.NewDiscountDecorator(wrapped, config)
-}
+	limits := f.config.Decorators.Discount
+	discounts := make([]*domain.Discount, 0, len(codes))
+	configs := make([]decorator.DiscountConfig, 0, len(codes))
+	for _, code := range codes {
+		discount, err := f.discounts.GetDiscountByCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		discounts = append(discounts, discount)
 
-func (f *DecoratorFactory) createCashbackDecorator(wrapped payment.Payment) (payment.Payment, error) {
-	if !f.config.Decorators.Cashback.Enabled {
-		return wrapped, nil
+		config := decorator.DiscountConfig{
+			DiscountType:  string(discount.Type),
+			DiscountValue: discount.Value,
+			MinAmount:     discount.MinAmount,
+			MaxDiscount:   discount.MaxAmount,
+			ExpiryDate:    discount.ExpiresAt,
+			DiscountCode:  discount.Code,
+		}
+
+		if discount.Type == domain.DiscountTypePercentage && limits.MaxPercentage > 0 && config.DiscountValue > limits.MaxPercentage {
+			config.DiscountValue = limits.MaxPercentage
+		}
+		if limits.MaxFixedAmount > 0 && (config.MaxDiscount == 0 || config.MaxDiscount > limits.MaxFixedAmount) {
+			config.MaxDiscount = limits.MaxFixedAmount
+		}
+		configs = append(configs, config)
+	}
+
+	policy := NewDiscountStackPolicy(limits)
+	if err := policy.Check(discounts); err != nil {
+		return nil, err
 	}
 
+	return decorator.NewCombinedDiscountDecorator(wrapped, decorator.CombinedDiscountConfig{
+		Discounts:          configs,
+		MaxTotalPercentage: policy.MaxTotalPercentage,
+	})
+}
+
+func (f *DecoratorFactory) createCashbackDecorator(
+	wrapped payment.Payment,
+	customer *domain.Customer,
+) (payment.Payment, error) {
+	cashback := f.config.Decorators.Cashback
 	config := decorator.CashbackConfig{
-		Tier1Threshold:  f.config.Decorators.Cashback.Tier1Threshold,
-		Tier1Percentage: f.config.Decorators.Cashback.Tier1Percentage,
-		Tier2Percentage: f.config.Decorators.Cashback.Tier2Percentage,
+		Tier1Threshold:  cashback.Tier1Threshold,
+		Tier1Percentage: cashback.Tier1Percentage,
+		Tier2Percentage: cashback.Tier2Percentage,
+		TierPercentages: map[domain.CustomerTier]float64{
+			domain.TierBronze: cashback.Tiers.BronzePercentage,
+			domain.TierSilver: cashback.Tiers.SilverPercentage,
+			domain.TierGold:   cashback.Tiers.GoldPercentage,
+		},
+	}
+	if customer != nil {
+		config.CustomerTier = customer.Tier(domain.TierThresholds{
+			Silver: cashback.Tiers.SilverPoints,
+			Gold:   cashback.Tiers.GoldPoints,
+		})
 	}
 
 	return decorator.NewCashbackDecorator(wrapped, config), nil
@@ -108,15 +200,14 @@ func (f *DecoratorFactory) createCashbackDecorator(wrapped payment.Payment) (pay
 
 func (f *DecoratorFactory) createFraudDetectionDecorator(
 	wrapped payment.Payment,
+	options domain.CheckoutOptions,
 	customer *domain.Customer,
 ) (payment.Payment, error) {
-	if !f.config.Decorators.FraudDetection.Enabled {
-		return wrapped, nil
-	}
-
 	customerID := ""
+	var profile *decorator.CustomerRiskProfile
 	if customer != nil {
 		customerID = customer.ID
+		profile = riskProfile(customer, options, time.Now())
 	}
 
 	config := decorator.FraudDetectionConfig{
@@ -124,28 +215,56 @@ func (f *DecoratorFactory) createFraudDetectionDecorator(
 		VelocityCheckWindow:      f.config.Decorators.FraudDetection.VelocityCheckWindow,
 		MaxTransactionsPerWindow: f.config.Decorators.FraudDetection.MaxTransactionsPerWindow,
 		CustomerID:               customerID,
+		Profile:                  profile,
+		History:                  f.fraudHistory,
 	}
 
 	return decorator.NewFraudDetectionDecorator(wrapped, config), nil
 }
 
+const (
+	newAccountAge       = 7 * 24 * time.Hour
+	newAccountRiskScore = 15
+)
+
+// riskProfile rates the customer record: accounts younger than newAccountAge
+// start with a higher score, and a checkout whose "country" metadata differs
+// from the customer's address is a geolocation mismatch.
+func riskProfile(customer *domain.Customer, options domain.CheckoutOptions, now time.Time) *decorator.CustomerRiskProfile {
+	profile := &decorator.CustomerRiskProfile{}
+
+	if !customer.CreatedAt.IsZero() && now.Sub(customer.CreatedAt) < newAccountAge {
+		profile.BaseScore = newAccountRiskScore
+	}
+
+	country, _ := options.Metadata["country"].(string)
+	if country != "" && customer.Address.Country != "" && !strings.EqualFold(country, customer.Address.Country) {
+		profile.GeolocationMismatch = true
+	}
+
+	return profile
+}
+
 func (f *DecoratorFactory) createTaxDecorator(
 	wrapped payment.Payment,
 	customer *domain.Customer,
 ) (payment.Payment, error) {
-	if !f.config.Decorators.Tax.Enabled {
-		return wrapped, nil
-	}
-
 	region := "DEFAULT"
 	if customer != nil && customer.Address.State != "" {
 		region = customer.Address.State
 	}
 
 	config := decorator.TaxConfig{
-		Region:      region,
-		TaxRates:    f.config.Decorators.Tax.Rates,
-		DefaultRate: f.config.Decorators.Tax.DefaultRate,
+		Region:        region,
+		Provider:      f.taxRates,
+		CategoryRates: f.config.Decorators.Tax.CategoryRates,
+	}
+
+	// Configured rates can be split into state, city and postal code
+	// components; an external tax service only answers a single rate.
+	if static, ok := f.taxRates.(*decorator.StaticTaxRateProvider); ok && customer != nil {
+		address := customer.Address
+		config.Components = static.Components(address.State, address.City, address.PostalCode)
 	}
 
 	return decorator.NewTaxDecorator(wrapped, config), nil
@@ -156,10 +275,6 @@ func (f *DecoratorFactory) createLoyaltyPointsDecorator(
 	options domain.CheckoutOptions,
 	customer *domain.Customer,
 ) (payment.Payment, error) {
-	if !f.config.Decorators.LoyaltyPoints.Enabled {
-		return wrapped, nil
-	}
-
 	if customer == nil || options.UseLoyaltyPoints == 0 {
 		return wrapped, nil
 	}
@@ -169,11 +284,65 @@ func (f *DecoratorFactory) createLoyaltyPointsDecorator(
 		PointsToRedeem:          options.UseLoyaltyPoints,
 		PointsToCurrencyRatio:   f.config.Decorators.LoyaltyPoints.PointsToCurrencyRatio,
 		MaxRedemptionPercentage: f.config.Decorators.LoyaltyPoints.MaxRedemptionPercentage,
+		EarnRate:                f.config.Decorators.LoyaltyPoints.EarnRate,
 	}
 
 	return decorator.NewLoyaltyPointsDecorator(wrapped, config)
 }
 
+func (f *DecoratorFactory) createCashbackRedemptionDecorator(
+	wrapped payment.Payment,
+	options domain.CheckoutOptions,
+) (payment.Payment, error) {
+	if options.UseCashback == 0 {
+		return wrapped, nil
+	}
+
+	config := decorator.CashbackRedemptionConfig{
+		AmountToRedeem: options.UseCashback,
+	}
+
+	return decorator.NewCashbackRedemptionDecorator(wrapped, config)
+}
+
+func (f *DecoratorFactory) createServiceFeeDecorator(wrapped payment.Payment) (payment.Payment, error) {
+	config := decorator.ServiceFeeConfig{
+		FeeType:     f.config.Decorators.ServiceFee.FeeType,
+		FeeValue:    f.config.Decorators.ServiceFee.FeeValue,
+		ExemptAbove: f.config.Decorators.ServiceFee.ExemptAbove,
+	}
+
+	return decorator.NewServiceFeeDecorator(wrapped, config)
+}
+
+// ValidateDecorators rejects unknown decorators and decorators that are
+// disabled in config, so a requested feature is never silently skipped.
+func (f *DecoratorFactory) ValidateDecorators(features []string) error {
+	available := make(map[string]bool)
+	for _, name := range f.GetAvailableDecorators() {
+		available[name] = true
+	}
+
+	var disabled []string
+	for _, feature := range features {
+		if available[feature] {
+			continue
+		}
+		if !knownDecorators[feature] {
+			return errors.NewValidationError(fmt.Sprintf("unsupported decorator: %s", feature))
+		}
+		disabled = append(disabled, feature)
+	}
+
+	if len(disabled) > 0 {
+		return errors.NewValidationError(
+			fmt.Sprintf("decorators disabled in config: %s", strings.Join(disabled, ", ")),
+		).WithDetails("disabled_decorators", disabled)
+	}
+
+	return nil
+}
+
 func (f *DecoratorFactory) GetAvailableDecorators() []string {
 	decorators := []string{}
 
@@ -192,6 +361,10 @@ func (f *DecoratorFactory) GetAvailableDecorators() []string {
 	if f.config.Decorators.LoyaltyPoints.Enabled {
 		decorators = append(decorators, "loyalty_points")
 	}
+	if f.config.Decorators.ServiceFee.Enabled {
+		decorators = append(decorators, "service_fee")
+	}
+	decorators = append(decorators, "cashback_redemption")
 
 	return decorators
 }
@@ -0,0 +1,229 @@
+package factory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/internal/service"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoratorFactoryValidateDecorators(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Decorators.Tax = config.TaxConfig{Enabled: true, DefaultRate: 8}
+	factory := NewDecoratorFactory(cfg, nil)
+
+	t.Run("Accepts Enabled Decorators", func(t *testing.T) {
+		assert.NoError(t, factory.ValidateDecorators([]string{"tax", "cashback_redemption"}))
+	})
+
+	t.Run("Rejects Disabled Decorators", func(t *testing.T) {
+		err := factory.ValidateDecorators([]string{"tax", "loyalty_points", "discount"})
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.Contains(t, err.Error(), "loyalty_points, discount")
+	})
+
+	t.Run("Rejects Unknown Decorators", func(t *testing.T) {
+		err := factory.ValidateDecorators([]string{"gift_wrap"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported decorator")
+	})
+
+	t.Run("Chain Fails Instead Of Skipping", func(t *testing.T) {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 100)
+		require.NoError(t, err)
+
+		_, err = factory.CreateDecoratorChain(context.Background(), base, []string{"loyalty_points"}, domain.CheckoutOptions{}, nil)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
+
+func TestDecoratorFactoryDiscountCodes(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Decorators.Discount = config.DiscountConfig{Enabled: true, MaxPercentage: 50, MaxFixedAmount: 500}
+	factory := NewDecoratorFactory(cfg, service.NewDiscountService(repository.NewMemoryRepository()))
+
+	charge := func(code string, amount float64) (*payment.PaymentResult, error) {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 1000)
+		require.NoError(t, err)
+
+		p, err := factory.CreateDecoratorChain(ctx, base, []string{"discount"}, domain.CheckoutOptions{DiscountCode: code}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return p.Process(ctx, payment.PaymentRequest{Amount: amount})
+	}
+
+	t.Run("Applies Percentage Code", func(t *testing.T) {
+		result, err := charge("welcome10", 50)
+		require.NoError(t, err)
+		assert.InDelta(t, 45.0, result.Amount, 0.001)
+		assert.Equal(t, "WELCOME10", result.Metadata["discount_code"])
+	})
+
+	t.Run("Applies Fixed Code Above Minimum", func(t *testing.T) {
+		result, err := charge("SAVE20", 150)
+		require.NoError(t, err)
+		assert.InDelta(t, 130.0, result.Amount, 0.001)
+
+		_, err = charge("SAVE20", 50)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Rejects Unknown And Expired Codes", func(t *testing.T) {
+		_, err := charge("BOGUS", 50)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.Contains(t, err.Error(), "invalid discount code")
+
+		_, err = charge("SUMMER2024", 50)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.Contains(t, err.Error(), "expired")
+	})
+}
+
+func TestDecoratorFactoryStacking(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	require.NoError(t, repo.CreateDiscount(ctx, &domain.Discount{
+		ID: "disc-extra", Code: "EXTRA40", Type: domain.DiscountTypePercentage, Value: 40, IsActive: true,
+	}))
+
+	newFactory := func(discount config.DiscountConfig) *DecoratorFactory {
+		cfg := &config.Config{}
+		cfg.Decorators.Discount = discount
+		cfg.Decorators.Tax = config.TaxConfig{Enabled: true, DefaultRate: 10}
+		return NewDecoratorFactory(cfg, service.NewDiscountService(repo))
+	}
+
+	charge := func(factory *DecoratorFactory, features []string, codes []string, amount float64) (*payment.PaymentResult, error) {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 1000)
+		require.NoError(t, err)
+
+		p, err := factory.CreateDecoratorChain(ctx, base, features, domain.CheckoutOptions{DiscountCodes: codes}, nil)
+		if err != nil {
+			return nil, err
+		}
+		return p.Process(ctx, payment.PaymentRequest{Amount: amount})
+	}
+
+	t.Run("Taxes The Discounted Amount Whatever The Requested Order", func(t *testing.T) {
+		factory := newFactory(config.DiscountConfig{Enabled: true})
+		for _, features := range [][]string{{"tax", "discount"}, {"discount", "tax"}} {
+			result, err := charge(factory, features, []string{"WELCOME10"}, 100)
+			require.NoError(t, err)
+			assert.InDelta(t, 99.0, result.Amount, 0.001, "features %v", features)
+			assert.InDelta(t, 100.0, result.OriginalAmount, 0.001)
+			assert.InDelta(t, 9.0, result.Breakdown.TaxAmount, 0.001)
+		}
+	})
+
+	t.Run("Rejects Percentage With Fixed Unless Allowed", func(t *testing.T) {
+		_, err := charge(newFactory(config.DiscountConfig{Enabled: true}), []string{"discount"}, []string{"WELCOME10", "SAVE20"}, 150)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+
+		result, err := charge(newFactory(config.DiscountConfig{Enabled: true, CombinePercentageAndFixed: true}),
+			[]string{"discount"}, []string{"WELCOME10", "SAVE20"}, 150)
+		require.NoError(t, err)
+		assert.InDelta(t, 115.0, result.Amount, 0.001)
+		assert.Equal(t, "WELCOME10,SAVE20", result.Metadata["discount_code"])
+	})
+
+	t.Run("Caps The Combined Discount", func(t *testing.T) {
+		factory := newFactory(config.DiscountConfig{Enabled: true, MaxTotalPercentage: 30})
+		result, err := charge(factory, []string{"discount"}, []string{"welcome10", "EXTRA40", "WELCOME10"}, 100)
+		require.NoError(t, err)
+		assert.InDelta(t, 70.0, result.Amount, 0.001)
+		assert.InDelta(t, 30.0, result.Breakdown.DiscountAmount, 0.001)
+	})
+}
+
+func TestDecoratorFactoryFraudVelocity(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Decorators.FraudDetection = config.FraudDetectionConfig{
+		Enabled:                  true,
+		MaxRiskScore:             100,
+		VelocityCheckWindow:      time.Hour,
+		MaxTransactionsPerWindow: 1,
+	}
+	factory := NewDecoratorFactory(cfg, nil)
+
+	charge := func(customer *domain.Customer) error {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 1000)
+		require.NoError(t, err)
+
+		p, err := factory.CreateDecoratorChain(ctx, base, []string{"fraud_detection"}, domain.CheckoutOptions{}, customer)
+		require.NoError(t, err)
+		_, err = p.Process(ctx, payment.PaymentRequest{Amount: 50})
+		return err
+	}
+
+	first := &domain.Customer{ID: "cust-first"}
+	require.NoError(t, charge(first))
+
+	err := charge(first)
+	assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+
+	assert.NoError(t, charge(&domain.Customer{ID: "cust-second"}))
+}
+
+func TestDecoratorFactoryFraudRiskScore(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Decorators.FraudDetection = config.FraudDetectionConfig{
+		Enabled:                  true,
+		MaxRiskScore:             30,
+		VelocityCheckWindow:      time.Hour,
+		MaxTransactionsPerWindow: 10,
+	}
+	factory := NewDecoratorFactory(cfg, nil)
+
+	charge := func(customer *domain.Customer, options domain.CheckoutOptions) (*payment.PaymentResult, error) {
+		base, err := payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", 1000)
+		require.NoError(t, err)
+
+		p, err := factory.CreateDecoratorChain(ctx, base, []string{"fraud_detection"}, options, customer)
+		require.NoError(t, err)
+		return p.Process(ctx, payment.PaymentRequest{Amount: 50})
+	}
+
+	t.Run("Scores Earlier Checkouts", func(t *testing.T) {
+		customer := &domain.Customer{ID: "cust-regular", CreatedAt: time.Now().AddDate(-1, 0, 0)}
+		for _, want := range []int{0, 10, 20, 30} {
+			result, err := charge(customer, domain.CheckoutOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, want, result.Metadata["fraud_risk_score"])
+		}
+
+		_, err := charge(customer, domain.CheckoutOptions{})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+	})
+
+	t.Run("New Accounts Start Higher", func(t *testing.T) {
+		customer := &domain.Customer{ID: "cust-new", CreatedAt: time.Now()}
+		result, err := charge(customer, domain.CheckoutOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 15, result.Metadata["fraud_risk_score"])
+	})
+
+	t.Run("Flags A Checkout From Another Country", func(t *testing.T) {
+		customer := &domain.Customer{ID: "cust-abroad", Address: domain.Address{Country: "US"}}
+		options := domain.CheckoutOptions{Metadata: map[string]interface{}{"country": "FR"}}
+
+		_, err := charge(customer, options)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+
+		options.Metadata["country"] = "us"
+		_, err = charge(customer, options)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ecommerce/payment-system/config"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+// DecoratorOrder is outermost first: discounts before tax, and cashback
+// redemption last.
+var DecoratorOrder = []string{
+	"fraud_detection",
+	"discount",
+	"loyalty_points",
+	"tax",
+	"service_fee",
+	"cashback",
+	"cashback_redemption",
+}
+
+// Unknown names keep their relative order at the end.
+func OrderDecorators(features []string) []string {
+	requested := make(map[string]bool, len(features))
+	for _, feature := range features {
+		requested[feature] = true
+	}
+
+	ordered := make([]string, 0, len(requested))
+	known := make(map[string]bool, len(DecoratorOrder))
+	for _, name := range DecoratorOrder {
+		known[name] = true
+		if requested[name] {
+			ordered = append(ordered, name)
+		}
+	}
+	for _, feature := range features {
+		if !known[feature] {
+			known[feature] = true
+			ordered = append(ordered, feature)
+		}
+	}
+
+	return ordered
+}
+
+type DiscountStackPolicy struct {
+	CombinePercentageAndFixed bool
+	MaxTotalPercentage        float64
+}
+
+func NewDiscountStackPolicy(cfg config.DiscountConfig) DiscountStackPolicy {
+	return DiscountStackPolicy{
+		CombinePercentageAndFixed: cfg.CombinePercentageAndFixed,
+		MaxTotalPercentage:        cfg.MaxTotalPercentage,
+	}
+}
+
+func (p DiscountStackPolicy) Check(discounts []*domain.Discount) error {
+	if p.CombinePercentageAndFixed {
+		return nil
+	}
+
+	var percentage, fixed []string
+	for _, discount := range discounts {
+		if discount.Type == domain.DiscountTypePercentage {
+			percentage = append(percentage, discount.Code)
+		} else {
+			fixed = append(fixed, discount.Code)
+		}
+	}
+
+	if len(percentage) > 0 && len(fixed) > 0 {
+		return errors.NewValidationError(fmt.Sprintf(
+			"percentage code %s cannot be combined with fixed code %s",
+			strings.Join(percentage, ", "), strings.Join(fixed, ", "),
+		)).WithDetails("percentage_codes", percentage).WithDetails("fixed_codes", fixed)
+	}
+
+	return nil
+}
@@ -17,6 +17,8 @@ func NewPaymentFactory() *PaymentFactory {
 			"credit_card": true,
 			"paypal":      true,
 			"crypto":      true,
+			"gift_card":   true,
+			"wallet":      true,
 		},
 	}
 }
@@ -29,6 +31,10 @@ func (f *PaymentFactory) CreatePayment(paymentType string, config payment.Paymen
 		)
 	}
 
+	if config.SavedToken != "" {
+		return f.createSavedPayment(paymentType, config)
+	}
+
 	switch paymentType {
 	case "credit_card":
 		return f.createCreditCardPayment(config)
@@ -36,6 +42,10 @@ func (f *PaymentFactory) CreatePayment(paymentType string, config payment.Paymen
 		return f.createPayPalPayment(config)
 	case "crypto":
 		return f.createCryptoPayment(config)
+	case "gift_card":
+		return f.createGiftCardPayment(config)
+	case "wallet":
+		return f.createWalletPayment(config)
 	default:
 		return nil, errors.NewInvalidPaymentError(
 			fmt.Sprintf("unsupported payment type: %s", paymentType),
@@ -58,12 +68,18 @@ func (f *PaymentFactory) createCreditCardPayment(config payment.PaymentConfig) (
 		return nil, errors.NewValidationError("CVV is required")
 	}
 
-	return payment.NewCreditCardPayment(
+	card, err := payment.NewCreditCardPayment(
 		config.CardNumber,
 		config.CardHolder,
 		config.ExpiryDate,
 		config.CVV,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	card.SetLimits(config.Limits)
+	return card, nil
 }
 
 func (f *PaymentFactory) createPayPalPayment(config payment.PaymentConfig) (payment.Payment, error) {
@@ -75,10 +91,16 @@ func (f *PaymentFactory) createPayPalPayment(config payment.PaymentConfig) (paym
 		return nil, errors.NewValidationError("PayPal password is required")
 	}
 
-	return payment.NewPayPalPayment(
+	paypal, err := payment.NewPayPalPayment(
 		config.PayPalEmail,
 		config.PayPalPassword,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	paypal.SetLimits(config.Limits)
+	return paypal, nil
 }
 
 func (f *PaymentFactory) createCryptoPayment(config payment.PaymentConfig) (payment.Payment, error) {
@@ -90,10 +112,53 @@ func (f *PaymentFactory) createCryptoPayment(config payment.PaymentConfig) (paym
 		return nil, errors.NewValidationError("crypto type is required")
 	}
 
-	return payment.NewCryptoPayment(
+	crypto, err := payment.NewCryptoPayment(
 		config.WalletAddress,
 		config.CryptoType,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	crypto.SetLimits(config.Limits)
+	return crypto, nil
+}
+
+func (f *PaymentFactory) createGiftCardPayment(config payment.PaymentConfig) (payment.Payment, error) {
+
+	if config.GiftCardCode == "" {
+		return nil, errors.NewValidationError("gift card code is required")
+	}
+
+	return payment.NewGiftCardPayment(
+		config.GiftCardCode,
+		config.GiftCardBalance,
+	)
+}
+
+func (f *PaymentFactory) createWalletPayment(config payment.PaymentConfig) (payment.Payment, error) {
+
+	if config.WalletToken == "" {
+		return nil, errors.NewValidationError("wallet token is required")
+	}
+	if config.WalletProvider == "" {
+		return nil, errors.NewValidationError("wallet provider is required")
+	}
+
+	return payment.NewWalletPayment(
+		config.WalletToken,
+		config.WalletProvider,
+	)
+}
+
+func (f *PaymentFactory) createSavedPayment(paymentType string, config payment.PaymentConfig) (payment.Payment, error) {
+	saved, err := payment.NewSavedPayment(paymentType, config.SavedToken, config.SavedDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	saved.SetLimits(config.Limits)
+	return saved, nil
 }
 
 func (f *PaymentFactory) IsSupported(paymentType string) bool {
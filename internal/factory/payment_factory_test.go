@@ -1,9 +1,11 @@
 package factory
 
 import (
+	"context"
 	"testing"
 
 	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +17,7 @@ func TestPaymentFactory(t *testing.T) {
 		config := payment.PaymentConfig{
 			CardNumber: "4532015112830366",
 			CardHolder: "John Doe",
-			ExpiryDate: "12/25",
+			ExpiryDate: "12/30",
 			CVV:        "123",
 		}
 
@@ -49,6 +51,30 @@ func TestPaymentFactory(t *testing.T) {
 		assert.Equal(t, "crypto", p.GetType())
 	})
 
+	t.Run("Create Gift Card Payment", func(t *testing.T) {
+		config := payment.PaymentConfig{
+			GiftCardCode:    "ABCD-EFGH-JKLM-1234",
+			GiftCardBalance: 50.00,
+		}
+
+		p, err := factory.CreatePayment("gift_card", config)
+		require.NoError(t, err)
+		assert.Equal(t, "gift_card", p.GetType())
+		assert.Equal(t, "************1234", p.GetDetails()["gift_card"])
+	})
+
+	t.Run("Create Wallet Payment", func(t *testing.T) {
+		config := payment.PaymentConfig{
+			WalletToken:    "apay_9f8e7d6c5b4a3210",
+			WalletProvider: "apple_pay",
+		}
+
+		p, err := factory.CreatePayment("wallet", config)
+		require.NoError(t, err)
+		assert.Equal(t, "wallet", p.GetType())
+		assert.Equal(t, "apay_************3210", p.GetDetails()["wallet_token"])
+	})
+
 	t.Run("Unsupported Payment Type", func(t *testing.T) {
 		config := payment.PaymentConfig{}
 		_, err := factory.CreatePayment("unsupported", config)
@@ -60,5 +86,60 @@ func TestPaymentFactory(t *testing.T) {
 		assert.Contains(t, types, "credit_card")
 		assert.Contains(t, types, "paypal")
 		assert.Contains(t, types, "crypto")
+		assert.Contains(t, types, "gift_card")
+		assert.Contains(t, types, "wallet")
+	})
+}
+
+func TestPaymentFactoryLimits(t *testing.T) {
+	ctx := context.Background()
+	factory := NewPaymentFactory()
+
+	card := payment.PaymentConfig{
+		CardNumber: "4532015112830366",
+		CardHolder: "John Doe",
+		ExpiryDate: "12/30",
+		CVV:        "123",
+	}
+
+	t.Run("Configured Max Is Enforced", func(t *testing.T) {
+		config := card
+		config.Limits = payment.AmountLimits{Min: 5, Max: 200}
+
+		p, err := factory.CreatePayment("credit_card", config)
+		require.NoError(t, err)
+
+		_, err = p.Process(ctx, payment.PaymentRequest{Amount: 250})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.ErrorContains(t, err, "exceeds maximum 200.00")
+
+		_, err = p.Process(ctx, payment.PaymentRequest{Amount: 2})
+		assert.ErrorContains(t, err, "below minimum 5.00")
+
+		result, err := p.Process(ctx, payment.PaymentRequest{Amount: 150})
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("Configured Max Above The Built-in One", func(t *testing.T) {
+		config := payment.PaymentConfig{
+			PayPalEmail:    "user@example.com",
+			PayPalPassword: "password",
+			Limits:         payment.AmountLimits{Min: 1, Max: 8000},
+		}
+
+		p, err := factory.CreatePayment("paypal", config)
+		require.NoError(t, err)
+
+		_, err = p.Process(ctx, payment.PaymentRequest{Amount: 7500})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Built-in Limits Without Config", func(t *testing.T) {
+		p, err := factory.CreatePayment("credit_card", card)
+		require.NoError(t, err)
+
+		_, err = p.Process(ctx, payment.PaymentRequest{Amount: payment.DefaultCreditCardLimits.Max + 1})
+		assert.ErrorContains(t, err, "exceeds maximum 10000.00")
 	})
 }
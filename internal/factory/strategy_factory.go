@@ -3,24 +3,36 @@ package factory
 import (
 	"fmt"
 
+	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/strategy"
 	"github.com/ecommerce/payment-system/pkg/errors"
 )
 
 type StrategyFactory struct {
 	supportedStrategies map[string]bool
+	splitTolerance      float64
 }
 
 func NewStrategyFactory() *StrategyFactory {
 	return &StrategyFactory{
 		supportedStrategies: map[string]bool{
-			"instant":  true,
-			"deferred": true,
-			"split":    true,
+			"instant":   true,
+			"deferred":  true,
+			"split":     true,
+			"authorize": true,
+			"escrow":    true,
+			"recurring": true,
 		},
+		splitTolerance: strategy.DefaultSplitTolerance,
 	}
 }
 
+// WithSplitTolerance sets how far split amounts may miss the total.
+func (f *StrategyFactory) WithSplitTolerance(tolerance float64) *StrategyFactory {
+	f.splitTolerance = tolerance
+	return f
+}
+
 func (f *StrategyFactory) CreateStrategy(strategyType string, params map[string]interface{}) (strategy.PaymentStrategy, error) {
 	if !f.supportedStrategies[strategyType] {
 		return nil, errors.NewValidationError(
@@ -33,6 +45,12 @@ func (f *StrategyFactory) CreateStrategy(strategyType string, params map[string]
 		return f.createInstantStrategy(params)
 	case "deferred":
 		return f.createDeferredStrategy(params)
+	case "authorize":
+		return f.createAuthorizeStrategy(params)
+	case "escrow":
+		return f.createEscrowStrategy(params)
+	case "recurring":
+		return f.createRecurringStrategy(params)
 	case "split":
 		return nil, errors.NewValidationError("split strategy must be created with CreateSplitStrategy")
 	default:
@@ -56,6 +74,54 @@ func (f *StrategyFactory) createInstantStrategy(params map[string]interface{}) (
 	return strategy.NewInstantPaymentStrategy(minAmount, maxAmount), nil
 }
 
+func (f *StrategyFactory) createAuthorizeStrategy(params map[string]interface{}) (strategy.PaymentStrategy, error) {
+	minAmount := 1.0
+	maxAmount := 10000.0
+
+	if val, ok := params["min_amount"].(float64); ok {
+		minAmount = val
+	}
+	if val, ok := params["max_amount"].(float64); ok {
+		maxAmount = val
+	}
+
+	return strategy.NewAuthorizeOnlyStrategy(minAmount, maxAmount), nil
+}
+
+func (f *StrategyFactory) createEscrowStrategy(params map[string]interface{}) (strategy.PaymentStrategy, error) {
+	minAmount := 100.0
+	maxAmount := 50000.0
+
+	if val, ok := params["min_amount"].(float64); ok {
+		minAmount = val
+	}
+	if val, ok := params["max_amount"].(float64); ok {
+		maxAmount = val
+	}
+
+	return strategy.NewEscrowPaymentStrategy(minAmount, maxAmount), nil
+}
+
+func (f *StrategyFactory) createRecurringStrategy(params map[string]interface{}) (strategy.PaymentStrategy, error) {
+	minAmount := 1.0
+	maxAmount := 10000.0
+
+	if val, ok := params["min_amount"].(float64); ok {
+		minAmount = val
+	}
+	if val, ok := params["max_amount"].(float64); ok {
+		maxAmount = val
+	}
+
+	value, _ := params["interval"].(string)
+	interval, err := domain.ParseBillingInterval(value)
+	if err != nil {
+		return nil, errors.NewValidationError(err.Error())
+	}
+
+	return strategy.NewRecurringPaymentStrategy(minAmount, maxAmount, interval), nil
+}
+
 func (f *StrategyFactory) createDeferredStrategy(params map[string]interface{}) (strategy.PaymentStrategy, error) {
 	minAmount := 100.0
 	maxAmount := 10000.0
@@ -88,7 +154,10 @@ func (f *StrategyFactory) createDeferredStrategy(params map[string]interface{})
 }
 
 func (f *StrategyFactory) CreateSplitStrategy(payments []strategy.SplitPaymentItem) (strategy.PaymentStrategy, error) {
-	return strategy.NewSplitPaymentStrategy(payments)
+	return strategy.NewSplitPaymentStrategyWithConfig(payments, strategy.SplitPaymentConfig{
+		Tolerance:   f.splitTolerance,
+		ResidualLeg: len(payments) - 1,
+	})
 }
 
 func (f *StrategyFactory) IsSupported(strategyType string) bool {
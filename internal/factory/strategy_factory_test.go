@@ -0,0 +1,47 @@
+package factory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/strategy"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrategyFactorySplitTolerance(t *testing.T) {
+	newCard := func() payment.Payment {
+		p, err := payment.NewCreditCardPayment("4532015112830366", "John Doe", "12/30", "123")
+		require.NoError(t, err)
+		return p
+	}
+	legs := func() []strategy.SplitPaymentItem {
+		return []strategy.SplitPaymentItem{
+			{Payment: newCard(), Amount: 10.00},
+			{Payment: newCard(), Amount: 20.00},
+		}
+	}
+	request := payment.PaymentRequest{Amount: 30.05}
+
+	t.Run("Default Tolerance Rejects A Nickel", func(t *testing.T) {
+		split, err := NewStrategyFactory().CreateSplitStrategy(legs())
+		require.NoError(t, err)
+
+		_, err = split.Execute(context.Background(), nil, request)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Configured Tolerance Charges The Residual To The Last Leg", func(t *testing.T) {
+		split, err := NewStrategyFactory().WithSplitTolerance(0.05).CreateSplitStrategy(legs())
+		require.NoError(t, err)
+
+		result, err := split.Execute(context.Background(), nil, request)
+		require.NoError(t, err)
+		assert.InDelta(t, 30.05, result.ProcessedAmount, 1e-9)
+
+		details := result.Metadata["split_details"].([]map[string]interface{})
+		assert.InDelta(t, 20.05, details[1]["amount"].(float64), 1e-9)
+	})
+}
@@ -0,0 +1,132 @@
+// Package i18n holds customer-facing strings keyed by locale and message ID.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const DefaultLocale = "en"
+
+// Every ID must exist in DefaultLocale; other locales fall back to it.
+var catalog = map[string]map[string]string{
+	"en": {
+		"receipt.title":              "RECEIPT",
+		"receipt.transaction_id":     "Transaction ID",
+		"receipt.order_id":           "Order ID",
+		"receipt.date":               "Date",
+		"receipt.customer":           "Customer",
+		"receipt.email":              "Email",
+		"receipt.payment":            "Payment",
+		"receipt.items":              "Items",
+		"receipt.amounts":            "Amounts",
+		"receipt.subtotal":           "Subtotal",
+		"receipt.discount":           "Discount",
+		"receipt.tax":                "Tax",
+		"receipt.service_fee":        "Service Fee",
+		"receipt.cashback_applied":   "Cashback Applied",
+		"receipt.total":              "Total",
+		"receipt.base_amount":        "Base Amount",
+		"receipt.locked_rate":        "Locked Rate",
+		"receipt.cashback_earned":    "Cashback Earned",
+		"receipt.loyalty_points":     "Loyalty Points",
+		"receipt.points":             "%d points",
+		"receipt.applied_features":   "Applied Features",
+		"email.payment_started":      "Payment Processing Started",
+		"email.payment_started.body": "Your payment of $%.2f has been initiated.\nTransaction ID: %s",
+		"email.payment_success":      "Payment Successful",
+		"email.payment_success.body": "Your payment of $%.2f has been processed successfully.\nTransaction ID: %s\nPayment Method: %s",
+		"email.payment_failed":       "Payment Failed",
+		"email.payment_failed.body":  "Your payment of $%.2f has failed.\nTransaction ID: %s\nPlease try again or contact support.",
+		"email.refund_issued":        "Refund Issued",
+		"email.refund_issued.body":   "A refund of $%.2f has been issued to your account.\nTransaction ID: %s",
+		"email.default":              "Payment Notification",
+		"email.default.body":         "Transaction ID: %s",
+	},
+	"es": {
+		"receipt.title":              "RECIBO",
+		"receipt.transaction_id":     "ID de transacción",
+		"receipt.order_id":           "ID de pedido",
+		"receipt.date":               "Fecha",
+		"receipt.customer":           "Cliente",
+		"receipt.email":              "Correo",
+		"receipt.payment":            "Pago",
+		"receipt.items":              "Artículos",
+		"receipt.amounts":            "Importes",
+		"receipt.subtotal":           "Subtotal",
+		"receipt.discount":           "Descuento",
+		"receipt.tax":                "Impuesto",
+		"receipt.service_fee":        "Cargo por servicio",
+		"receipt.cashback_applied":   "Reembolso aplicado",
+		"receipt.total":              "Total",
+		"receipt.base_amount":        "Importe base",
+		"receipt.locked_rate":        "Tipo fijado",
+		"receipt.cashback_earned":    "Reembolso ganado",
+		"receipt.loyalty_points":     "Puntos de fidelidad",
+		"receipt.points":             "%d puntos",
+		"receipt.applied_features":   "Funciones aplicadas",
+		"email.payment_started":      "Pago en proceso",
+		"email.payment_started.body": "Se ha iniciado su pago de $%.2f.\nID de transacción: %s",
+		"email.payment_success":      "Pago realizado",
+		"email.payment_success.body": "Su pago de $%.2f se ha procesado correctamente.\nID de transacción: %s\nMétodo de pago: %s",
+		"email.payment_failed":       "Pago fallido",
+		"email.payment_failed.body":  "Su pago de $%.2f no se ha podido completar.\nID de transacción: %s\nInténtelo de nuevo o contacte con soporte.",
+		"email.refund_issued":        "Reembolso emitido",
+		"email.refund_issued.body":   "Se ha emitido un reembolso de $%.2f a su cuenta.\nID de transacción: %s",
+		"email.default":              "Notificación de pago",
+		"email.default.body":         "ID de transacción: %s",
+	},
+}
+
+// Normalize makes "es_MX" and "es-mx" the same locale.
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if locale == "" {
+		return DefaultLocale
+	}
+	return locale
+}
+
+func resolve(locale string) string {
+	locale = Normalize(locale)
+	if _, ok := catalog[locale]; ok {
+		return locale
+	}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		if _, ok := catalog[language]; ok {
+			return language
+		}
+	}
+	return DefaultLocale
+}
+
+func Supported(locale string) bool {
+	locale = Normalize(locale)
+	language, _, _ := strings.Cut(locale, "-")
+	return catalog[locale] != nil || catalog[language] != nil
+}
+
+func Locales() []string {
+	locales := make([]string, 0, len(catalog))
+	for locale := range catalog {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// T returns unknown IDs as-is so a gap shows up instead of a blank.
+func T(locale, id string, args ...interface{}) string {
+	message, ok := catalog[resolve(locale)][id]
+	if !ok {
+		message, ok = catalog[DefaultLocale][id]
+	}
+	if !ok {
+		return id
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
@@ -0,0 +1,36 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Run("Every Locale Only Uses Default IDs", func(t *testing.T) {
+		for locale, messages := range catalog {
+			for id := range messages {
+				_, ok := catalog[DefaultLocale][id]
+				assert.True(t, ok, "%s defines %s, which %s lacks", locale, id, DefaultLocale)
+			}
+		}
+	})
+
+	t.Run("Falls Back To Language And Default", func(t *testing.T) {
+		assert.Equal(t, "RECIBO", T("es", "receipt.title"))
+		assert.Equal(t, "RECIBO", T("es_MX", "receipt.title"))
+		assert.Equal(t, "RECEIPT", T("fr", "receipt.title"))
+		assert.Equal(t, "RECEIPT", T("", "receipt.title"))
+		assert.Equal(t, "no.such.message", T("es", "no.such.message"))
+	})
+
+	t.Run("Formats Arguments", func(t *testing.T) {
+		assert.Equal(t, "12 puntos", T("es", "receipt.points", 12))
+	})
+
+	t.Run("Supported Locales", func(t *testing.T) {
+		assert.True(t, Supported("ES-mx"))
+		assert.False(t, Supported("fr"))
+		assert.Equal(t, []string{"en", "es"}, Locales())
+	})
+}
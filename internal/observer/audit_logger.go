@@ -1,10 +1,16 @@
 package observer
 
 import (
+	"bufio"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,27 +18,57 @@ import (
 	"go.uber.org/zap"
 )
 
-type AuditLogger struct {
-	logPath string
-	file    *os.File
-	mu      sync.Mutex
+const (
+	AuditFormatJSON = "json"
+	AuditFormatCSV  = "csv"
+)
+
+var auditCSVHeader = []string{
+	"timestamp", "event_type", "transaction_id", "customer_id", "cart_id",
+	"amount", "payment_method", "strategy", "discount_code", "error", "metadata",
+}
+
+var auditFlattenedKeys = map[string]string{
+	"payment_strategy": "strategy",
+	"discount_code":    "discount_code",
 }
 
-func NewAuditLogger(logPath string) (*AuditLogger, error) {
+// AuditSink is one destination of the audit pipeline.
+type AuditSink interface {
+	Write(entry AuditEntry) error
+	Name() string
+	Close() error
+}
 
-	if err := os.MkdirAll("logs", 0755); err != nil {
-		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+// AuditLogger fans every entry out to all sinks. A failing sink is logged
+// and skipped so the other sinks still receive the entry.
+type AuditLogger struct {
+	sinks []AuditSink
+	mu    sync.Mutex
+}
+
+func ResolveAuditFormat(logPath, format string) string {
+	format = strings.ToLower(format)
+	if format == AuditFormatJSON || format == AuditFormatCSV {
+		return format
+	}
+	if strings.EqualFold(filepath.Ext(logPath), ".csv") {
+		return AuditFormatCSV
 	}
+	return AuditFormatJSON
+}
 
-	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// NewAuditLogger writes to a single file sink.
+func NewAuditLogger(logPath, format string) (*AuditLogger, error) {
+	sink, err := NewFileAuditSink(logPath, format)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open audit log: %w", err)
+		return nil, err
 	}
+	return NewAuditLoggerWithSinks(sink), nil
+}
 
-	return &AuditLogger{
-		logPath: logPath,
-		file:    file,
-	}, nil
+func NewAuditLoggerWithSinks(sinks ...AuditSink) *AuditLogger {
+	return &AuditLogger{sinks: sinks}
 }
 
 func (a *AuditLogger) Notify(ctx context.Context, event Event) error {
@@ -49,8 +85,10 @@ func (a *AuditLogger) Notify(ctx context.Context, event Event) error {
 		EventType:     string(event.Type),
 		TransactionID: event.TransactionID,
 		CustomerID:    event.CustomerID,
+		CartID:        event.CartID,
 		Amount:        event.Amount,
 		PaymentMethod: event.PaymentMethod,
+		Loyalty:       event.Loyalty,
 		Metadata:      event.Metadata,
 	}
 
@@ -58,21 +96,27 @@ func (a *AuditLogger) Notify(ctx context.Context, event Event) error {
 		entry.Error = event.Error.Error()
 	}
 
-	data, err := json.Marshal(entry)
-	if err != nil {
-		return fmt.Errorf("failed to marshal audit entry: %w", err)
-	}
-
-	if _, err := a.file.Write(append(data, '\n')); err != nil {
-		return fmt.Errorf("failed to write audit entry: %w", err)
+	var failed []string
+	var lastErr error
+	for _, sink := range a.sinks {
+		if err := sink.Write(entry); err != nil {
+			logger.Warn("Audit sink failed",
+				zap.String("sink", sink.Name()),
+				zap.String("transaction_id", event.TransactionID),
+				zap.Error(err),
+			)
+			failed = append(failed, sink.Name())
+			lastErr = err
+		}
 	}
 
-	if err := a.file.Sync(); err != nil {
-		return fmt.Errorf("failed to sync audit log: %w", err)
+	if len(a.sinks) > 0 && len(failed) == len(a.sinks) {
+		return fmt.Errorf("all audit sinks failed (%s): %w", strings.Join(failed, ", "), lastErr)
 	}
 
 	logger.Debug("Audit entry written",
 		zap.String("transaction_id", event.TransactionID),
+		zap.Int("sinks", len(a.sinks)-len(failed)),
 	)
 
 	return nil
@@ -86,10 +130,13 @@ func (a *AuditLogger) Close() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	if a.file != nil {
-		return a.file.Close()
+	var firstErr error
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return nil
+	return firstErr
 }
 
 type AuditEntry struct {
@@ -97,8 +144,107 @@ type AuditEntry struct {
 	EventType     string                 `json:"event_type"`
 	TransactionID string                 `json:"transaction_id"`
 	CustomerID    string                 `json:"customer_id"`
+	CartID        string                 `json:"cart_id,omitempty"`
 	Amount        float64                `json:"amount"`
 	PaymentMethod string                 `json:"payment_method"`
+	Loyalty       *LoyaltyChange         `json:"loyalty,omitempty"`
 	Error         string                 `json:"error,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata"`
 }
+
+func ReadAuditLog(logPath, format string) ([]AuditEntry, error) {
+	file, err := os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if ResolveAuditFormat(logPath, format) == AuditFormatCSV {
+		return readAuditCSV(file)
+	}
+	return readAuditJSON(file)
+}
+
+func readAuditJSON(r io.Reader) ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func readAuditCSV(r io.Reader) ([]AuditEntry, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []AuditEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+
+	entries := []AuditEntry{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse audit entry: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+
+		amount, _ := strconv.ParseFloat(field("amount"), 64)
+		entry := AuditEntry{
+			Timestamp:     field("timestamp"),
+			EventType:     field("event_type"),
+			TransactionID: field("transaction_id"),
+			CustomerID:    field("customer_id"),
+			CartID:        field("cart_id"),
+			Amount:        amount,
+			PaymentMethod: field("payment_method"),
+			Error:         field("error"),
+			Metadata:      map[string]interface{}{},
+		}
+
+		if raw := field("metadata"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &entry.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to parse audit metadata: %w", err)
+			}
+		}
+		for key, column := range auditFlattenedKeys {
+			if value := field(column); value != "" {
+				entry.Metadata[key] = value
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
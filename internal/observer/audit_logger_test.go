@@ -0,0 +1,110 @@
+package observer
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLoggerFormats(t *testing.T) {
+	event := Event{
+		Type:          EventPaymentSuccess,
+		TransactionID: "tx-123",
+		CustomerID:    "cust-1",
+		Amount:        42.50,
+		PaymentMethod: "credit_card",
+		Metadata: map[string]interface{}{
+			"payment_strategy": "instant",
+			"note":             "a, \"quoted\" value",
+		},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		file   string
+		format string
+	}{
+		{name: "JSON Lines", file: "audit.log", format: AuditFormatJSON},
+		{name: "CSV", file: "audit.csv", format: AuditFormatCSV},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), tc.file)
+
+			auditLogger, err := NewAuditLogger(logPath, tc.format)
+			require.NoError(t, err)
+			require.NoError(t, auditLogger.Notify(context.Background(), event))
+			require.NoError(t, auditLogger.Close())
+
+			// Reopening must not write a second CSV header.
+			auditLogger, err = NewAuditLogger(logPath, tc.format)
+			require.NoError(t, err)
+			require.NoError(t, auditLogger.Notify(context.Background(), event))
+			require.NoError(t, auditLogger.Close())
+
+			entries, err := ReadAuditLog(logPath, "")
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+
+			entry := entries[0]
+			assert.Equal(t, "payment_success", entry.EventType)
+			assert.Equal(t, "tx-123", entry.TransactionID)
+			assert.Equal(t, "cust-1", entry.CustomerID)
+			assert.InDelta(t, 42.50, entry.Amount, 0.001)
+			assert.Equal(t, "instant", entry.Metadata["payment_strategy"])
+			assert.Equal(t, "a, \"quoted\" value", entry.Metadata["note"])
+		})
+	}
+}
+
+func TestAuditLoggerSinks(t *testing.T) {
+	event := Event{Type: EventPaymentSuccess, TransactionID: "tx-sinks", Amount: 10}
+
+	var received int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer collector.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	t.Run("Fans Out Despite Failing Sink", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "audit.log")
+		fileSink, err := NewFileAuditSink(logPath, AuditFormatJSON)
+		require.NoError(t, err)
+
+		var stdout bytes.Buffer
+		auditLogger := NewAuditLoggerWithSinks(
+			NewHTTPAuditSink(broken.URL, time.Second),
+			fileSink,
+			NewWriterAuditSink(AuditSinkStdout, &stdout),
+			NewHTTPAuditSink(collector.URL, time.Second),
+		)
+
+		require.NoError(t, auditLogger.Notify(context.Background(), event))
+		require.NoError(t, auditLogger.Close())
+
+		entries, err := ReadAuditLog(logPath, "")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "tx-sinks", entries[0].TransactionID)
+		assert.Contains(t, stdout.String(), `"transaction_id":"tx-sinks"`)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&received))
+	})
+
+	t.Run("Fails When Every Sink Fails", func(t *testing.T) {
+		auditLogger := NewAuditLoggerWithSinks(NewHTTPAuditSink(broken.URL, time.Second))
+		assert.Error(t, auditLogger.Notify(context.Background(), event))
+	})
+}
@@ -0,0 +1,243 @@
+package observer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	AuditSinkFile   = "file"
+	AuditSinkHTTP   = "http"
+	AuditSinkStdout = "stdout"
+)
+
+// FileAuditSink appends entries to a local file as JSON lines or CSV.
+type FileAuditSink struct {
+	logPath string
+	format  string
+	file    *os.File
+	csv     *csv.Writer
+	mu      sync.Mutex
+}
+
+func NewFileAuditSink(logPath, format string) (*FileAuditSink, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+
+	file, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	s := &FileAuditSink{
+		logPath: logPath,
+		format:  ResolveAuditFormat(logPath, format),
+		file:    file,
+	}
+
+	if s.format == AuditFormatCSV {
+		s.csv = csv.NewWriter(file)
+
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to stat audit log: %w", err)
+		}
+		if info.Size() == 0 {
+			if err := s.writeCSVRow(auditCSVHeader); err != nil {
+				file.Close()
+				return nil, err
+			}
+		}
+	}
+
+	return s, nil
+}
+
+func (s *FileAuditSink) Write(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.format == AuditFormatCSV {
+		err = s.writeCSVEntry(entry)
+	} else {
+		err = writeJSONLine(s.file, entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileAuditSink) writeCSVEntry(entry AuditEntry) error {
+	flattened := map[string]string{}
+	remaining := map[string]interface{}{}
+
+	for key, value := range entry.Metadata {
+		if column, ok := auditFlattenedKeys[key]; ok {
+			flattened[column] = fmt.Sprint(value)
+			continue
+		}
+		remaining[key] = value
+	}
+	// The CSV columns are fixed, so loyalty changes ride in the metadata column.
+	if entry.Loyalty != nil {
+		remaining["loyalty"] = entry.Loyalty
+	}
+
+	metadata := ""
+	if len(remaining) > 0 {
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit metadata: %w", err)
+		}
+		metadata = string(data)
+	}
+
+	return s.writeCSVRow([]string{
+		entry.Timestamp,
+		entry.EventType,
+		entry.TransactionID,
+		entry.CustomerID,
+		entry.CartID,
+		strconv.FormatFloat(entry.Amount, 'f', 2, 64),
+		entry.PaymentMethod,
+		flattened["strategy"],
+		flattened["discount_code"],
+		entry.Error,
+		metadata,
+	})
+}
+
+func (s *FileAuditSink) writeCSVRow(row []string) error {
+	if err := s.csv.Write(row); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	s.csv.Flush()
+	if err := s.csv.Error(); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *FileAuditSink) Name() string {
+	return "file:" + s.logPath
+}
+
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// WriterAuditSink writes JSON lines to an io.Writer such as stdout.
+type WriterAuditSink struct {
+	name string
+	w    io.Writer
+	mu   sync.Mutex
+}
+
+func NewWriterAuditSink(name string, w io.Writer) *WriterAuditSink {
+	return &WriterAuditSink{name: name, w: w}
+}
+
+func NewStdoutAuditSink() *WriterAuditSink {
+	return NewWriterAuditSink(AuditSinkStdout, os.Stdout)
+}
+
+func (s *WriterAuditSink) Write(entry AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return writeJSONLine(s.w, entry)
+}
+
+func (s *WriterAuditSink) Name() string {
+	return s.name
+}
+
+func (s *WriterAuditSink) Close() error {
+	return nil
+}
+
+// HTTPAuditSink ships each entry as a JSON POST to a remote collector.
+type HTTPAuditSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPAuditSink(url string, timeout time.Duration) *HTTPAuditSink {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &HTTPAuditSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPAuditSink) Write(entry AuditEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ECommerce-Payment-System/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit collector returned status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *HTTPAuditSink) Name() string {
+	return "http:" + s.url
+}
+
+func (s *HTTPAuditSink) Close() error {
+	return nil
+}
+
+func writeJSONLine(w io.Writer, entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	return nil
+}
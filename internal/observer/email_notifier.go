@@ -3,21 +3,49 @@ package observer
 import (
 	"context"
 	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/ecommerce/payment-system/internal/i18n"
 	"github.com/ecommerce/payment-system/pkg/logger"
 	"go.uber.org/zap"
 )
 
+const maxDeadLetters = 100
+
+// EmailOptions tunes queueing, retries and delivery. Zero values fall back
+// to the defaults below. Username enables SMTP PLAIN auth; DryRun logs
+// messages instead of connecting to the SMTP server. Templates, when set,
+// replace the built-in wording for the event types they cover.
+type EmailOptions struct {
+	QueueSize      int
+	EnqueueTimeout time.Duration
+	RetryAttempts  int
+	RetryBackoff   time.Duration
+	Username       string
+	Password       string
+	DryRun         bool
+	Templates      *EmailTemplates
+}
+
 type EmailNotifier struct {
 	fromAddress    string
 	smtpHost       string
 	smtpPort       int
 	workerPoolSize int
+	options        EmailOptions
 	emailQueue     chan EmailMessage
+	send           func(EmailMessage) error
+	deadLetters    []EmailMessage
+	deadMu         sync.Mutex
 	wg             sync.WaitGroup
 	started        bool
+	closed         bool
 	mu             sync.Mutex
 }
 
@@ -27,16 +55,36 @@ type EmailMessage struct {
 	Body    string
 }
 
-func NewEmailNotifier(fromAddress, smtpHost string, smtpPort, workerPoolSize int) *EmailNotifier {
+func NewEmailNotifier(fromAddress, smtpHost string, smtpPort, workerPoolSize int, options EmailOptions) *EmailNotifier {
+	notifier := newEmailNotifier(fromAddress, smtpHost, smtpPort, workerPoolSize, options)
+	notifier.startWorkers()
+	return notifier
+}
+
+func newEmailNotifier(fromAddress, smtpHost string, smtpPort, workerPoolSize int, options EmailOptions) *EmailNotifier {
+	if options.QueueSize <= 0 {
+		options.QueueSize = 100
+	}
+	if options.EnqueueTimeout <= 0 {
+		options.EnqueueTimeout = 2 * time.Second
+	}
+	if options.RetryAttempts < 0 {
+		options.RetryAttempts = 0
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = 200 * time.Millisecond
+	}
+
 	notifier := &EmailNotifier{
 		fromAddress:    fromAddress,
 		smtpHost:       smtpHost,
 		smtpPort:       smtpPort,
 		workerPoolSize: workerPoolSize,
-		emailQueue:     make(chan EmailMessage, 100),
+		options:        options,
+		emailQueue:     make(chan EmailMessage, options.QueueSize),
 	}
+	notifier.send = notifier.sendEmail
 
-	notifier.startWorkers()
 	return notifier
 }
 
@@ -67,12 +115,13 @@ func (n *EmailNotifier) worker(id int) {
 	)
 
 	for msg := range n.emailQueue {
-		if err := n.sendEmail(msg); err != nil {
+		if err := n.sendWithRetry(msg); err != nil {
 			logger.Error("Failed to send email",
 				zap.Int("worker_id", id),
 				zap.String("to", msg.To),
 				zap.Error(err),
 			)
+			n.deadLetter(msg)
 		} else {
 			logger.Info("Email sent successfully",
 				zap.Int("worker_id", id),
@@ -92,70 +141,158 @@ func (n *EmailNotifier) Notify(ctx context.Context, event Event) error {
 		zap.String("transaction_id", event.TransactionID),
 	)
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	msg := n.createEmailMessage(event)
+	if msg.To == "" {
+		logger.Warn("Skipping email notification without recipient",
+			zap.String("event_type", string(event.Type)),
+			zap.String("customer_id", event.CustomerID),
+		)
+		return nil
+	}
+
+	n.mu.Lock()
+	closed := n.closed
+	n.mu.Unlock()
+	if closed {
+		n.deadLetter(msg)
+		return fmt.Errorf("email notifier is closed")
+	}
+
+	select {
+	case n.emailQueue <- msg:
+		return nil
+	default:
+	}
+
+	logger.Warn("Email queue full, waiting for capacity",
+		zap.Duration("timeout", n.options.EnqueueTimeout),
+	)
+
+	timer := time.NewTimer(n.options.EnqueueTimeout)
+	defer timer.Stop()
 
 	select {
 	case n.emailQueue <- msg:
 		return nil
 	case <-ctx.Done():
+		n.deadLetter(msg)
 		return ctx.Err()
-	default:
-		logger.Warn("Email queue full, dropping message")
-		return fmt.Errorf("email queue full")
+	case <-timer.C:
+		n.deadLetter(msg)
+		return fmt.Errorf("email queue full after %s", n.options.EnqueueTimeout)
 	}
 }
 
-func (n *EmailNotifier) GetName() string {
-	return "email_notifier"
-}
+func (n *EmailNotifier) sendWithRetry(msg EmailMessage) error {
+	var err error
+	for attempt := 0; attempt <= n.options.RetryAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := n.options.RetryBackoff * time.Duration(1<<(attempt-1))
+			logger.Info("Retrying email",
+				zap.Int("attempt", attempt),
+				zap.String("to", msg.To),
+				zap.Duration("backoff", backoff),
+			)
+			time.Sleep(backoff)
+		}
 
-func (n *EmailNotifier) createEmailMessage(event Event) EmailMessage {
-	var subject, body string
+		if err = n.send(msg); err == nil {
+			return nil
+		}
 
-	switch event.Type {
-	case EventPaymentStarted:
-		subject = "Payment Processing Started"
-		body = fmt.Sprintf(
-			"Your payment of $%.2f has been initiated.\nTransaction ID: %s",
-			event.Amount, event.TransactionID,
+		logger.Warn("Email attempt failed",
+			zap.Int("attempt", attempt+1),
+			zap.Error(err),
 		)
+	}
 
-	case EventPaymentSuccess:
-		subject = "Payment Successful"
-		body = fmt.Sprintf(
-			"Your payment of $%.2f has been processed successfully.\nTransaction ID: %s\nPayment Method: %s",
-			event.Amount, event.TransactionID, event.PaymentMethod,
-		)
+	return fmt.Errorf("email failed after %d attempts: %w", n.options.RetryAttempts+1, err)
+}
 
-	case EventPaymentFailed:
-		subject = "Payment Failed"
-		body = fmt.Sprintf(
-			"Your payment of $%.2f has failed.\nTransaction ID: %s\nPlease try again or contact support.",
-			event.Amount, event.TransactionID,
-		)
+// deadLetter keeps the most recent undeliverable messages for inspection.
+func (n *EmailNotifier) deadLetter(msg EmailMessage) {
+	n.deadMu.Lock()
+	defer n.deadMu.Unlock()
 
-	case EventRefundIssued:
-		subject = "Refund Issued"
-		body = fmt.Sprintf(
-			"A refund of $%.2f has been issued to your account.\nTransaction ID: %s",
-			event.Amount, event.TransactionID,
-		)
+	if len(n.deadLetters) >= maxDeadLetters {
+		n.deadLetters = n.deadLetters[1:]
+	}
+	n.deadLetters = append(n.deadLetters, msg)
 
-	default:
-		subject = "Payment Notification"
-		body = fmt.Sprintf("Transaction ID: %s", event.TransactionID)
+	logger.Error("Email dead-lettered",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+	)
+}
+
+func (n *EmailNotifier) DeadLetters() []EmailMessage {
+	n.deadMu.Lock()
+	defer n.deadMu.Unlock()
+
+	return append([]EmailMessage(nil), n.deadLetters...)
+}
+
+func (n *EmailNotifier) GetName() string {
+	return "email_notifier"
+}
+
+func (n *EmailNotifier) createEmailMessage(event Event) EmailMessage {
+	subject, body, ok, err := n.options.Templates.Render(event)
+	if err != nil {
+		logger.Warn("Email template failed, using default wording",
+			zap.String("event_type", string(event.Type)),
+			zap.Error(err),
+		)
+	}
+	if !ok || err != nil {
+		subject, body = defaultEmailContent(event)
 	}
 
 	return EmailMessage{
-		To:      "customer@example.com",
+		To:      event.CustomerEmail,
 		Subject: subject,
 		Body:    body,
 	}
 }
 
+func defaultEmailContent(event Event) (subject, body string) {
+	switch event.Type {
+	case EventPaymentStarted, EventPaymentFailed, EventRefundIssued:
+		id := "email." + string(event.Type)
+		return i18n.T(event.Locale, id), i18n.T(event.Locale, id+".body", event.Amount, event.TransactionID)
+
+	case EventPaymentSuccess:
+		id := "email." + string(event.Type)
+		return i18n.T(event.Locale, id),
+			i18n.T(event.Locale, id+".body", event.Amount, event.TransactionID, event.PaymentMethod)
+
+	default:
+		return i18n.T(event.Locale, "email.default"), i18n.T(event.Locale, "email.default.body", event.TransactionID)
+	}
+}
+
 func (n *EmailNotifier) sendEmail(msg EmailMessage) error {
+	if n.options.DryRun {
+		logger.Info("Email dry run, not sent",
+			zap.String("to", msg.To),
+			zap.String("subject", msg.Subject),
+		)
+		return nil
+	}
 
-	time.Sleep(50 * time.Millisecond)
+	var auth smtp.Auth
+	if n.options.Username != "" {
+		auth = smtp.PlainAuth("", n.options.Username, n.options.Password, n.smtpHost)
+	}
+
+	addr := net.JoinHostPort(n.smtpHost, strconv.Itoa(n.smtpPort))
+	if err := smtp.SendMail(addr, auth, n.fromAddress, []string{msg.To}, n.formatMessage(msg)); err != nil {
+		return fmt.Errorf("smtp send to %s failed: %w", addr, err)
+	}
 
 	logger.Debug("Email sent",
 		zap.String("to", msg.To),
@@ -165,15 +302,34 @@ func (n *EmailNotifier) sendEmail(msg EmailMessage) error {
 	return nil
 }
 
-func (n *EmailNotifier) Close() {
+func (n *EmailNotifier) formatMessage(msg EmailMessage) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.fromAddress)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// Close stops accepting emails and waits for the workers to send the ones
+// already queued. Calling it again is a no-op.
+func (n *EmailNotifier) Close() error {
 	n.mu.Lock()
-	if !n.started {
+	if !n.started || n.closed {
+		n.closed = true
 		n.mu.Unlock()
-		return
+		return nil
 	}
+	n.closed = true
 	n.mu.Unlock()
 
 	close(n.emailQueue)
 	n.wg.Wait()
 	logger.Info("Email notifier closed")
+	return nil
 }
@@ -0,0 +1,214 @@
+package observer
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailNotifierRetry(t *testing.T) {
+	event := Event{Type: EventPaymentSuccess, TransactionID: "tx-email", CustomerEmail: "jane@example.com", Amount: 10}
+
+	t.Run("Retries Transient Failure", func(t *testing.T) {
+		notifier := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 1, EmailOptions{
+			RetryAttempts: 3,
+			RetryBackoff:  time.Millisecond,
+		})
+
+		var attempts int32
+		notifier.send = func(msg EmailMessage) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return errors.New("smtp: temporary failure")
+			}
+			return nil
+		}
+		notifier.startWorkers()
+
+		require.NoError(t, notifier.Notify(context.Background(), event))
+		notifier.Close()
+
+		assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+		assert.Empty(t, notifier.DeadLetters())
+	})
+
+	t.Run("Dead Letters When Retries Exhausted", func(t *testing.T) {
+		notifier := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 1, EmailOptions{
+			RetryAttempts: 1,
+			RetryBackoff:  time.Millisecond,
+		})
+		notifier.send = func(msg EmailMessage) error {
+			return errors.New("smtp: mailbox unavailable")
+		}
+		notifier.startWorkers()
+
+		require.NoError(t, notifier.Notify(context.Background(), event))
+		notifier.Close()
+
+		require.Len(t, notifier.DeadLetters(), 1)
+		assert.Equal(t, "Payment Successful", notifier.DeadLetters()[0].Subject)
+	})
+
+	t.Run("Dead Letters When Queue Stays Full", func(t *testing.T) {
+		notifier := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 1, EmailOptions{
+			QueueSize:      1,
+			EnqueueTimeout: 10 * time.Millisecond,
+		})
+
+		require.NoError(t, notifier.Notify(context.Background(), event))
+		assert.Error(t, notifier.Notify(context.Background(), event))
+		assert.Len(t, notifier.DeadLetters(), 1)
+	})
+}
+
+// fakeSMTPServer accepts one message over a minimal SMTP dialogue and returns
+// the recipient and DATA section.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan [2]string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan [2]string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 fake ESMTP")
+
+		var rcpt string
+		var data strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				rcpt = strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>")
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				received <- [2]string{rcpt, data.String()}
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestEmailNotifierSMTP(t *testing.T) {
+	event := Event{Type: EventPaymentSuccess, TransactionID: "tx-smtp", CustomerEmail: "jane@example.com", Amount: 42}
+
+	t.Run("Sends To Customer Over SMTP", func(t *testing.T) {
+		host, port, received := fakeSMTPServer(t)
+		notifier := newEmailNotifier("noreply@example.com", host, port, 1, EmailOptions{})
+
+		require.NoError(t, notifier.sendEmail(notifier.createEmailMessage(event)))
+
+		msg := <-received
+		assert.Equal(t, "jane@example.com", msg[0])
+		assert.Contains(t, msg[1], "To: jane@example.com")
+		assert.Contains(t, msg[1], "Subject: Payment Successful")
+		assert.Contains(t, msg[1], "Transaction ID: tx-smtp")
+	})
+
+	t.Run("Dry Run Does Not Connect", func(t *testing.T) {
+		notifier := newEmailNotifier("noreply@example.com", "127.0.0.1", 1, 1, EmailOptions{DryRun: true})
+		assert.NoError(t, notifier.sendEmail(notifier.createEmailMessage(event)))
+	})
+}
+
+func TestEmailNotifierTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "payment_success.tmpl"), []byte(
+		`{{define "subject"}}Thanks, {{.CustomerName}}{{end}}
+{{define "body"}}We received {{money .Amount}} by {{.PaymentMethod}}.
+Reference: {{.TransactionID}}{{end}}
+`), 0644))
+
+	templates, err := LoadEmailTemplates(dir)
+	require.NoError(t, err)
+
+	notifier := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 1, EmailOptions{Templates: templates})
+	event := Event{
+		Type:          EventPaymentSuccess,
+		TransactionID: "tx-template",
+		CustomerEmail: "jane@example.com",
+		CustomerName:  "Jane",
+		Amount:        42.5,
+		PaymentMethod: "credit_card",
+	}
+
+	t.Run("Renders Event Fields", func(t *testing.T) {
+		msg := notifier.createEmailMessage(event)
+		assert.Equal(t, "Thanks, Jane", msg.Subject)
+		assert.Equal(t, "We received $42.50 by credit_card.\nReference: tx-template", msg.Body)
+	})
+
+	t.Run("Falls Back Without Template File", func(t *testing.T) {
+		failed := event
+		failed.Type = EventPaymentFailed
+		assert.Equal(t, "Payment Failed", notifier.createEmailMessage(failed).Subject)
+	})
+
+	t.Run("Default Wording Follows Customer Locale", func(t *testing.T) {
+		refund := event
+		refund.Type = EventRefundIssued
+		refund.Locale = "es"
+
+		msg := notifier.createEmailMessage(refund)
+		assert.Equal(t, "Reembolso emitido", msg.Subject)
+		assert.Contains(t, msg.Body, "$42.50")
+	})
+
+	t.Run("Localized Template Wins For Its Locale", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "payment_success.es.tmpl"), []byte(
+			`{{define "subject"}}Gracias, {{.CustomerName}}{{end}}{{define "body"}}Recibimos {{money .Amount}}.{{end}}`), 0644))
+		templates, err := LoadEmailTemplates(dir)
+		require.NoError(t, err)
+		localized := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 1, EmailOptions{Templates: templates})
+
+		spanish := event
+		spanish.Locale = "es-MX"
+		assert.Equal(t, "Gracias, Jane", localized.createEmailMessage(spanish).Subject)
+		assert.Equal(t, "Thanks, Jane", localized.createEmailMessage(event).Subject)
+	})
+
+	t.Run("Rejects Unknown Event Type", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "payment_sucess.tmpl"), []byte(
+			`{{define "subject"}}x{{end}}{{define "body"}}y{{end}}`), 0644))
+		_, err := LoadEmailTemplates(dir)
+		assert.ErrorContains(t, err, "payment_sucess.tmpl")
+	})
+}
@@ -0,0 +1,124 @@
+package observer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ecommerce/payment-system/internal/i18n"
+)
+
+// EmailTemplates renders customer emails from operator-supplied files. Each
+// event type has its own <event_type>.tmpl file in the template directory
+// defining a "subject" and a "body" template, executed with the Event:
+//
+//	{{define "subject"}}Payment received{{end}}
+//	{{define "body"}}Hi {{.CustomerName}}, we received {{money .Amount}}.{{end}}
+//
+// <event_type>.<locale>.tmpl files override it for that locale.
+type EmailTemplates struct {
+	dir       string
+	templates map[string]*template.Template
+}
+
+var emailTemplateFuncs = template.FuncMap{
+	"money": func(amount float64) string {
+		return fmt.Sprintf("$%.2f", amount)
+	},
+}
+
+// LoadEmailTemplates parses every *.tmpl file in dir. A file named after an
+// unknown event type, or one missing its subject or body, is an error so
+// mistakes show up at startup rather than in a customer's inbox.
+func LoadEmailTemplates(dir string) (*EmailTemplates, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("email template directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := &EmailTemplates{
+		dir:       dir,
+		templates: make(map[string]*template.Template, len(paths)),
+	}
+	for _, path := range paths {
+		name, locale, localized := strings.Cut(strings.TrimSuffix(filepath.Base(path), ".tmpl"), ".")
+		eventTypes, err := ParseEventTypes([]string{name})
+		if err != nil {
+			return nil, fmt.Errorf("email template %s: %w", filepath.Base(path), err)
+		}
+		key := name
+		if localized {
+			if !i18n.Supported(locale) {
+				return nil, fmt.Errorf("email template %s: unsupported locale %q", filepath.Base(path), locale)
+			}
+			key = templateKey(eventTypes[0], i18n.Normalize(locale))
+		}
+
+		tmpl, err := template.New(filepath.Base(path)).Funcs(emailTemplateFuncs).ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("email template %s: %w", filepath.Base(path), err)
+		}
+		for _, part := range []string{"subject", "body"} {
+			if tmpl.Lookup(part) == nil {
+				return nil, fmt.Errorf("email template %s: missing %q template", filepath.Base(path), part)
+			}
+		}
+
+		templates.templates[key] = tmpl
+	}
+
+	return templates, nil
+}
+
+func templateKey(eventType EventType, locale string) string {
+	if locale == "" {
+		return string(eventType)
+	}
+	return string(eventType) + "." + locale
+}
+
+func (t *EmailTemplates) lookup(event Event) *template.Template {
+	if t == nil {
+		return nil
+	}
+	locale := i18n.Normalize(event.Locale)
+	language, _, _ := strings.Cut(locale, "-")
+	for _, key := range []string{
+		templateKey(event.Type, locale),
+		templateKey(event.Type, language),
+		templateKey(event.Type, ""),
+	} {
+		if tmpl := t.templates[key]; tmpl != nil {
+			return tmpl
+		}
+	}
+	return nil
+}
+
+// Render executes the event's template. ok is false when the event type
+// has no template file.
+func (t *EmailTemplates) Render(event Event) (subject, body string, ok bool, err error) {
+	tmpl := t.lookup(event)
+	if tmpl == nil {
+		return "", "", false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", event); err != nil {
+		return "", "", true, err
+	}
+	// A subject is a single header line.
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", event); err != nil {
+		return "", "", true, err
+	}
+	return subject, strings.TrimSpace(buf.String()), true, nil
+}
@@ -0,0 +1,57 @@
+package observer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type LoyaltyEntryStore interface {
+	CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error
+}
+
+// LoyaltyLedger records an entry for every loyalty_changed event, so the
+// ledger holds exactly the movements that were announced to other observers.
+type LoyaltyLedger struct {
+	store LoyaltyEntryStore
+}
+
+func NewLoyaltyLedger(store LoyaltyEntryStore) *LoyaltyLedger {
+	return &LoyaltyLedger{store: store}
+}
+
+func (l *LoyaltyLedger) Notify(ctx context.Context, event Event) error {
+	if event.Type != EventLoyaltyChanged || event.Loyalty == nil {
+		return nil
+	}
+
+	entry := &domain.LoyaltyEntry{
+		ID:            domain.NewID(),
+		CustomerID:    event.CustomerID,
+		TransactionID: event.TransactionID,
+		Delta:         event.Loyalty.Delta,
+		Reason:        event.Loyalty.Reason,
+		Balance:       event.Loyalty.NewBalance,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := l.store.CreateLoyaltyEntry(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record loyalty entry: %w", err)
+	}
+
+	logger.Debug("Loyalty entry recorded",
+		zap.String("customer_id", entry.CustomerID),
+		zap.Int("delta", entry.Delta),
+		zap.String("reason", entry.Reason),
+	)
+
+	return nil
+}
+
+func (l *LoyaltyLedger) GetName() string {
+	return "loyalty_ledger"
+}
@@ -6,25 +6,35 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type MetricsCollector struct {
-	successCount   atomic.Int64
-	failureCount   atomic.Int64
-	totalAmount    atomic.Uint64
-	paymentCounts  map[string]*atomic.Int64
-	lastExport     time.Time
-	exportInterval time.Duration
-	mu             sync.RWMutex
+	successCount    atomic.Int64
+	failureCount    atomic.Int64
+	fraudBlockCount atomic.Int64
+	totalAmount     atomic.Uint64
+	paymentCounts   map[string]*atomic.Int64
+	failureCounts   map[string]*atomic.Int64
+	strategyCounts  map[string]*atomic.Int64
+	decoratorCounts map[string]*atomic.Int64
+	amounts         Histogram
+	lastExport      time.Time
+	exportInterval  time.Duration
+	mu              sync.RWMutex
 }
 
 func NewMetricsCollector(exportInterval time.Duration) *MetricsCollector {
 	return &MetricsCollector{
-		paymentCounts:  make(map[string]*atomic.Int64),
-		exportInterval: exportInterval,
-		lastExport:     time.Now(),
+		paymentCounts:   make(map[string]*atomic.Int64),
+		failureCounts:   make(map[string]*atomic.Int64),
+		strategyCounts:  make(map[string]*atomic.Int64),
+		decoratorCounts: make(map[string]*atomic.Int64),
+		amounts:         newHistogram(AmountBuckets),
+		exportInterval:  exportInterval,
+		lastExport:      time.Now(),
 	}
 }
 
@@ -38,10 +48,23 @@ func (m *MetricsCollector) Notify(ctx context.Context, event Event) error {
 	case EventPaymentSuccess:
 		m.successCount.Add(1)
 		m.addAmount(event.Amount)
+		m.observeAmount(event.Amount)
 		m.incrementPaymentMethodCount(event.PaymentMethod)
+		if event.Result != nil {
+			if event.Result.Strategy != "" {
+				m.incrementCounter(m.strategyCounts, event.Result.Strategy)
+			}
+			for _, decorator := range event.Result.AppliedDecorators {
+				m.incrementCounter(m.decoratorCounts, decorator)
+			}
+		}
 
 	case EventPaymentFailed:
 		m.failureCount.Add(1)
+		m.incrementCounter(m.failureCounts, event.PaymentMethod)
+		if errors.HasErrorCode(event.Error, errors.ErrCodeFraudDetected) {
+			m.fraudBlockCount.Add(1)
+		}
 
 	case EventRefundIssued:
 		m.addAmount(-event.Amount)
@@ -61,18 +84,37 @@ func (m *MetricsCollector) addAmount(amount float64) {
 	m.totalAmount.Add(cents)
 }
 
+func (m *MetricsCollector) observeAmount(amount float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.amounts.observe(amount)
+}
+
 func (m *MetricsCollector) incrementPaymentMethodCount(method string) {
+	m.incrementCounter(m.paymentCounts, method)
+}
+
+func (m *MetricsCollector) incrementCounter(counters map[string]*atomic.Int64, key string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	counter, exists := m.paymentCounts[method]
+	counter, exists := counters[key]
 	if !exists {
 		counter = &atomic.Int64{}
-		m.paymentCounts[method] = counter
+		counters[key] = counter
 	}
 	counter.Add(1)
 }
 
+func snapshotCounters(counters map[string]*atomic.Int64) map[string]int64 {
+	snapshot := make(map[string]int64, len(counters))
+	for key, counter := range counters {
+		snapshot[key] = counter.Load()
+	}
+	return snapshot
+}
+
 func (m *MetricsCollector) maybeExportMetrics() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -102,6 +144,7 @@ func (m *MetricsCollector) exportMetrics() {
 		zap.Int64("failed_payments", failureCount),
 		zap.Float64("success_rate", successRate),
 		zap.Float64("total_amount", totalAmount),
+		zap.Int64("fraud_blocks", m.fraudBlockCount.Load()),
 	)
 
 	for method, counter := range m.paymentCounts {
@@ -111,32 +154,62 @@ func (m *MetricsCollector) exportMetrics() {
 			zap.Int64("count", count),
 		)
 	}
+
+	for strategy, counter := range m.strategyCounts {
+		logger.Info("Payment Strategy Metrics",
+			zap.String("strategy", strategy),
+			zap.Int64("count", counter.Load()),
+		)
+	}
+
+	for decorator, counter := range m.decoratorCounts {
+		logger.Info("Decorator Metrics",
+			zap.String("decorator", decorator),
+			zap.Int64("count", counter.Load()),
+		)
+	}
+}
+
+// Close exports the metrics one last time so counts gathered since the
+// previous export are not lost at shutdown.
+func (m *MetricsCollector) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.exportMetrics()
+	m.lastExport = time.Now()
+	return nil
 }
 
 func (m *MetricsCollector) GetMetrics() Metrics {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	paymentMethodCounts := make(map[string]int64)
-	for method, counter := range m.paymentCounts {
-		paymentMethodCounts[method] = counter.Load()
-	}
-
 	return Metrics{
 		SuccessCount:        m.successCount.Load(),
 		FailureCount:        m.failureCount.Load(),
+		FraudBlockCount:     m.fraudBlockCount.Load(),
 		TotalAmount:         float64(m.totalAmount.Load()) / 100.0,
-		PaymentMethodCounts: paymentMethodCounts,
+		PaymentMethodCounts: snapshotCounters(m.paymentCounts),
+		FailureMethodCounts: snapshotCounters(m.failureCounts),
+		StrategyCounts:      snapshotCounters(m.strategyCounts),
+		DecoratorCounts:     snapshotCounters(m.decoratorCounts),
+		AmountHistogram:     m.amounts.snapshot(),
 	}
 }
 
 func (m *MetricsCollector) Reset() {
 	m.successCount.Store(0)
 	m.failureCount.Store(0)
+	m.fraudBlockCount.Store(0)
 	m.totalAmount.Store(0)
 
 	m.mu.Lock()
 	m.paymentCounts = make(map[string]*atomic.Int64)
+	m.failureCounts = make(map[string]*atomic.Int64)
+	m.strategyCounts = make(map[string]*atomic.Int64)
+	m.decoratorCounts = make(map[string]*atomic.Int64)
+	m.amounts = newHistogram(AmountBuckets)
 	m.mu.Unlock()
 
 	logger.Info("Metrics reset")
@@ -145,6 +218,19 @@ func (m *MetricsCollector) Reset() {
 type Metrics struct {
 	SuccessCount        int64            `json:"success_count"`
 	FailureCount        int64            `json:"failure_count"`
+	FraudBlockCount     int64            `json:"fraud_block_count"`
 	TotalAmount         float64          `json:"total_amount"`
 	PaymentMethodCounts map[string]int64 `json:"payment_method_counts"`
+	FailureMethodCounts map[string]int64 `json:"failure_method_counts"`
+	StrategyCounts      map[string]int64 `json:"strategy_counts"`
+	DecoratorCounts     map[string]int64 `json:"decorator_counts"`
+	AmountHistogram     Histogram        `json:"amount_histogram"`
+}
+
+func (m Metrics) SuccessRate() float64 {
+	total := m.SuccessCount + m.FailureCount
+	if total == 0 {
+		return 0
+	}
+	return float64(m.SuccessCount) / float64(total)
 }
@@ -0,0 +1,166 @@
+package observer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AmountBuckets are the upper bounds of the payment amount histogram.
+var AmountBuckets = []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Histogram counts observations per bucket. Counts are cumulative, as in the
+// Prometheus exposition format, with the last entry for +Inf.
+type Histogram struct {
+	Buckets []float64 `json:"buckets"`
+	Counts  []int64   `json:"counts"`
+	Sum     float64   `json:"sum"`
+	Count   int64     `json:"count"`
+}
+
+func newHistogram(buckets []float64) Histogram {
+	return Histogram{
+		Buckets: buckets,
+		Counts:  make([]int64, len(buckets)+1),
+	}
+}
+
+func (h *Histogram) observe(value float64) {
+	for i, bound := range h.Buckets {
+		if value <= bound {
+			h.Counts[i]++
+		}
+	}
+	h.Counts[len(h.Buckets)]++
+	h.Sum += value
+	h.Count++
+}
+
+func (h Histogram) snapshot() Histogram {
+	return Histogram{
+		Buckets: append([]float64(nil), h.Buckets...),
+		Counts:  append([]int64(nil), h.Counts...),
+		Sum:     h.Sum,
+		Count:   h.Count,
+	}
+}
+
+// WritePrometheus writes the snapshot in the Prometheus text exposition
+// format.
+func (m Metrics) WritePrometheus(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "# HELP payments_total Payments processed, by payment method and outcome.")
+	fmt.Fprintln(out, "# TYPE payments_total counter")
+	for _, status := range []struct {
+		name   string
+		counts map[string]int64
+	}{
+		{"success", m.PaymentMethodCounts},
+		{"failed", m.FailureMethodCounts},
+	} {
+		for _, method := range sortedKeys(status.counts) {
+			label := method
+			if label == "" {
+				label = "unknown"
+			}
+			fmt.Fprintf(out, "payments_total{method=%s,status=%s} %d\n",
+				quoteLabel(label), quoteLabel(status.name), status.counts[method])
+		}
+	}
+
+	fmt.Fprintln(out, "# HELP payments_fraud_blocked_total Payments blocked by fraud checks.")
+	fmt.Fprintln(out, "# TYPE payments_fraud_blocked_total counter")
+	fmt.Fprintf(out, "payments_fraud_blocked_total %d\n", m.FraudBlockCount)
+
+	h := m.AmountHistogram
+	fmt.Fprintln(out, "# HELP payment_amount Amounts of successful payments.")
+	fmt.Fprintln(out, "# TYPE payment_amount histogram")
+	for i, bound := range h.Buckets {
+		fmt.Fprintf(out, "payment_amount_bucket{le=%s} %d\n", quoteLabel(formatFloat(bound)), h.Counts[i])
+	}
+	var total int64
+	if len(h.Counts) > 0 {
+		total = h.Counts[len(h.Counts)-1]
+	}
+	fmt.Fprintf(out, "payment_amount_bucket{le=\"+Inf\"} %d\n", total)
+	fmt.Fprintf(out, "payment_amount_sum %s\n", formatFloat(h.Sum))
+	fmt.Fprintf(out, "payment_amount_count %d\n", h.Count)
+
+	fmt.Fprintln(out, "# HELP payments_success_rate Fraction of payments that succeeded.")
+	fmt.Fprintln(out, "# TYPE payments_success_rate gauge")
+	fmt.Fprintf(out, "payments_success_rate %s\n", formatFloat(m.SuccessRate()))
+
+	return out.Flush()
+}
+
+// ServeHTTP serves the current snapshot for Prometheus to scrape.
+func (m *MetricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := m.GetMetrics().WritePrometheus(w); err != nil {
+		logger.Warn("Failed to write metrics", zap.Error(err))
+	}
+}
+
+// StartMetricsServer serves /metrics on addr in the background. The listener
+// is opened before returning so a bad or busy address is reported to the
+// caller; stop the server with Shutdown.
+func (m *MetricsCollector) StartMetricsServer(addr string) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error("Metrics server stopped", zap.Error(err))
+		}
+	}()
+
+	logger.Info("Metrics server listening", zap.String("addr", listener.Addr().String()))
+
+	return server, nil
+}
+
+// StopMetricsServer gives in-flight scrapes a moment to finish.
+func StopMetricsServer(server *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+func sortedKeys(counts map[string]int64) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func quoteLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
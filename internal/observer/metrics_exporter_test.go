@@ -0,0 +1,51 @@
+package observer
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsExporter(t *testing.T) {
+	ctx := context.Background()
+	collector := NewMetricsCollector(0)
+
+	for _, event := range []Event{
+		{Type: EventPaymentSuccess, PaymentMethod: "credit_card", Amount: 40},
+		{Type: EventPaymentSuccess, PaymentMethod: "credit_card", Amount: 700},
+		{Type: EventPaymentSuccess, PaymentMethod: "paypal", Amount: 20000},
+		{Type: EventPaymentFailed, PaymentMethod: "paypal", Error: errors.NewFraudDetectedError("blocked")},
+	} {
+		require.NoError(t, collector.Notify(ctx, event))
+	}
+
+	t.Run("Serves Prometheus Text Format", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+
+		body := recorder.Body.String()
+		assert.Contains(t, body, "# TYPE payments_total counter\n")
+		assert.Contains(t, body, `payments_total{method="credit_card",status="success"} 2`+"\n")
+		assert.Contains(t, body, `payments_total{method="paypal",status="success"} 1`+"\n")
+		assert.Contains(t, body, `payments_total{method="paypal",status="failed"} 1`+"\n")
+		assert.Contains(t, body, "payments_fraud_blocked_total 1\n")
+		assert.Contains(t, body, "# TYPE payment_amount histogram\n")
+		assert.Contains(t, body, `payment_amount_bucket{le="50"} 1`+"\n")
+		assert.Contains(t, body, `payment_amount_bucket{le="1000"} 2`+"\n")
+		assert.Contains(t, body, `payment_amount_bucket{le="+Inf"} 3`+"\n")
+		assert.Contains(t, body, "payment_amount_sum 20740\n")
+		assert.Contains(t, body, "payment_amount_count 3\n")
+		assert.Contains(t, body, "payments_success_rate 0.75\n")
+	})
+
+	t.Run("Reports Bad Listen Address", func(t *testing.T) {
+		_, err := collector.StartMetricsServer("not-an-address")
+		assert.Error(t, err)
+	})
+}
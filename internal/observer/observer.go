@@ -2,6 +2,10 @@ package observer
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/ecommerce/payment-system/internal/payment"
@@ -16,15 +20,112 @@ const (
 	EventPaymentSuccess EventType = "payment_success"
 	EventPaymentFailed  EventType = "payment_failed"
 	EventRefundIssued   EventType = "refund_issued"
+
+	EventCartCreated   EventType = "cart_created"
+	EventItemAdded     EventType = "item_added"
+	EventCartAbandoned EventType = "cart_abandoned"
+
+	EventInventoryChanged EventType = "inventory_changed"
+	EventLowStock         EventType = "low_stock"
+
+	EventLoyaltyChanged EventType = "loyalty_changed"
+)
+
+var PaymentEvents = []EventType{
+	EventPaymentStarted,
+	EventPaymentSuccess,
+	EventPaymentFailed,
+	EventRefundIssued,
+}
+
+// OutcomeEvents are the payment events that report a result, excluding
+// EventPaymentStarted.
+var OutcomeEvents = []EventType{
+	EventPaymentSuccess,
+	EventPaymentFailed,
+	EventRefundIssued,
+}
+
+var CartEvents = []EventType{
+	EventCartCreated,
+	EventItemAdded,
+	EventCartAbandoned,
+}
+
+var InventoryEvents = []EventType{
+	EventInventoryChanged,
+	EventLowStock,
+}
+
+var LoyaltyEvents = []EventType{
+	EventLoyaltyChanged,
+}
+
+// ParseEventTypes converts configured event names, rejecting unknown ones.
+func ParseEventTypes(names []string) ([]EventType, error) {
+	known := make(map[EventType]bool)
+	for _, group := range [][]EventType{PaymentEvents, CartEvents, InventoryEvents, LoyaltyEvents} {
+		for _, eventType := range group {
+			known[eventType] = true
+		}
+	}
+
+	eventTypes := make([]EventType, 0, len(names))
+	for _, name := range names {
+		eventType := EventType(strings.TrimSpace(name))
+		if !known[eventType] {
+			return nil, fmt.Errorf("unknown event type %q", name)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	return eventTypes, nil
+}
+
+const (
+	InventoryReasonReservation = "reservation"
+	InventoryReasonRelease     = "release"
+	InventoryReasonRestock     = "restock"
 )
 
+type InventoryChange struct {
+	ProductID string `json:"product_id"`
+	SKU       string `json:"sku,omitempty"`
+	Delta     int    `json:"delta"`
+	NewLevel  int    `json:"new_level"`
+	Reason    string `json:"reason"`
+}
+
+const (
+	LoyaltyReasonEarn     = "earn"
+	LoyaltyReasonRedeem   = "redeem"
+	LoyaltyReasonRefund   = "refund"
+	LoyaltyReasonCashback = "cashback"
+	LoyaltyReasonAdjust   = "adjust"
+)
+
+// LoyaltyChange is one movement of a customer's loyalty balance. NewBalance
+// is the balance right after this movement.
+type LoyaltyChange struct {
+	Delta      int    `json:"delta"`
+	Reason     string `json:"reason"`
+	NewBalance int    `json:"new_balance"`
+}
+
 type Event struct {
 	Type          EventType              `json:"type"`
 	TransactionID string                 `json:"transaction_id"`
 	CustomerID    string                 `json:"customer_id"`
+	CustomerEmail string                 `json:"customer_email,omitempty"`
+	CustomerName  string                 `json:"customer_name,omitempty"`
+	Locale        string                 `json:"locale,omitempty"`
+	CartID        string                 `json:"cart_id,omitempty"`
 	Amount        float64                `json:"amount"`
 	PaymentMethod string                 `json:"payment_method"`
 	Result        *payment.PaymentResult `json:"result,omitempty"`
+	Inventory     []InventoryChange      `json:"inventory,omitempty"`
+	Loyalty       *LoyaltyChange         `json:"loyalty,omitempty"`
+	Order         *OrderSnapshot         `json:"order,omitempty"`
 	Error         error                  `json:"error,omitempty"`
 	Metadata      map[string]interface{} `json:"metadata"`
 	Timestamp     string                 `json:"timestamp"`
@@ -35,50 +136,158 @@ type Observer interface {
 	GetName() string
 }
 
+// Handle identifies one attached observer instance so it can be detached
+// even when several observers share a name.
+type Handle uint64
+
+type subscription struct {
+	handle     Handle
+	observer   Observer
+	eventTypes map[EventType]bool
+}
+
+func (s subscription) accepts(eventType EventType) bool {
+	if s.eventTypes == nil {
+		return true
+	}
+	return s.eventTypes[eventType]
+}
+
 type Subject struct {
-	observers []Observer
-	mu        sync.RWMutex
+	observers  []subscription
+	nextHandle Handle
+	mu         sync.RWMutex
 }
 
 func NewSubject() *Subject {
 	return &Subject{
-		observers: make([]Observer, 0),
+		observers: make([]subscription, 0),
 	}
 }
 
-func (s *Subject) Attach(observer Observer) {
+func (s *Subject) Attach(observer Observer) Handle {
+	return s.attach(subscription{observer: observer})
+}
+
+func (s *Subject) AttachFiltered(observer Observer, eventTypes ...EventType) Handle {
+	filter := make(map[EventType]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		filter[eventType] = true
+	}
+	return s.attach(subscription{observer: observer, eventTypes: filter})
+}
+
+// attach registers sub and returns its handle. Attaching an instance that is
+// already registered keeps the existing subscription and returns its handle.
+func (s *Subject) attach(sub subscription) Handle {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.observers = append(s.observers, observer)
+	for _, existing := range s.observers {
+		if sameObserver(existing.observer, sub.observer) {
+			logger.Warn("Observer already attached",
+				zap.String("observer", sub.observer.GetName()),
+				zap.Uint64("handle", uint64(existing.handle)),
+			)
+			return existing.handle
+		}
+	}
+
+	s.nextHandle++
+	sub.handle = s.nextHandle
+	s.observers = append(s.observers, sub)
 	logger.Info("Observer attached",
-		zap.String("observer", observer.GetName()),
+		zap.String("observer", sub.observer.GetName()),
+		zap.Uint64("handle", uint64(sub.handle)),
+		zap.Int("subscribed_events", len(sub.eventTypes)),
 		zap.Int("total_observers", len(s.observers)),
 	)
+	return sub.handle
 }
 
-func (s *Subject) Detach(observer Observer) {
+// Detach removes the observer registered under handle and reports whether
+// one was found.
+func (s *Subject) Detach(handle Handle) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for i, obs := range s.observers {
-		if obs.GetName() == observer.GetName() {
+	for i, sub := range s.observers {
+		if sub.handle == handle {
 			s.observers = append(s.observers[:i], s.observers[i+1:]...)
 			logger.Info("Observer detached",
-				zap.String("observer", observer.GetName()),
+				zap.String("observer", sub.observer.GetName()),
+				zap.Uint64("handle", uint64(handle)),
 				zap.Int("total_observers", len(s.observers)),
 			)
-			return
+			return true
 		}
 	}
+	return false
+}
+
+// sameObserver reports whether a and b are the same instance. Observers of a
+// non-comparable dynamic type are never considered equal.
+func sameObserver(a, b Observer) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
 }
 
+// Close detaches every observer and closes the ones that hold resources
+// (those implementing io.Closer), such as the email worker pool, which drains
+// its queue first. It returns the first close error.
+func (s *Subject) Close() error {
+	s.mu.Lock()
+	observers := s.observers
+	s.observers = nil
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, sub := range observers {
+		closer, ok := sub.observer.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := closer.Close(); err != nil {
+			logger.Error("Failed to close observer",
+				zap.String("observer", sub.observer.GetName()),
+				zap.Error(err),
+			)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		logger.Info("Observer closed",
+			zap.String("observer", sub.observer.GetName()),
+		)
+	}
+
+	return firstErr
+}
+
+// Notify delivers event to the subscribed observers concurrently and waits
+// until they finish or ctx is done.
 func (s *Subject) Notify(ctx context.Context, event Event) {
 	s.mu.RLock()
-	observers := make([]Observer, len(s.observers))
-	copy(observers, s.observers)
+	observers := make([]Observer, 0, len(s.observers))
+	for _, sub := range s.observers {
+		if sub.accepts(event.Type) {
+			observers = append(observers, sub.observer)
+		}
+	}
 	s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		logger.Warn("Skipping notification, context already done",
+			zap.String("event_type", string(event.Type)),
+			zap.Error(err),
+		)
+		return
+	}
+
 	logger.Info("Notifying observers",
 		zap.String("event_type", string(event.Type)),
 		zap.Int("observer_count", len(observers)),
@@ -113,9 +322,23 @@ func (s *Subject) Notify(ctx context.Context, event Event) {
 		}(observer)
 	}
 
-	wg.Wait()
+	// Observers get ctx too and are expected to give up once it is done; Notify
+	// stops waiting for stragglers at the same point.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-	logger.Info("All observers notified",
-		zap.String("event_type", string(event.Type)),
-	)
+	select {
+	case <-done:
+		logger.Info("All observers notified",
+			zap.String("event_type", string(event.Type)),
+		)
+	case <-ctx.Done():
+		logger.Warn("Stopped waiting for observers",
+			zap.String("event_type", string(event.Type)),
+			zap.Error(ctx.Err()),
+		)
+	}
 }
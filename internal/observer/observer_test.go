@@ -56,9 +56,10 @@ func TestObserverPattern(t *testing.T) {
 		observer1 := &mockObserver{name: "observer1"}
 		observer2 := &mockObserver{name: "observer2"}
 
-		subject.Attach(observer1)
+		handle := subject.Attach(observer1)
 		subject.Attach(observer2)
-		subject.Detach(observer1)
+		assert.True(t, subject.Detach(handle))
+		assert.False(t, subject.Detach(handle))
 
 		event := Event{
 			Type:      EventPaymentSuccess,
@@ -71,4 +72,133 @@ func TestObserverPattern(t *testing.T) {
 		assert.Equal(t, int32(0), observer1.notifyCount.Load())
 		assert.Equal(t, int32(1), observer2.notifyCount.Load())
 	})
+	t.Run("Detach Same-Named Instance", func(t *testing.T) {
+		subject := NewSubject()
+		first := &mockObserver{name: "webhook_notifier"}
+		second := &mockObserver{name: "webhook_notifier"}
+
+		subject.Attach(first)
+		handle := subject.Attach(second)
+		subject.Detach(handle)
+
+		subject.Notify(context.Background(), Event{Type: EventPaymentSuccess})
+
+		assert.Equal(t, int32(1), first.notifyCount.Load())
+		assert.Equal(t, int32(0), second.notifyCount.Load())
+	})
+
+	t.Run("Attach Same Instance Twice", func(t *testing.T) {
+		subject := NewSubject()
+		observer1 := &mockObserver{name: "observer1"}
+
+		handle := subject.Attach(observer1)
+		assert.Equal(t, handle, subject.AttachFiltered(observer1, EventPaymentSuccess))
+
+		subject.Notify(context.Background(), Event{Type: EventPaymentSuccess})
+
+		assert.Equal(t, int32(1), observer1.notifyCount.Load())
+	})
+	t.Run("Filtered Observer", func(t *testing.T) {
+		subject := NewSubject()
+		paymentObserver := &mockObserver{name: "payments"}
+		cartObserver := &mockObserver{name: "carts"}
+
+		subject.AttachFiltered(paymentObserver, PaymentEvents...)
+		subject.Attach(cartObserver)
+
+		event := Event{
+			Type:      EventItemAdded,
+			CartID:    "cart-123",
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+
+		subject.Notify(context.Background(), event)
+		time.Sleep(100 * time.Millisecond)
+
+		assert.Equal(t, int32(0), paymentObserver.notifyCount.Load())
+		assert.Equal(t, int32(1), cartObserver.notifyCount.Load())
+		assert.Equal(t, EventItemAdded, cartObserver.lastEvent.Type)
+	})
+}
+
+func TestParseEventTypes(t *testing.T) {
+	eventTypes, err := ParseEventTypes([]string{"payment_success", " refund_issued"})
+	assert.NoError(t, err)
+	assert.Equal(t, []EventType{EventPaymentSuccess, EventRefundIssued}, eventTypes)
+
+	_, err = ParseEventTypes([]string{"payment_sucess"})
+	assert.ErrorContains(t, err, "payment_sucess")
+}
+
+type blockingObserver struct {
+	release chan struct{}
+}
+
+func (b *blockingObserver) Notify(ctx context.Context, event Event) error {
+	<-b.release
+	return nil
+}
+
+func (b *blockingObserver) GetName() string {
+	return "blocking"
+}
+
+func TestSubjectNotifyContext(t *testing.T) {
+	t.Run("Done Context Skips Observers", func(t *testing.T) {
+		subject := NewSubject()
+		observer1 := &mockObserver{name: "observer1"}
+		subject.Attach(observer1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		subject.Notify(ctx, Event{Type: EventPaymentSuccess})
+
+		assert.Equal(t, int32(0), observer1.notifyCount.Load())
+	})
+
+	t.Run("Stops Waiting At Deadline", func(t *testing.T) {
+		subject := NewSubject()
+		release := make(chan struct{})
+		defer close(release)
+		subject.Attach(&blockingObserver{release: release})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		subject.Notify(ctx, Event{Type: EventPaymentSuccess})
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestSubjectClose(t *testing.T) {
+	t.Run("Drains Queued Emails", func(t *testing.T) {
+		notifier := newEmailNotifier("noreply@example.com", "smtp.example.com", 587, 2, EmailOptions{})
+
+		var sent atomic.Int32
+		notifier.send = func(msg EmailMessage) error {
+			time.Sleep(10 * time.Millisecond)
+			sent.Add(1)
+			return nil
+		}
+		notifier.startWorkers()
+
+		subject := NewSubject()
+		subject.Attach(notifier)
+		counter := &mockObserver{name: "counter"}
+		subject.Attach(counter)
+
+		for i := 0; i < 5; i++ {
+			subject.Notify(context.Background(), Event{Type: EventPaymentSuccess, CustomerEmail: "jane@example.com", Amount: 10})
+		}
+
+		assert.NoError(t, subject.Close())
+		assert.Equal(t, int32(5), sent.Load())
+		assert.Empty(t, notifier.DeadLetters())
+
+		subject.Notify(context.Background(), Event{Type: EventPaymentSuccess, CustomerEmail: "jane@example.com"})
+		assert.Equal(t, int32(5), counter.notifyCount.Load())
+		assert.NoError(t, notifier.Close())
+	})
 }
@@ -0,0 +1,119 @@
+package observer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+)
+
+// OrderSnapshot is the itemized order attached to EventPaymentStarted so
+// screening and analytics consumers see what is being bought before the
+// payment completes. Customer contact details and sensitive metadata values
+// are masked.
+type OrderSnapshot struct {
+	CartID    string                 `json:"cart_id"`
+	Items     []OrderSnapshotItem    `json:"items"`
+	ItemCount int                    `json:"item_count"`
+	Subtotal  float64                `json:"subtotal"`
+	Customer  CustomerSnapshot       `json:"customer"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type OrderSnapshotItem struct {
+	ProductID string  `json:"product_id"`
+	Name      string  `json:"name"`
+	SKU       string  `json:"sku"`
+	Category  string  `json:"category"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
+
+type CustomerSnapshot struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	Phone         string    `json:"phone,omitempty"`
+	City          string    `json:"city,omitempty"`
+	State         string    `json:"state,omitempty"`
+	Country       string    `json:"country,omitempty"`
+	LoyaltyPoints int       `json:"loyalty_points"`
+	CustomerSince time.Time `json:"customer_since"`
+}
+
+var sensitiveMetadataKeys = []string{"token", "password", "secret", "card", "cvv"}
+
+func NewOrderSnapshot(cart *domain.Cart, customer *domain.Customer, metadata map[string]interface{}) *OrderSnapshot {
+	breakdown := cart.Breakdown(domain.PricingInputs{})
+
+	snapshot := &OrderSnapshot{
+		CartID:    cart.ID,
+		Items:     make([]OrderSnapshotItem, 0, len(cart.Items)),
+		ItemCount: breakdown.ItemCount,
+		Subtotal:  breakdown.Subtotal,
+		Customer: CustomerSnapshot{
+			ID:            customer.ID,
+			Email:         MaskEmail(customer.Email),
+			Phone:         maskTail(customer.Phone, 4),
+			City:          customer.Address.City,
+			State:         customer.Address.State,
+			Country:       customer.Address.Country,
+			LoyaltyPoints: customer.LoyaltyPoints,
+			CustomerSince: customer.CreatedAt,
+		},
+	}
+
+	for _, item := range cart.Items {
+		snapshot.Items = append(snapshot.Items, OrderSnapshotItem{
+			ProductID: item.ProductID,
+			Name:      item.Product.Name,
+			SKU:       item.Product.SKU,
+			Category:  item.Product.Category,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Total:     item.Price * float64(item.Quantity),
+		})
+	}
+
+	if len(metadata) > 0 {
+		snapshot.Metadata = make(map[string]interface{}, len(metadata))
+		for key, value := range metadata {
+			if isSensitiveKey(key) {
+				value = "****"
+			}
+			snapshot.Metadata[key] = value
+		}
+	}
+
+	return snapshot
+}
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. j***@example.com.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return maskTail(email, 0)
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+func maskTail(value string, visible int) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= visible {
+		return strings.Repeat("*", len(value))
+	}
+	return strings.Repeat("*", len(value)-visible) + value[len(value)-visible:]
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveMetadataKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
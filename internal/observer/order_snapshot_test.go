@@ -0,0 +1,47 @@
+package observer
+
+import (
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrderSnapshot(t *testing.T) {
+	customer := &domain.Customer{
+		ID:      "cust-1",
+		Email:   "jane.doe@example.com",
+		Phone:   "+15551234567",
+		Address: domain.Address{Street: "1 Main St", City: "Austin", State: "TX", Country: "US"},
+	}
+
+	cart := &domain.Cart{ID: "cart-1", CustomerID: customer.ID}
+	cart.AddItem(domain.Product{ID: "prod-1", Name: "Laptop", SKU: "LAP-001", Category: "Electronics", Price: 999.99}, 1)
+	cart.AddItem(domain.Product{ID: "prod-2", Name: "Mouse", SKU: "MOU-001", Category: "Accessories", Price: 25.00}, 2)
+
+	snapshot := NewOrderSnapshot(cart, customer, map[string]interface{}{
+		"device_id":    "dev-42",
+		"wallet_token": "apay_abcdef",
+	})
+
+	t.Run("Itemizes Cart", func(t *testing.T) {
+		require.Len(t, snapshot.Items, 2)
+		assert.Equal(t, "Electronics", snapshot.Items[0].Category)
+		assert.Equal(t, 2, snapshot.Items[1].Quantity)
+		assert.Equal(t, 50.00, snapshot.Items[1].Total)
+		assert.Equal(t, 3, snapshot.ItemCount)
+		assert.InDelta(t, 1049.99, snapshot.Subtotal, 0.001)
+	})
+
+	t.Run("Masks Customer Contact Details", func(t *testing.T) {
+		assert.Equal(t, "j***@example.com", snapshot.Customer.Email)
+		assert.Equal(t, "********4567", snapshot.Customer.Phone)
+		assert.Equal(t, "TX", snapshot.Customer.State)
+	})
+
+	t.Run("Masks Sensitive Metadata", func(t *testing.T) {
+		assert.Equal(t, "dev-42", snapshot.Metadata["device_id"])
+		assert.Equal(t, "****", snapshot.Metadata["wallet_token"])
+	})
+}
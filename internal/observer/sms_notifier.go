@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -68,7 +69,14 @@ func (n *SMSNotifier) checkRateLimit() error {
 	n.messageTimes = recent
 
 	if len(n.messageTimes) >= n.rateLimit {
-		return fmt.Errorf("SMS rate limit exceeded (%d messages per minute)", n.rateLimit)
+		retryAfter := time.Minute
+		if i := len(n.messageTimes) - n.rateLimit; i >= 0 && i < len(n.messageTimes) {
+			retryAfter = time.Until(n.messageTimes[i].Add(time.Minute))
+		}
+		return errors.NewRateLimitError(
+			fmt.Sprintf("SMS rate limit exceeded (%d messages per minute)", n.rateLimit),
+			retryAfter,
+		)
 	}
 
 	return nil
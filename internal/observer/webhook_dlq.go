@@ -0,0 +1,206 @@
+package observer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// WebhookDeadLetter is a webhook delivery that failed after every retry.
+// Payload is the exact request body so a replay is signed over the same
+// bytes, and EventID is kept so consumers can deduplicate it.
+type WebhookDeadLetter struct {
+	EventID       string          `json:"event_id"`
+	EventType     EventType       `json:"event_type"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	Error         string          `json:"error"`
+	FailedAt      time.Time       `json:"failed_at"`
+}
+
+// WebhookReplayResult reports what a replay delivered and what is still
+// left in the dead-letter file.
+type WebhookReplayResult struct {
+	Delivered []WebhookDeadLetter `json:"delivered"`
+	Remaining []WebhookDeadLetter `json:"remaining"`
+}
+
+// ReadWebhookDLQ reads the dead-letter file written by WebhookNotifier.
+func ReadWebhookDLQ(path string) ([]WebhookDeadLetter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var letters []WebhookDeadLetter
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var letter WebhookDeadLetter
+		if err := json.Unmarshal(scanner.Bytes(), &letter); err != nil {
+			return nil, fmt.Errorf("invalid dead letter on line %d: %w", line, err)
+		}
+		letters = append(letters, letter)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return letters, nil
+}
+
+// DLQPath returns the dead-letter file, empty when failed events are dropped.
+func (n *WebhookNotifier) DLQPath() string {
+	return n.dlqPath
+}
+
+// Replay tries each dead letter once more. Delivered letters are removed
+// from the file; the rest stay with their attempt count and last error
+// updated. Letters dead-lettered while the replay runs are kept.
+func (n *WebhookNotifier) Replay(ctx context.Context) (*WebhookReplayResult, error) {
+	if n.dlqPath == "" {
+		return nil, fmt.Errorf("no webhook dead-letter file configured")
+	}
+
+	n.dlqMu.Lock()
+	letters, err := ReadWebhookDLQ(n.dlqPath)
+	n.dlqMu.Unlock()
+	if os.IsNotExist(err) {
+		return &WebhookReplayResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &WebhookReplayResult{}
+	for _, letter := range letters {
+		if ctx.Err() != nil {
+			result.Remaining = append(result.Remaining, letter)
+			continue
+		}
+
+		err := n.sendWebhook(ctx, letter.EventID, letter.Payload)
+		if err == nil {
+			logger.Info("Replayed dead-lettered webhook",
+				zap.String("event_id", letter.EventID),
+				zap.String("transaction_id", letter.TransactionID),
+			)
+			result.Delivered = append(result.Delivered, letter)
+			continue
+		}
+
+		letter.Attempts++
+		letter.Error = err.Error()
+		letter.FailedAt = n.now()
+		result.Remaining = append(result.Remaining, letter)
+	}
+
+	n.dlqMu.Lock()
+	defer n.dlqMu.Unlock()
+
+	current, err := ReadWebhookDLQ(n.dlqPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	keep := result.Remaining
+	if len(current) > len(letters) {
+		keep = append(append([]WebhookDeadLetter{}, keep...), current[len(letters):]...)
+	}
+	if err := writeWebhookDLQ(n.dlqPath, keep); err != nil {
+		return nil, err
+	}
+
+	return result, ctx.Err()
+}
+
+func (n *WebhookNotifier) deadLetter(event Event, eventID string, payload []byte, attempts int, cause error) {
+	if n.dlqPath == "" {
+		return
+	}
+
+	letter := WebhookDeadLetter{
+		EventID:       eventID,
+		EventType:     event.Type,
+		TransactionID: event.TransactionID,
+		Payload:       payload,
+		Attempts:      attempts,
+		Error:         cause.Error(),
+		FailedAt:      n.now(),
+	}
+
+	if err := n.appendDeadLetter(letter); err != nil {
+		logger.Error("Failed to dead-letter webhook",
+			zap.String("event_id", eventID),
+			zap.String("transaction_id", event.TransactionID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	logger.Warn("Webhook dead-lettered",
+		zap.String("event_id", eventID),
+		zap.String("transaction_id", event.TransactionID),
+		zap.String("dlq_path", n.dlqPath),
+	)
+}
+
+func (n *WebhookNotifier) appendDeadLetter(letter WebhookDeadLetter) error {
+	n.dlqMu.Lock()
+	defer n.dlqMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(n.dlqPath), 0755); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+
+	file, err := os.OpenFile(n.dlqPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// writeWebhookDLQ replaces the dead-letter file through a temporary file so
+// a crash mid-write cannot lose letters.
+func writeWebhookDLQ(path string, letters []WebhookDeadLetter) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	for _, letter := range letters {
+		data, err := json.Marshal(letter)
+		if err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to marshal dead letter: %w", err)
+		}
+		writer.Write(append(data, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
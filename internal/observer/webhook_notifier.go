@@ -3,33 +3,67 @@ package observer
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	WebhookSignatureHeader = "X-Signature-256"
+	WebhookTimestampHeader = "X-Timestamp"
+	WebhookEventIDHeader   = "X-Event-ID"
+)
+
 type WebhookNotifier struct {
 	url           string
 	timeout       time.Duration
 	retryAttempts int
+	secret        string
+	dlqPath       string
+	dlqMu         sync.Mutex
 	client        *http.Client
+	now           func() time.Time
 }
 
-func NewWebhookNotifier(url string, timeout time.Duration, retryAttempts int) *WebhookNotifier {
+// NewWebhookNotifier creates a notifier; when secret is non-empty every
+// request carries an HMAC signature consumers can verify. Events that still
+// fail after the last retry are appended to the dead-letter file at dlqPath
+// for Replay; an empty dlqPath drops them.
+func NewWebhookNotifier(url string, timeout time.Duration, retryAttempts int, secret, dlqPath string) *WebhookNotifier {
 	return &WebhookNotifier{
 		url:           url,
 		timeout:       timeout,
 		retryAttempts: retryAttempts,
+		secret:        secret,
+		dlqPath:       dlqPath,
 		client: &http.Client{
 			Timeout: timeout,
 		},
+		now: time.Now,
 	}
 }
 
+// SignWebhookPayload returns the X-Signature-256 value for a request:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Covering the timestamp lets consumers reject replayed requests.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
 	logger.Info("Sending webhook notification",
 		zap.String("event_type", string(event.Type)),
@@ -42,6 +76,19 @@ func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	// Retries reuse the event ID so consumers can deduplicate deliveries.
+	eventID := uuid.New().String()
+
+	attempts, err := n.deliver(ctx, event, eventID, payload)
+	if err != nil {
+		n.deadLetter(event, eventID, payload, attempts, err)
+	}
+	return err
+}
+
+// deliver sends payload until it succeeds or the retries run out, returning
+// how many attempts were made.
+func (n *WebhookNotifier) deliver(ctx context.Context, event Event, eventID string, payload []byte) (int, error) {
 	var lastErr error
 	for attempt := 0; attempt <= n.retryAttempts; attempt++ {
 		if attempt > 0 {
@@ -50,33 +97,51 @@ func (n *WebhookNotifier) Notify(ctx context.Context, event Event) error {
 				zap.String("transaction_id", event.TransactionID),
 			)
 
-			time.Sleep(time.Duration(attempt) * time.Second)
+			if err := sleepContext(ctx, time.Duration(attempt)*time.Second); err != nil {
+				return attempt, fmt.Errorf("webhook abandoned after %d attempts: %w", attempt, err)
+			}
 		}
 
-		err := n.sendWebhook(ctx, payload)
+		err := n.sendWebhook(ctx, eventID, payload)
 		if err == nil {
 			logger.Info("Webhook sent successfully",
 				zap.String("transaction_id", event.TransactionID),
 				zap.Int("attempts", attempt+1),
 			)
-			return nil
+			return attempt + 1, nil
 		}
 
 		lastErr = err
+		if ctx.Err() != nil {
+			return attempt + 1, fmt.Errorf("webhook abandoned after %d attempts: %w", attempt+1, ctx.Err())
+		}
 		logger.Warn("Webhook attempt failed",
 			zap.Int("attempt", attempt+1),
 			zap.Error(err),
 		)
 	}
 
-	return fmt.Errorf("webhook failed after %d attempts: %w", n.retryAttempts+1, lastErr)
+	return n.retryAttempts + 1, fmt.Errorf("webhook failed after %d attempts: %w", n.retryAttempts+1, lastErr)
+}
+
+// sleepContext waits for d, returning early with ctx's error once ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (n *WebhookNotifier) GetName() string {
 	return "webhook_notifier"
 }
 
-func (n *WebhookNotifier) sendWebhook(ctx context.Context, payload []byte) error {
+func (n *WebhookNotifier) sendWebhook(ctx context.Context, eventID string, payload []byte) error {
 
 	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewBuffer(payload))
 	if err != nil {
@@ -86,6 +151,13 @@ func (n *WebhookNotifier) sendWebhook(ctx context.Context, payload []byte) error
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("User-Agent", "ECommerce-Payment-System/1.0")
 
+	timestamp := strconv.FormatInt(n.now().Unix(), 10)
+	req.Header.Set(WebhookEventIDHeader, eventID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if n.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(n.secret, timestamp, payload))
+	}
+
 	resp, err := n.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
@@ -0,0 +1,124 @@
+package observer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifierSignature(t *testing.T) {
+	t.Run("Known Secret And Payload", func(t *testing.T) {
+		signature := SignWebhookPayload("whsec_test", "1700000000", []byte(`{"type":"payment_success"}`))
+		assert.Equal(t, "sha256=18d26b60e003035126662ebeb8bad6a674a5ea44c9c93e17a76d737315e5e0b2", signature)
+	})
+
+	t.Run("Signs Timestamp And Body", func(t *testing.T) {
+		var headers http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header.Clone()
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.URL, time.Second, 0, "whsec_test", "")
+		notifier.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+		require.NoError(t, notifier.Notify(context.Background(), Event{Type: EventPaymentSuccess, TransactionID: "tx-hook"}))
+
+		assert.Equal(t, "1700000000", headers.Get(WebhookTimestampHeader))
+		assert.NotEmpty(t, headers.Get(WebhookEventIDHeader))
+		assert.Equal(t, SignWebhookPayload("whsec_test", "1700000000", body), headers.Get(WebhookSignatureHeader))
+		assert.NotEqual(t, SignWebhookPayload("whsec_test", "1700000001", body), headers.Get(WebhookSignatureHeader))
+	})
+}
+
+func TestWebhookNotifierContext(t *testing.T) {
+	t.Run("Stops Retrying When Context Ends", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.URL, time.Second, 5, "", "")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := notifier.Notify(ctx, Event{Type: EventPaymentFailed, TransactionID: "tx-slow"})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+}
+
+func TestWebhookNotifierDeadLetter(t *testing.T) {
+	var healthy atomic.Bool
+	var eventIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		eventIDs = append(eventIDs, r.Header.Get(WebhookEventIDHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dlqPath := filepath.Join(t.TempDir(), "webhook_dlq.jsonl")
+	notifier := NewWebhookNotifier(server.URL, time.Second, 0, "", dlqPath)
+	ctx := context.Background()
+
+	t.Run("Failed Delivery Is Dead-Lettered", func(t *testing.T) {
+		err := notifier.Notify(ctx, Event{Type: EventPaymentSuccess, TransactionID: "tx-down"})
+		require.Error(t, err)
+
+		letters, err := ReadWebhookDLQ(dlqPath)
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, EventPaymentSuccess, letters[0].EventType)
+		assert.Equal(t, "tx-down", letters[0].TransactionID)
+		assert.Equal(t, 1, letters[0].Attempts)
+	})
+
+	t.Run("Replay Keeps Failures", func(t *testing.T) {
+		result, err := notifier.Replay(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, result.Delivered)
+		require.Len(t, result.Remaining, 1)
+		assert.Equal(t, 2, result.Remaining[0].Attempts)
+
+		letters, err := ReadWebhookDLQ(dlqPath)
+		require.NoError(t, err)
+		assert.Len(t, letters, 1)
+	})
+
+	t.Run("Replay Delivers With Original Event ID", func(t *testing.T) {
+		letters, err := ReadWebhookDLQ(dlqPath)
+		require.NoError(t, err)
+
+		healthy.Store(true)
+		result, err := notifier.Replay(ctx)
+		require.NoError(t, err)
+		assert.Len(t, result.Delivered, 1)
+		assert.Empty(t, result.Remaining)
+		assert.Equal(t, []string{letters[0].EventID}, eventIDs)
+
+		letters, err = ReadWebhookDLQ(dlqPath)
+		require.NoError(t, err)
+		assert.Empty(t, letters)
+	})
+}
@@ -17,6 +17,8 @@ type CreditCardPayment struct {
 	cardHolder string
 	expiryDate string
 	cvv        string
+	brand      string
+	limits     AmountLimits
 	validator  *validator.CreditCardValidator
 }
 
@@ -27,7 +29,12 @@ func NewCreditCardPayment(cardNumber, cardHolder, expiryDate, cvv string) (*Cred
 		return nil, errors.Wrap(err, errors.ErrCodeInvalidPayment, "invalid card number")
 	}
 
-	if err := v.ValidateCVV(cvv); err != nil {
+	brand, err := v.DetectBrand(cardNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInvalidPayment, "invalid card number")
+	}
+
+	if err := v.ValidateCVV(cvv, brand); err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeInvalidPayment, "invalid CVV")
 	}
 
@@ -44,11 +51,21 @@ func NewCreditCardPayment(cardNumber, cardHolder, expiryDate, cvv string) (*Cred
 		cardHolder: cardHolder,
 		expiryDate: expiryDate,
 		cvv:        cvv,
+		brand:      brand,
+		limits:     DefaultCreditCardLimits,
 		validator:  v,
 	}, nil
 }
 
-func (p *CreditCardPayment) Process(ctx context.Context, amount float64) (*PaymentResult, error) {
+func (p *CreditCardPayment) SetLimits(limits AmountLimits) {
+	if limits.Max > 0 {
+		p.limits = limits
+	}
+}
+
+func (p *CreditCardPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Processing credit card payment",
 		zap.Float64("amount", amount),
 		zap.String("card_holder", p.cardHolder),
@@ -58,8 +75,7 @@ func (p *CreditCardPayment) Process(ctx context.Context, amount float64) (*Payme
 		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
 	}
 
-	amountValidator := validator.NewAmountValidator()
-	if err := amountValidator.Validate(amount, 1.0, 10000.0); err != nil {
+	if err := validateAmount(ctx, amount, p.limits.Min, p.limits.Max); err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeValidation, "invalid payment amount")
 	}
 
@@ -73,7 +89,7 @@ func (p *CreditCardPayment) Process(ctx context.Context, amount float64) (*Payme
 		Amount:          amount,
 		OriginalAmount:  amount,
 		ProcessedAmount: amount,
-		Currency:        "USD",
+		Currency:        CurrencyFromContext(ctx),
 		PaymentMethod:   "credit_card",
 		Message:         "Payment processed successfully",
 		Metadata: map[string]interface{}{
@@ -83,6 +99,10 @@ func (p *CreditCardPayment) Process(ctx context.Context, amount float64) (*Payme
 		},
 		AppliedDecorators: []string{},
 	}
+	if request.AuthorizeOnly {
+		result.Message = "Payment authorized"
+		result.Metadata["authorization_hold"] = true
+	}
 
 	logger.Info("Credit card payment processed successfully",
 		zap.String("transaction_id", transactionID),
@@ -100,6 +120,7 @@ func (p *CreditCardPayment) GetDetails() map[string]interface{} {
 	return map[string]interface{}{
 		"type":          "credit_card",
 		"card_holder":   p.cardHolder,
+		"card_brand":    p.brand,
 		"last_4_digits": p.getLastFourDigits(),
 		"expiry_date":   p.expiryDate,
 	}
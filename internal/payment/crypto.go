@@ -15,6 +15,7 @@ import (
 type CryptoPayment struct {
 	walletAddress string
 	cryptoType    string
+	limits        AmountLimits
 	validator     *validator.CryptoAddressValidator
 }
 
@@ -40,11 +41,20 @@ func NewCryptoPayment(walletAddress, cryptoType string) (*CryptoPayment, error)
 	return &CryptoPayment{
 		walletAddress: walletAddress,
 		cryptoType:    cryptoType,
+		limits:        DefaultCryptoLimits,
 		validator:     v,
 	}, nil
 }
 
-func (p *CryptoPayment) Process(ctx context.Context, amount float64) (*PaymentResult, error) {
+func (p *CryptoPayment) SetLimits(limits AmountLimits) {
+	if limits.Max > 0 {
+		p.limits = limits
+	}
+}
+
+func (p *CryptoPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Processing crypto payment",
 		zap.Float64("amount", amount),
 		zap.String("crypto_type", p.cryptoType),
@@ -55,8 +65,7 @@ func (p *CryptoPayment) Process(ctx context.Context, amount float64) (*PaymentRe
 		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
 	}
 
-	amountValidator := validator.NewAmountValidator()
-	if err := amountValidator.Validate(amount, 10.0, 50000.0); err != nil {
+	if err := validateAmount(ctx, amount, p.limits.Min, p.limits.Max); err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeValidation, "invalid payment amount")
 	}
 
@@ -75,6 +84,7 @@ func (p *CryptoPayment) Process(ctx context.Context, amount float64) (*PaymentRe
 		Message:         "Cryptocurrency payment processed successfully",
 		Metadata: map[string]interface{}{
 			"crypto_type":    p.cryptoType,
+			"fiat_currency":  CurrencyFromContext(ctx),
 			"wallet_address": p.maskWalletAddress(),
 			"blockchain_tx":  "0x" + transactionID[:16],
 			"processed_at":   time.Now().Format(time.RFC3339),
@@ -0,0 +1,76 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/pkg/validator"
+)
+
+type currencyContextKey struct{}
+
+type chargeCurrency struct {
+	code      string
+	converter *currency.Converter
+	rate      float64
+}
+
+// WithCurrency records the currency of the amounts passed to Process.
+// Payments report it on their results, and built-in amount limits, which are
+// set in currency.DefaultCurrency, are checked through the converter. The
+// rate into currency.DefaultCurrency is looked up once here, so every check
+// and the recorded transaction use the same rate even if the source moves.
+func WithCurrency(ctx context.Context, code string, converter *currency.Converter) context.Context {
+	c := chargeCurrency{code: code, converter: converter}
+	if code != "" && code != currency.DefaultCurrency {
+		if c.converter == nil {
+			c.converter = currency.NewDefaultConverter()
+		}
+		c.rate, _ = c.converter.Rate(code, currency.DefaultCurrency)
+	}
+	return context.WithValue(ctx, currencyContextKey{}, c)
+}
+
+func CurrencyFromContext(ctx context.Context) string {
+	if c, ok := ctx.Value(currencyContextKey{}).(chargeCurrency); ok && c.code != "" {
+		return c.code
+	}
+	return currency.DefaultCurrency
+}
+
+// ToLimitCurrency converts an amount in the context currency into
+// currency.DefaultCurrency for comparison with built-in limits.
+func ToLimitCurrency(ctx context.Context, amount float64) (float64, error) {
+	c, ok := ctx.Value(currencyContextKey{}).(chargeCurrency)
+	if !ok || c.code == "" || c.code == currency.DefaultCurrency {
+		return amount, nil
+	}
+	if c.rate > 0 {
+		return amount * c.rate, nil
+	}
+
+	converter := c.converter
+	if converter == nil {
+		converter = currency.NewDefaultConverter()
+	}
+	return converter.Convert(amount, c.code, currency.DefaultCurrency)
+}
+
+// ExchangeRateFromContext returns the rate captured by WithCurrency, from
+// the context currency into currency.DefaultCurrency. It reports false when
+// the charge needs no conversion.
+func ExchangeRateFromContext(ctx context.Context) (float64, bool) {
+	c, ok := ctx.Value(currencyContextKey{}).(chargeCurrency)
+	if !ok || c.rate <= 0 {
+		return 0, false
+	}
+	return c.rate, true
+}
+
+func validateAmount(ctx context.Context, amount, min, max float64) error {
+	converted, err := ToLimitCurrency(ctx, amount)
+	if err != nil {
+		return err
+	}
+	return validator.NewAmountValidator().Validate(converted, min, max)
+}
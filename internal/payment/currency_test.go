@@ -0,0 +1,43 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentCurrency(t *testing.T) {
+	card, err := NewCreditCardPayment("4532015112830366", "John Doe", "12/30", "123")
+	require.NoError(t, err)
+
+	converter := currency.NewConverter(currency.NewStaticRateProvider(map[string]float64{
+		"USD": 1.0,
+		"EUR": 1.10,
+	}))
+
+	t.Run("Defaults To USD", func(t *testing.T) {
+		result, err := card.Process(context.Background(), PaymentRequest{Amount: 25.0})
+		require.NoError(t, err)
+		assert.Equal(t, currency.DefaultCurrency, result.Currency)
+	})
+
+	t.Run("Result Carries Order Currency", func(t *testing.T) {
+		ctx := WithCurrency(context.Background(), "EUR", converter)
+
+		result, err := card.Process(ctx, PaymentRequest{Amount: 25.0})
+		require.NoError(t, err)
+		assert.Equal(t, "EUR", result.Currency)
+	})
+
+	t.Run("Limits Are Converted", func(t *testing.T) {
+		ctx := WithCurrency(context.Background(), "EUR", converter)
+
+		// 9500 EUR is 10450 USD, above the 10000 USD card limit.
+		_, err := card.Process(ctx, PaymentRequest{Amount: 9500.0})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
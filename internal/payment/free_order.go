@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type FreeOrderPayment struct {
+	wrapped   Payment
+	threshold float64
+}
+
+func NewFreeOrderPayment(wrapped Payment, threshold float64) *FreeOrderPayment {
+	return &FreeOrderPayment{
+		wrapped:   wrapped,
+		threshold: threshold,
+	}
+}
+
+func (p *FreeOrderPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	if amount > p.threshold+1e-9 {
+		return p.wrapped.Process(ctx, request)
+	}
+
+	logger.Info("Skipping payment processor for free order",
+		zap.Float64("amount", amount),
+		zap.Float64("threshold", p.threshold),
+		zap.String("payment_method", p.wrapped.GetType()),
+	)
+
+	return &PaymentResult{
+		Success:         true,
+		TransactionID:   domain.NewID(),
+		Amount:          0,
+		OriginalAmount:  amount,
+		ProcessedAmount: 0,
+		Currency:        CurrencyFromContext(ctx),
+		PaymentMethod:   p.wrapped.GetType(),
+		Message:         "Free order, no payment required",
+		Metadata: map[string]interface{}{
+			"free_order":       true,
+			"waived_amount":    amount,
+			"free_order_limit": p.threshold,
+		},
+		AppliedDecorators: []string{},
+	}, nil
+}
+
+func (p *FreeOrderPayment) GetType() string {
+	return p.wrapped.GetType()
+}
+
+func (p *FreeOrderPayment) GetDetails() map[string]interface{} {
+	return p.wrapped.GetDetails()
+}
@@ -0,0 +1,112 @@
+package payment
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Gift card codes are 16 alphanumeric characters, optionally grouped in
+// blocks of four separated by dashes (XXXX-XXXX-XXXX-XXXX).
+var giftCardCodePattern = regexp.MustCompile(`^[A-Z0-9]{16}$`)
+
+type GiftCardPayment struct {
+	cardCode string
+	balance  float64
+	mu       sync.Mutex
+}
+
+func NewGiftCardPayment(cardCode string, balance float64) (*GiftCardPayment, error) {
+	code := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(cardCode), "-", ""))
+
+	if !giftCardCodePattern.MatchString(code) {
+		return nil, errors.NewInvalidPaymentError("invalid gift card code format")
+	}
+
+	if balance < 0 {
+		return nil, errors.NewInvalidPaymentError("gift card balance cannot be negative")
+	}
+
+	return &GiftCardPayment{
+		cardCode: code,
+		balance:  balance,
+	}, nil
+}
+
+func (p *GiftCardPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Processing gift card payment",
+		zap.Float64("amount", amount),
+		zap.String("gift_card", p.maskedCode()),
+	)
+
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
+	}
+
+	if amount <= 0 {
+		return nil, errors.NewValidationError("invalid payment amount")
+	}
+
+	p.mu.Lock()
+	if amount > p.balance {
+		p.mu.Unlock()
+		return nil, errors.NewInsufficientFundsError()
+	}
+	p.balance -= amount
+	remaining := p.balance
+	p.mu.Unlock()
+
+	transactionID := domain.NewID()
+
+	result := &PaymentResult{
+		Success:         true,
+		TransactionID:   transactionID,
+		Amount:          amount,
+		OriginalAmount:  amount,
+		ProcessedAmount: amount,
+		Currency:        CurrencyFromContext(ctx),
+		PaymentMethod:   "gift_card",
+		Message:         "Gift card payment processed successfully",
+		Metadata: map[string]interface{}{
+			"gift_card":         p.maskedCode(),
+			"remaining_balance": remaining,
+			"processed_at":      time.Now().Format(time.RFC3339),
+		},
+		AppliedDecorators: []string{},
+	}
+
+	logger.Info("Gift card payment processed successfully",
+		zap.String("transaction_id", transactionID),
+		zap.Float64("amount", amount),
+	)
+
+	return result, nil
+}
+
+func (p *GiftCardPayment) GetType() string {
+	return "gift_card"
+}
+
+func (p *GiftCardPayment) GetDetails() map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return map[string]interface{}{
+		"type":      "gift_card",
+		"gift_card": p.maskedCode(),
+		"balance":   p.balance,
+	}
+}
+
+func (p *GiftCardPayment) maskedCode() string {
+	return strings.Repeat("*", len(p.cardCode)-4) + p.cardCode[len(p.cardCode)-4:]
+}
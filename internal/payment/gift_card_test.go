@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftCardPayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Invalid Code Format", func(t *testing.T) {
+		_, err := NewGiftCardPayment("ABC-123", 50.00)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInvalidPayment))
+	})
+
+	t.Run("Deducts From Balance", func(t *testing.T) {
+		p, err := NewGiftCardPayment("abcd-efgh-jklm-1234", 50.00)
+		require.NoError(t, err)
+
+		result, err := p.Process(ctx, PaymentRequest{Amount: 20.00})
+		require.NoError(t, err)
+		assert.Equal(t, 20.00, result.Amount)
+		assert.Equal(t, 30.00, result.Metadata["remaining_balance"])
+	})
+
+	t.Run("Insufficient Balance", func(t *testing.T) {
+		p, err := NewGiftCardPayment("ABCDEFGHJKLM1234", 10.00)
+		require.NoError(t, err)
+
+		_, err = p.Process(ctx, PaymentRequest{Amount: 10.01})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInsufficientFunds))
+	})
+
+	t.Run("Concurrent Charges Never Overdraw", func(t *testing.T) {
+		p, err := NewGiftCardPayment("ABCDEFGHJKLM1234", 100.00)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		var charged atomic.Int32
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := p.Process(ctx, PaymentRequest{Amount: 10.00}); err == nil {
+					charged.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(10), charged.Load())
+		assert.Equal(t, 0.0, p.GetDetails()["balance"])
+	})
+}
@@ -0,0 +1,14 @@
+package payment
+
+type AmountLimits struct {
+	Min float64
+	Max float64
+}
+
+// Built-in limits, in currency.DefaultCurrency, that payments check in
+// Process unless PaymentConfig.Limits overrides them.
+var (
+	DefaultCreditCardLimits = AmountLimits{Min: 1, Max: 10000}
+	DefaultPayPalLimits     = AmountLimits{Min: 1, Max: 5000}
+	DefaultCryptoLimits     = AmountLimits{Min: 10, Max: 50000}
+)
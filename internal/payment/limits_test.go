@@ -0,0 +1,49 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmountLimits(t *testing.T) {
+	converter := currency.NewConverter(currency.NewStaticRateProvider(map[string]float64{
+		"USD": 1.0,
+		"EUR": 1.10,
+	}))
+
+	card, err := NewCreditCardPayment("4532015112830366", "John Doe", "12/30", "123")
+	require.NoError(t, err)
+	card.SetLimits(AmountLimits{Min: 1.0, Max: 10000.0})
+
+	t.Run("EUR Order Converted To USD Limit", func(t *testing.T) {
+		eur := WithCurrency(context.Background(), "EUR", converter)
+
+		_, err := card.Process(eur, PaymentRequest{Amount: 9000.0})
+		assert.NoError(t, err)
+
+		_, err = card.Process(eur, PaymentRequest{Amount: 9500.0})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+
+		_, err = card.Process(context.Background(), PaymentRequest{Amount: 9500.0})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown Currency", func(t *testing.T) {
+		_, err := card.Process(WithCurrency(context.Background(), "XYZ", converter), PaymentRequest{Amount: 10.0})
+		assert.True(t, errors.HasErrorCode(err, errors.ErrCodeCurrencyUnavailable))
+	})
+
+	t.Run("Limits Without A Max Keep The Built-in Ones", func(t *testing.T) {
+		paypal, err := NewPayPalPayment("buyer@example.com", "secret")
+		require.NoError(t, err)
+		paypal.SetLimits(AmountLimits{Min: 1.0})
+
+		_, err = paypal.Process(context.Background(), PaymentRequest{Amount: DefaultPayPalLimits.Max + 1})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
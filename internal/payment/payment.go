@@ -2,10 +2,12 @@ package payment
 
 import (
 	"context"
+
+	"github.com/ecommerce/payment-system/internal/domain"
 )
 
 type Payment interface {
-	Process(ctx context.Context, amount float64) (*PaymentResult, error)
+	Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error)
 	GetType() string
 	GetDetails() map[string]interface{}
 }
@@ -18,14 +20,34 @@ type PaymentResult struct {
 	ProcessedAmount   float64                `json:"processed_amount"`
 	Currency          string                 `json:"currency"`
 	PaymentMethod     string                 `json:"payment_method"`
+	Strategy          string                 `json:"strategy,omitempty"`
 	Message           string                 `json:"message"`
+	Breakdown         ResultBreakdown        `json:"breakdown"`
 	Metadata          map[string]interface{} `json:"metadata"`
 	AppliedDecorators []string               `json:"applied_decorators"`
 }
 
+// ResultBreakdown holds the amounts decorators contribute to a charge. Unlike
+// Metadata it keeps its types across a JSON roundtrip, so receipts and
+// loyalty updates read from here.
+type ResultBreakdown struct {
+	DiscountAmount        float64          `json:"discount_amount"`
+	LoyaltyDiscount       float64          `json:"loyalty_discount"`
+	TaxAmount             float64          `json:"tax_amount"`
+	TaxLines              []domain.TaxLine `json:"tax_lines,omitempty"`
+	ServiceFeeAmount      float64          `json:"service_fee_amount"`
+	CashbackAmount        float64          `json:"cashback_amount"`
+	CashbackRedeemed      float64          `json:"cashback_redeemed"`
+	LoyaltyPointsEarned   int              `json:"loyalty_points_earned"`
+	LoyaltyPointsRedeemed int              `json:"loyalty_points_redeemed"`
+}
+
 type PaymentConfig struct {
 	Currency string
 	Metadata map[string]interface{}
+	// Limits, in currency.DefaultCurrency, override the method's built-in
+	// amount limits when Max is set.
+	Limits AmountLimits
 
 	CardNumber string
 	CardHolder string
@@ -37,4 +59,32 @@ type PaymentConfig struct {
 
 	WalletAddress string
 	CryptoType    string
+
+	GiftCardCode    string
+	GiftCardBalance float64
+
+	WalletToken    string
+	WalletProvider string
+
+	// SavedToken charges a vault method in place of the raw details above.
+	SavedToken   string
+	SavedDetails map[string]interface{}
+}
+
+// ConfigFromDetails copies the details a customer entered into a config.
+func ConfigFromDetails(details domain.PaymentDetails) PaymentConfig {
+	return PaymentConfig{
+		CardNumber:      details.CardNumber,
+		CardHolder:      details.CardHolder,
+		ExpiryDate:      details.ExpiryDate,
+		CVV:             details.CVV,
+		PayPalEmail:     details.PayPalEmail,
+		PayPalPassword:  details.PayPalPassword,
+		WalletAddress:   details.WalletAddress,
+		CryptoType:      details.CryptoType,
+		WalletToken:     details.WalletToken,
+		WalletProvider:  details.WalletProvider,
+		GiftCardCode:    details.GiftCardCode,
+		GiftCardBalance: details.GiftCardBalance,
+	}
 }
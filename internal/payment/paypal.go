@@ -14,6 +14,7 @@ import (
 type PayPalPayment struct {
 	email     string
 	password  string
+	limits    AmountLimits
 	validator *validator.EmailValidator
 }
 
@@ -31,11 +32,20 @@ func NewPayPalPayment(email, password string) (*PayPalPayment, error) {
 	return &PayPalPayment{
 		email:     email,
 		password:  password,
+		limits:    DefaultPayPalLimits,
 		validator: v,
 	}, nil
 }
 
-func (p *PayPalPayment) Process(ctx context.Context, amount float64) (*PaymentResult, error) {
+func (p *PayPalPayment) SetLimits(limits AmountLimits) {
+	if limits.Max > 0 {
+		p.limits = limits
+	}
+}
+
+func (p *PayPalPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Processing PayPal payment",
 		zap.Float64("amount", amount),
 		zap.String("email", p.email),
@@ -45,8 +55,7 @@ func (p *PayPalPayment) Process(ctx context.Context, amount float64) (*PaymentRe
 		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
 	}
 
-	amountValidator := validator.NewAmountValidator()
-	if err := amountValidator.Validate(amount, 1.0, 5000.0); err != nil {
+	if err := validateAmount(ctx, amount, p.limits.Min, p.limits.Max); err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeValidation, "invalid payment amount")
 	}
 
@@ -60,7 +69,7 @@ func (p *PayPalPayment) Process(ctx context.Context, amount float64) (*PaymentRe
 		Amount:          amount,
 		OriginalAmount:  amount,
 		ProcessedAmount: amount,
-		Currency:        "USD",
+		Currency:        CurrencyFromContext(ctx),
 		PaymentMethod:   "paypal",
 		Message:         "PayPal payment processed successfully",
 		Metadata: map[string]interface{}{
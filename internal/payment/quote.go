@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"context"
+)
+
+type QuotePayment struct {
+	paymentMethod string
+	currency      string
+}
+
+func NewQuotePayment(paymentMethod, currency string) *QuotePayment {
+	return &QuotePayment{
+		paymentMethod: paymentMethod,
+		currency:      currency,
+	}
+}
+
+func (p *QuotePayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	return &PaymentResult{
+		Success:           true,
+		Amount:            amount,
+		OriginalAmount:    amount,
+		ProcessedAmount:   amount,
+		Currency:          p.currency,
+		PaymentMethod:     p.paymentMethod,
+		Message:           "Quote only, no payment processed",
+		Metadata:          map[string]interface{}{"charge_amount": amount},
+		AppliedDecorators: []string{},
+	}, nil
+}
+
+func (p *QuotePayment) GetType() string {
+	return p.paymentMethod
+}
+
+func (p *QuotePayment) GetDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"type":  p.paymentMethod,
+		"quote": true,
+	}
+}
@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotePayment(t *testing.T) {
+	quote := NewQuotePayment("paypal", "EUR")
+
+	result, err := quote.Process(context.Background(), PaymentRequest{Amount: 49.99})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 49.99, result.Amount)
+	assert.Equal(t, 49.99, result.ProcessedAmount)
+	assert.Equal(t, "EUR", result.Currency)
+	assert.Equal(t, "paypal", result.PaymentMethod)
+	assert.Empty(t, result.TransactionID, "a quote charges nothing")
+
+	assert.Equal(t, "paypal", quote.GetType())
+	assert.Equal(t, true, quote.GetDetails()["quote"])
+}
@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+)
+
+// PaymentRequest is what a payment is asked to charge. Beyond the amount it
+// carries the order behind it, so decorators can price per item or per
+// customer. Currency is the charge currency; ctx still carries its
+// conversion rate for limit checks (see WithCurrency).
+type PaymentRequest struct {
+	Amount   float64
+	Currency string
+	Items    []LineItem
+	Customer *domain.Customer
+	Metadata map[string]interface{}
+	// AuthorizeOnly places a hold for Amount instead of charging it; the
+	// hold is captured later. Only card payments support it.
+	AuthorizeOnly bool
+}
+
+// LineItem is one cart line of a request.
+type LineItem struct {
+	ProductID string
+	Category  string
+	TaxExempt bool
+	Amount    float64
+}
+
+// WithAmount returns a copy of the request for another amount, as passed on
+// by a decorator that adjusts the price or a strategy that charges part of
+// it.
+func (r PaymentRequest) WithAmount(amount float64) PaymentRequest {
+	r.Amount = amount
+	return r
+}
+
+// ProcessAmount charges a bare amount in the ctx currency, for callers with
+// no order behind the charge such as installments and subscription renewals.
+func ProcessAmount(ctx context.Context, p Payment, amount float64) (*PaymentResult, error) {
+	return p.Process(ctx, PaymentRequest{Amount: amount, Currency: CurrencyFromContext(ctx)})
+}
@@ -0,0 +1,48 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPayment struct {
+	stubPayment
+	request PaymentRequest
+}
+
+func (p *recordingPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	p.request = request
+	return p.stubPayment.Process(ctx, request)
+}
+
+func TestPaymentRequest(t *testing.T) {
+	t.Run("WithAmount Keeps The Order", func(t *testing.T) {
+		request := PaymentRequest{
+			Amount:   100,
+			Currency: "EUR",
+			Items:    []LineItem{{ProductID: "prod-1", Category: "Books", Amount: 100}},
+		}
+
+		adjusted := request.WithAmount(90)
+		assert.Equal(t, 90.0, adjusted.Amount)
+		assert.Equal(t, request.Items, adjusted.Items)
+		assert.Equal(t, "EUR", adjusted.Currency)
+		assert.Equal(t, 100.0, request.Amount)
+	})
+
+	t.Run("ProcessAmount Uses The Context Currency", func(t *testing.T) {
+		converter := currency.NewConverter(currency.NewStaticRateProvider(map[string]float64{"USD": 1.0, "EUR": 1.10}))
+		ctx := WithCurrency(context.Background(), "EUR", converter)
+
+		p := &recordingPayment{}
+		result, err := ProcessAmount(ctx, p, 42.5)
+		require.NoError(t, err)
+
+		assert.Equal(t, 42.5, result.Amount)
+		assert.Equal(t, PaymentRequest{Amount: 42.5, Currency: "EUR"}, p.request)
+	})
+}
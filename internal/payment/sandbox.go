@@ -0,0 +1,98 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	SandboxForceFraud   = "force_fraud"
+	SandboxForceTimeout = "force_timeout"
+
+	sandboxDeclineCents    = 1
+	sandboxTimeoutFallback = 30 * time.Second
+)
+
+type SandboxPayment struct {
+	wrapped  Payment
+	metadata map[string]interface{}
+}
+
+func NewSandboxPayment(wrapped Payment, metadata map[string]interface{}) *SandboxPayment {
+	return &SandboxPayment{
+		wrapped:  wrapped,
+		metadata: metadata,
+	}
+}
+
+func (p *SandboxPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	if p.flag(SandboxForceTimeout) {
+		logger.Warn("Sandbox: forcing payment timeout",
+			zap.String("payment_method", p.wrapped.GetType()),
+		)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(sandboxTimeoutFallback):
+		}
+		return nil, errors.NewTimeoutError("sandbox: payment processor timed out")
+	}
+
+	if p.flag(SandboxForceFraud) {
+		logger.Warn("Sandbox: forcing fraud block",
+			zap.String("payment_method", p.wrapped.GetType()),
+		)
+		return nil, errors.NewFraudDetectedError("sandbox: transaction blocked by fraud rules")
+	}
+
+	if int(math.Round(amount*100))%100 == sandboxDeclineCents {
+		logger.Warn("Sandbox: declining magic amount",
+			zap.Float64("amount", amount),
+		)
+		return nil, errors.NewPaymentError(
+			fmt.Sprintf("sandbox: payment of $%.2f declined by processor", amount),
+		)
+	}
+
+	result, err := p.wrapped.Process(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Metadata["sandbox"] = true
+	return result, nil
+}
+
+func (p *SandboxPayment) GetType() string {
+	return p.wrapped.GetType()
+}
+
+func (p *SandboxPayment) GetDetails() map[string]interface{} {
+	details := p.wrapped.GetDetails()
+	details["sandbox"] = true
+	return details
+}
+
+func (p *SandboxPayment) flag(key string) bool {
+	switch v := p.metadata[key].(type) {
+	case bool:
+		return v
+	case string:
+		enabled, _ := strconv.ParseBool(v)
+		return enabled
+	default:
+		return false
+	}
+}
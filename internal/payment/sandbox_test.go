@@ -0,0 +1,82 @@
+package payment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPayment struct{}
+
+func (p *stubPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	return &PaymentResult{
+		Success:  true,
+		Amount:   request.Amount,
+		Metadata: map[string]interface{}{},
+	}, nil
+}
+
+func (p *stubPayment) GetType() string {
+	return "stub"
+}
+
+func (p *stubPayment) GetDetails() map[string]interface{} {
+	return map[string]interface{}{"type": "stub"}
+}
+
+// bareResultPayment returns results without a metadata map.
+type bareResultPayment struct {
+	stubPayment
+}
+
+func (p *bareResultPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	return &PaymentResult{Success: true, Amount: request.Amount}, nil
+}
+
+func TestSandboxPayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Magic Amount Declines", func(t *testing.T) {
+		p := NewSandboxPayment(&stubPayment{}, nil)
+
+		_, err := p.Process(ctx, PaymentRequest{Amount: 25.01})
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodePaymentFailed))
+
+		result, err := p.Process(ctx, PaymentRequest{Amount: 25.10})
+		require.NoError(t, err)
+		assert.Equal(t, true, result.Metadata["sandbox"])
+	})
+
+	t.Run("Marks Results Without Metadata", func(t *testing.T) {
+		p := NewSandboxPayment(&bareResultPayment{}, nil)
+
+		result, err := p.Process(ctx, PaymentRequest{Amount: 25.10})
+		require.NoError(t, err)
+		assert.Equal(t, true, result.Metadata["sandbox"])
+	})
+
+	t.Run("Force Fraud", func(t *testing.T) {
+		p := NewSandboxPayment(&stubPayment{}, map[string]interface{}{SandboxForceFraud: "true"})
+
+		_, err := p.Process(ctx, PaymentRequest{Amount: 50.00})
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeFraudDetected))
+	})
+
+	t.Run("Force Timeout Waits For Deadline", func(t *testing.T) {
+		p := NewSandboxPayment(&stubPayment{}, map[string]interface{}{SandboxForceTimeout: true})
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err := p.Process(timeoutCtx, PaymentRequest{Amount: 50.00})
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeTimeout))
+		assert.Error(t, timeoutCtx.Err())
+	})
+}
@@ -0,0 +1,155 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const VaultTokenPrefix = "pm_"
+
+var savedPaymentLimits = map[string]AmountLimits{
+	"credit_card": DefaultCreditCardLimits,
+	"paypal":      DefaultPayPalLimits,
+	"crypto":      DefaultCryptoLimits,
+}
+
+// Tokenize returns only masked details, so raw card numbers are never stored.
+func Tokenize(method string, config PaymentConfig) (string, map[string]interface{}, error) {
+	var p Payment
+	var err error
+
+	switch method {
+	case "credit_card":
+		p, err = NewCreditCardPayment(config.CardNumber, config.CardHolder, config.ExpiryDate, config.CVV)
+	case "paypal":
+		p, err = NewPayPalPayment(config.PayPalEmail, config.PayPalPassword)
+	case "crypto":
+		p, err = NewCryptoPayment(config.WalletAddress, config.CryptoType)
+	case "wallet":
+		p, err = NewWalletPayment(config.WalletToken, config.WalletProvider)
+	default:
+		return "", nil, errors.NewInvalidPaymentError(
+			fmt.Sprintf("payment method %s cannot be saved", method),
+		)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	details := p.GetDetails()
+	delete(details, "type")
+
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to generate vault token")
+	}
+
+	return VaultTokenPrefix + hex.EncodeToString(random), details, nil
+}
+
+type SavedPayment struct {
+	method  string
+	token   string
+	details map[string]interface{}
+	limits  AmountLimits
+}
+
+func NewSavedPayment(method, token string, details map[string]interface{}) (*SavedPayment, error) {
+	if !strings.HasPrefix(token, VaultTokenPrefix) || len(token) <= len(VaultTokenPrefix) {
+		return nil, errors.NewInvalidPaymentError("invalid vault token")
+	}
+
+	return &SavedPayment{
+		method:  method,
+		token:   token,
+		details: details,
+		limits:  savedPaymentLimits[method],
+	}, nil
+}
+
+func (p *SavedPayment) SetLimits(limits AmountLimits) {
+	if limits.Max > 0 {
+		p.limits = limits
+	}
+}
+
+func (p *SavedPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Processing saved payment method",
+		zap.Float64("amount", amount),
+		zap.String("payment_method", p.method),
+	)
+
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
+	}
+
+	if p.limits.Max > 0 {
+		if err := validateAmount(ctx, amount, p.limits.Min, p.limits.Max); err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeValidation, "invalid payment amount")
+		}
+	} else if amount <= 0 {
+		return nil, errors.NewValidationError("invalid payment amount")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	transactionID := domain.NewID()
+
+	metadata := make(map[string]interface{}, len(p.details)+2)
+	for key, value := range p.details {
+		metadata[key] = value
+	}
+	metadata["saved_method"] = true
+	metadata["processed_at"] = time.Now().Format(time.RFC3339)
+
+	result := &PaymentResult{
+		Success:           true,
+		TransactionID:     transactionID,
+		Amount:            amount,
+		OriginalAmount:    amount,
+		ProcessedAmount:   amount,
+		Currency:          CurrencyFromContext(ctx),
+		PaymentMethod:     p.method,
+		Message:           "Payment processed successfully",
+		Metadata:          metadata,
+		AppliedDecorators: []string{},
+	}
+
+	if request.AuthorizeOnly {
+		result.Message = "Payment authorized"
+		result.Metadata["authorization_hold"] = true
+	}
+
+	logger.Info("Saved payment method charged successfully",
+		zap.String("transaction_id", transactionID),
+		zap.Float64("amount", amount),
+	)
+
+	return result, nil
+}
+
+func (p *SavedPayment) GetType() string {
+	return p.method
+}
+
+func (p *SavedPayment) GetDetails() map[string]interface{} {
+	details := map[string]interface{}{
+		"type":         p.method,
+		"saved_method": true,
+	}
+	for key, value := range p.details {
+		details[key] = value
+	}
+	return details
+}
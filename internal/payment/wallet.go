@@ -0,0 +1,123 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	WalletProviderApplePay  = "apple_pay"
+	WalletProviderGooglePay = "google_pay"
+)
+
+// walletTokenPrefixes are the prefixes each provider issues device tokens with.
+var walletTokenPrefixes = map[string]string{
+	WalletProviderApplePay:  "apay_",
+	WalletProviderGooglePay: "gpay_",
+}
+
+// WalletPayment charges a tokenized mobile wallet, so no raw card data is
+// handled by the system.
+type WalletPayment struct {
+	token    string
+	provider string
+}
+
+func NewWalletPayment(token, provider string) (*WalletPayment, error) {
+	token = strings.TrimSpace(token)
+	provider = strings.ToLower(strings.TrimSpace(provider))
+
+	prefix, ok := walletTokenPrefixes[provider]
+	if !ok {
+		return nil, errors.NewInvalidPaymentError(
+			fmt.Sprintf("unsupported wallet provider: %s", provider),
+		)
+	}
+
+	if token == "" {
+		return nil, errors.NewInvalidPaymentError("wallet token is required")
+	}
+
+	if !strings.HasPrefix(token, prefix) || len(token) <= len(prefix)+4 {
+		return nil, errors.NewInvalidPaymentError(
+			fmt.Sprintf("invalid %s token format", provider),
+		)
+	}
+
+	return &WalletPayment{
+		token:    token,
+		provider: provider,
+	}, nil
+}
+
+func (p *WalletPayment) Process(ctx context.Context, request PaymentRequest) (*PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Processing wallet payment",
+		zap.Float64("amount", amount),
+		zap.String("provider", p.provider),
+		zap.String("token", p.maskedToken()),
+	)
+
+	if ctx.Err() != nil {
+		return nil, errors.Wrap(ctx.Err(), errors.ErrCodeTimeout, "payment context expired")
+	}
+
+	if amount <= 0 {
+		return nil, errors.NewValidationError("invalid payment amount")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	transactionID := domain.NewID()
+
+	result := &PaymentResult{
+		Success:         true,
+		TransactionID:   transactionID,
+		Amount:          amount,
+		OriginalAmount:  amount,
+		ProcessedAmount: amount,
+		Currency:        CurrencyFromContext(ctx),
+		PaymentMethod:   "wallet",
+		Message:         "Wallet payment processed successfully",
+		Metadata: map[string]interface{}{
+			"wallet_provider": p.provider,
+			"wallet_token":    p.maskedToken(),
+			"processed_at":    time.Now().Format(time.RFC3339),
+		},
+		AppliedDecorators: []string{},
+	}
+
+	logger.Info("Wallet payment processed successfully",
+		zap.String("transaction_id", transactionID),
+		zap.Float64("amount", amount),
+	)
+
+	return result, nil
+}
+
+func (p *WalletPayment) GetType() string {
+	return "wallet"
+}
+
+func (p *WalletPayment) GetDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"type":            "wallet",
+		"wallet_provider": p.provider,
+		"wallet_token":    p.maskedToken(),
+	}
+}
+
+// maskedToken keeps the provider prefix and the last four characters.
+func (p *WalletPayment) maskedToken() string {
+	prefix := walletTokenPrefixes[p.provider]
+	body := p.token[len(prefix):]
+	return prefix + strings.Repeat("*", len(body)-4) + body[len(body)-4:]
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletPayment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Rejects Invalid Tokens", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			token    string
+			provider string
+		}{
+			{name: "Empty Token", token: "", provider: WalletProviderApplePay},
+			{name: "Wrong Prefix", token: "gpay_1234567890", provider: WalletProviderApplePay},
+			{name: "Unknown Provider", token: "apay_1234567890", provider: "samsung_pay"},
+		} {
+			_, err := NewWalletPayment(tc.token, tc.provider)
+			assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInvalidPayment), tc.name)
+		}
+	})
+
+	t.Run("Records Provider And Masked Token", func(t *testing.T) {
+		p, err := NewWalletPayment("gpay_abcdef123456", "google_pay")
+		require.NoError(t, err)
+
+		result, err := p.Process(ctx, PaymentRequest{Amount: 25.00})
+		require.NoError(t, err)
+		assert.Equal(t, "wallet", result.PaymentMethod)
+		assert.Equal(t, "google_pay", result.Metadata["wallet_provider"])
+		assert.Equal(t, "gpay_********3456", result.Metadata["wallet_token"])
+	})
+}
@@ -0,0 +1,281 @@
+package receipt
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/currency"
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/i18n"
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+type Format string
+
+const (
+	FormatHTML Format = "html"
+	FormatText Format = "text"
+	FormatPDF  Format = "pdf"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatHTML, FormatText, FormatPDF:
+		return format, nil
+	default:
+		return "", errors.NewValidationError(
+			fmt.Sprintf("unsupported receipt format %q (expected html, text or pdf)", value),
+		)
+	}
+}
+
+const rule = "═══════════════════════════════════════"
+
+//go:embed templates/receipt.html
+var defaultTemplate string
+
+// ReceiptRenderer exports receipts in the same layout the CLI prints them in.
+type ReceiptRenderer struct {
+	html *template.Template
+}
+
+// NewReceiptRenderer uses the built-in HTML template, or the one at
+// templatePath when it is set. The template is executed with a View.
+func NewReceiptRenderer(templatePath string) (*ReceiptRenderer, error) {
+	source := defaultTemplate
+	if templatePath != "" {
+		data, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read receipt template: %w", err)
+		}
+		source = string(data)
+	}
+
+	tmpl, err := template.New("receipt").Parse(source)
+	if err != nil {
+		return nil, errors.NewValidationError(fmt.Sprintf("invalid receipt template: %v", err))
+	}
+
+	return &ReceiptRenderer{html: tmpl}, nil
+}
+
+func (r *ReceiptRenderer) Render(w io.Writer, receipt *domain.Receipt, format Format) error {
+	switch format {
+	case FormatHTML:
+		return r.RenderHTML(w, receipt)
+	case FormatText:
+		return r.RenderText(w, receipt)
+	case FormatPDF:
+		return r.RenderPDF(w, receipt)
+	default:
+		_, err := ParseFormat(string(format))
+		return err
+	}
+}
+
+func (r *ReceiptRenderer) RenderHTML(w io.Writer, receipt *domain.Receipt) error {
+	return r.html.Execute(w, NewView(receipt))
+}
+
+func (r *ReceiptRenderer) RenderText(w io.Writer, receipt *domain.Receipt) error {
+	out := bufio.NewWriter(w)
+	for _, line := range NewView(receipt).Lines() {
+		fmt.Fprintln(out, line)
+	}
+	return out.Flush()
+}
+
+func (r *ReceiptRenderer) RenderPDF(w io.Writer, receipt *domain.Receipt) error {
+	return writePDF(w, NewView(receipt).Lines())
+}
+
+type View struct {
+	Receipt  *domain.Receipt
+	Lang     string
+	Labels   Labels
+	Date     string
+	Payment  string
+	Items    []ItemLine
+	Currency string
+	Amounts  []AmountLine
+	Total    AmountLine
+	Base     []AmountLine
+	Rewards  []AmountLine
+	Features string
+}
+
+type Labels struct {
+	Title           string
+	TransactionID   string
+	OrderID         string
+	Date            string
+	Customer        string
+	Email           string
+	Payment         string
+	Items           string
+	Amounts         string
+	AppliedFeatures string
+}
+
+type ItemLine struct {
+	Name     string
+	Quantity int
+	Total    string
+}
+
+type AmountLine struct {
+	Label string
+	Value string
+}
+
+func NewView(receipt *domain.Receipt) *View {
+	symbol := currency.Symbol(receipt.Currency)
+	money := func(amount float64) string {
+		return fmt.Sprintf("%s%8.2f", symbol, amount)
+	}
+
+	t := func(id string, args ...interface{}) string {
+		return i18n.T(receipt.Locale, id, args...)
+	}
+
+	view := &View{
+		Receipt: receipt,
+		Lang:    i18n.Normalize(receipt.Locale),
+		Labels: Labels{
+			Title:           t("receipt.title"),
+			TransactionID:   t("receipt.transaction_id"),
+			OrderID:         t("receipt.order_id"),
+			Date:            t("receipt.date"),
+			Customer:        t("receipt.customer"),
+			Email:           t("receipt.email"),
+			Payment:         t("receipt.payment"),
+			Items:           t("receipt.items"),
+			Amounts:         t("receipt.amounts"),
+			AppliedFeatures: t("receipt.applied_features"),
+		},
+		Date:     receipt.CreatedAt.Format("2006-01-02 15:04:05"),
+		Currency: receipt.Currency,
+		Total:    AmountLine{t("receipt.total"), money(receipt.Total)},
+	}
+	if receipt.Strategy != "" {
+		view.Payment = fmt.Sprintf("%s (%s)", receipt.PaymentMethod, receipt.Strategy)
+	}
+
+	for _, item := range receipt.Items {
+		view.Items = append(view.Items, ItemLine{Name: item.ProductName, Quantity: item.Quantity, Total: money(item.Total)})
+	}
+
+	view.Amounts = append(view.Amounts, AmountLine{t("receipt.subtotal"), money(receipt.Subtotal)})
+	if receipt.Discount > 0 {
+		view.Amounts = append(view.Amounts, AmountLine{t("receipt.discount"), "-" + money(receipt.Discount)})
+	}
+	if len(receipt.TaxLines) > 0 {
+		for _, line := range receipt.TaxLines {
+			view.Amounts = append(view.Amounts, AmountLine{line.Name, money(line.Amount)})
+		}
+	} else if receipt.Tax > 0 {
+		view.Amounts = append(view.Amounts, AmountLine{t("receipt.tax"), money(receipt.Tax)})
+	}
+	if receipt.ServiceFee > 0 {
+		view.Amounts = append(view.Amounts, AmountLine{t("receipt.service_fee"), money(receipt.ServiceFee)})
+	}
+	if receipt.CashbackRedeemed > 0 {
+		view.Amounts = append(view.Amounts, AmountLine{t("receipt.cashback_applied"), "-" + money(receipt.CashbackRedeemed)})
+	}
+
+	if receipt.ExchangeRate > 0 {
+		view.Base = []AmountLine{
+			{t("receipt.base_amount"), fmt.Sprintf("%s%8.2f", currency.Symbol(receipt.BaseCurrency), receipt.BaseAmount)},
+			{t("receipt.locked_rate"), fmt.Sprintf("1 %s = %.4f %s", receipt.Currency, receipt.ExchangeRate, receipt.BaseCurrency)},
+		}
+	}
+
+	if receipt.Cashback > 0 {
+		view.Rewards = append(view.Rewards, AmountLine{t("receipt.cashback_earned"), money(receipt.Cashback)})
+	}
+	if receipt.LoyaltyPoints > 0 {
+		view.Rewards = append(view.Rewards, AmountLine{t("receipt.loyalty_points"), t("receipt.points", receipt.LoyaltyPoints)})
+	}
+
+	if len(receipt.AppliedDecorators) > 0 {
+		view.Features = fmt.Sprintf("%v", receipt.AppliedDecorators)
+	}
+
+	return view
+}
+
+// Lines lays the receipt out as the CLI's printReceipt does.
+func (v *View) Lines() []string {
+	lines := []string{rule, v.TitleLine(), rule, ""}
+	lines = append(lines, v.HeaderLines()...)
+
+	lines = append(lines, "", v.Labels.Items+":")
+	for _, item := range v.Items {
+		lines = append(lines, item.String())
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, v.AmountsHeading())
+	for _, line := range v.Amounts {
+		lines = append(lines, line.String())
+	}
+	lines = append(lines, v.Total.String())
+	for _, line := range v.Base {
+		lines = append(lines, line.String())
+	}
+	lines = append(lines, "")
+
+	for _, line := range v.Rewards {
+		lines = append(lines, line.String())
+	}
+
+	if v.Features != "" {
+		lines = append(lines, "", v.FeaturesLine())
+	}
+
+	return append(lines, "", rule)
+}
+
+func (v *View) TitleLine() string {
+	return "              " + v.Labels.Title
+}
+
+func (v *View) HeaderLines() []string {
+	r := v.Receipt
+	lines := []string{v.Labels.TransactionID + ": " + r.TransactionID}
+	if r.OrderID != "" {
+		lines = append(lines, v.Labels.OrderID+": "+r.OrderID)
+	}
+	lines = append(lines, v.Labels.Date+": "+v.Date, "")
+
+	lines = append(lines, v.Labels.Customer+": "+r.CustomerName, v.Labels.Email+": "+r.CustomerEmail)
+	if v.Payment != "" {
+		lines = append(lines, v.Labels.Payment+": "+v.Payment)
+	}
+	return lines
+}
+
+func (v *View) AmountsHeading() string {
+	if v.Currency != "" {
+		return fmt.Sprintf("%s (%s):", v.Labels.Amounts, v.Currency)
+	}
+	return v.Labels.Amounts + ":"
+}
+
+func (v *View) FeaturesLine() string {
+	return v.Labels.AppliedFeatures + ": " + v.Features
+}
+
+func (l ItemLine) String() string {
+	return fmt.Sprintf("  %-30s x%-3d %s", l.Name, l.Quantity, l.Total)
+}
+
+// String keeps a space before the value when a tax name runs long.
+func (l AmountLine) String() string {
+	return fmt.Sprintf("  %-18s %s", l.Label+":", l.Value)
+}
@@ -0,0 +1,93 @@
+package receipt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptRenderer(t *testing.T) {
+	issued := &domain.Receipt{
+		TransactionID: "tx-1",
+		OrderID:       "order-1",
+		CustomerName:  "Jane <Doe>",
+		CustomerEmail: "jane@example.com",
+		Items: []domain.ReceiptItem{
+			{ProductName: "Laptop", Quantity: 1, UnitPrice: 999.99, Total: 999.99},
+		},
+		Subtotal:          999.99,
+		Discount:          100,
+		Tax:               76.50,
+		Total:             976.49,
+		Currency:          "USD",
+		PaymentMethod:     "credit_card",
+		Strategy:          "instant",
+		AppliedDecorators: []string{"tax", "discount"},
+		CreatedAt:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	renderer, err := NewReceiptRenderer("")
+	require.NoError(t, err)
+
+	t.Run("Text Matches The Terminal Layout", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, renderer.Render(&out, issued, FormatText))
+
+		text := out.String()
+		assert.Contains(t, text, "Payment: credit_card (instant)\n")
+		assert.Contains(t, text, "  Laptop                         x1   $  999.99\n")
+		assert.Contains(t, text, "  Discount:          -$  100.00\n")
+		assert.Contains(t, text, "  Total:             $  976.49\n")
+		assert.Contains(t, text, "Applied Features: [tax discount]\n")
+	})
+
+	t.Run("HTML Escapes Customer Data", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, renderer.Render(&out, issued, FormatHTML))
+
+		html := out.String()
+		assert.Contains(t, html, "Jane &lt;Doe&gt;")
+		assert.Contains(t, html, "Amounts (USD):")
+		assert.Contains(t, html, "$  976.49")
+	})
+
+	t.Run("PDF Is A Single Page Document", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, renderer.Render(&out, issued, FormatPDF))
+
+		pdf := out.String()
+		assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+		assert.Contains(t, pdf, "/Count 1")
+		assert.Contains(t, pdf, "(  Total:             $  976.49) Tj")
+		assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	})
+
+	t.Run("Labels Follow The Receipt Locale", func(t *testing.T) {
+		spanish := *issued
+		spanish.Locale = "es"
+
+		var out bytes.Buffer
+		require.NoError(t, renderer.Render(&out, &spanish, FormatText))
+
+		text := out.String()
+		assert.Contains(t, text, "              RECIBO\n")
+		assert.Contains(t, text, "Pago: credit_card (instant)\n")
+		assert.Contains(t, text, "  Descuento:         -$  100.00\n")
+		assert.Contains(t, text, "Importes (USD):\n")
+
+		out.Reset()
+		require.NoError(t, renderer.Render(&out, &spanish, FormatHTML))
+		assert.Contains(t, out.String(), `<html lang="es">`)
+	})
+
+	t.Run("Rejects Unknown Formats", func(t *testing.T) {
+		_, err := ParseFormat("docx")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
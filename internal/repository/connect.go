@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type ConnectOptions struct {
+	MaxAttempts     int
+	RetryDelay      time.Duration
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ReadOnly        bool
+	SeedDataset     SeedDataset
+}
+
+func configurePool(db *sql.DB, opts ConnectOptions) {
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+}
+
+func pingWithRetry(db *sql.DB, opts ConnectOptions) error {
+	attempts := opts.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := opts.RetryDelay
+	var lastErr error
+
+	for attempt := 1; attempt <= attempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		lastErr = db.PingContext(ctx)
+		cancel()
+
+		if lastErr == nil {
+			if attempt > 1 {
+				logger.Info("Database connection established",
+					zap.Int("attempt", attempt),
+				)
+			}
+			return nil
+		}
+
+		logger.Warn("Database connection attempt failed",
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("next_delay", delay),
+			zap.Error(lastErr),
+		)
+
+		if attempt < attempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	return fmt.Errorf("database unreachable after %d attempts: %w", attempts, lastErr)
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
 )
@@ -13,36 +15,55 @@ import (
 type FileRepository struct {
 	*MemoryRepository
 	filePath string
-	mu       sync.RWMutex
+	// fileMu serializes writes to filePath; the maps themselves are guarded
+	// by the embedded MemoryRepository's mutex.
+	fileMu sync.Mutex
 }
 
 type PersistentData struct {
-	Customers    map[string]*domain.Customer    `json:"customers"`
-	Products     map[string]*domain.Product     `json:"products"`
-	Carts        map[string]*domain.Cart        `json:"carts"`
-	Transactions map[string]*domain.Transaction `json:"transactions"`
+	Customers     map[string]*domain.Customer           `json:"customers"`
+	Products      map[string]*domain.Product            `json:"products"`
+	Carts         map[string]*domain.Cart               `json:"carts"`
+	Transactions  map[string]*domain.Transaction        `json:"transactions"`
+	Orders        map[string]*domain.Order              `json:"orders"`
+	Schedules     map[string]*domain.PaymentSchedule    `json:"payment_schedules"`
+	Subscriptions map[string]*domain.Subscription       `json:"subscriptions"`
+	Discounts     map[string]*domain.Discount           `json:"discounts"`
+	Reservations  map[string]*domain.Reservation        `json:"reservations"`
+	Loyalty       []*domain.LoyaltyEntry                `json:"loyalty_ledger"`
+	Cashback      []*domain.CashbackEntry               `json:"cashback_ledger"`
+	Receipts      map[string]*domain.Receipt            `json:"receipts"`
+	Methods       map[string]*domain.SavedPaymentMethod `json:"payment_methods"`
 }
 
-func NewFileRepository(filePath string) (*FileRepository, error) {
-	memRepo := NewMemoryRepository()
-
+func NewFileRepository(filePath string, dataset SeedDataset) (*FileRepository, error) {
 	repo := &FileRepository{
-		MemoryRepository: memRepo,
+		MemoryRepository: NewMemoryRepositoryWithSeed(SeedNone),
 		filePath:         filePath,
 	}
 
 	if err := repo.load(); err != nil {
-		fmt.Printf("⚠ Could not load data from file, using fresh data: %v\n", err)
+		fmt.Fprintf(os.Stderr, "⚠ Could not load data from file, using fresh data: %v\n", err)
 	} else {
-		fmt.Println("✓ Data loaded from file")
+		fmt.Fprintln(os.Stderr, "✓ Data loaded from file")
+	}
+
+	seeded, err := seedStore(context.Background(), repo.MemoryRepository, dataset)
+	if err != nil {
+		return nil, err
+	}
+	if seeded {
+		if err := repo.save(); err != nil {
+			return nil, err
+		}
 	}
 
 	return repo, nil
 }
 
 func (r *FileRepository) load() error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	r.MemoryRepository.mu.Lock()
+	defer r.MemoryRepository.mu.Unlock()
 
 	data, err := os.ReadFile(r.filePath)
 	if err != nil {
@@ -69,27 +90,61 @@ func (r *FileRepository) load() error {
 	if len(persistentData.Transactions) > 0 {
 		r.transactions = persistentData.Transactions
 	}
+	if len(persistentData.Orders) > 0 {
+		r.orders = persistentData.Orders
+	}
+	if len(persistentData.Schedules) > 0 {
+		r.schedules = persistentData.Schedules
+	}
+	if len(persistentData.Subscriptions) > 0 {
+		r.subscriptions = persistentData.Subscriptions
+	}
+	if len(persistentData.Discounts) > 0 {
+		r.discounts = persistentData.Discounts
+	}
+	if len(persistentData.Reservations) > 0 {
+		r.reservations = persistentData.Reservations
+	}
+	r.loyalty = persistentData.Loyalty
+	r.cashback = persistentData.Cashback
+	if len(persistentData.Receipts) > 0 {
+		r.receipts = persistentData.Receipts
+	}
+	if len(persistentData.Methods) > 0 {
+		r.methods = persistentData.Methods
+	}
 
 	return nil
 }
 
 func (r *FileRepository) save() error {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	r.fileMu.Lock()
+	defer r.fileMu.Unlock()
 
+	r.MemoryRepository.mu.RLock()
 	persistentData := PersistentData{
-		Customers:    r.customers,
-		Products:     r.products,
-		Carts:        r.carts,
-		Transactions: r.transactions,
+		Customers:     r.customers,
+		Products:      r.products,
+		Carts:         r.carts,
+		Transactions:  r.transactions,
+		Orders:        r.orders,
+		Schedules:     r.schedules,
+		Subscriptions: r.subscriptions,
+		Discounts:     r.discounts,
+		Reservations:  r.reservations,
+		Loyalty:       r.loyalty,
+		Cashback:      r.cashback,
+		Receipts:      r.receipts,
+		Methods:       r.methods,
 	}
 
 	data, err := json.MarshalIndent(persistentData, "", "  ")
+	r.MemoryRepository.mu.RUnlock()
 	if err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll("data", 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
 		return err
 	}
 
@@ -110,6 +165,13 @@ func (r *FileRepository) UpdateCart(ctx context.Context, cart *domain.Cart) erro
 	return r.save()
 }
 
+func (r *FileRepository) DeleteCart(ctx context.Context, id string) error {
+	if err := r.MemoryRepository.DeleteCart(ctx, id); err != nil {
+		return err
+	}
+	return r.save()
+}
+
 func (r *FileRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
 	if err := r.MemoryRepository.CreateTransaction(ctx, transaction); err != nil {
 		return err
@@ -117,6 +179,83 @@ func (r *FileRepository) CreateTransaction(ctx context.Context, transaction *dom
 	return r.save()
 }
 
+func (r *FileRepository) AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error {
+	if err := r.MemoryRepository.AbandonStaleTransaction(ctx, key, staleBefore); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	if err := r.MemoryRepository.UpdateTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	if err := r.MemoryRepository.CreateOrder(ctx, order); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	if err := r.MemoryRepository.UpdateOrder(ctx, order); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateDiscount(ctx context.Context, discount *domain.Discount) error {
+	if err := r.MemoryRepository.CreateDiscount(ctx, discount); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) UpdateDiscount(ctx context.Context, discount *domain.Discount) error {
+	if err := r.MemoryRepository.UpdateDiscount(ctx, discount); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) DeleteDiscount(ctx context.Context, code string) error {
+	if err := r.MemoryRepository.DeleteDiscount(ctx, code); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	if err := r.MemoryRepository.CreatePaymentSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) UpdatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	if err := r.MemoryRepository.UpdatePaymentSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if err := r.MemoryRepository.CreateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) UpdateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if err := r.MemoryRepository.UpdateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	return r.save()
+}
+
 func (r *FileRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) error {
 	if err := r.MemoryRepository.UpdateCustomer(ctx, customer); err != nil {
 		return err
@@ -124,6 +263,13 @@ func (r *FileRepository) UpdateCustomer(ctx context.Context, customer *domain.Cu
 	return r.save()
 }
 
+func (r *FileRepository) DeleteCustomer(ctx context.Context, id string) error {
+	if err := r.MemoryRepository.DeleteCustomer(ctx, id); err != nil {
+		return err
+	}
+	return r.save()
+}
+
 func (r *FileRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
 	if err := r.MemoryRepository.UpdateProduct(ctx, product); err != nil {
 		return err
@@ -131,6 +277,70 @@ func (r *FileRepository) UpdateProduct(ctx context.Context, product *domain.Prod
 	return r.save()
 }
 
+func (r *FileRepository) DeleteProduct(ctx context.Context, id string) error {
+	if err := r.MemoryRepository.DeleteProduct(ctx, id); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	if err := r.MemoryRepository.CreateReservation(ctx, reservation); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) DeleteReservation(ctx context.Context, id string) error {
+	if err := r.MemoryRepository.DeleteReservation(ctx, id); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) DeleteExpiredReservations(ctx context.Context, at time.Time) ([]*domain.Reservation, error) {
+	expired, err := r.MemoryRepository.DeleteExpiredReservations(ctx, at)
+	if err != nil || len(expired) == 0 {
+		return expired, err
+	}
+	return expired, r.save()
+}
+
+func (r *FileRepository) CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error {
+	if err := r.MemoryRepository.CreateLoyaltyEntry(ctx, entry); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) RecordCashback(ctx context.Context, entry *domain.CashbackEntry) error {
+	if err := r.MemoryRepository.RecordCashback(ctx, entry); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreateReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	if err := r.MemoryRepository.CreateReceipt(ctx, receipt); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) CreatePaymentMethod(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	if err := r.MemoryRepository.CreatePaymentMethod(ctx, method); err != nil {
+		return err
+	}
+	return r.save()
+}
+
+func (r *FileRepository) DeletePaymentMethod(ctx context.Context, id string) error {
+	if err := r.MemoryRepository.DeletePaymentMethod(ctx, id); err != nil {
+		return err
+	}
+	return r.save()
+}
+
 func (r *FileRepository) Close() error {
 	return r.save()
 }
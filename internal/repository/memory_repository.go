@@ -2,30 +2,53 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/pkg/errors"
 )
 
 type MemoryRepository struct {
-	customers    map[string]*domain.Customer
-	products     map[string]*domain.Product
-	carts        map[string]*domain.Cart
-	transactions map[string]*domain.Transaction
-	mu           sync.RWMutex
+	customers     map[string]*domain.Customer
+	products      map[string]*domain.Product
+	carts         map[string]*domain.Cart
+	transactions  map[string]*domain.Transaction
+	orders        map[string]*domain.Order
+	schedules     map[string]*domain.PaymentSchedule
+	subscriptions map[string]*domain.Subscription
+	discounts     map[string]*domain.Discount
+	reservations  map[string]*domain.Reservation
+	loyalty       []*domain.LoyaltyEntry
+	cashback      []*domain.CashbackEntry
+	receipts      map[string]*domain.Receipt
+	methods       map[string]*domain.SavedPaymentMethod
+	mu            sync.RWMutex
 }
 
 func NewMemoryRepository() *MemoryRepository {
+	return NewMemoryRepositoryWithSeed(SeedMinimal)
+}
+
+func NewMemoryRepositoryWithSeed(dataset SeedDataset) *MemoryRepository {
 	repo := &MemoryRepository{
-		customers:    make(map[string]*domain.Customer),
-		products:     make(map[string]*domain.Product),
-		carts:        make(map[string]*domain.Cart),
-		transactions: make(map[string]*domain.Transaction),
+		customers:     make(map[string]*domain.Customer),
+		products:      make(map[string]*domain.Product),
+		carts:         make(map[string]*domain.Cart),
+		transactions:  make(map[string]*domain.Transaction),
+		orders:        make(map[string]*domain.Order),
+		schedules:     make(map[string]*domain.PaymentSchedule),
+		subscriptions: make(map[string]*domain.Subscription),
+		discounts:     make(map[string]*domain.Discount),
+		reservations:  make(map[string]*domain.Reservation),
+		receipts:      make(map[string]*domain.Receipt),
+		methods:       make(map[string]*domain.SavedPaymentMethod),
 	}
 
-	repo.seedData()
+	// Seeding an empty in-memory store cannot fail.
+	_, _ = seedStore(context.Background(), repo, dataset)
 
 	return repo
 }
@@ -79,6 +102,29 @@ func (r *MemoryRepository) UpdateCustomer(ctx context.Context, customer *domain.
 	return nil
 }
 
+func (r *MemoryRepository) DeleteCustomer(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.customers[id]; !exists {
+		return errors.NewNotFoundError("customer")
+	}
+
+	for cartID, cart := range r.carts {
+		if cart.CustomerID == id {
+			delete(r.carts, cartID)
+		}
+	}
+	for methodID, method := range r.methods {
+		if method.CustomerID == id {
+			delete(r.methods, methodID)
+		}
+	}
+
+	delete(r.customers, id)
+	return nil
+}
+
 func (r *MemoryRepository) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -101,6 +147,13 @@ func (r *MemoryRepository) ListCustomers(ctx context.Context, limit, offset int)
 	return customers[start:end], nil
 }
 
+func (r *MemoryRepository) CountCustomers(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.customers), nil
+}
+
 func (r *MemoryRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -109,7 +162,8 @@ func (r *MemoryRepository) CreateProduct(ctx context.Context, product *domain.Pr
 		return errors.NewAlreadyExistsError("product")
 	}
 
-	r.products[product.ID] = product
+	stored := *product
+	r.products[product.ID] = &stored
 	return nil
 }
 
@@ -122,7 +176,22 @@ func (r *MemoryRepository) GetProduct(ctx context.Context, id string) (*domain.P
 		return nil, errors.NewNotFoundError("product")
 	}
 
-	return product, nil
+	copied := *product
+	return &copied, nil
+}
+
+func (r *MemoryRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, product := range r.products {
+		if product.SKU == sku {
+			copied := *product
+			return &copied, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("product")
 }
 
 func (r *MemoryRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
@@ -133,10 +202,36 @@ func (r *MemoryRepository) UpdateProduct(ctx context.Context, product *domain.Pr
 		return errors.NewNotFoundError("product")
 	}
 
-	r.products[product.ID] = product
+	stored := *product
+	r.products[product.ID] = &stored
 	return nil
 }
 
+func (r *MemoryRepository) DeleteProduct(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.products[id]; !exists {
+		return errors.NewNotFoundError("product")
+	}
+
+	for reservationID, reservation := range r.reservations {
+		if reservation.ProductID == id {
+			delete(r.reservations, reservationID)
+		}
+	}
+
+	delete(r.products, id)
+	return nil
+}
+
+func (r *MemoryRepository) CountProducts(ctx context.Context) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.products), nil
+}
+
 func (r *MemoryRepository) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -159,6 +254,42 @@ func (r *MemoryRepository) ListProducts(ctx context.Context, limit, offset int)
 	return products[start:end], nil
 }
 
+func (r *MemoryRepository) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	term := strings.ToLower(strings.TrimSpace(query))
+
+	products := []*domain.Product{}
+	for _, p := range r.products {
+		if productMatches(p, term) {
+			products = append(products, p)
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].ID < products[j].ID
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(products) {
+		return []*domain.Product{}, nil
+	}
+	if end > len(products) {
+		end = len(products)
+	}
+
+	return products[start:end], nil
+}
+
+func productMatches(p *domain.Product, term string) bool {
+	return strings.Contains(strings.ToLower(p.Name), term) ||
+		strings.Contains(strings.ToLower(p.Description), term) ||
+		strings.Contains(strings.ToLower(p.Category), term)
+}
+
 func (r *MemoryRepository) CreateCart(ctx context.Context, cart *domain.Cart) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -167,7 +298,7 @@ func (r *MemoryRepository) CreateCart(ctx context.Context, cart *domain.Cart) er
 		return errors.NewAlreadyExistsError("cart")
 	}
 
-	r.carts[cart.ID] = cart
+	r.carts[cart.ID] = copyCart(cart)
 	return nil
 }
 
@@ -180,18 +311,23 @@ func (r *MemoryRepository) GetCart(ctx context.Context, id string) (*domain.Cart
 		return nil, errors.NewNotFoundError("cart")
 	}
 
-	return cart, nil
+	return copyCart(cart), nil
 }
 
 func (r *MemoryRepository) UpdateCart(ctx context.Context, cart *domain.Cart) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.carts[cart.ID]; !exists {
+	stored, exists := r.carts[cart.ID]
+	if !exists {
 		return errors.NewNotFoundError("cart")
 	}
+	if stored.Version != cart.Version {
+		return errors.New(errors.ErrCodeConflict, "cart was modified concurrently")
+	}
 
-	r.carts[cart.ID] = cart
+	cart.Version++
+	r.carts[cart.ID] = copyCart(cart)
 	return nil
 }
 
@@ -201,13 +337,63 @@ func (r *MemoryRepository) GetCartByCustomer(ctx context.Context, customerID str
 
 	for _, cart := range r.carts {
 		if cart.CustomerID == customerID {
-			return cart, nil
+			return copyCart(cart), nil
 		}
 	}
 
 	return nil, errors.NewNotFoundError("cart")
 }
 
+func (r *MemoryRepository) ListCarts(ctx context.Context, limit, offset int) ([]*domain.Cart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	carts := make([]*domain.Cart, 0, len(r.carts))
+	for _, c := range r.carts {
+		carts = append(carts, copyCart(c))
+	}
+
+	sort.Slice(carts, func(i, j int) bool {
+		if !carts[i].UpdatedAt.Equal(carts[j].UpdatedAt) {
+			return carts[i].UpdatedAt.Before(carts[j].UpdatedAt)
+		}
+		return carts[i].ID < carts[j].ID
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(carts) {
+		return []*domain.Cart{}, nil
+	}
+	if end > len(carts) {
+		end = len(carts)
+	}
+
+	return carts[start:end], nil
+}
+
+// copyCart keeps callers from changing a stored cart without going through
+// UpdateCart and its version check.
+func copyCart(cart *domain.Cart) *domain.Cart {
+	copied := *cart
+	copied.Items = make([]domain.CartItem, len(cart.Items))
+	copy(copied.Items, cart.Items)
+	return &copied
+}
+
+func (r *MemoryRepository) DeleteCart(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.carts[id]; !exists {
+		return errors.NewNotFoundError("cart")
+	}
+
+	delete(r.carts, id)
+	return nil
+}
+
 func (r *MemoryRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -216,6 +402,14 @@ func (r *MemoryRepository) CreateTransaction(ctx context.Context, transaction *d
 		return errors.NewAlreadyExistsError("transaction")
 	}
 
+	if key := transaction.IdempotencyKey(); key != "" {
+		for _, existing := range r.transactions {
+			if existing.IdempotencyKey() == key {
+				return errors.NewAlreadyExistsError("transaction with this idempotency key")
+			}
+		}
+	}
+
 	r.transactions[transaction.ID] = transaction
 	return nil
 }
@@ -232,6 +426,49 @@ func (r *MemoryRepository) GetTransaction(ctx context.Context, id string) (*doma
 	return transaction, nil
 }
 
+func (r *MemoryRepository) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, transaction := range r.transactions {
+		if transaction.IdempotencyKey() == key {
+			return transaction, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("transaction")
+}
+
+func (r *MemoryRepository) AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, transaction := range r.transactions {
+		if transaction.IdempotencyKey() != key {
+			continue
+		}
+		if !isStaleCheckout(transaction, staleBefore) {
+			return errors.New(errors.ErrCodeConflict, "transaction is not a stale checkout")
+		}
+		transaction.Abandon()
+		return nil
+	}
+
+	return errors.NewNotFoundError("transaction")
+}
+
+func (r *MemoryRepository) UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.transactions[transaction.ID]; !exists {
+		return errors.NewNotFoundError("transaction")
+	}
+
+	r.transactions[transaction.ID] = transaction
+	return nil
+}
+
 func (r *MemoryRepository) ListTransactionsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -256,81 +493,568 @@ func (r *MemoryRepository) ListTransactionsByCustomer(ctx context.Context, custo
 	return transactions[start:end], nil
 }
 
-func (r *MemoryRepository) Close() error {
+func (r *MemoryRepository) CountTransactionsByCustomer(ctx context.Context, customerID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, t := range r.transactions {
+		if t.CustomerID == customerID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *MemoryRepository) ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]*domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, t := range r.transactions {
+		if filter.Matches(t) {
+			transactions = append(transactions, t)
+		}
+	}
 
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) {
+			return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+		}
+		return transactions[i].ID > transactions[j].ID
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(transactions) {
+		return []*domain.Transaction{}, nil
+	}
+	if end > len(transactions) {
+		end = len(transactions)
+	}
+
+	return transactions[start:end], nil
+}
+
+func (r *MemoryRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; exists {
+		return errors.NewAlreadyExistsError("order")
+	}
+
+	stored := *order
+	r.orders[order.ID] = &stored
 	return nil
 }
 
-func (r *MemoryRepository) seedData() {
-
-	products := []*domain.Product{
-		{
-			ID:          "prod-1",
-			Name:        "Laptop",
-			Description: "High-performance laptop",
-			Price:       999.99,
-			SKU:         "LAP-001",
-			Stock:       10,
-			Category:    "Electronics",
-		},
-		{
-			ID:          "prod-2",
-			Name:        "Wireless Mouse",
-			Description: "Ergonomic wireless mouse",
-			Price:       29.99,
-			SKU:         "MOU-001",
-			Stock:       50,
-			Category:    "Accessories",
-		},
-		{
-			ID:          "prod-3",
-			Name:        "USB-C Cable",
-			Description: "High-speed USB-C cable",
-			Price:       19.99,
-			SKU:         "CAB-001",
-			Stock:       100,
-			Category:    "Accessories",
-		},
-		{
-			ID:          "prod-4",
-			Name:        "Mechanical Keyboard",
-			Description: "RGB mechanical keyboard",
-			Price:       149.99,
-			SKU:         "KEY-001",
-			Stock:       25,
-			Category:    "Accessories",
-		},
-		{
-			ID:          "prod-5",
-			Name:        "Monitor",
-			Description: "27-inch 4K monitor",
-			Price:       399.99,
-			SKU:         "MON-001",
-			Stock:       15,
-			Category:    "Electronics",
-		},
-	}
-
-	for _, p := range products {
-		r.products[p.ID] = p
-	}
-
-	customer := &domain.Customer{
-		ID:            "cust-1",
-		Email:         "john.doe@example.com",
-		Name:          "John Doe",
-		Phone:         "+1234567890",
-		LoyaltyPoints: 500,
-		Address: domain.Address{
-			Street:     "123 Main St",
-			City:       "San Francisco",
-			State:      "CA",
-			PostalCode: "94105",
-			Country:    "USA",
-		},
+func (r *MemoryRepository) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, exists := r.orders[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("order")
 	}
 
-	r.customers[customer.ID] = customer
+	copied := *order
+	return &copied, nil
+}
+
+func (r *MemoryRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.orders[order.ID]; !exists {
+		return errors.NewNotFoundError("order")
+	}
+
+	stored := *order
+	r.orders[order.ID] = &stored
+	return nil
+}
+
+func (r *MemoryRepository) ListOrdersByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	orders := make([]*domain.Order, 0)
+	for _, o := range r.orders {
+		if o.CustomerID == customerID {
+			orders = append(orders, o)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(orders) {
+		return []*domain.Order{}, nil
+	}
+	if end > len(orders) {
+		end = len(orders)
+	}
+
+	return orders[start:end], nil
+}
+
+// Discounts are keyed by their normalized code, which is unique.
+func (r *MemoryRepository) CreateDiscount(ctx context.Context, discount *domain.Discount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.NormalizeDiscountCode(discount.Code)
+	if _, exists := r.discounts[key]; exists {
+		return errors.NewAlreadyExistsError("discount")
+	}
+
+	r.discounts[key] = discount
+	return nil
+}
+
+func (r *MemoryRepository) GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	discount, exists := r.discounts[domain.NormalizeDiscountCode(code)]
+	if !exists {
+		return nil, errors.NewNotFoundError("discount")
+	}
+
+	return discount, nil
+}
+
+func (r *MemoryRepository) UpdateDiscount(ctx context.Context, discount *domain.Discount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.NormalizeDiscountCode(discount.Code)
+	if _, exists := r.discounts[key]; !exists {
+		return errors.NewNotFoundError("discount")
+	}
+
+	r.discounts[key] = discount
+	return nil
+}
+
+func (r *MemoryRepository) DeleteDiscount(ctx context.Context, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.NormalizeDiscountCode(code)
+	if _, exists := r.discounts[key]; !exists {
+		return errors.NewNotFoundError("discount")
+	}
+
+	delete(r.discounts, key)
+	return nil
+}
+
+func (r *MemoryRepository) ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	discounts := make([]*domain.Discount, 0, len(r.discounts))
+	for _, d := range r.discounts {
+		discounts = append(discounts, d)
+	}
+
+	sort.Slice(discounts, func(i, j int) bool {
+		return discounts[i].Code < discounts[j].Code
+	})
 
-	fmt.Println("✓ Sample data seeded successfully")
+	start := offset
+	end := offset + limit
+
+	if start >= len(discounts) {
+		return []*domain.Discount{}, nil
+	}
+	if end > len(discounts) {
+		end = len(discounts)
+	}
+
+	return discounts[start:end], nil
+}
+
+func (r *MemoryRepository) CreatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.schedules[schedule.ID]; exists {
+		return errors.NewAlreadyExistsError("payment schedule")
+	}
+
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *MemoryRepository) GetPaymentSchedule(ctx context.Context, id string) (*domain.PaymentSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, exists := r.schedules[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("payment schedule")
+	}
+
+	return schedule, nil
+}
+
+func (r *MemoryRepository) UpdatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.schedules[schedule.ID]; !exists {
+		return errors.NewNotFoundError("payment schedule")
+	}
+
+	r.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (r *MemoryRepository) ListPaymentSchedules(ctx context.Context, limit, offset int) ([]*domain.PaymentSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedules := make([]*domain.PaymentSchedule, 0, len(r.schedules))
+	for _, s := range r.schedules {
+		schedules = append(schedules, s)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt.After(schedules[j].CreatedAt)
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(schedules) {
+		return []*domain.PaymentSchedule{}, nil
+	}
+	if end > len(schedules) {
+		end = len(schedules)
+	}
+
+	return schedules[start:end], nil
+}
+
+func (r *MemoryRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[subscription.ID]; exists {
+		return errors.NewAlreadyExistsError("subscription")
+	}
+
+	r.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (r *MemoryRepository) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, exists := r.subscriptions[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("subscription")
+	}
+
+	return subscription, nil
+}
+
+func (r *MemoryRepository) UpdateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.subscriptions[subscription.ID]; !exists {
+		return errors.NewNotFoundError("subscription")
+	}
+
+	r.subscriptions[subscription.ID] = subscription
+	return nil
+}
+
+func (r *MemoryRepository) ListSubscriptions(ctx context.Context, limit, offset int) ([]*domain.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscriptions := make([]*domain.Subscription, 0, len(r.subscriptions))
+	for _, s := range r.subscriptions {
+		subscriptions = append(subscriptions, s)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.After(subscriptions[j].CreatedAt)
+	})
+
+	start := offset
+	end := offset + limit
+
+	if start >= len(subscriptions) {
+		return []*domain.Subscription{}, nil
+	}
+	if end > len(subscriptions) {
+		end = len(subscriptions)
+	}
+
+	return subscriptions[start:end], nil
+}
+
+func (r *MemoryRepository) ListDueSubscriptions(ctx context.Context, at time.Time) ([]*domain.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	due := []*domain.Subscription{}
+	for _, s := range r.subscriptions {
+		if s.IsDue(at) {
+			due = append(due, s)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextBillingDate.Before(due[j].NextBillingDate)
+	})
+
+	return due, nil
+}
+
+func (r *MemoryRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.reservations[reservation.ID]; exists {
+		return errors.NewAlreadyExistsError("reservation")
+	}
+
+	r.reservations[reservation.ID] = reservation
+	return nil
+}
+
+func (r *MemoryRepository) ListReservationsByTransaction(ctx context.Context, transactionID string) ([]*domain.Reservation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reservations := []*domain.Reservation{}
+	for _, reservation := range r.reservations {
+		if reservation.TransactionID == transactionID {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].ID < reservations[j].ID
+	})
+
+	return reservations, nil
+}
+
+func (r *MemoryRepository) ReservedQuantity(ctx context.Context, productID string, at time.Time) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reserved := 0
+	for _, reservation := range r.reservations {
+		if reservation.ProductID == productID && !reservation.IsExpired(at) {
+			reserved += reservation.Quantity
+		}
+	}
+
+	return reserved, nil
+}
+
+func (r *MemoryRepository) DeleteReservation(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.reservations[id]; !exists {
+		return errors.NewNotFoundError("reservation")
+	}
+
+	delete(r.reservations, id)
+	return nil
+}
+
+func (r *MemoryRepository) DeleteExpiredReservations(ctx context.Context, at time.Time) ([]*domain.Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := []*domain.Reservation{}
+	for id, reservation := range r.reservations {
+		if reservation.IsExpired(at) {
+			expired = append(expired, reservation)
+			delete(r.reservations, id)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].ID < expired[j].ID
+	})
+
+	return expired, nil
+}
+
+func (r *MemoryRepository) CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loyalty = append(r.loyalty, entry)
+	return nil
+}
+
+func (r *MemoryRepository) ListLoyaltyEntries(ctx context.Context, customerID string) ([]*domain.LoyaltyEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []*domain.LoyaltyEntry{}
+	for _, entry := range r.loyalty {
+		if entry.CustomerID == customerID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (r *MemoryRepository) RecordCashback(ctx context.Context, entry *domain.CashbackEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	customer, exists := r.customers[entry.CustomerID]
+	if !exists {
+		return errors.NewNotFoundError("customer")
+	}
+
+	customer.CashbackBalance = entry.Balance
+	r.cashback = append(r.cashback, entry)
+	return nil
+}
+
+func (r *MemoryRepository) ListCashbackEntries(ctx context.Context, customerID string) ([]*domain.CashbackEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []*domain.CashbackEntry{}
+	for _, entry := range r.cashback {
+		if entry.CustomerID == customerID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// Receipts are keyed by transaction ID.
+func (r *MemoryRepository) CreateReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.receipts[receipt.TransactionID]; exists {
+		return errors.NewAlreadyExistsError("receipt")
+	}
+	for _, existing := range r.receipts {
+		if existing.ID == receipt.ID {
+			return errors.NewAlreadyExistsError("receipt")
+		}
+	}
+
+	r.receipts[receipt.TransactionID] = receipt
+	return nil
+}
+
+func (r *MemoryRepository) GetReceipt(ctx context.Context, id string) (*domain.Receipt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, receipt := range r.receipts {
+		if receipt.ID == id {
+			return receipt, nil
+		}
+	}
+
+	return nil, errors.NewNotFoundError("receipt")
+}
+
+func (r *MemoryRepository) GetReceiptByTransaction(ctx context.Context, transactionID string) (*domain.Receipt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	receipt, exists := r.receipts[transactionID]
+	if !exists {
+		return nil, errors.NewNotFoundError("receipt")
+	}
+
+	return receipt, nil
+}
+
+func (r *MemoryRepository) CreatePaymentMethod(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.methods[method.ID]; exists {
+		return errors.NewAlreadyExistsError("payment method")
+	}
+
+	r.methods[method.ID] = method
+	return nil
+}
+
+func (r *MemoryRepository) GetPaymentMethod(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	method, exists := r.methods[id]
+	if !exists {
+		return nil, errors.NewNotFoundError("payment method")
+	}
+
+	return method, nil
+}
+
+func (r *MemoryRepository) ListPaymentMethodsByCustomer(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	methods := []*domain.SavedPaymentMethod{}
+	for _, method := range r.methods {
+		if method.CustomerID == customerID {
+			methods = append(methods, method)
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		if methods[i].CreatedAt.Equal(methods[j].CreatedAt) {
+			return methods[i].ID < methods[j].ID
+		}
+		return methods[i].CreatedAt.Before(methods[j].CreatedAt)
+	})
+
+	return methods, nil
+}
+
+func (r *MemoryRepository) DeletePaymentMethod(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.methods[id]; !exists {
+		return errors.NewNotFoundError("payment method")
+	}
+
+	delete(r.methods, id)
+	return nil
+}
+
+func (r *MemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (r *MemoryRepository) Close() error {
+
+	return nil
 }
@@ -0,0 +1,421 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepositoryListTransactions(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+	now := time.Now()
+
+	seed := []*domain.Transaction{
+		{ID: "tx-1", CustomerID: "a", Amount: 20, Status: domain.TransactionStatusCompleted, PaymentMethod: "credit_card", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "tx-2", CustomerID: "b", Amount: 150, Status: domain.TransactionStatusFailed, PaymentMethod: "paypal", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "tx-3", CustomerID: "a", Amount: 300, Status: domain.TransactionStatusFailed, PaymentMethod: "credit_card", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+	for _, tx := range seed {
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+
+	ids := func(transactions []*domain.Transaction) []string {
+		result := make([]string, 0, len(transactions))
+		for _, tx := range transactions {
+			result = append(result, tx.ID)
+		}
+		return result
+	}
+
+	t.Run("No Filter Returns All Newest First", func(t *testing.T) {
+		transactions, err := repo.ListTransactions(ctx, TransactionFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tx-3", "tx-2", "tx-1"}, ids(transactions))
+	})
+
+	t.Run("Status And Method", func(t *testing.T) {
+		transactions, err := repo.ListTransactions(ctx, TransactionFilter{
+			Status:        domain.TransactionStatusFailed,
+			PaymentMethod: "credit_card",
+		}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tx-3"}, ids(transactions))
+	})
+
+	t.Run("Amount Range And Time Window", func(t *testing.T) {
+		transactions, err := repo.ListTransactions(ctx, TransactionFilter{
+			MinAmount:     100,
+			MaxAmount:     200,
+			CreatedAfter:  now.Add(-150 * time.Minute),
+			CreatedBefore: now,
+		}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tx-2"}, ids(transactions))
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		transactions, err := repo.ListTransactions(ctx, TransactionFilter{}, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tx-2"}, ids(transactions))
+	})
+}
+
+func TestSeedDatasets(t *testing.T) {
+	ctx := context.Background()
+
+	count := func(t *testing.T, repo Repository) (int, int) {
+		products, err := repo.ListProducts(ctx, 100, 0)
+		require.NoError(t, err)
+		customers, err := repo.ListCustomers(ctx, 100, 0)
+		require.NoError(t, err)
+		return len(products), len(customers)
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		products, customers := count(t, NewMemoryRepositoryWithSeed(SeedNone))
+		assert.Equal(t, 0, products)
+		assert.Equal(t, 0, customers)
+
+		products, customers = count(t, NewMemoryRepositoryWithSeed(SeedMinimal))
+		assert.Equal(t, 5, products)
+		assert.Equal(t, 1, customers)
+
+		products, customers = count(t, NewMemoryRepositoryWithSeed(SeedDemo))
+		assert.Equal(t, 12, products)
+		assert.Equal(t, 3, customers)
+	})
+
+	t.Run("File Is Not Reseeded", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+
+		empty, err := NewFileRepository(filepath.Join(t.TempDir(), "empty.json"), SeedNone)
+		require.NoError(t, err)
+		products, customers := count(t, empty)
+		assert.Equal(t, 0, products)
+		assert.Equal(t, 0, customers)
+
+		_, err = NewFileRepository(path, SeedMinimal)
+		require.NoError(t, err)
+
+		reopened, err := NewFileRepository(path, SeedDemo)
+		require.NoError(t, err)
+		products, customers = count(t, reopened)
+		assert.Equal(t, 5, products)
+		assert.Equal(t, 1, customers)
+	})
+
+	t.Run("Unknown Dataset", func(t *testing.T) {
+		_, err := ParseSeedDataset("huge")
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryRepositorySearchProducts(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+
+	ids := func(products []*domain.Product) []string {
+		result := make([]string, 0, len(products))
+		for _, p := range products {
+			result = append(result, p.ID)
+		}
+		return result
+	}
+
+	t.Run("Matches Name Case Insensitively", func(t *testing.T) {
+		products, err := repo.SearchProducts(ctx, "MOUSE", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-2"}, ids(products))
+	})
+
+	t.Run("Matches Description And Category", func(t *testing.T) {
+		products, err := repo.SearchProducts(ctx, "high-speed", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-3"}, ids(products))
+
+		products, err = repo.SearchProducts(ctx, "electronics", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-1", "prod-5"}, ids(products))
+	})
+
+	t.Run("Paginates Results", func(t *testing.T) {
+		products, err := repo.SearchProducts(ctx, "accessories", 2, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"prod-3", "prod-4"}, ids(products))
+	})
+
+	t.Run("No Match", func(t *testing.T) {
+		products, err := repo.SearchProducts(ctx, "blender", 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, products)
+	})
+}
+
+func TestReceiptsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	receipt := &domain.Receipt{
+		ID:            "rcpt-1",
+		TransactionID: "tx-1",
+		OrderID:       "order-1",
+		CustomerID:    "cust-1",
+		CustomerName:  "Jane Doe",
+		Items: []domain.ReceiptItem{
+			{ProductID: "prod-1", ProductName: "Laptop", SKU: "LAP-001", Quantity: 1, UnitPrice: 999.99, Total: 999.99},
+			{ProductID: "prod-2", ProductName: "Mouse", SKU: "MOU-001", Quantity: 2, UnitPrice: 29.99, Total: 59.98},
+		},
+		Subtotal:          1059.97,
+		Discount:          100,
+		Tax:               81.60,
+		ServiceFee:        2.50,
+		Cashback:          5.22,
+		LoyaltyPoints:     1044,
+		Total:             1044.07,
+		Currency:          "USD",
+		PaymentMethod:     "credit_card",
+		Strategy:          "instant",
+		AppliedDecorators: []string{"tax", "discount", "cashback"},
+		CreatedAt:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	check := func(t *testing.T, repo Repository) {
+		stored, err := repo.GetReceipt(ctx, "rcpt-1")
+		require.NoError(t, err)
+		assert.Equal(t, receipt, stored)
+
+		stored, err = repo.GetReceiptByTransaction(ctx, "tx-1")
+		require.NoError(t, err)
+		assert.Equal(t, receipt, stored)
+
+		_, err = repo.GetReceiptByTransaction(ctx, "tx-2")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		repo := NewMemoryRepositoryWithSeed(SeedNone)
+		require.NoError(t, repo.CreateReceipt(ctx, receipt))
+		check(t, repo)
+
+		duplicate := *receipt
+		duplicate.ID = "rcpt-2"
+		assert.True(t, errors.IsErrorCode(repo.CreateReceipt(ctx, &duplicate), errors.ErrCodeAlreadyExists))
+	})
+
+	t.Run("File Survives Reopen", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "store.json")
+		repo, err := NewFileRepository(path, SeedNone)
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateReceipt(ctx, receipt))
+
+		reopened, err := NewFileRepository(path, SeedNone)
+		require.NoError(t, err)
+		check(t, reopened)
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "store.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+		defer repo.Close()
+
+		require.NoError(t, repo.CreateReceipt(ctx, receipt))
+		check(t, repo)
+
+		assert.True(t, errors.IsErrorCode(repo.CreateReceipt(ctx, receipt), errors.ErrCodeAlreadyExists))
+	})
+}
+
+func TestUpdateCartVersionConflict(t *testing.T) {
+	ctx := context.Background()
+
+	check := func(t *testing.T, repo Repository) {
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-1", Email: "jane@example.com", Name: "Jane Doe"}))
+		require.NoError(t, repo.CreateCart(ctx, &domain.Cart{ID: "cart-1", CustomerID: "cust-1", Items: []domain.CartItem{}}))
+
+		first, err := repo.GetCart(ctx, "cart-1")
+		require.NoError(t, err)
+		second, err := repo.GetCart(ctx, "cart-1")
+		require.NoError(t, err)
+
+		first.AddItem(domain.Product{ID: "prod-1", Price: 10}, 1)
+		require.NoError(t, repo.UpdateCart(ctx, first))
+		assert.Equal(t, 1, first.Version)
+
+		second.AddItem(domain.Product{ID: "prod-2", Price: 20}, 1)
+		err = repo.UpdateCart(ctx, second)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeConflict))
+
+		stored, err := repo.GetCart(ctx, "cart-1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, stored.Version)
+		require.Len(t, stored.Items, 1)
+		assert.Equal(t, "prod-1", stored.Items[0].ProductID)
+
+		err = repo.UpdateCart(ctx, &domain.Cart{ID: "cart-missing"})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		check(t, NewMemoryRepositoryWithSeed(SeedNone))
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "store.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+		defer repo.Close()
+
+		check(t, repo)
+	})
+}
+
+func TestAbandonStaleTransaction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	check := func(t *testing.T, repo Repository) {
+		for _, tx := range []*domain.Transaction{
+			{ID: "tx-stale", CreatedAt: now.Add(-time.Hour), Metadata: map[string]interface{}{"idempotency_key": "key-stale"}},
+			{ID: "tx-live", CreatedAt: now, Metadata: map[string]interface{}{"idempotency_key": "key-live"}},
+		} {
+			tx.CustomerID = "cust-1"
+			tx.Amount = 10
+			tx.Status = domain.TransactionStatusPending
+			require.NoError(t, repo.CreateTransaction(ctx, tx))
+		}
+		staleBefore := now.Add(-time.Minute)
+
+		err := repo.AbandonStaleTransaction(ctx, "key-live", staleBefore)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeConflict))
+
+		require.NoError(t, repo.AbandonStaleTransaction(ctx, "key-stale", staleBefore))
+
+		abandoned, err := repo.GetTransaction(ctx, "tx-stale")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusFailed, abandoned.Status)
+		assert.Equal(t, "", abandoned.IdempotencyKey())
+		assert.Equal(t, "key-stale", abandoned.Metadata["abandoned_idempotency_key"])
+
+		_, err = repo.GetTransactionByIdempotencyKey(ctx, "key-stale")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+		assert.True(t, errors.IsErrorCode(repo.AbandonStaleTransaction(ctx, "key-stale", staleBefore), errors.ErrCodeNotFound))
+
+		require.NoError(t, repo.CreateTransaction(ctx, &domain.Transaction{
+			ID:         "tx-retry",
+			CustomerID: "cust-1",
+			Amount:     10,
+			Status:     domain.TransactionStatusPending,
+			Metadata:   map[string]interface{}{"idempotency_key": "key-stale"},
+			CreatedAt:  now,
+		}))
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		check(t, NewMemoryRepositoryWithSeed(SeedNone))
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "store.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+		defer repo.Close()
+
+		check(t, repo)
+	})
+}
+
+func TestProductSKULookupAndDelete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	check := func(t *testing.T, repo Repository) {
+		require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: "prod-1", Name: "Hub", SKU: "HUB-001", Price: 24.99, Stock: 4, TaxExempt: true, CreatedAt: now, UpdatedAt: now}))
+		require.NoError(t, repo.CreateReservation(ctx, &domain.Reservation{
+			ID: "res-1", ProductID: "prod-1", Quantity: 2, TransactionID: "tx-1", ExpiresAt: now.Add(time.Hour), CreatedAt: now,
+		}))
+
+		product, err := repo.GetProductBySKU(ctx, "HUB-001")
+		require.NoError(t, err)
+		assert.Equal(t, "prod-1", product.ID)
+		assert.True(t, product.TaxExempt)
+
+		_, err = repo.GetProductBySKU(ctx, "HUB-002")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+
+		require.NoError(t, repo.DeleteProduct(ctx, "prod-1"))
+		_, err = repo.GetProduct(ctx, "prod-1")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+
+		reserved, err := repo.ReservedQuantity(ctx, "prod-1", now)
+		require.NoError(t, err)
+		assert.Zero(t, reserved)
+
+		assert.True(t, errors.IsErrorCode(repo.DeleteProduct(ctx, "prod-1"), errors.ErrCodeNotFound))
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		check(t, NewMemoryRepositoryWithSeed(SeedNone))
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "store.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+		defer repo.Close()
+
+		check(t, repo)
+	})
+}
+
+func TestListCounts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	check := func(t *testing.T, repo Repository) {
+		for i, email := range []string{"a@example.com", "b@example.com"} {
+			id := fmt.Sprintf("cust-%d", i+1)
+			require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: id, Email: email, Name: id, CreatedAt: now, UpdatedAt: now}))
+		}
+		for i := 1; i <= 3; i++ {
+			id := fmt.Sprintf("prod-%d", i)
+			require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: id, Name: id, SKU: id, Price: 10, Stock: 1, CreatedAt: now, UpdatedAt: now}))
+		}
+		for i, customerID := range []string{"cust-1", "cust-1", "cust-2"} {
+			require.NoError(t, repo.CreateTransaction(ctx, &domain.Transaction{
+				ID: fmt.Sprintf("tx-%d", i+1), CustomerID: customerID, Amount: 10, Status: domain.TransactionStatusCompleted, PaymentMethod: "credit_card", CreatedAt: now,
+			}))
+		}
+
+		customers, err := repo.CountCustomers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, customers)
+
+		products, err := repo.CountProducts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, products)
+
+		transactions, err := repo.CountTransactionsByCustomer(ctx, "cust-1")
+		require.NoError(t, err)
+		assert.Equal(t, 2, transactions)
+
+		page, err := repo.ListTransactionsByCustomer(ctx, "cust-1", 1, 1)
+		require.NoError(t, err)
+		assert.Len(t, page, 1)
+	}
+
+	t.Run("Memory", func(t *testing.T) {
+		check(t, NewMemoryRepositoryWithSeed(SeedNone))
+	})
+
+	t.Run("SQLite", func(t *testing.T) {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "store.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+		defer repo.Close()
+
+		check(t, repo)
+	})
+}
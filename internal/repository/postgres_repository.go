@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+type PostgresRepository struct {
+	*sqlRepository
+}
+
+func NewPostgresRepository(dsn string, opts ConnectOptions) (*PostgresRepository, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	configurePool(db, opts)
+
+	if err := pingWithRetry(db, opts); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	repo := &PostgresRepository{
+		sqlRepository: &sqlRepository{db: db, rebind: postgresRebind},
+	}
+
+	if opts.ReadOnly {
+		return repo, nil
+	}
+
+	if err := repo.initSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	if _, err := seedStore(context.Background(), repo, opts.SeedDataset); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to seed data: %w", err)
+	}
+
+	return repo, nil
+}
+
+func postgresRebind(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	for _, ch := range query {
+		if ch == '?' {
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+
+	return b.String()
+}
+
+func (r *PostgresRepository) initSchema() error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS customers (
+		id TEXT PRIMARY KEY,
+		email TEXT UNIQUE NOT NULL,
+		name TEXT NOT NULL,
+		phone TEXT,
+		loyalty_points INTEGER DEFAULT 0,
+		cashback_balance DOUBLE PRECISION DEFAULT 0,
+		spending_limit DOUBLE PRECISION DEFAULT 0,
+		locale TEXT DEFAULT '',
+		address_street TEXT,
+		address_city TEXT,
+		address_state TEXT,
+		address_postal_code TEXT,
+		address_country TEXT,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS products (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		description TEXT,
+		price DOUBLE PRECISION NOT NULL,
+		sku TEXT UNIQUE NOT NULL,
+		stock INTEGER DEFAULT 0,
+		category TEXT,
+		tax_exempt BOOLEAN DEFAULT FALSE,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS carts (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL REFERENCES customers(id),
+		items JSONB,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		version INTEGER DEFAULT 0
+	);
+
+	CREATE TABLE IF NOT EXISTS transactions (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL REFERENCES customers(id),
+		amount DOUBLE PRECISION NOT NULL,
+		status TEXT NOT NULL,
+		payment_method TEXT NOT NULL,
+		strategy TEXT DEFAULT '',
+		payment_details JSONB,
+		metadata JSONB,
+		error_message TEXT,
+		idempotency_key TEXT,
+		exchange_rate DOUBLE PRECISION DEFAULT 0,
+		base_amount DOUBLE PRECISION DEFAULT 0,
+		base_currency TEXT DEFAULT '',
+		display_amount DOUBLE PRECISION DEFAULT 0,
+		display_currency TEXT DEFAULT '',
+		processed_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS orders (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL REFERENCES customers(id),
+		transaction_id TEXT,
+		cart_id TEXT,
+		status TEXT NOT NULL,
+		items JSONB,
+		subtotal DOUBLE PRECISION NOT NULL,
+		total DOUBLE PRECISION NOT NULL,
+		fulfillments JSONB,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS discounts (
+		id TEXT PRIMARY KEY,
+		code TEXT UNIQUE NOT NULL,
+		description TEXT,
+		type TEXT NOT NULL,
+		value DOUBLE PRECISION NOT NULL,
+		min_amount DOUBLE PRECISION DEFAULT 0,
+		max_amount DOUBLE PRECISION DEFAULT 0,
+		expires_at TIMESTAMPTZ,
+		is_active BOOLEAN DEFAULT TRUE,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS subscriptions (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT,
+		customer_id TEXT NOT NULL REFERENCES customers(id),
+		payment_method TEXT NOT NULL,
+		saved_method_id TEXT DEFAULT '',
+		amount DOUBLE PRECISION NOT NULL,
+		billing_interval TEXT NOT NULL,
+		status TEXT NOT NULL,
+		next_billing_date TIMESTAMPTZ NOT NULL,
+		charge_count INTEGER DEFAULT 0,
+		failed_attempts INTEGER DEFAULT 0,
+		last_transaction_id TEXT DEFAULT '',
+		last_charged_at TIMESTAMPTZ,
+		canceled_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS payment_schedules (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT,
+		customer_id TEXT NOT NULL REFERENCES customers(id),
+		payment_method TEXT NOT NULL,
+		saved_method_id TEXT DEFAULT '',
+		total_amount DOUBLE PRECISION NOT NULL,
+		installments INTEGER NOT NULL,
+		interest_rate DOUBLE PRECISION DEFAULT 0,
+		payments JSONB,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS inventory_reservations (
+		id TEXT PRIMARY KEY,
+		product_id TEXT NOT NULL REFERENCES products(id),
+		quantity INTEGER NOT NULL,
+		cart_id TEXT,
+		transaction_id TEXT,
+		expires_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS loyalty_ledger (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		transaction_id TEXT,
+		delta INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		balance INTEGER NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS cashback_ledger (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		transaction_id TEXT,
+		delta DOUBLE PRECISION NOT NULL,
+		reason TEXT NOT NULL,
+		balance DOUBLE PRECISION NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS receipts (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT NOT NULL UNIQUE,
+		customer_id TEXT NOT NULL,
+		data TEXT NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS payment_methods (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		method TEXT NOT NULL,
+		token TEXT NOT NULL UNIQUE,
+		details TEXT,
+		created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_customers_email ON customers(email);
+	CREATE INDEX IF NOT EXISTS idx_carts_customer ON carts(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_transactions_customer ON transactions(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_orders_customer ON orders(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_payment_schedules_customer ON payment_schedules(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_subscriptions_next_billing ON subscriptions(status, next_billing_date);
+	CREATE INDEX IF NOT EXISTS idx_inventory_reservations_product ON inventory_reservations(product_id);
+	CREATE INDEX IF NOT EXISTS idx_inventory_reservations_transaction ON inventory_reservations(transaction_id);
+	CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer ON loyalty_ledger(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_cashback_ledger_customer ON cashback_ledger(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_payment_methods_customer ON payment_methods(customer_id);
+
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS strategy TEXT DEFAULT '';
+	ALTER TABLE customers ADD COLUMN IF NOT EXISTS cashback_balance DOUBLE PRECISION DEFAULT 0;
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+	ALTER TABLE customers ADD COLUMN IF NOT EXISTS spending_limit DOUBLE PRECISION DEFAULT 0;
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate DOUBLE PRECISION DEFAULT 0;
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS base_amount DOUBLE PRECISION DEFAULT 0;
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS base_currency TEXT DEFAULT '';
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS display_amount DOUBLE PRECISION DEFAULT 0;
+	ALTER TABLE transactions ADD COLUMN IF NOT EXISTS display_currency TEXT DEFAULT '';
+	ALTER TABLE carts ADD COLUMN IF NOT EXISTS version INTEGER DEFAULT 0;
+	ALTER TABLE customers ADD COLUMN IF NOT EXISTS locale TEXT DEFAULT '';
+	ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_exempt BOOLEAN DEFAULT FALSE;
+	ALTER TABLE payment_schedules ADD COLUMN IF NOT EXISTS saved_method_id TEXT DEFAULT '';
+	ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS saved_method_id TEXT DEFAULT '';
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key
+		ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
+	`
+
+	_, err := r.db.Exec(schema)
+	return err
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	assert.Equal(t,
+		"SELECT id FROM orders WHERE customer_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3",
+		postgresRebind("SELECT id FROM orders WHERE customer_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?"),
+	)
+}
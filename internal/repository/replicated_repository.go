@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+)
+
+type ReplicatedRepository struct {
+	primary         Repository
+	replicas        []Repository
+	next            uint64
+	readAfterWrite  time.Duration
+	recentWrites    map[string]time.Time
+	recentWritesMux sync.Mutex
+}
+
+func NewReplicatedRepository(primary Repository, replicas []Repository, readAfterWrite time.Duration) *ReplicatedRepository {
+	return &ReplicatedRepository{
+		primary:        primary,
+		replicas:       replicas,
+		readAfterWrite: readAfterWrite,
+		recentWrites:   make(map[string]time.Time),
+	}
+}
+
+func (r *ReplicatedRepository) reader(keys ...string) Repository {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+
+	if r.readAfterWrite > 0 {
+		r.recentWritesMux.Lock()
+		now := time.Now()
+		for _, key := range keys {
+			if writtenAt, ok := r.recentWrites[key]; ok && now.Sub(writtenAt) < r.readAfterWrite {
+				r.recentWritesMux.Unlock()
+				return r.primary
+			}
+		}
+		r.recentWritesMux.Unlock()
+	}
+
+	n := atomic.AddUint64(&r.next, 1)
+	return r.replicas[(n-1)%uint64(len(r.replicas))]
+}
+
+func (r *ReplicatedRepository) markWritten(keys ...string) {
+	if r.readAfterWrite <= 0 || len(r.replicas) == 0 {
+		return
+	}
+
+	r.recentWritesMux.Lock()
+	defer r.recentWritesMux.Unlock()
+
+	now := time.Now()
+	for key, writtenAt := range r.recentWrites {
+		if now.Sub(writtenAt) >= r.readAfterWrite {
+			delete(r.recentWrites, key)
+		}
+	}
+	for _, key := range keys {
+		r.recentWrites[key] = now
+	}
+}
+
+func (r *ReplicatedRepository) CreateCustomer(ctx context.Context, customer *domain.Customer) error {
+	if err := r.primary.CreateCustomer(ctx, customer); err != nil {
+		return err
+	}
+	r.markWritten("customer:"+customer.ID, "customer_email:"+customer.Email, "customers")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetCustomer(ctx context.Context, id string) (*domain.Customer, error) {
+	return r.reader("customer:"+id).GetCustomer(ctx, id)
+}
+
+func (r *ReplicatedRepository) GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error) {
+	return r.reader("customer_email:"+email).GetCustomerByEmail(ctx, email)
+}
+
+func (r *ReplicatedRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) error {
+	if err := r.primary.UpdateCustomer(ctx, customer); err != nil {
+		return err
+	}
+	r.markWritten("customer:"+customer.ID, "customer_email:"+customer.Email, "customers")
+	return nil
+}
+
+func (r *ReplicatedRepository) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
+	return r.reader("customers").ListCustomers(ctx, limit, offset)
+}
+
+func (r *ReplicatedRepository) CountCustomers(ctx context.Context) (int, error) {
+	return r.reader("customers").CountCustomers(ctx)
+}
+
+func (r *ReplicatedRepository) DeleteCustomer(ctx context.Context, id string) error {
+	customer, _ := r.primary.GetCustomer(ctx, id)
+
+	if err := r.primary.DeleteCustomer(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{"customer:" + id, "customers", "cart_customer:" + id, "carts", "payment_methods:" + id}
+	if customer != nil {
+		keys = append(keys, "customer_email:"+customer.Email)
+	}
+	r.markWritten(keys...)
+	return nil
+}
+
+func (r *ReplicatedRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	if err := r.primary.CreateProduct(ctx, product); err != nil {
+		return err
+	}
+	r.markWritten("product:"+product.ID, "product_sku:"+product.SKU, "products")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	return r.reader("product:"+id).GetProduct(ctx, id)
+}
+
+func (r *ReplicatedRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	return r.reader("product_sku:"+sku).GetProductBySKU(ctx, sku)
+}
+
+func (r *ReplicatedRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	previous, _ := r.primary.GetProduct(ctx, product.ID)
+
+	if err := r.primary.UpdateProduct(ctx, product); err != nil {
+		return err
+	}
+
+	keys := []string{"product:" + product.ID, "product_sku:" + product.SKU, "products"}
+	if previous != nil && previous.SKU != product.SKU {
+		keys = append(keys, "product_sku:"+previous.SKU)
+	}
+	r.markWritten(keys...)
+	return nil
+}
+
+func (r *ReplicatedRepository) DeleteProduct(ctx context.Context, id string) error {
+	product, _ := r.primary.GetProduct(ctx, id)
+
+	if err := r.primary.DeleteProduct(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{"product:" + id, "products"}
+	if product != nil {
+		keys = append(keys, "product_sku:"+product.SKU)
+	}
+	r.markWritten(keys...)
+	return nil
+}
+
+func (r *ReplicatedRepository) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+	return r.reader("products").ListProducts(ctx, limit, offset)
+}
+
+func (r *ReplicatedRepository) CountProducts(ctx context.Context) (int, error) {
+	return r.reader("products").CountProducts(ctx)
+}
+
+func (r *ReplicatedRepository) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.Product, error) {
+	return r.reader("products").SearchProducts(ctx, query, limit, offset)
+}
+
+func (r *ReplicatedRepository) CreateCart(ctx context.Context, cart *domain.Cart) error {
+	if err := r.primary.CreateCart(ctx, cart); err != nil {
+		return err
+	}
+	r.markWritten("cart:"+cart.ID, "cart_customer:"+cart.CustomerID, "carts")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetCart(ctx context.Context, id string) (*domain.Cart, error) {
+	return r.reader("cart:"+id).GetCart(ctx, id)
+}
+
+func (r *ReplicatedRepository) UpdateCart(ctx context.Context, cart *domain.Cart) error {
+	if err := r.primary.UpdateCart(ctx, cart); err != nil {
+		return err
+	}
+	r.markWritten("cart:"+cart.ID, "cart_customer:"+cart.CustomerID, "carts")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetCartByCustomer(ctx context.Context, customerID string) (*domain.Cart, error) {
+	return r.reader("cart_customer:"+customerID).GetCartByCustomer(ctx, customerID)
+}
+
+func (r *ReplicatedRepository) ListCarts(ctx context.Context, limit, offset int) ([]*domain.Cart, error) {
+	return r.reader("carts").ListCarts(ctx, limit, offset)
+}
+
+func (r *ReplicatedRepository) DeleteCart(ctx context.Context, id string) error {
+	cart, _ := r.primary.GetCart(ctx, id)
+
+	if err := r.primary.DeleteCart(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{"cart:" + id, "carts"}
+	if cart != nil {
+		keys = append(keys, "cart_customer:"+cart.CustomerID)
+	}
+	r.markWritten(keys...)
+	return nil
+}
+
+func (r *ReplicatedRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	if err := r.primary.CreateTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	r.markWritten("transaction:"+transaction.ID, "transactions_customer:"+transaction.CustomerID, "transactions")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
+	return r.reader("transaction:"+id).GetTransaction(ctx, id)
+}
+
+// Idempotency checks must never see a stale replica, so they always go to the primary.
+func (r *ReplicatedRepository) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	return r.primary.GetTransactionByIdempotencyKey(ctx, key)
+}
+
+func (r *ReplicatedRepository) AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error {
+	if err := r.primary.AbandonStaleTransaction(ctx, key, staleBefore); err != nil {
+		return err
+	}
+	r.markWritten("transactions")
+	return nil
+}
+
+func (r *ReplicatedRepository) UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	if err := r.primary.UpdateTransaction(ctx, transaction); err != nil {
+		return err
+	}
+	r.markWritten("transaction:"+transaction.ID, "transactions_customer:"+transaction.CustomerID, "transactions")
+	return nil
+}
+
+func (r *ReplicatedRepository) ListTransactionsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error) {
+	return r.reader("transactions_customer:"+customerID).ListTransactionsByCustomer(ctx, customerID, limit, offset)
+}
+
+func (r *ReplicatedRepository) CountTransactionsByCustomer(ctx context.Context, customerID string) (int, error) {
+	return r.reader("transactions_customer:"+customerID).CountTransactionsByCustomer(ctx, customerID)
+}
+
+func (r *ReplicatedRepository) ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]*domain.Transaction, error) {
+	return r.reader("transactions").ListTransactions(ctx, filter, limit, offset)
+}
+
+func (r *ReplicatedRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	if err := r.primary.CreateOrder(ctx, order); err != nil {
+		return err
+	}
+	r.markWritten("order:"+order.ID, "orders_customer:"+order.CustomerID)
+	return nil
+}
+
+func (r *ReplicatedRepository) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
+	return r.reader("order:"+id).GetOrder(ctx, id)
+}
+
+func (r *ReplicatedRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	if err := r.primary.UpdateOrder(ctx, order); err != nil {
+		return err
+	}
+	r.markWritten("order:"+order.ID, "orders_customer:"+order.CustomerID)
+	return nil
+}
+
+func (r *ReplicatedRepository) ListOrdersByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error) {
+	return r.reader("orders_customer:"+customerID).ListOrdersByCustomer(ctx, customerID, limit, offset)
+}
+
+func (r *ReplicatedRepository) CreateDiscount(ctx context.Context, discount *domain.Discount) error {
+	if err := r.primary.CreateDiscount(ctx, discount); err != nil {
+		return err
+	}
+	r.markWritten("discount:"+domain.NormalizeDiscountCode(discount.Code), "discounts")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error) {
+	return r.reader("discount:"+domain.NormalizeDiscountCode(code)).GetDiscountByCode(ctx, code)
+}
+
+func (r *ReplicatedRepository) UpdateDiscount(ctx context.Context, discount *domain.Discount) error {
+	if err := r.primary.UpdateDiscount(ctx, discount); err != nil {
+		return err
+	}
+	r.markWritten("discount:"+domain.NormalizeDiscountCode(discount.Code), "discounts")
+	return nil
+}
+
+func (r *ReplicatedRepository) DeleteDiscount(ctx context.Context, code string) error {
+	if err := r.primary.DeleteDiscount(ctx, code); err != nil {
+		return err
+	}
+	r.markWritten("discount:"+domain.NormalizeDiscountCode(code), "discounts")
+	return nil
+}
+
+func (r *ReplicatedRepository) ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error) {
+	return r.reader("discounts").ListDiscounts(ctx, limit, offset)
+}
+
+func (r *ReplicatedRepository) CreatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	if err := r.primary.CreatePaymentSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	r.markWritten("schedule:"+schedule.ID, "schedules")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetPaymentSchedule(ctx context.Context, id string) (*domain.PaymentSchedule, error) {
+	return r.reader("schedule:"+id).GetPaymentSchedule(ctx, id)
+}
+
+func (r *ReplicatedRepository) UpdatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	if err := r.primary.UpdatePaymentSchedule(ctx, schedule); err != nil {
+		return err
+	}
+	r.markWritten("schedule:"+schedule.ID, "schedules")
+	return nil
+}
+
+func (r *ReplicatedRepository) ListPaymentSchedules(ctx context.Context, limit, offset int) ([]*domain.PaymentSchedule, error) {
+	return r.reader("schedules").ListPaymentSchedules(ctx, limit, offset)
+}
+
+func (r *ReplicatedRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if err := r.primary.CreateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	r.markWritten("subscription:"+subscription.ID, "subscriptions")
+	return nil
+}
+
+func (r *ReplicatedRepository) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	return r.reader("subscription:"+id).GetSubscription(ctx, id)
+}
+
+func (r *ReplicatedRepository) UpdateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if err := r.primary.UpdateSubscription(ctx, subscription); err != nil {
+		return err
+	}
+	r.markWritten("subscription:"+subscription.ID, "subscriptions")
+	return nil
+}
+
+func (r *ReplicatedRepository) ListSubscriptions(ctx context.Context, limit, offset int) ([]*domain.Subscription, error) {
+	return r.reader("subscriptions").ListSubscriptions(ctx, limit, offset)
+}
+
+// ListDueSubscriptions always reads the primary: billing from a lagging
+// replica could charge a subscription that was just charged or canceled.
+func (r *ReplicatedRepository) ListDueSubscriptions(ctx context.Context, at time.Time) ([]*domain.Subscription, error) {
+	return r.primary.ListDueSubscriptions(ctx, at)
+}
+
+// Reservations are read from the primary; a lagging replica could oversell.
+func (r *ReplicatedRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	return r.primary.CreateReservation(ctx, reservation)
+}
+
+func (r *ReplicatedRepository) ListReservationsByTransaction(ctx context.Context, transactionID string) ([]*domain.Reservation, error) {
+	return r.primary.ListReservationsByTransaction(ctx, transactionID)
+}
+
+func (r *ReplicatedRepository) ReservedQuantity(ctx context.Context, productID string, at time.Time) (int, error) {
+	return r.primary.ReservedQuantity(ctx, productID, at)
+}
+
+func (r *ReplicatedRepository) DeleteReservation(ctx context.Context, id string) error {
+	return r.primary.DeleteReservation(ctx, id)
+}
+
+func (r *ReplicatedRepository) DeleteExpiredReservations(ctx context.Context, at time.Time) ([]*domain.Reservation, error) {
+	return r.primary.DeleteExpiredReservations(ctx, at)
+}
+
+func (r *ReplicatedRepository) CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error {
+	if err := r.primary.CreateLoyaltyEntry(ctx, entry); err != nil {
+		return err
+	}
+	r.markWritten("loyalty:" + entry.CustomerID)
+	return nil
+}
+
+func (r *ReplicatedRepository) ListLoyaltyEntries(ctx context.Context, customerID string) ([]*domain.LoyaltyEntry, error) {
+	return r.reader("loyalty:"+customerID).ListLoyaltyEntries(ctx, customerID)
+}
+
+func (r *ReplicatedRepository) RecordCashback(ctx context.Context, entry *domain.CashbackEntry) error {
+	if err := r.primary.RecordCashback(ctx, entry); err != nil {
+		return err
+	}
+	r.markWritten("customer:"+entry.CustomerID, "customers", "cashback:"+entry.CustomerID)
+	return nil
+}
+
+func (r *ReplicatedRepository) ListCashbackEntries(ctx context.Context, customerID string) ([]*domain.CashbackEntry, error) {
+	return r.reader("cashback:"+customerID).ListCashbackEntries(ctx, customerID)
+}
+
+func (r *ReplicatedRepository) CreateReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	if err := r.primary.CreateReceipt(ctx, receipt); err != nil {
+		return err
+	}
+	r.markWritten("receipt:"+receipt.ID, "receipt:"+receipt.TransactionID)
+	return nil
+}
+
+func (r *ReplicatedRepository) GetReceipt(ctx context.Context, id string) (*domain.Receipt, error) {
+	return r.reader("receipt:"+id).GetReceipt(ctx, id)
+}
+
+func (r *ReplicatedRepository) GetReceiptByTransaction(ctx context.Context, transactionID string) (*domain.Receipt, error) {
+	return r.reader("receipt:"+transactionID).GetReceiptByTransaction(ctx, transactionID)
+}
+
+func (r *ReplicatedRepository) CreatePaymentMethod(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	if err := r.primary.CreatePaymentMethod(ctx, method); err != nil {
+		return err
+	}
+	r.markWritten("payment_method:"+method.ID, "payment_methods:"+method.CustomerID)
+	return nil
+}
+
+func (r *ReplicatedRepository) GetPaymentMethod(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	return r.reader("payment_method:"+id).GetPaymentMethod(ctx, id)
+}
+
+func (r *ReplicatedRepository) ListPaymentMethodsByCustomer(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error) {
+	return r.reader("payment_methods:"+customerID).ListPaymentMethodsByCustomer(ctx, customerID)
+}
+
+func (r *ReplicatedRepository) DeletePaymentMethod(ctx context.Context, id string) error {
+	method, _ := r.primary.GetPaymentMethod(ctx, id)
+
+	if err := r.primary.DeletePaymentMethod(ctx, id); err != nil {
+		return err
+	}
+
+	keys := []string{"payment_method:" + id}
+	if method != nil {
+		keys = append(keys, "payment_methods:"+method.CustomerID)
+	}
+	r.markWritten(keys...)
+	return nil
+}
+
+// Ping fails if the primary or any replica is unreachable.
+func (r *ReplicatedRepository) Ping(ctx context.Context) error {
+	if err := r.primary.Ping(ctx); err != nil {
+		return fmt.Errorf("primary: %w", err)
+	}
+	for i, replica := range r.replicas {
+		if err := replica.Ping(ctx); err != nil {
+			return fmt.Errorf("replica %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r *ReplicatedRepository) Close() error {
+	firstErr := r.primary.Close()
+	for _, replica := range r.replicas {
+		if err := replica.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicatedRepository(t *testing.T) {
+	ctx := context.Background()
+	product := &domain.Product{ID: "prod-replica", Name: "Replica", Price: 10, Stock: 1}
+
+	t.Run("Writes Go To Primary And Reads To Replicas", func(t *testing.T) {
+		primary := NewMemoryRepository()
+		replicaA := NewMemoryRepository()
+		replicaB := NewMemoryRepository()
+		require.NoError(t, replicaB.CreateProduct(ctx, product))
+
+		repo := NewReplicatedRepository(primary, []Repository{replicaA, replicaB}, 0)
+		require.NoError(t, repo.CreateProduct(ctx, product))
+
+		_, err := replicaA.GetProduct(ctx, product.ID)
+		assert.Error(t, err, "write must not reach replicas directly")
+
+		_, err = repo.GetProduct(ctx, product.ID)
+		assert.Error(t, err, "first read goes to replica A")
+
+		_, err = repo.GetProduct(ctx, product.ID)
+		assert.NoError(t, err, "second read goes to replica B")
+	})
+
+	t.Run("Recent Write Reads From Primary", func(t *testing.T) {
+		primary := NewMemoryRepository()
+		replica := NewMemoryRepository()
+
+		repo := NewReplicatedRepository(primary, []Repository{replica}, time.Minute)
+		require.NoError(t, repo.CreateProduct(ctx, product))
+
+		got, err := repo.GetProduct(ctx, product.ID)
+		require.NoError(t, err)
+		assert.Equal(t, product.Name, got.Name)
+
+		other := &domain.Product{ID: "prod-replica-other", Name: "Other", Price: 5, Stock: 1}
+		require.NoError(t, primary.CreateProduct(ctx, other))
+
+		_, err = repo.GetProduct(ctx, other.ID)
+		assert.Error(t, err, "keys without a recent write still read from the replica")
+	})
+
+	t.Run("SKU Change Reads From Primary", func(t *testing.T) {
+		primary := NewMemoryRepository()
+		replica := NewMemoryRepository()
+		stale := &domain.Product{ID: "prod-sku", Name: "Renamed", SKU: "OLD-001", Price: 10, Stock: 1}
+		require.NoError(t, primary.CreateProduct(ctx, stale))
+		require.NoError(t, replica.CreateProduct(ctx, stale))
+
+		repo := NewReplicatedRepository(primary, []Repository{replica}, time.Minute)
+		updated := *stale
+		updated.SKU = "NEW-001"
+		require.NoError(t, repo.UpdateProduct(ctx, &updated))
+
+		got, err := repo.GetProductBySKU(ctx, "NEW-001")
+		require.NoError(t, err)
+		assert.Equal(t, updated.ID, got.ID)
+
+		_, err = repo.GetProductBySKU(ctx, "OLD-001")
+		assert.Error(t, err, "the old SKU must not be served from the stale replica")
+	})
+	t.Run("Ping Reports An Unreachable Replica", func(t *testing.T) {
+		replica, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "replica.db"), ConnectOptions{SeedDataset: SeedNone})
+		require.NoError(t, err)
+
+		repo := NewReplicatedRepository(NewMemoryRepository(), []Repository{replica}, 0)
+		require.NoError(t, repo.Ping(ctx))
+
+		require.NoError(t, replica.Close())
+		err = repo.Ping(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "replica 1")
+	})
+}
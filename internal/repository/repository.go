@@ -2,30 +2,165 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
 )
 
+type TransactionFilter struct {
+	CustomerID    string
+	Status        domain.TransactionStatus
+	PaymentMethod string
+	MinAmount     float64
+	MaxAmount     float64
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// After resumes a listing past the given transaction.
+	After *TransactionCursor
+}
+
+// TransactionCursor is a position in the created_at DESC, id DESC order that
+// transaction listings use.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func (f TransactionFilter) Matches(t *domain.Transaction) bool {
+	if f.CustomerID != "" && t.CustomerID != f.CustomerID {
+		return false
+	}
+	if f.Status != "" && t.Status != f.Status {
+		return false
+	}
+	if f.PaymentMethod != "" && t.PaymentMethod != f.PaymentMethod {
+		return false
+	}
+	if f.MinAmount > 0 && t.Amount < f.MinAmount {
+		return false
+	}
+	if f.MaxAmount > 0 && t.Amount > f.MaxAmount {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && t.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !t.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	if f.After != nil && !transactionBefore(f.After, t) {
+		return false
+	}
+	return true
+}
+
+func isStaleCheckout(t *domain.Transaction, staleBefore time.Time) bool {
+	switch t.Status {
+	case domain.TransactionStatusPending, domain.TransactionStatusProcessing:
+		return t.CreatedAt.Before(staleBefore)
+	}
+	return false
+}
+
+// transactionBefore reports whether t comes after cursor in listing order.
+func transactionBefore(cursor *TransactionCursor, t *domain.Transaction) bool {
+	if !t.CreatedAt.Equal(cursor.CreatedAt) {
+		return t.CreatedAt.Before(cursor.CreatedAt)
+	}
+	return t.ID < cursor.ID
+}
+
 type Repository interface {
 	CreateCustomer(ctx context.Context, customer *domain.Customer) error
 	GetCustomer(ctx context.Context, id string) (*domain.Customer, error)
 	GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error)
 	UpdateCustomer(ctx context.Context, customer *domain.Customer) error
 	ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
+	CountCustomers(ctx context.Context) (int, error)
+	// DeleteCustomer removes the customer together with their carts.
+	DeleteCustomer(ctx context.Context, id string) error
 
 	CreateProduct(ctx context.Context, product *domain.Product) error
 	GetProduct(ctx context.Context, id string) (*domain.Product, error)
+	GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error)
 	UpdateProduct(ctx context.Context, product *domain.Product) error
+	// DeleteProduct removes the product together with its stock reservations.
+	DeleteProduct(ctx context.Context, id string) error
 	ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error)
+	CountProducts(ctx context.Context) (int, error)
+	SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.Product, error)
 
 	CreateCart(ctx context.Context, cart *domain.Cart) error
 	GetCart(ctx context.Context, id string) (*domain.Cart, error)
 	UpdateCart(ctx context.Context, cart *domain.Cart) error
 	GetCartByCustomer(ctx context.Context, customerID string) (*domain.Cart, error)
+	ListCarts(ctx context.Context, limit, offset int) ([]*domain.Cart, error)
+	DeleteCart(ctx context.Context, id string) error
 
 	CreateTransaction(ctx context.Context, transaction *domain.Transaction) error
 	GetTransaction(ctx context.Context, id string) (*domain.Transaction, error)
+	GetTransactionByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error)
+	// AbandonStaleTransaction abandons the pending transaction holding key if it
+	// was created before staleBefore, failing with ErrCodeConflict otherwise.
+	AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error
+	UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error
 	ListTransactionsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error)
+	CountTransactionsByCustomer(ctx context.Context, customerID string) (int, error)
+	ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]*domain.Transaction, error)
+
+	CreateOrder(ctx context.Context, order *domain.Order) error
+	GetOrder(ctx context.Context, id string) (*domain.Order, error)
+	UpdateOrder(ctx context.Context, order *domain.Order) error
+	ListOrdersByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error)
+
+	CreateDiscount(ctx context.Context, discount *domain.Discount) error
+	GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error)
+	UpdateDiscount(ctx context.Context, discount *domain.Discount) error
+	DeleteDiscount(ctx context.Context, code string) error
+	ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error)
+
+	CreatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error
+	GetPaymentSchedule(ctx context.Context, id string) (*domain.PaymentSchedule, error)
+	UpdatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error
+	ListPaymentSchedules(ctx context.Context, limit, offset int) ([]*domain.PaymentSchedule, error)
+
+	CreateSubscription(ctx context.Context, subscription *domain.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*domain.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscription *domain.Subscription) error
+	ListSubscriptions(ctx context.Context, limit, offset int) ([]*domain.Subscription, error)
+	// ListDueSubscriptions returns uncanceled subscriptions due by at, oldest
+	// first.
+	ListDueSubscriptions(ctx context.Context, at time.Time) ([]*domain.Subscription, error)
+
+	CreateReservation(ctx context.Context, reservation *domain.Reservation) error
+	ListReservationsByTransaction(ctx context.Context, transactionID string) ([]*domain.Reservation, error)
+	ReservedQuantity(ctx context.Context, productID string, at time.Time) (int, error)
+	DeleteReservation(ctx context.Context, id string) error
+	DeleteExpiredReservations(ctx context.Context, at time.Time) ([]*domain.Reservation, error)
+
+	CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error
+	// ListLoyaltyEntries returns a customer's ledger, oldest entry first.
+	ListLoyaltyEntries(ctx context.Context, customerID string) ([]*domain.LoyaltyEntry, error)
+
+	// RecordCashback sets the customer's cashback balance to entry.Balance and
+	// appends entry to the cashback ledger as one unit.
+	RecordCashback(ctx context.Context, entry *domain.CashbackEntry) error
+	// ListCashbackEntries returns a customer's ledger, oldest entry first.
+	ListCashbackEntries(ctx context.Context, customerID string) ([]*domain.CashbackEntry, error)
+
+	// CreateReceipt stores the receipt issued for a transaction; there is at
+	// most one per transaction.
+	CreateReceipt(ctx context.Context, receipt *domain.Receipt) error
+	GetReceipt(ctx context.Context, id string) (*domain.Receipt, error)
+	GetReceiptByTransaction(ctx context.Context, transactionID string) (*domain.Receipt, error)
+
+	CreatePaymentMethod(ctx context.Context, method *domain.SavedPaymentMethod) error
+	GetPaymentMethod(ctx context.Context, id string) (*domain.SavedPaymentMethod, error)
+	// ListPaymentMethodsByCustomer returns the oldest method first.
+	ListPaymentMethodsByCustomer(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error)
+	DeletePaymentMethod(ctx context.Context, id string) error
 
+	// Ping checks the backing store is reachable.
+	Ping(ctx context.Context) error
 	Close() error
 }
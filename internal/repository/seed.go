@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+)
+
+// SeedDataset selects the sample data a fresh store is populated with.
+type SeedDataset string
+
+const (
+	SeedNone    SeedDataset = "none"
+	SeedMinimal SeedDataset = "minimal"
+	SeedDemo    SeedDataset = "demo"
+)
+
+func ParseSeedDataset(name string) (SeedDataset, error) {
+	switch SeedDataset(name) {
+	case "":
+		return SeedMinimal, nil
+	case SeedNone, SeedMinimal, SeedDemo:
+		return SeedDataset(name), nil
+	default:
+		return "", fmt.Errorf("unknown seed dataset %q (expected none, minimal or demo)", name)
+	}
+}
+
+type seedTarget interface {
+	CreateProduct(ctx context.Context, product *domain.Product) error
+	CreateCustomer(ctx context.Context, customer *domain.Customer) error
+	ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error)
+	ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error)
+	CreateDiscount(ctx context.Context, discount *domain.Discount) error
+	ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error)
+}
+
+// seedStore populates the store with the dataset only when it holds no
+// products and no customers, so every backend treats existing data the same.
+// Sample discount codes are seeded separately whenever none exist, so stores
+// created before discounts were added get them too.
+func seedStore(ctx context.Context, target seedTarget, dataset SeedDataset) (bool, error) {
+	if dataset == SeedNone {
+		return false, nil
+	}
+	if dataset == "" {
+		dataset = SeedMinimal
+	}
+
+	discountsSeeded, err := seedDiscounts(ctx, target)
+	if err != nil {
+		return false, err
+	}
+
+	products, err := target.ListProducts(ctx, 1, 0)
+	if err != nil {
+		return false, err
+	}
+	customers, err := target.ListCustomers(ctx, 1, 0)
+	if err != nil {
+		return false, err
+	}
+	if len(products) > 0 || len(customers) > 0 {
+		return discountsSeeded, nil
+	}
+
+	now := time.Now()
+
+	for _, p := range seedProducts(dataset) {
+		p.CreatedAt = now
+		p.UpdatedAt = now
+		if err := target.CreateProduct(ctx, p); err != nil {
+			return false, err
+		}
+	}
+
+	customers = seedCustomers(dataset)
+	for _, c := range customers {
+		c.CreatedAt = now
+		c.UpdatedAt = now
+		if err := target.CreateCustomer(ctx, c); err != nil {
+			return false, err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Sample data seeded successfully (%s)\n", dataset)
+	fmt.Fprintf(os.Stderr, "✓ Default user created: %s\n", customers[0].Email)
+	return true, nil
+}
+
+func seedDiscounts(ctx context.Context, target seedTarget) (bool, error) {
+	existing, err := target.ListDiscounts(ctx, 1, 0)
+	if err != nil {
+		return false, err
+	}
+	if len(existing) > 0 {
+		return false, nil
+	}
+
+	now := time.Now()
+	discounts := []*domain.Discount{
+		{ID: "disc-welcome10", Code: "WELCOME10", Description: "10% off your order", Type: domain.DiscountTypePercentage, Value: 10, MaxAmount: 100, IsActive: true},
+		{ID: "disc-save20", Code: "SAVE20", Description: "$20 off orders over $100", Type: domain.DiscountTypeFixed, Value: 20, MinAmount: 100, IsActive: true},
+		{ID: "disc-summer", Code: "SUMMER2024", Description: "Expired summer sale", Type: domain.DiscountTypePercentage, Value: 15, ExpiresAt: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC), IsActive: true},
+	}
+
+	for _, d := range discounts {
+		d.CreatedAt = now
+		if err := target.CreateDiscount(ctx, d); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func seedProducts(dataset SeedDataset) []*domain.Product {
+	products := []*domain.Product{
+		{ID: "prod-1", Name: "Laptop", Description: "High-performance laptop", Price: 999.99, SKU: "LAP-001", Stock: 10, Category: "Electronics"},
+		{ID: "prod-2", Name: "Wireless Mouse", Description: "Ergonomic wireless mouse", Price: 29.99, SKU: "MOU-001", Stock: 50, Category: "Accessories"},
+		{ID: "prod-3", Name: "USB-C Cable", Description: "High-speed USB-C cable", Price: 19.99, SKU: "CAB-001", Stock: 100, Category: "Accessories"},
+		{ID: "prod-4", Name: "Mechanical Keyboard", Description: "RGB mechanical keyboard", Price: 149.99, SKU: "KEY-001", Stock: 25, Category: "Accessories"},
+		{ID: "prod-5", Name: "Monitor", Description: "27-inch 4K monitor", Price: 399.99, SKU: "MON-001", Stock: 15, Category: "Electronics"},
+	}
+
+	if dataset != SeedDemo {
+		return products
+	}
+
+	return append(products,
+		&domain.Product{ID: "prod-6", Name: "Noise-Cancelling Headphones", Description: "Over-ear wireless headphones", Price: 249.99, SKU: "AUD-001", Stock: 30, Category: "Audio"},
+		&domain.Product{ID: "prod-7", Name: "Bluetooth Speaker", Description: "Portable waterproof speaker", Price: 79.99, SKU: "AUD-002", Stock: 40, Category: "Audio"},
+		&domain.Product{ID: "prod-8", Name: "Webcam", Description: "1080p webcam with microphone", Price: 69.99, SKU: "CAM-001", Stock: 35, Category: "Electronics"},
+		&domain.Product{ID: "prod-9", Name: "Laptop Stand", Description: "Adjustable aluminium stand", Price: 39.99, SKU: "STD-001", Stock: 60, Category: "Accessories"},
+		&domain.Product{ID: "prod-10", Name: "External SSD", Description: "1TB portable SSD", Price: 119.99, SKU: "STO-001", Stock: 45, Category: "Storage"},
+		&domain.Product{ID: "prod-11", Name: "USB Hub", Description: "7-port USB-C hub", Price: 49.99, SKU: "HUB-001", Stock: 55, Category: "Accessories"},
+		&domain.Product{ID: "prod-12", Name: "Tablet", Description: "10-inch tablet", Price: 329.99, SKU: "TAB-001", Stock: 12, Category: "Electronics"},
+	)
+}
+
+func seedCustomers(dataset SeedDataset) []*domain.Customer {
+	customers := []*domain.Customer{
+		{
+			ID:            "cust-default",
+			Email:         "john.doe@example.com",
+			Name:          "John Doe",
+			Phone:         "+1234567890",
+			LoyaltyPoints: 500,
+			Address: domain.Address{
+				Street:     "123 Main St",
+				City:       "San Francisco",
+				State:      "CA",
+				PostalCode: "94105",
+				Country:    "USA",
+			},
+		},
+	}
+
+	if dataset != SeedDemo {
+		return customers
+	}
+
+	return append(customers,
+		&domain.Customer{
+			ID:            "cust-jane",
+			Email:         "jane.smith@example.com",
+			Name:          "Jane Smith",
+			Phone:         "+1987654321",
+			LoyaltyPoints: 2500,
+			Address: domain.Address{
+				Street:     "456 Park Ave",
+				City:       "New York",
+				State:      "NY",
+				PostalCode: "10022",
+				Country:    "USA",
+			},
+		},
+		&domain.Customer{
+			ID:            "cust-carlos",
+			Email:         "carlos.diaz@example.com",
+			Name:          "Carlos Diaz",
+			Phone:         "+1555012345",
+			LoyaltyPoints: 0,
+			Address: domain.Address{
+				Street:     "789 Elm St",
+				City:       "Austin",
+				State:      "TX",
+				PostalCode: "73301",
+				Country:    "USA",
+			},
+		},
+	)
+}
@@ -0,0 +1,1482 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+type sqlRepository struct {
+	db     *sql.DB
+	rebind func(query string) string
+}
+
+const customerColumns = `id, email, name, phone, loyalty_points, cashback_balance, spending_limit, locale,
+	address_street, address_city, address_state, address_postal_code, address_country,
+	created_at, updated_at`
+
+func scanCustomer(row rowScanner) (*domain.Customer, error) {
+	customer := &domain.Customer{}
+	err := row.Scan(
+		&customer.ID, &customer.Email, &customer.Name, &customer.Phone,
+		&customer.LoyaltyPoints, &customer.CashbackBalance, &customer.SpendingLimit, &customer.Locale,
+		&customer.Address.Street, &customer.Address.City, &customer.Address.State,
+		&customer.Address.PostalCode, &customer.Address.Country,
+		&customer.CreatedAt, &customer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+func (r *sqlRepository) CreateCustomer(ctx context.Context, customer *domain.Customer) error {
+	query := `
+		INSERT INTO customers (` + customerColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		customer.ID, customer.Email, customer.Name, customer.Phone,
+		customer.LoyaltyPoints, customer.CashbackBalance, customer.SpendingLimit, customer.Locale,
+		customer.Address.Street, customer.Address.City, customer.Address.State,
+		customer.Address.PostalCode, customer.Address.Country,
+		customer.CreatedAt, customer.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *sqlRepository) GetCustomer(ctx context.Context, id string) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = ?`
+
+	customer, err := scanCustomer(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("customer")
+	}
+
+	return customer, err
+}
+
+func (r *sqlRepository) GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE email = ?`
+
+	customer, err := scanCustomer(r.db.QueryRowContext(ctx, r.rebind(query), email))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("customer")
+	}
+
+	return customer, err
+}
+
+func (r *sqlRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) error {
+	query := `
+		UPDATE customers SET email = ?, name = ?, phone = ?, loyalty_points = ?, cashback_balance = ?,
+			spending_limit = ?, locale = ?, address_street = ?, address_city = ?, address_state = ?, 
+			address_postal_code = ?, address_country = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		customer.Email, customer.Name, customer.Phone, customer.LoyaltyPoints, customer.CashbackBalance,
+		customer.SpendingLimit, customer.Locale,
+		customer.Address.Street, customer.Address.City, customer.Address.State,
+		customer.Address.PostalCode, customer.Address.Country,
+		time.Now(), customer.ID,
+	)
+
+	return err
+}
+
+func (r *sqlRepository) DeleteCustomer(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM carts WHERE customer_id = ?`), id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM payment_methods WHERE customer_id = ?`), id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM customers WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("customer")
+	}
+
+	return tx.Commit()
+}
+
+func (r *sqlRepository) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
+	query := `
+		SELECT ` + customerColumns + `
+		FROM customers
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []*domain.Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, nil
+}
+
+func (r *sqlRepository) CountCustomers(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM customers`).Scan(&count)
+	return count, err
+}
+
+func (r *sqlRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	query := `
+		INSERT INTO products (id, name, description, price, sku, stock, category, tax_exempt, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		product.ID, product.Name, product.Description, product.Price,
+		product.SKU, product.Stock, product.Category, product.TaxExempt,
+		product.CreatedAt, product.UpdatedAt,
+	)
+
+	return err
+}
+
+func (r *sqlRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
+	query := `SELECT id, name, description, price, sku, stock, category, tax_exempt, created_at, updated_at FROM products WHERE id = ?`
+
+	product := &domain.Product{}
+	err := r.db.QueryRowContext(ctx, r.rebind(query), id).Scan(
+		&product.ID, &product.Name, &product.Description, &product.Price,
+		&product.SKU, &product.Stock, &product.Category, &product.TaxExempt,
+		&product.CreatedAt, &product.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("product")
+	}
+
+	return product, err
+}
+
+func (r *sqlRepository) GetProductBySKU(ctx context.Context, sku string) (*domain.Product, error) {
+	query := `SELECT id, name, description, price, sku, stock, category, tax_exempt, created_at, updated_at FROM products WHERE sku = ?`
+
+	product := &domain.Product{}
+	err := r.db.QueryRowContext(ctx, r.rebind(query), sku).Scan(
+		&product.ID, &product.Name, &product.Description, &product.Price,
+		&product.SKU, &product.Stock, &product.Category, &product.TaxExempt,
+		&product.CreatedAt, &product.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("product")
+	}
+
+	return product, err
+}
+
+func (r *sqlRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	query := `
+		UPDATE products SET name = ?, description = ?, price = ?, stock = ?, category = ?, tax_exempt = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		product.Name, product.Description, product.Price, product.Stock,
+		product.Category, product.TaxExempt, time.Now(), product.ID,
+	)
+
+	return err
+}
+
+func (r *sqlRepository) DeleteProduct(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM inventory_reservations WHERE product_id = ?`), id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM products WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("product")
+	}
+
+	return tx.Commit()
+}
+
+func (r *sqlRepository) CountProducts(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products`).Scan(&count)
+	return count, err
+}
+
+func (r *sqlRepository) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+	query := `SELECT id, name, description, price, sku, stock, category, tax_exempt, created_at, updated_at FROM products LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*domain.Product{}
+	for rows.Next() {
+		product := &domain.Product{}
+		err := rows.Scan(
+			&product.ID, &product.Name, &product.Description, &product.Price,
+			&product.SKU, &product.Stock, &product.Category, &product.TaxExempt,
+			&product.CreatedAt, &product.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+func (r *sqlRepository) SearchProducts(ctx context.Context, query string, limit, offset int) ([]*domain.Product, error) {
+	pattern := "%" + strings.ToLower(strings.TrimSpace(query)) + "%"
+
+	sqlQuery := `SELECT id, name, description, price, sku, stock, category, tax_exempt, created_at, updated_at FROM products
+		WHERE LOWER(name) LIKE ? OR LOWER(description) LIKE ? OR LOWER(category) LIKE ?
+		ORDER BY id LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(sqlQuery), pattern, pattern, pattern, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*domain.Product{}
+	for rows.Next() {
+		product := &domain.Product{}
+		err := rows.Scan(
+			&product.ID, &product.Name, &product.Description, &product.Price,
+			&product.SKU, &product.Stock, &product.Category, &product.TaxExempt,
+			&product.CreatedAt, &product.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+func (r *sqlRepository) CreateCart(ctx context.Context, cart *domain.Cart) error {
+	itemsJSON, err := json.Marshal(cart.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO carts (id, customer_id, items, created_at, updated_at, version) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, r.rebind(query), cart.ID, cart.CustomerID, string(itemsJSON), cart.CreatedAt, cart.UpdatedAt, cart.Version)
+	return err
+}
+
+func (r *sqlRepository) GetCart(ctx context.Context, id string) (*domain.Cart, error) {
+	query := `SELECT id, customer_id, items, created_at, updated_at, version FROM carts WHERE id = ?`
+
+	var itemsJSON string
+	cart := &domain.Cart{}
+
+	err := r.db.QueryRowContext(ctx, r.rebind(query), id).Scan(
+		&cart.ID, &cart.CustomerID, &itemsJSON, &cart.CreatedAt, &cart.UpdatedAt, &cart.Version,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("cart")
+	}
+
+	if err := json.Unmarshal([]byte(itemsJSON), &cart.Items); err != nil {
+		return nil, err
+	}
+
+	return cart, err
+}
+
+func (r *sqlRepository) UpdateCart(ctx context.Context, cart *domain.Cart) error {
+	itemsJSON, err := json.Marshal(cart.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE carts SET items = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ?`
+	result, err := r.db.ExecContext(ctx, r.rebind(query), string(itemsJSON), time.Now(), cart.ID, cart.Version)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		var exists int
+		err := r.db.QueryRowContext(ctx, r.rebind(`SELECT 1 FROM carts WHERE id = ?`), cart.ID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("cart")
+		}
+		if err != nil {
+			return err
+		}
+		return errors.New(errors.ErrCodeConflict, "cart was modified concurrently")
+	}
+
+	cart.Version++
+	return nil
+}
+
+func (r *sqlRepository) GetCartByCustomer(ctx context.Context, customerID string) (*domain.Cart, error) {
+	query := `SELECT id, customer_id, items, created_at, updated_at, version FROM carts WHERE customer_id = ? ORDER BY updated_at DESC LIMIT 1`
+
+	var itemsJSON string
+	cart := &domain.Cart{}
+
+	err := r.db.QueryRowContext(ctx, r.rebind(query), customerID).Scan(
+		&cart.ID, &cart.CustomerID, &itemsJSON, &cart.CreatedAt, &cart.UpdatedAt, &cart.Version,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("cart")
+	}
+
+	if err := json.Unmarshal([]byte(itemsJSON), &cart.Items); err != nil {
+		return nil, err
+	}
+
+	return cart, err
+}
+
+func (r *sqlRepository) ListCarts(ctx context.Context, limit, offset int) ([]*domain.Cart, error) {
+	query := `SELECT id, customer_id, items, created_at, updated_at, version FROM carts ORDER BY updated_at ASC, id ASC LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := []*domain.Cart{}
+	for rows.Next() {
+		var itemsJSON string
+		cart := &domain.Cart{}
+
+		err := rows.Scan(&cart.ID, &cart.CustomerID, &itemsJSON, &cart.CreatedAt, &cart.UpdatedAt, &cart.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal([]byte(itemsJSON), &cart.Items); err != nil {
+			return nil, err
+		}
+
+		carts = append(carts, cart)
+	}
+
+	return carts, nil
+}
+
+func (r *sqlRepository) DeleteCart(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(`DELETE FROM carts WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("cart")
+	}
+
+	return nil
+}
+
+const transactionColumns = `id, customer_id, amount, status, payment_method, strategy, payment_details, metadata, error_message, processed_at, created_at,
+	exchange_rate, base_amount, base_currency, display_amount, display_currency`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransaction(row rowScanner) (*domain.Transaction, error) {
+	var detailsJSON, metadataJSON string
+	transaction := &domain.Transaction{}
+
+	err := row.Scan(
+		&transaction.ID, &transaction.CustomerID, &transaction.Amount, &transaction.Status,
+		&transaction.PaymentMethod, &transaction.Strategy, &detailsJSON, &metadataJSON,
+		&transaction.ErrorMessage, &transaction.ProcessedAt, &transaction.CreatedAt,
+		&transaction.ExchangeRate, &transaction.BaseAmount, &transaction.BaseCurrency,
+		&transaction.DisplayAmount, &transaction.DisplayCurrency,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(detailsJSON), &transaction.PaymentDetails)
+	json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
+
+	return transaction, nil
+}
+
+func (r *sqlRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	detailsJSON, _ := json.Marshal(transaction.PaymentDetails)
+	metadataJSON, _ := json.Marshal(transaction.Metadata)
+
+	key := transaction.IdempotencyKey()
+
+	query := `
+		INSERT INTO transactions (` + transactionColumns + `, idempotency_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		transaction.ID, transaction.CustomerID, transaction.Amount, transaction.Status,
+		transaction.PaymentMethod, transaction.Strategy, string(detailsJSON), string(metadataJSON),
+		transaction.ErrorMessage, transaction.ProcessedAt, transaction.CreatedAt,
+		transaction.ExchangeRate, transaction.BaseAmount, transaction.BaseCurrency,
+		transaction.DisplayAmount, transaction.DisplayCurrency,
+		sql.NullString{String: key, Valid: key != ""},
+	)
+	if err != nil && key != "" && isUniqueViolation(err) {
+		return errors.NewAlreadyExistsError("transaction with this idempotency key")
+	}
+
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || strings.Contains(msg, "duplicate key value")
+}
+
+func (r *sqlRepository) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = ?`
+
+	transaction, err := scanTransaction(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("transaction")
+	}
+
+	return transaction, err
+}
+
+func (r *sqlRepository) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM transactions WHERE idempotency_key = ?`
+
+	transaction, err := scanTransaction(r.db.QueryRowContext(ctx, r.rebind(query), key))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("transaction")
+	}
+
+	return transaction, err
+}
+
+func (r *sqlRepository) AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error {
+	transaction, err := r.GetTransactionByIdempotencyKey(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !isStaleCheckout(transaction, staleBefore) {
+		return errors.New(errors.ErrCodeConflict, "transaction is not a stale checkout")
+	}
+
+	status := transaction.Status
+	transaction.Abandon()
+	metadataJSON, _ := json.Marshal(transaction.Metadata)
+
+	// Matching on the status it was read with keeps two retries from both
+	// taking over the key.
+	query := `
+		UPDATE transactions
+		SET status = ?, error_message = ?, metadata = ?, idempotency_key = NULL
+		WHERE id = ? AND idempotency_key = ? AND status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query),
+		transaction.Status, transaction.ErrorMessage, string(metadataJSON),
+		transaction.ID, key, status,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New(errors.ErrCodeConflict, "transaction is not a stale checkout")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	detailsJSON, _ := json.Marshal(transaction.PaymentDetails)
+	metadataJSON, _ := json.Marshal(transaction.Metadata)
+
+	query := `
+		UPDATE transactions
+		SET amount = ?, status = ?, strategy = ?, payment_details = ?, metadata = ?,
+			error_message = ?, processed_at = ?, exchange_rate = ?, base_amount = ?,
+			base_currency = ?, display_amount = ?, display_currency = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query),
+		transaction.Amount, transaction.Status, transaction.Strategy, string(detailsJSON),
+		string(metadataJSON), transaction.ErrorMessage, transaction.ProcessedAt,
+		transaction.ExchangeRate, transaction.BaseAmount, transaction.BaseCurrency,
+		transaction.DisplayAmount, transaction.DisplayCurrency, transaction.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("transaction")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) ListTransactionsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error) {
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE customer_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []*domain.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+func (r *sqlRepository) CountTransactionsByCustomer(ctx context.Context, customerID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM transactions WHERE customer_id = ?`
+	err := r.db.QueryRowContext(ctx, r.rebind(query), customerID).Scan(&count)
+	return count, err
+}
+
+func (r *sqlRepository) ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]*domain.Transaction, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.CustomerID != "" {
+		conditions = append(conditions, "customer_id = ?")
+		args = append(args, filter.CustomerID)
+	}
+	if filter.PaymentMethod != "" {
+		conditions = append(conditions, "payment_method = ?")
+		args = append(args, filter.PaymentMethod)
+	}
+	if filter.MinAmount > 0 {
+		conditions = append(conditions, "amount >= ?")
+		args = append(args, filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		conditions = append(conditions, "amount <= ?")
+		args = append(args, filter.MaxAmount)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.CreatedBefore)
+	}
+	if filter.After != nil {
+		conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, filter.After.CreatedAt, filter.After.CreatedAt, filter.After.ID)
+	}
+
+	query := `SELECT ` + transactionColumns + ` FROM transactions`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?`
+	args = append(args, limit, offset)
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []*domain.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+const orderColumns = `id, customer_id, transaction_id, cart_id, status, items, subtotal, total, fulfillments, created_at, updated_at`
+
+func scanOrder(row rowScanner) (*domain.Order, error) {
+	var itemsJSON, fulfillmentsJSON string
+	order := &domain.Order{}
+
+	err := row.Scan(
+		&order.ID, &order.CustomerID, &order.TransactionID, &order.CartID, &order.Status,
+		&itemsJSON, &order.Subtotal, &order.Total, &fulfillmentsJSON,
+		&order.CreatedAt, &order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(itemsJSON), &order.Items); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(fulfillmentsJSON), &order.Fulfillments); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *sqlRepository) CreateOrder(ctx context.Context, order *domain.Order) error {
+	itemsJSON, err := json.Marshal(order.Items)
+	if err != nil {
+		return err
+	}
+	fulfillmentsJSON, err := json.Marshal(order.Fulfillments)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO orders (` + orderColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, r.rebind(query),
+		order.ID, order.CustomerID, order.TransactionID, order.CartID, order.Status,
+		string(itemsJSON), order.Subtotal, order.Total, string(fulfillmentsJSON),
+		order.CreatedAt, order.UpdatedAt,
+	)
+	return err
+}
+
+func (r *sqlRepository) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = ?`
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("order")
+	}
+
+	return order, err
+}
+
+func (r *sqlRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	fulfillmentsJSON, err := json.Marshal(order.Fulfillments)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE orders SET transaction_id = ?, status = ?, total = ?, fulfillments = ?, updated_at = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query),
+		order.TransactionID, order.Status, order.Total, string(fulfillmentsJSON), order.UpdatedAt, order.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("order")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) ListOrdersByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE customer_id = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []*domain.Order{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+const discountColumns = `id, code, description, type, value, min_amount, max_amount, expires_at, is_active, created_at`
+
+func scanDiscount(row rowScanner) (*domain.Discount, error) {
+	var expiresAt sql.NullTime
+	discount := &domain.Discount{}
+
+	err := row.Scan(
+		&discount.ID, &discount.Code, &discount.Description, &discount.Type, &discount.Value,
+		&discount.MinAmount, &discount.MaxAmount, &expiresAt, &discount.IsActive, &discount.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	discount.ExpiresAt = expiresAt.Time
+	return discount, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (r *sqlRepository) CreateDiscount(ctx context.Context, discount *domain.Discount) error {
+	query := `INSERT INTO discounts (` + discountColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		discount.ID, domain.NormalizeDiscountCode(discount.Code), discount.Description, discount.Type, discount.Value,
+		discount.MinAmount, discount.MaxAmount, nullTime(discount.ExpiresAt), discount.IsActive, discount.CreatedAt,
+	)
+	if err != nil && isUniqueViolation(err) {
+		return errors.NewAlreadyExistsError("discount")
+	}
+	return err
+}
+
+func (r *sqlRepository) GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error) {
+	query := `SELECT ` + discountColumns + ` FROM discounts WHERE code = ?`
+
+	discount, err := scanDiscount(r.db.QueryRowContext(ctx, r.rebind(query), domain.NormalizeDiscountCode(code)))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("discount")
+	}
+
+	return discount, err
+}
+
+func (r *sqlRepository) UpdateDiscount(ctx context.Context, discount *domain.Discount) error {
+	query := `
+		UPDATE discounts
+		SET description = ?, type = ?, value = ?, min_amount = ?, max_amount = ?, expires_at = ?, is_active = ?
+		WHERE code = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query),
+		discount.Description, discount.Type, discount.Value, discount.MinAmount, discount.MaxAmount,
+		nullTime(discount.ExpiresAt), discount.IsActive, domain.NormalizeDiscountCode(discount.Code),
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("discount")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) DeleteDiscount(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(`DELETE FROM discounts WHERE code = ?`), domain.NormalizeDiscountCode(code))
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("discount")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error) {
+	query := `SELECT ` + discountColumns + ` FROM discounts ORDER BY code LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discounts := []*domain.Discount{}
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		discounts = append(discounts, discount)
+	}
+
+	return discounts, nil
+}
+
+const scheduleColumns = `id, transaction_id, customer_id, payment_method, saved_method_id, total_amount,
+	installments, interest_rate, payments, created_at, updated_at`
+
+func scanPaymentSchedule(row rowScanner) (*domain.PaymentSchedule, error) {
+	var paymentsJSON string
+	schedule := &domain.PaymentSchedule{}
+
+	err := row.Scan(
+		&schedule.ID, &schedule.TransactionID, &schedule.CustomerID, &schedule.PaymentMethod,
+		&schedule.SavedMethodID, &schedule.TotalAmount, &schedule.Installments, &schedule.InterestRate,
+		&paymentsJSON, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(paymentsJSON), &schedule.Payments); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *sqlRepository) CreatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	paymentsJSON, err := json.Marshal(schedule.Payments)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO payment_schedules (` + scheduleColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, r.rebind(query),
+		schedule.ID, schedule.TransactionID, schedule.CustomerID, schedule.PaymentMethod,
+		schedule.SavedMethodID, schedule.TotalAmount, schedule.Installments, schedule.InterestRate,
+		string(paymentsJSON), schedule.CreatedAt, schedule.UpdatedAt,
+	)
+	return err
+}
+
+func (r *sqlRepository) GetPaymentSchedule(ctx context.Context, id string) (*domain.PaymentSchedule, error) {
+	query := `SELECT ` + scheduleColumns + ` FROM payment_schedules WHERE id = ?`
+
+	schedule, err := scanPaymentSchedule(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("payment schedule")
+	}
+
+	return schedule, err
+}
+
+func (r *sqlRepository) UpdatePaymentSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	paymentsJSON, err := json.Marshal(schedule.Payments)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE payment_schedules SET payments = ?, updated_at = ? WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query), string(paymentsJSON), schedule.UpdatedAt, schedule.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("payment schedule")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) ListPaymentSchedules(ctx context.Context, limit, offset int) ([]*domain.PaymentSchedule, error) {
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM payment_schedules
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []*domain.PaymentSchedule{}
+	for rows.Next() {
+		schedule, err := scanPaymentSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+const subscriptionColumns = `id, transaction_id, customer_id, payment_method, saved_method_id, amount,
+	billing_interval, status, next_billing_date, charge_count, failed_attempts, last_transaction_id,
+	last_charged_at, canceled_at, created_at, updated_at`
+
+func scanSubscription(row rowScanner) (*domain.Subscription, error) {
+	var lastChargedAt, canceledAt sql.NullTime
+	subscription := &domain.Subscription{}
+
+	err := row.Scan(
+		&subscription.ID, &subscription.TransactionID, &subscription.CustomerID, &subscription.PaymentMethod,
+		&subscription.SavedMethodID, &subscription.Amount, &subscription.Interval, &subscription.Status,
+		&subscription.NextBillingDate, &subscription.ChargeCount, &subscription.FailedAttempts,
+		&subscription.LastTransactionID, &lastChargedAt, &canceledAt,
+		&subscription.CreatedAt, &subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastChargedAt.Valid {
+		subscription.LastChargedAt = &lastChargedAt.Time
+	}
+	if canceledAt.Valid {
+		subscription.CanceledAt = &canceledAt.Time
+	}
+	return subscription, nil
+}
+
+func nullTimePtr(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return nullTime(*t)
+}
+
+func (r *sqlRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	query := `INSERT INTO subscriptions (` + subscriptionColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		subscription.ID, subscription.TransactionID, subscription.CustomerID, subscription.PaymentMethod,
+		subscription.SavedMethodID, subscription.Amount, subscription.Interval, subscription.Status,
+		subscription.NextBillingDate, subscription.ChargeCount, subscription.FailedAttempts,
+		subscription.LastTransactionID, nullTimePtr(subscription.LastChargedAt), nullTimePtr(subscription.CanceledAt),
+		subscription.CreatedAt, subscription.UpdatedAt,
+	)
+	return err
+}
+
+func (r *sqlRepository) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = ?`
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("subscription")
+	}
+
+	return subscription, err
+}
+
+func (r *sqlRepository) UpdateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	query := `
+		UPDATE subscriptions SET status = ?, next_billing_date = ?, charge_count = ?, failed_attempts = ?,
+			last_transaction_id = ?, last_charged_at = ?, canceled_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, r.rebind(query),
+		subscription.Status, subscription.NextBillingDate, subscription.ChargeCount, subscription.FailedAttempts,
+		subscription.LastTransactionID, nullTimePtr(subscription.LastChargedAt), nullTimePtr(subscription.CanceledAt),
+		subscription.UpdatedAt, subscription.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("subscription")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) ListSubscriptions(ctx context.Context, limit, offset int) ([]*domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	return r.querySubscriptions(ctx, query, limit, offset)
+}
+
+func (r *sqlRepository) ListDueSubscriptions(ctx context.Context, at time.Time) ([]*domain.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE status <> ? AND next_billing_date <= ?
+		ORDER BY next_billing_date
+	`
+
+	return r.querySubscriptions(ctx, query, domain.SubscriptionStatusCanceled, at)
+}
+
+func (r *sqlRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*domain.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []*domain.Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+const reservationColumns = `id, product_id, quantity, cart_id, transaction_id, expires_at, created_at`
+
+func scanReservation(row rowScanner) (*domain.Reservation, error) {
+	reservation := &domain.Reservation{}
+	err := row.Scan(
+		&reservation.ID, &reservation.ProductID, &reservation.Quantity, &reservation.CartID,
+		&reservation.TransactionID, &reservation.ExpiresAt, &reservation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// Reservation times are UTC so text-stored timestamps compare correctly.
+func (r *sqlRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	query := `INSERT INTO inventory_reservations (` + reservationColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		reservation.ID, reservation.ProductID, reservation.Quantity, reservation.CartID,
+		reservation.TransactionID, reservation.ExpiresAt.UTC(), reservation.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *sqlRepository) ListReservationsByTransaction(ctx context.Context, transactionID string) ([]*domain.Reservation, error) {
+	query := `SELECT ` + reservationColumns + ` FROM inventory_reservations WHERE transaction_id = ? ORDER BY id`
+
+	return r.queryReservations(ctx, r.db, query, transactionID)
+}
+
+func (r *sqlRepository) ReservedQuantity(ctx context.Context, productID string, at time.Time) (int, error) {
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM inventory_reservations WHERE product_id = ? AND expires_at > ?`
+
+	var reserved int
+	if err := r.db.QueryRowContext(ctx, r.rebind(query), productID, at.UTC()).Scan(&reserved); err != nil {
+		return 0, err
+	}
+	return reserved, nil
+}
+
+func (r *sqlRepository) DeleteReservation(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(`DELETE FROM inventory_reservations WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("reservation")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) DeleteExpiredReservations(ctx context.Context, at time.Time) ([]*domain.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT ` + reservationColumns + ` FROM inventory_reservations WHERE expires_at <= ? ORDER BY id`
+	expired, err := r.queryReservations(ctx, tx, query, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range expired {
+		if _, err := tx.ExecContext(ctx, r.rebind(`DELETE FROM inventory_reservations WHERE id = ?`), reservation.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+type reservationQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (r *sqlRepository) queryReservations(ctx context.Context, db reservationQuerier, query string, args ...interface{}) ([]*domain.Reservation, error) {
+	rows, err := db.QueryContext(ctx, r.rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []*domain.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	return reservations, nil
+}
+
+func (r *sqlRepository) CreateLoyaltyEntry(ctx context.Context, entry *domain.LoyaltyEntry) error {
+	query := `INSERT INTO loyalty_ledger (id, customer_id, transaction_id, delta, reason, balance, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		entry.ID, entry.CustomerID, entry.TransactionID, entry.Delta, entry.Reason, entry.Balance, entry.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *sqlRepository) ListLoyaltyEntries(ctx context.Context, customerID string) ([]*domain.LoyaltyEntry, error) {
+	query := `SELECT id, customer_id, transaction_id, delta, reason, balance, created_at
+		FROM loyalty_ledger WHERE customer_id = ? ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.LoyaltyEntry{}
+	for rows.Next() {
+		entry := &domain.LoyaltyEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.CustomerID, &entry.TransactionID, &entry.Delta,
+			&entry.Reason, &entry.Balance, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (r *sqlRepository) RecordCashback(ctx context.Context, entry *domain.CashbackEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, r.rebind(`UPDATE customers SET cashback_balance = ?, updated_at = ? WHERE id = ?`),
+		entry.Balance, time.Now(), entry.CustomerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("customer")
+	}
+
+	query := `INSERT INTO cashback_ledger (id, customer_id, transaction_id, delta, reason, balance, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.ExecContext(ctx, r.rebind(query),
+		entry.ID, entry.CustomerID, entry.TransactionID, entry.Delta, entry.Reason, entry.Balance, entry.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *sqlRepository) ListCashbackEntries(ctx context.Context, customerID string) ([]*domain.CashbackEntry, error) {
+	query := `SELECT id, customer_id, transaction_id, delta, reason, balance, created_at
+		FROM cashback_ledger WHERE customer_id = ? ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.CashbackEntry{}
+	for rows.Next() {
+		entry := &domain.CashbackEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.CustomerID, &entry.TransactionID, &entry.Delta,
+			&entry.Reason, &entry.Balance, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Receipts are stored as JSON; only the transaction they belong to is
+// queried.
+func (r *sqlRepository) CreateReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO receipts (id, transaction_id, customer_id, data, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	_, err = r.db.ExecContext(ctx, r.rebind(query),
+		receipt.ID, receipt.TransactionID, receipt.CustomerID, string(data), receipt.CreatedAt.UTC(),
+	)
+	if err != nil && isUniqueViolation(err) {
+		return errors.NewAlreadyExistsError("receipt")
+	}
+
+	return err
+}
+
+func (r *sqlRepository) GetReceipt(ctx context.Context, id string) (*domain.Receipt, error) {
+	return r.queryReceipt(ctx, `SELECT data FROM receipts WHERE id = ?`, id)
+}
+
+func (r *sqlRepository) GetReceiptByTransaction(ctx context.Context, transactionID string) (*domain.Receipt, error) {
+	return r.queryReceipt(ctx, `SELECT data FROM receipts WHERE transaction_id = ?`, transactionID)
+}
+
+func (r *sqlRepository) queryReceipt(ctx context.Context, query string, arg string) (*domain.Receipt, error) {
+	var data string
+	err := r.db.QueryRowContext(ctx, r.rebind(query), arg).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("receipt")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &domain.Receipt{}
+	if err := json.Unmarshal([]byte(data), receipt); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+const paymentMethodColumns = `id, customer_id, method, token, details, created_at`
+
+func scanPaymentMethod(row rowScanner) (*domain.SavedPaymentMethod, error) {
+	var detailsJSON string
+	method := &domain.SavedPaymentMethod{}
+
+	err := row.Scan(&method.ID, &method.CustomerID, &method.Method, &method.Token, &detailsJSON, &method.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal([]byte(detailsJSON), &method.Details)
+	return method, nil
+}
+
+func (r *sqlRepository) CreatePaymentMethod(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	detailsJSON, _ := json.Marshal(method.Details)
+
+	query := `INSERT INTO payment_methods (` + paymentMethodColumns + `) VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, r.rebind(query),
+		method.ID, method.CustomerID, method.Method, method.Token, string(detailsJSON), method.CreatedAt.UTC(),
+	)
+	if err != nil && isUniqueViolation(err) {
+		return errors.NewAlreadyExistsError("payment method")
+	}
+
+	return err
+}
+
+func (r *sqlRepository) GetPaymentMethod(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	query := `SELECT ` + paymentMethodColumns + ` FROM payment_methods WHERE id = ?`
+
+	method, err := scanPaymentMethod(r.db.QueryRowContext(ctx, r.rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("payment method")
+	}
+
+	return method, err
+}
+
+func (r *sqlRepository) ListPaymentMethodsByCustomer(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error) {
+	query := `SELECT ` + paymentMethodColumns + ` FROM payment_methods WHERE customer_id = ? ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, r.rebind(query), customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	methods := []*domain.SavedPaymentMethod{}
+	for rows.Next() {
+		method, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(methods, method)
+	}
+
+	return methods, rows.Err()
+}
+
+func (r *sqlRepository) DeletePaymentMethod(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, r.rebind(`DELETE FROM payment_methods WHERE id = ?`), id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.NewNotFoundError("payment method")
+	}
+
+	return nil
+}
+
+func (r *sqlRepository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *sqlRepository) Close() error {
+	return r.db.Close()
+}
@@ -3,36 +3,43 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"time"
 
-	"github.com/ecommerce/payment-system/internal/domain"
-	"github.com/ecommerce/payment-system/pkg/errors"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 type SQLiteRepository struct {
-	db *sql.DB
+	*sqlRepository
 }
 
-func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
+func NewSQLiteRepository(dbPath string, opts ConnectOptions) (*SQLiteRepository, error) {
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
-	if err := db.Ping(); err != nil {
+	configurePool(db, opts)
+
+	if err := pingWithRetry(db, opts); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	repo := &SQLiteRepository{db: db}
+	repo := &SQLiteRepository{
+		sqlRepository: &sqlRepository{db: db, rebind: func(query string) string { return query }},
+	}
+
+	if opts.ReadOnly {
+		return repo, nil
+	}
 
 	if err := repo.initSchema(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
-	if err := repo.seedData(); err != nil {
+	if _, err := seedStore(context.Background(), repo, opts.SeedDataset); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to seed data: %w", err)
 	}
 
@@ -47,6 +54,9 @@ func (r *SQLiteRepository) initSchema() error {
 		name TEXT NOT NULL,
 		phone TEXT,
 		loyalty_points INTEGER DEFAULT 0,
+		cashback_balance REAL DEFAULT 0,
+		spending_limit REAL DEFAULT 0,
+		locale TEXT DEFAULT '',
 		address_street TEXT,
 		address_city TEXT,
 		address_state TEXT,
@@ -64,6 +74,7 @@ func (r *SQLiteRepository) initSchema() error {
 		sku TEXT UNIQUE NOT NULL,
 		stock INTEGER DEFAULT 0,
 		category TEXT,
+		tax_exempt BOOLEAN DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -74,6 +85,7 @@ func (r *SQLiteRepository) initSchema() error {
 		items TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		version INTEGER DEFAULT 0,
 		FOREIGN KEY (customer_id) REFERENCES customers(id)
 	);
 
@@ -83,455 +95,193 @@ func (r *SQLiteRepository) initSchema() error {
 		amount REAL NOT NULL,
 		status TEXT NOT NULL,
 		payment_method TEXT NOT NULL,
+		strategy TEXT DEFAULT '',
 		payment_details TEXT,
 		metadata TEXT,
 		error_message TEXT,
+		idempotency_key TEXT,
+		exchange_rate REAL DEFAULT 0,
+		base_amount REAL DEFAULT 0,
+		base_currency TEXT DEFAULT '',
+		display_amount REAL DEFAULT 0,
+		display_currency TEXT DEFAULT '',
 		processed_at DATETIME,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (customer_id) REFERENCES customers(id)
 	);
 
-	CREATE INDEX IF NOT EXISTS idx_customers_email ON customers(email);
-	CREATE INDEX IF NOT EXISTS idx_carts_customer ON carts(customer_id);
-	CREATE INDEX IF NOT EXISTS idx_transactions_customer ON transactions(customer_id);
-	`
-
-	_, err := r.db.Exec(schema)
-	return err
-}
-
-func (r *SQLiteRepository) seedData() error {
-	var count int
-	err := r.db.QueryRow("SELECT COUNT(*) FROM products").Scan(&count)
-	if err != nil {
-		return err
-	}
-
-	if count > 0 {
-		return nil
-	}
-
-	products := []*domain.Product{
-		{
-			ID:          "prod-1",
-			Name:        "Laptop",
-			Description: "High-performance laptop",
-			Price:       999.99,
-			SKU:         "LAP-001",
-			Stock:       10,
-			Category:    "Electronics",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          "prod-2",
-			Name:        "Wireless Mouse",
-			Description: "Ergonomic wireless mouse",
-			Price:       29.99,
-			SKU:         "MOU-001",
-			Stock:       50,
-			Category:    "Accessories",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          "prod-3",
-			Name:        "USB-C Cable",
-			Description: "High-speed USB-C cable",
-			Price:       19.99,
-			SKU:         "CAB-001",
-			Stock:       100,
-			Category:    "Accessories",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          "prod-4",
-			Name:        "Mechanical Keyboard",
-			Description: "RGB mechanical keyboard",
-			Price:       149.99,
-			SKU:         "KEY-001",
-			Stock:       25,
-			Category:    "Accessories",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-		{
-			ID:          "prod-5",
-			Name:        "Monitor",
-			Description: "27-inch 4K monitor",
-			Price:       399.99,
-			SKU:         "MON-001",
-			Stock:       15,
-			Category:    "Electronics",
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		},
-	}
-
-	for _, p := range products {
-		if err := r.CreateProduct(context.Background(), p); err != nil {
-			return err
-		}
-	}
-
-	defaultCustomer := &domain.Customer{
-		ID:            "cust-default",
-		Email:         "john.doe@example.com",
-		Name:          "John Doe",
-		Phone:         "+1234567890",
-		LoyaltyPoints: 500,
-		Address: domain.Address{
-			Street:     "123 Main St",
-			City:       "San Francisco",
-			State:      "CA",
-			PostalCode: "94105",
-			Country:    "USA",
-		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	if err := r.CreateCustomer(context.Background(), defaultCustomer); err != nil {
-		return err
-	}
+	CREATE TABLE IF NOT EXISTS orders (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		transaction_id TEXT,
+		cart_id TEXT,
+		status TEXT NOT NULL,
+		items TEXT,
+		subtotal REAL NOT NULL,
+		total REAL NOT NULL,
+		fulfillments TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (customer_id) REFERENCES customers(id)
+	);
 
-	fmt.Println("✓ Sample data seeded successfully")
-	fmt.Printf("✓ Default user created: %s\n", defaultCustomer.Email)
-	return nil
-}
+	CREATE TABLE IF NOT EXISTS discounts (
+		id TEXT PRIMARY KEY,
+		code TEXT UNIQUE NOT NULL,
+		description TEXT,
+		type TEXT NOT NULL,
+		value REAL NOT NULL,
+		min_amount REAL DEFAULT 0,
+		max_amount REAL DEFAULT 0,
+		expires_at DATETIME,
+		is_active BOOLEAN DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 
-func (r *SQLiteRepository) CreateCustomer(ctx context.Context, customer *domain.Customer) error {
-	query := `
-		INSERT INTO customers (id, email, name, phone, loyalty_points, 
-			address_street, address_city, address_state, address_postal_code, address_country,
-			created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
+	CREATE TABLE IF NOT EXISTS subscriptions (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT,
+		customer_id TEXT NOT NULL,
+		payment_method TEXT NOT NULL,
+		saved_method_id TEXT DEFAULT '',
+		amount REAL NOT NULL,
+		billing_interval TEXT NOT NULL,
+		status TEXT NOT NULL,
+		next_billing_date DATETIME NOT NULL,
+		charge_count INTEGER DEFAULT 0,
+		failed_attempts INTEGER DEFAULT 0,
+		last_transaction_id TEXT DEFAULT '',
+		last_charged_at DATETIME,
+		canceled_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (customer_id) REFERENCES customers(id)
+	);
 
-	_, err := r.db.ExecContext(ctx, query,
-		customer.ID, customer.Email, customer.Name, customer.Phone, customer.LoyaltyPoints,
-		customer.Address.Street, customer.Address.City, customer.Address.State,
-		customer.Address.PostalCode, customer.Address.Country,
-		customer.CreatedAt, customer.UpdatedAt,
-	)
+	CREATE TABLE IF NOT EXISTS payment_schedules (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT,
+		customer_id TEXT NOT NULL,
+		payment_method TEXT NOT NULL,
+		saved_method_id TEXT DEFAULT '',
+		total_amount REAL NOT NULL,
+		installments INTEGER NOT NULL,
+		interest_rate REAL DEFAULT 0,
+		payments TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (customer_id) REFERENCES customers(id)
+	);
 
-	return err
-}
+	CREATE TABLE IF NOT EXISTS inventory_reservations (
+		id TEXT PRIMARY KEY,
+		product_id TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		cart_id TEXT,
+		transaction_id TEXT,
+		expires_at DATETIME NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (product_id) REFERENCES products(id)
+	);
 
-func (r *SQLiteRepository) GetCustomer(ctx context.Context, id string) (*domain.Customer, error) {
-	query := `
-		SELECT id, email, name, phone, loyalty_points,
-			address_street, address_city, address_state, address_postal_code, address_country,
-			created_at, updated_at
-		FROM customers WHERE id = ?
-	`
+	CREATE TABLE IF NOT EXISTS loyalty_ledger (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		transaction_id TEXT,
+		delta INTEGER NOT NULL,
+		reason TEXT NOT NULL,
+		balance INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 
-	customer := &domain.Customer{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&customer.ID, &customer.Email, &customer.Name, &customer.Phone, &customer.LoyaltyPoints,
-		&customer.Address.Street, &customer.Address.City, &customer.Address.State,
-		&customer.Address.PostalCode, &customer.Address.Country,
-		&customer.CreatedAt, &customer.UpdatedAt,
-	)
+	CREATE TABLE IF NOT EXISTS cashback_ledger (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		transaction_id TEXT,
+		delta REAL NOT NULL,
+		reason TEXT NOT NULL,
+		balance REAL NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("customer")
-	}
+	CREATE TABLE IF NOT EXISTS receipts (
+		id TEXT PRIMARY KEY,
+		transaction_id TEXT NOT NULL UNIQUE,
+		customer_id TEXT NOT NULL,
+		data TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 
-	return customer, err
-}
+	CREATE TABLE IF NOT EXISTS payment_methods (
+		id TEXT PRIMARY KEY,
+		customer_id TEXT NOT NULL,
+		method TEXT NOT NULL,
+		token TEXT NOT NULL UNIQUE,
+		details TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 
-func (r *SQLiteRepository) GetCustomerByEmail(ctx context.Context, email string) (*domain.Customer, error) {
-	query := `
-		SELECT id, email, name, phone, loyalty_points,
-			address_street, address_city, address_state, address_postal_code, address_country,
-			created_at, updated_at
-		FROM customers WHERE email = ?
+	CREATE INDEX IF NOT EXISTS idx_customers_email ON customers(email);
+	CREATE INDEX IF NOT EXISTS idx_carts_customer ON carts(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_transactions_customer ON transactions(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_orders_customer ON orders(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_payment_schedules_customer ON payment_schedules(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_subscriptions_next_billing ON subscriptions(status, next_billing_date);
+	CREATE INDEX IF NOT EXISTS idx_inventory_reservations_product ON inventory_reservations(product_id);
+	CREATE INDEX IF NOT EXISTS idx_inventory_reservations_transaction ON inventory_reservations(transaction_id);
+	CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer ON loyalty_ledger(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_cashback_ledger_customer ON cashback_ledger(customer_id);
+	CREATE INDEX IF NOT EXISTS idx_payment_methods_customer ON payment_methods(customer_id);
 	`
 
-	customer := &domain.Customer{}
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&customer.ID, &customer.Email, &customer.Name, &customer.Phone, &customer.LoyaltyPoints,
-		&customer.Address.Street, &customer.Address.City, &customer.Address.State,
-		&customer.Address.PostalCode, &customer.Address.Country,
-		&customer.CreatedAt, &customer.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("customer")
+	if _, err := r.db.Exec(schema); err != nil {
+		return err
 	}
 
-	return customer, err
+	return r.migrate()
 }
 
-func (r *SQLiteRepository) UpdateCustomer(ctx context.Context, customer *domain.Customer) error {
-	query := `
-		UPDATE customers SET email = ?, name = ?, phone = ?, loyalty_points = ?,
-			address_street = ?, address_city = ?, address_state = ?, 
-			address_postal_code = ?, address_country = ?, updated_at = ?
-		WHERE id = ?
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		customer.Email, customer.Name, customer.Phone, customer.LoyaltyPoints,
-		customer.Address.Street, customer.Address.City, customer.Address.State,
-		customer.Address.PostalCode, customer.Address.Country,
-		time.Now(), customer.ID,
-	)
-
-	return err
-}
-
-func (r *SQLiteRepository) ListCustomers(ctx context.Context, limit, offset int) ([]*domain.Customer, error) {
-	query := `
-		SELECT id, email, name, phone, loyalty_points,
-			address_street, address_city, address_state, address_postal_code, address_country,
-			created_at, updated_at
-		FROM customers
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
+func (r *SQLiteRepository) migrate() error {
+	columns := []struct {
+		table      string
+		column     string
+		definition string
+	}{
+		{"transactions", "strategy", "TEXT DEFAULT ''"},
+		{"customers", "cashback_balance", "REAL DEFAULT 0"},
+		{"transactions", "idempotency_key", "TEXT"},
+		{"customers", "spending_limit", "REAL DEFAULT 0"},
+		{"transactions", "exchange_rate", "REAL DEFAULT 0"},
+		{"transactions", "base_amount", "REAL DEFAULT 0"},
+		{"transactions", "base_currency", "TEXT DEFAULT ''"},
+		{"transactions", "display_amount", "REAL DEFAULT 0"},
+		{"transactions", "display_currency", "TEXT DEFAULT ''"},
+		{"carts", "version", "INTEGER DEFAULT 0"},
+		{"customers", "locale", "TEXT DEFAULT ''"},
+		{"products", "tax_exempt", "BOOLEAN DEFAULT 0"},
+		{"payment_schedules", "saved_method_id", "TEXT DEFAULT ''"},
+		{"subscriptions", "saved_method_id", "TEXT DEFAULT ''"},
 	}
-	defer rows.Close()
 
-	customers := []*domain.Customer{}
-	for rows.Next() {
-		customer := &domain.Customer{}
-		err := rows.Scan(
-			&customer.ID, &customer.Email, &customer.Name, &customer.Phone, &customer.LoyaltyPoints,
-			&customer.Address.Street, &customer.Address.City, &customer.Address.State,
-			&customer.Address.PostalCode, &customer.Address.Country,
-			&customer.CreatedAt, &customer.UpdatedAt,
-		)
+	for _, c := range columns {
+		var count int
+		err := r.db.QueryRow(
+			`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column,
+		).Scan(&count)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		customers = append(customers, customer)
-	}
-
-	return customers, nil
-}
-
-func (r *SQLiteRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
-	query := `
-		INSERT INTO products (id, name, description, price, sku, stock, category, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		product.ID, product.Name, product.Description, product.Price,
-		product.SKU, product.Stock, product.Category,
-		product.CreatedAt, product.UpdatedAt,
-	)
-
-	return err
-}
-
-func (r *SQLiteRepository) GetProduct(ctx context.Context, id string) (*domain.Product, error) {
-	query := `SELECT id, name, description, price, sku, stock, category, created_at, updated_at FROM products WHERE id = ?`
-
-	product := &domain.Product{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&product.ID, &product.Name, &product.Description, &product.Price,
-		&product.SKU, &product.Stock, &product.Category,
-		&product.CreatedAt, &product.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("product")
-	}
-
-	return product, err
-}
-
-func (r *SQLiteRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
-	query := `
-		UPDATE products SET name = ?, description = ?, price = ?, stock = ?, category = ?, updated_at = ?
-		WHERE id = ?
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		product.Name, product.Description, product.Price, product.Stock,
-		product.Category, time.Now(), product.ID,
-	)
-
-	return err
-}
-
-func (r *SQLiteRepository) ListProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	query := `SELECT id, name, description, price, sku, stock, category, created_at, updated_at FROM products LIMIT ? OFFSET ?`
-
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	products := []*domain.Product{}
-	for rows.Next() {
-		product := &domain.Product{}
-		err := rows.Scan(
-			&product.ID, &product.Name, &product.Description, &product.Price,
-			&product.SKU, &product.Stock, &product.Category,
-			&product.CreatedAt, &product.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
+		if count > 0 {
+			continue
 		}
-		products = append(products, product)
-	}
-
-	return products, nil
-}
-
-func (r *SQLiteRepository) CreateCart(ctx context.Context, cart *domain.Cart) error {
-	itemsJSON, err := json.Marshal(cart.Items)
-	if err != nil {
-		return err
-	}
-
-	query := `INSERT INTO carts (id, customer_id, items, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
-
-	_, err = r.db.ExecContext(ctx, query, cart.ID, cart.CustomerID, string(itemsJSON), cart.CreatedAt, cart.UpdatedAt)
-	return err
-}
-
-func (r *SQLiteRepository) GetCart(ctx context.Context, id string) (*domain.Cart, error) {
-	query := `SELECT id, customer_id, items, created_at, updated_at FROM carts WHERE id = ?`
-
-	var itemsJSON string
-	cart := &domain.Cart{}
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&cart.ID, &cart.CustomerID, &itemsJSON, &cart.CreatedAt, &cart.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("cart")
-	}
-
-	if err := json.Unmarshal([]byte(itemsJSON), &cart.Items); err != nil {
-		return nil, err
-	}
 
-	return cart, err
-}
-
-func (r *SQLiteRepository) UpdateCart(ctx context.Context, cart *domain.Cart) error {
-	itemsJSON, err := json.Marshal(cart.Items)
-	if err != nil {
-		return err
-	}
-
-	query := `UPDATE carts SET items = ?, updated_at = ? WHERE id = ?`
-	_, err = r.db.ExecContext(ctx, query, string(itemsJSON), time.Now(), cart.ID)
-	return err
-}
-
-func (r *SQLiteRepository) GetCartByCustomer(ctx context.Context, customerID string) (*domain.Cart, error) {
-	query := `SELECT id, customer_id, items, created_at, updated_at FROM carts WHERE customer_id = ? ORDER BY updated_at DESC LIMIT 1`
-
-	var itemsJSON string
-	cart := &domain.Cart{}
-
-	err := r.db.QueryRowContext(ctx, query, customerID).Scan(
-		&cart.ID, &cart.CustomerID, &itemsJSON, &cart.CreatedAt, &cart.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("cart")
-	}
-
-	if err := json.Unmarshal([]byte(itemsJSON), &cart.Items); err != nil {
-		return nil, err
-	}
-
-	return cart, err
-}
-
-func (r *SQLiteRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
-	detailsJSON, _ := json.Marshal(transaction.PaymentDetails)
-	metadataJSON, _ := json.Marshal(transaction.Metadata)
-
-	query := `
-		INSERT INTO transactions (id, customer_id, amount, status, payment_method, payment_details, metadata, error_message, processed_at, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := r.db.ExecContext(ctx, query,
-		transaction.ID, transaction.CustomerID, transaction.Amount, transaction.Status,
-		transaction.PaymentMethod, string(detailsJSON), string(metadataJSON),
-		transaction.ErrorMessage, transaction.ProcessedAt, transaction.CreatedAt,
-	)
-
-	return err
-}
-
-func (r *SQLiteRepository) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
-	query := `SELECT id, customer_id, amount, status, payment_method, payment_details, metadata, error_message, processed_at, created_at FROM transactions WHERE id = ?`
-
-	var detailsJSON, metadataJSON string
-	transaction := &domain.Transaction{}
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&transaction.ID, &transaction.CustomerID, &transaction.Amount, &transaction.Status,
-		&transaction.PaymentMethod, &detailsJSON, &metadataJSON,
-		&transaction.ErrorMessage, &transaction.ProcessedAt, &transaction.CreatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFoundError("transaction")
-	}
-
-	json.Unmarshal([]byte(detailsJSON), &transaction.PaymentDetails)
-	json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
-
-	return transaction, err
-}
-
-func (r *SQLiteRepository) ListTransactionsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error) {
-	query := `
-		SELECT id, customer_id, amount, status, payment_method, payment_details, metadata, error_message, processed_at, created_at
-		FROM transactions
-		WHERE customer_id = ?
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, customerID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	transactions := []*domain.Transaction{}
-	for rows.Next() {
-		var detailsJSON, metadataJSON string
-		transaction := &domain.Transaction{}
-
-		err := rows.Scan(
-			&transaction.ID, &transaction.CustomerID, &transaction.Amount, &transaction.Status,
-			&transaction.PaymentMethod, &detailsJSON, &metadataJSON,
-			&transaction.ErrorMessage, &transaction.ProcessedAt, &transaction.CreatedAt,
-		)
-		if err != nil {
-			return nil, err
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)
+		if _, err := r.db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", c.table, c.column, err)
 		}
-
-		json.Unmarshal([]byte(detailsJSON), &transaction.PaymentDetails)
-		json.Unmarshal([]byte(metadataJSON), &transaction.Metadata)
-
-		transactions = append(transactions, transaction)
 	}
 
-	return transactions, nil
-}
-
-func (r *SQLiteRepository) Close() error {
-	return r.db.Close()
+	_, err := r.db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key
+		ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL`)
+	return err
 }
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const maxSubscriptionFailures = 3
+
+type BillingService struct {
+	repo       repository.Repository
+	paymentFor PaymentProvider
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+func NewBillingService(repo repository.Repository) *BillingService {
+	return &BillingService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *BillingService) SetPaymentProvider(provider PaymentProvider) {
+	s.paymentFor = provider
+}
+
+func (s *BillingService) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternalError, "failed to save subscription")
+	}
+
+	logger.Info("Subscription created",
+		zap.String("subscription_id", subscription.ID),
+		zap.String("customer_id", subscription.CustomerID),
+		zap.String("interval", string(subscription.Interval)),
+	)
+
+	return nil
+}
+
+func (s *BillingService) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	return s.repo.GetSubscription(ctx, id)
+}
+
+func (s *BillingService) ListSubscriptions(ctx context.Context, limit, offset int) ([]*domain.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx, limit, offset)
+}
+
+func (s *BillingService) CancelSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription.Status == domain.SubscriptionStatusCanceled {
+		return nil, errors.NewValidationError("subscription is already canceled")
+	}
+
+	now := s.now()
+	subscription.Status = domain.SubscriptionStatusCanceled
+	subscription.CanceledAt = &now
+	subscription.UpdatedAt = now
+
+	if err := s.repo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to cancel subscription")
+	}
+
+	logger.Info("Subscription canceled", zap.String("subscription_id", id))
+	return subscription, nil
+}
+
+type BillingOutcome struct {
+	SubscriptionID  string                    `json:"subscription_id"`
+	CustomerID      string                    `json:"customer_id"`
+	Amount          float64                   `json:"amount"`
+	TransactionID   string                    `json:"transaction_id,omitempty"`
+	Error           string                    `json:"error,omitempty"`
+	Status          domain.SubscriptionStatus `json:"status"`
+	NextBillingDate time.Time                 `json:"next_billing_date"`
+}
+
+// RunDueCharges retries past-due subscriptions on the next run and cancels
+// them after maxSubscriptionFailures failures in a row.
+func (s *BillingService) RunDueCharges(ctx context.Context) ([]BillingOutcome, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.paymentFor == nil {
+		return nil, errors.New(errors.ErrCodeInternalError, "no payment provider configured for subscriptions")
+	}
+
+	due, err := s.repo.ListDueSubscriptions(ctx, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]BillingOutcome, 0, len(due))
+	for _, subscription := range due {
+		if err := ctx.Err(); err != nil {
+			return outcomes, err
+		}
+		outcomes = append(outcomes, s.charge(ctx, subscription))
+	}
+
+	logger.Info("Billing run finished",
+		zap.Int("due", len(due)),
+	)
+
+	return outcomes, nil
+}
+
+func (s *BillingService) charge(ctx context.Context, subscription *domain.Subscription) BillingOutcome {
+	outcome := BillingOutcome{
+		SubscriptionID: subscription.ID,
+		CustomerID:     subscription.CustomerID,
+		Amount:         subscription.Amount,
+	}
+
+	logger.Info("Charging subscription",
+		zap.String("subscription_id", subscription.ID),
+		zap.Float64("amount", subscription.Amount),
+	)
+
+	transactionID, err := s.process(ctx, subscription)
+	now := s.now()
+	if err != nil {
+		subscription.FailedAttempts++
+		subscription.Status = domain.SubscriptionStatusPastDue
+		if subscription.FailedAttempts >= maxSubscriptionFailures {
+			subscription.Status = domain.SubscriptionStatusCanceled
+			subscription.CanceledAt = &now
+		}
+		subscription.UpdatedAt = now
+		outcome.Error = err.Error()
+
+		logger.Warn("Subscription charge failed",
+			zap.String("subscription_id", subscription.ID),
+			zap.Int("failed_attempts", subscription.FailedAttempts),
+			zap.Error(err),
+		)
+	} else {
+		subscription.MarkCharged(transactionID, now)
+		outcome.TransactionID = transactionID
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, subscription); err != nil {
+		logger.Error("Failed to save subscription",
+			zap.Error(err),
+			zap.String("subscription_id", subscription.ID),
+		)
+		if outcome.Error == "" {
+			outcome.Error = "charged but subscription could not be saved: " + err.Error()
+		}
+	}
+
+	outcome.Status = subscription.Status
+	outcome.NextBillingDate = subscription.NextBillingDate
+	return outcome
+}
+
+func (s *BillingService) process(ctx context.Context, subscription *domain.Subscription) (string, error) {
+	paymentInstance, err := s.paymentFor(ctx, subscription.CustomerID, subscription.PaymentMethod, subscription.SavedMethodID)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := payment.ProcessAmount(ctx, paymentInstance, subscription.Amount)
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodePaymentFailed, "subscription payment failed")
+	}
+
+	now := s.now()
+	transaction := &domain.Transaction{
+		ID:             domain.NewID(),
+		CustomerID:     subscription.CustomerID,
+		Amount:         subscription.Amount,
+		Status:         domain.TransactionStatusCompleted,
+		PaymentMethod:  subscription.PaymentMethod,
+		Strategy:       "recurring",
+		PaymentDetails: result.Metadata,
+		Metadata: map[string]interface{}{
+			"subscription_id": subscription.ID,
+			"billing_period":  subscription.ChargeCount + 1,
+			"charged_amount":  result.Amount,
+		},
+		ProcessedAt: now,
+		CreatedAt:   now,
+	}
+
+	if err := s.repo.CreateTransaction(ctx, transaction); err != nil {
+		logger.Error("Failed to save subscription transaction",
+			zap.Error(err),
+			zap.String("subscription_id", subscription.ID),
+		)
+	}
+
+	return transaction.ID, nil
+}
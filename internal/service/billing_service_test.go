@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillingServiceRunDueCharges(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, balance float64) (*BillingService, repository.Repository) {
+		repo := repository.NewMemoryRepository()
+		billing := NewBillingService(repo)
+		billing.SetPaymentProvider(func(ctx context.Context, customerID, method, savedMethodID string) (payment.Payment, error) {
+			return payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", balance)
+		})
+
+		for _, subscription := range []*domain.Subscription{
+			{ID: "sub-due", NextBillingDate: start},
+			{ID: "sub-later", NextBillingDate: start.AddDate(0, 0, 10)},
+		} {
+			subscription.CustomerID = "cust-default"
+			subscription.PaymentMethod = "gift_card"
+			subscription.Amount = 20
+			subscription.Interval = domain.BillingIntervalMonthly
+			subscription.Status = domain.SubscriptionStatusActive
+			subscription.ChargeCount = 1
+			require.NoError(t, billing.CreateSubscription(ctx, subscription))
+		}
+
+		billing.now = func() time.Time { return start.Add(time.Hour) }
+		return billing, repo
+	}
+
+	t.Run("Charges Due Subscriptions", func(t *testing.T) {
+		billing, repo := setup(t, 500)
+
+		outcomes, err := billing.RunDueCharges(ctx)
+		require.NoError(t, err)
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, "sub-due", outcomes[0].SubscriptionID)
+		assert.Empty(t, outcomes[0].Error)
+
+		stored, err := billing.GetSubscription(ctx, "sub-due")
+		require.NoError(t, err)
+		assert.Equal(t, 2, stored.ChargeCount)
+		assert.Equal(t, start.AddDate(0, 1, 0), stored.NextBillingDate)
+
+		transaction, err := repo.GetTransaction(ctx, outcomes[0].TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, "recurring", transaction.Strategy)
+		assert.Equal(t, "sub-due", transaction.Metadata["subscription_id"])
+
+		outcomes, err = billing.RunDueCharges(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, outcomes)
+	})
+
+	t.Run("Cancels After Repeated Failures", func(t *testing.T) {
+		billing, _ := setup(t, 5)
+
+		for attempt := 1; attempt <= maxSubscriptionFailures; attempt++ {
+			outcomes, err := billing.RunDueCharges(ctx)
+			require.NoError(t, err)
+			require.Len(t, outcomes, 1)
+			assert.NotEmpty(t, outcomes[0].Error)
+		}
+
+		stored, err := billing.GetSubscription(ctx, "sub-due")
+		require.NoError(t, err)
+		assert.Equal(t, domain.SubscriptionStatusCanceled, stored.Status)
+		assert.Equal(t, maxSubscriptionFailures, stored.FailedAttempts)
+		assert.Equal(t, start, stored.NextBillingDate)
+	})
+}
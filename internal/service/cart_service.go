@@ -2,20 +2,41 @@ package service
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
 	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
 	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
 	"go.uber.org/zap"
 )
 
 type CartService struct {
-	repo repository.Repository
+	repo            repository.Repository
+	eventSubject    *observer.Subject
+	restrictions    *RestrictionPolicy
+	allowBackorders bool
 }
 
-func NewCartService(repo repository.Repository) *CartService {
-	return &CartService{repo: repo}
+func NewCartService(
+	repo repository.Repository,
+	eventSubject *observer.Subject,
+	restrictions *RestrictionPolicy,
+) *CartService {
+	return &CartService{
+		repo:         repo,
+		eventSubject: eventSubject,
+		restrictions: restrictions,
+	}
+}
+
+// SetAllowBackorders turns off the stock check made when items are added
+// or their quantity raised.
+func (s *CartService) SetAllowBackorders(allow bool) {
+	s.allowBackorders = allow
 }
 
 func (s *CartService) CreateCart(ctx context.Context, customerID string) (*domain.Cart, error) {
@@ -36,9 +57,20 @@ func (s *CartService) CreateCart(ctx context.Context, customerID string) (*domai
 		zap.String("customer_id", customerID),
 	)
 
+	s.notifyEvent(ctx, observer.Event{
+		Type:       observer.EventCartCreated,
+		CustomerID: customerID,
+		CartID:     cart.ID,
+		Metadata:   map[string]interface{}{},
+	})
+
 	return cart, nil
 }
 
+func (s *CartService) GetCart(ctx context.Context, id string) (*domain.Cart, error) {
+	return s.repo.GetCart(ctx, id)
+}
+
 func (s *CartService) GetOrCreateCart(ctx context.Context, customerID string) (*domain.Cart, error) {
 	cart, err := s.repo.GetCartByCustomer(ctx, customerID)
 	if err == nil {
@@ -49,15 +81,29 @@ func (s *CartService) GetOrCreateCart(ctx context.Context, customerID string) (*
 }
 
 func (s *CartService) AddItem(ctx context.Context, cartID string, product *domain.Product, quantity int) error {
-	cart, err := s.repo.GetCart(ctx, cartID)
-	if err != nil {
-		return err
-	}
+	cart, err := s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		customer, err := s.repo.GetCustomer(ctx, cart.CustomerID)
+		if err != nil {
+			return err
+		}
 
-	cart.AddItem(*product, quantity)
-	cart.UpdatedAt = time.Now()
+		if err := s.restrictions.CheckProduct(customer, product); err != nil {
+			logger.Warn("Blocked restricted product",
+				zap.String("cart_id", cartID),
+				zap.String("customer_id", customer.ID),
+				zap.String("product_id", product.ID),
+			)
+			return err
+		}
 
-	if err := s.repo.UpdateCart(ctx, cart); err != nil {
+		if err := s.checkStock(ctx, product, cartQuantity(cart, product.ID), quantity); err != nil {
+			return err
+		}
+
+		cart.AddItem(*product, quantity)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -67,19 +113,135 @@ func (s *CartService) AddItem(ctx context.Context, cartID string, product *domai
 		zap.Int("quantity", quantity),
 	)
 
+	s.notifyEvent(ctx, observer.Event{
+		Type:       observer.EventItemAdded,
+		CustomerID: cart.CustomerID,
+		CartID:     cart.ID,
+		Amount:     cart.GetTotal(),
+		Metadata: map[string]interface{}{
+			"product_id": product.ID,
+			"sku":        product.SKU,
+			"quantity":   quantity,
+			"unit_price": product.Price,
+		},
+	})
+
 	return nil
 }
 
-func (s *CartService) RemoveItem(ctx context.Context, cartID, productID string) error {
+// RejectedItem is a line of a batch add that was not added to the cart.
+type RejectedItem struct {
+	Line      int    `json:"line"`
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"`
+}
+
+// AddItems checks every item and adds the valid ones with a single cart
+// update. Items only need ProductID and Quantity; products are looked up
+// here. Invalid lines are returned rather than failing the batch unless
+// strict is set, in which case any invalid line leaves the cart unchanged.
+func (s *CartService) AddItems(ctx context.Context, cartID string, items []domain.CartItem, strict bool) ([]RejectedItem, error) {
 	cart, err := s.repo.GetCart(ctx, cartID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	customer, err := s.repo.GetCustomer(ctx, cart.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rejected := []RejectedItem{}
+	var accepted []domain.CartItem
+	// Lines for the same product draw on the same stock.
+	inCart := make(map[string]int)
+	for _, item := range cart.Items {
+		inCart[item.ProductID] = item.Quantity
+	}
+	for i, item := range items {
+		reject := func(reason string) {
+			rejected = append(rejected, RejectedItem{Line: i + 1, ProductID: item.ProductID, Quantity: item.Quantity, Reason: reason})
+		}
+
+		if item.Quantity <= 0 {
+			reject(fmt.Sprintf("invalid quantity %d", item.Quantity))
+			continue
+		}
+
+		product, err := s.repo.GetProduct(ctx, item.ProductID)
+		if errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+			reject("unknown product")
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.restrictions.CheckProduct(customer, product); err != nil {
+			reject(err.Error())
+			continue
+		}
+
+		if err := s.checkStock(ctx, product, inCart[product.ID], item.Quantity); err != nil {
+			reject(err.Error())
+			continue
+		}
+		inCart[product.ID] += item.Quantity
+
+		item.Product = *product
+		accepted = append(accepted, item)
+	}
+
+	if strict && len(rejected) > 0 {
+		return rejected, errors.NewValidationError(
+			fmt.Sprintf("%d of %d items are invalid; nothing was added", len(rejected), len(items)),
+		).WithDetails("rejected", rejected)
+	}
+	if len(accepted) == 0 {
+		return rejected, nil
 	}
 
-	cart.RemoveItem(productID)
-	cart.UpdatedAt = time.Now()
+	cart, err = s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		for _, item := range accepted {
+			cart.AddItem(item.Product, item.Quantity)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.UpdateCart(ctx, cart); err != nil {
+	logger.Info("Items added to cart",
+		zap.String("cart_id", cartID),
+		zap.Int("added", len(accepted)),
+		zap.Int("rejected", len(rejected)),
+	)
+
+	for _, item := range accepted {
+		s.notifyEvent(ctx, observer.Event{
+			Type:       observer.EventItemAdded,
+			CustomerID: cart.CustomerID,
+			CartID:     cart.ID,
+			Amount:     cart.GetTotal(),
+			Metadata: map[string]interface{}{
+				"product_id": item.Product.ID,
+				"sku":        item.Product.SKU,
+				"quantity":   item.Quantity,
+				"unit_price": item.Product.Price,
+			},
+		})
+	}
+
+	return rejected, nil
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, cartID, productID string) error {
+	_, err := s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		cart.RemoveItem(productID)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -92,15 +254,21 @@ func (s *CartService) RemoveItem(ctx context.Context, cartID, productID string)
 }
 
 func (s *CartService) UpdateQuantity(ctx context.Context, cartID, productID string, quantity int) error {
-	cart, err := s.repo.GetCart(ctx, cartID)
-	if err != nil {
-		return err
-	}
-
-	cart.UpdateQuantity(productID, quantity)
-	cart.UpdatedAt = time.Now()
+	_, err := s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		if quantity > cartQuantity(cart, productID) {
+			product, err := s.repo.GetProduct(ctx, productID)
+			if err != nil {
+				return err
+			}
+			if err := s.checkStock(ctx, product, 0, quantity); err != nil {
+				return err
+			}
+		}
 
-	if err := s.repo.UpdateCart(ctx, cart); err != nil {
+		cart.UpdateQuantity(productID, quantity)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -113,16 +281,48 @@ func (s *CartService) UpdateQuantity(ctx context.Context, cartID, productID stri
 	return nil
 }
 
-func (s *CartService) ClearCart(ctx context.Context, cartID string) error {
-	cart, err := s.repo.GetCart(ctx, cartID)
+// Reprice refreshes the cart's snapshot prices from the catalog and saves the
+// cart if any changed.
+func (s *CartService) Reprice(ctx context.Context, cartID string) ([]domain.PriceChange, error) {
+	var changes []domain.PriceChange
+	_, err := s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		products := make(map[string]*domain.Product, len(cart.Items))
+		for _, item := range cart.Items {
+			product, err := s.repo.GetProduct(ctx, item.ProductID)
+			if err != nil {
+				return err
+			}
+			products[item.ProductID] = product
+		}
+
+		changes = cart.Reprice(products)
+		if len(changes) == 0 {
+			return errCartUnchanged
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return changes, nil
 	}
 
-	cart.Clear()
-	cart.UpdatedAt = time.Now()
+	logger.Info("Cart repriced",
+		zap.String("cart_id", cartID),
+		zap.Int("changed_items", len(changes)),
+		zap.Bool("prices_increased", domain.PricesIncreased(changes)),
+	)
+
+	return changes, nil
+}
 
-	if err := s.repo.UpdateCart(ctx, cart); err != nil {
+func (s *CartService) ClearCart(ctx context.Context, cartID string) error {
+	_, err := s.updateCart(ctx, cartID, func(cart *domain.Cart) error {
+		cart.Clear()
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
@@ -132,3 +332,163 @@ func (s *CartService) ClearCart(ctx context.Context, cartID string) error {
 
 	return nil
 }
+
+// purgePageSize is how many carts PurgeAbandonedCarts reads at a time.
+const purgePageSize = 500
+
+// PurgeAbandonedCarts deletes carts not updated within ttl. Carts are listed
+// oldest first, so the scan stops at the first one still in use; carts that
+// fail to delete stay at the front and are skipped by the offset.
+func (s *CartService) PurgeAbandonedCarts(ctx context.Context, ttl time.Duration) (int, error) {
+	cutoff := time.Now().Add(-ttl)
+	purged := 0
+	offset := 0
+
+	for {
+		carts, err := s.repo.ListCarts(ctx, purgePageSize, offset)
+		if err != nil {
+			return purged, err
+		}
+
+		done := len(carts) < purgePageSize
+		for _, cart := range carts {
+			if cart.UpdatedAt.After(cutoff) {
+				done = true
+				break
+			}
+
+			if err := s.repo.DeleteCart(ctx, cart.ID); err != nil {
+				logger.Warn("Failed to purge abandoned cart",
+					zap.String("cart_id", cart.ID),
+					zap.Error(err),
+				)
+				offset++
+				continue
+			}
+			purged++
+			s.notifyAbandoned(ctx, cart)
+		}
+
+		if done {
+			break
+		}
+	}
+
+	logger.Info("Abandoned carts purged",
+		zap.Int("purged", purged),
+		zap.Duration("ttl", ttl),
+	)
+
+	return purged, nil
+}
+
+func (s *CartService) notifyAbandoned(ctx context.Context, cart *domain.Cart) {
+
+	if len(cart.Items) == 0 {
+		return
+	}
+
+	s.notifyEvent(ctx, observer.Event{
+		Type:       observer.EventCartAbandoned,
+		CustomerID: cart.CustomerID,
+		CartID:     cart.ID,
+		Amount:     cart.GetTotal(),
+		Metadata: map[string]interface{}{
+			"item_count":   cart.GetItemCount(),
+			"last_updated": cart.UpdatedAt.Format(time.RFC3339),
+		},
+	})
+}
+
+// maxCartUpdateAttempts bounds how often a cart update is retried after
+// losing a race with another writer.
+const maxCartUpdateAttempts = 5
+
+// errCartUnchanged lets a mutation passed to updateCart skip the save.
+var errCartUnchanged = stderrors.New("cart unchanged")
+
+// updateCart runs a read-modify-write of the cart, re-reading and applying
+// mutate again when the save fails because the cart was updated in between.
+// mutate must therefore be safe to run more than once.
+func (s *CartService) updateCart(ctx context.Context, cartID string, mutate func(*domain.Cart) error) (*domain.Cart, error) {
+	var err error
+	for attempt := 1; attempt <= maxCartUpdateAttempts; attempt++ {
+		var cart *domain.Cart
+		cart, err = s.repo.GetCart(ctx, cartID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := mutate(cart); err != nil {
+			if err == errCartUnchanged {
+				return cart, nil
+			}
+			return nil, err
+		}
+		cart.UpdatedAt = time.Now()
+
+		err = s.repo.UpdateCart(ctx, cart)
+		if err == nil {
+			return cart, nil
+		}
+		if !errors.IsErrorCode(err, errors.ErrCodeConflict) {
+			return nil, err
+		}
+
+		logger.Debug("Cart update conflict, retrying",
+			zap.String("cart_id", cartID),
+			zap.Int("attempt", attempt),
+		)
+	}
+
+	return nil, err
+}
+
+// checkStock fails with an inventory error when quantity more of product
+// does not fit in its available stock next to the inCart already held by the
+// cart. Available stock is what is not reserved by checkouts in progress.
+func (s *CartService) checkStock(ctx context.Context, product *domain.Product, inCart, quantity int) error {
+	if s.allowBackorders {
+		return nil
+	}
+
+	reserved, err := s.repo.ReservedQuantity(ctx, product.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	available := product.Stock - reserved - inCart
+	if available < 0 {
+		available = 0
+	}
+	if quantity <= available {
+		return nil
+	}
+
+	message := fmt.Sprintf("only %d of %s left", available, product.Name)
+	if inCart > 0 {
+		message = fmt.Sprintf("only %d more of %s left (%d already in cart)", available, product.Name, inCart)
+	}
+	return errors.NewInventoryError(message).
+		WithDetails("product_id", product.ID).
+		WithDetails("requested", quantity).
+		WithDetails("available", available)
+}
+
+func cartQuantity(cart *domain.Cart, productID string) int {
+	for _, item := range cart.Items {
+		if item.ProductID == productID {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+func (s *CartService) notifyEvent(ctx context.Context, event observer.Event) {
+	if s.eventSubject == nil {
+		return
+	}
+
+	event.Timestamp = time.Now().Format(time.RFC3339)
+	s.eventSubject.Notify(ctx, event)
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCartServiceRestrictions(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+
+	restrictions := NewRestrictionPolicy([]ProductRestriction{
+		{Category: "Alcohol", Regions: []string{"UT"}},
+	})
+	cartService := NewCartService(repo, nil, restrictions)
+
+	wine := &domain.Product{ID: "prod-wine", Name: "Red Wine", Category: "Alcohol", Price: 20.00, Stock: 5}
+	require.NoError(t, repo.CreateProduct(ctx, wine))
+
+	newCustomer := func(id, state string) *domain.Customer {
+		customer := &domain.Customer{
+			ID:      id,
+			Email:   id + "@example.com",
+			Name:    id,
+			Address: domain.Address{State: state, Country: "US"},
+		}
+		require.NoError(t, repo.CreateCustomer(ctx, customer))
+		return customer
+	}
+
+	t.Run("Restricted Region Cannot Add Product", func(t *testing.T) {
+		customer := newCustomer("cust-utah", "UT")
+		cart, err := cartService.CreateCart(ctx, customer.ID)
+		require.NoError(t, err)
+
+		err = cartService.AddItem(ctx, cart.ID, wine, 1)
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+
+		cart, err = repo.GetCart(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+	})
+
+	t.Run("Other Regions Can Add Product", func(t *testing.T) {
+		customer := newCustomer("cust-texas", "TX")
+		cart, err := cartService.CreateCart(ctx, customer.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, cartService.AddItem(ctx, cart.ID, wine, 1))
+	})
+}
+
+func TestCartServiceReprice(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	cartService := NewCartService(repo, nil, NewRestrictionPolicy(nil))
+
+	mouse := &domain.Product{ID: "prod-mouse", Name: "Mouse", Price: 20.00, Stock: 10}
+	cable := &domain.Product{ID: "prod-cable", Name: "Cable", Price: 5.00, Stock: 10}
+	require.NoError(t, repo.CreateProduct(ctx, mouse))
+	require.NoError(t, repo.CreateProduct(ctx, cable))
+	require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-reprice", Email: "reprice@example.com"}))
+
+	cart, err := cartService.CreateCart(ctx, "cust-reprice")
+	require.NoError(t, err)
+	require.NoError(t, cartService.AddItem(ctx, cart.ID, mouse, 2))
+	require.NoError(t, cartService.AddItem(ctx, cart.ID, cable, 1))
+
+	t.Run("Unchanged Prices", func(t *testing.T) {
+		changes, err := cartService.Reprice(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Reports And Saves Changed Prices", func(t *testing.T) {
+		raised := *mouse
+		raised.Price = 22.50
+		require.NoError(t, repo.UpdateProduct(ctx, &raised))
+
+		changes, err := cartService.Reprice(ctx, cart.ID)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, "prod-mouse", changes[0].ProductID)
+		assert.Equal(t, 20.00, changes[0].OldPrice)
+		assert.Equal(t, 22.50, changes[0].NewPrice)
+		assert.Equal(t, 2.50, changes[0].Delta)
+		assert.Equal(t, 5.00, changes[0].LineDelta)
+		assert.True(t, domain.PricesIncreased(changes))
+
+		stored, err := repo.GetCart(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 50.00, stored.GetTotal())
+	})
+}
+
+func TestCartServiceAddItems(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	cartService := NewCartService(repo, nil, nil)
+
+	items := []domain.CartItem{
+		{ProductID: "prod-1", Quantity: 1},
+		{ProductID: "prod-missing", Quantity: 2},
+		{ProductID: "prod-2", Quantity: 0},
+		{ProductID: "prod-2", Quantity: 3},
+		{ProductID: "prod-1", Quantity: 1},
+	}
+
+	t.Run("Adds Valid Lines And Reports The Rest", func(t *testing.T) {
+		cart, err := cartService.CreateCart(ctx, "cust-default")
+		require.NoError(t, err)
+
+		rejected, err := cartService.AddItems(ctx, cart.ID, items, false)
+		require.NoError(t, err)
+		require.Len(t, rejected, 2)
+		assert.Equal(t, 2, rejected[0].Line)
+		assert.Equal(t, "unknown product", rejected[0].Reason)
+		assert.Equal(t, 3, rejected[1].Line)
+
+		cart, err = repo.GetCart(ctx, cart.ID)
+		require.NoError(t, err)
+		require.Len(t, cart.Items, 2)
+		assert.Equal(t, 2, cart.Items[0].Quantity)
+		assert.Equal(t, 3, cart.Items[1].Quantity)
+	})
+
+	t.Run("Strict Leaves The Cart Unchanged", func(t *testing.T) {
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-strict", Email: "strict@example.com", Name: "Strict"}))
+		cart, err := cartService.CreateCart(ctx, "cust-strict")
+		require.NoError(t, err)
+
+		rejected, err := cartService.AddItems(ctx, cart.ID, items, true)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.Len(t, rejected, 2)
+
+		cart, err = repo.GetCart(ctx, cart.ID)
+		require.NoError(t, err)
+		assert.Empty(t, cart.Items)
+	})
+}
+
+func TestCartServiceConcurrentAddItem(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	cartService := NewCartService(repo, nil, nil)
+
+	cart, err := cartService.CreateCart(ctx, "cust-default")
+	require.NoError(t, err)
+	product, err := repo.GetProduct(ctx, "prod-2")
+	require.NoError(t, err)
+
+	// Each writer can only lose to the others' saves, so as many writers as
+	// attempts always succeed.
+	var wg sync.WaitGroup
+	errs := make(chan error, maxCartUpdateAttempts)
+	for i := 0; i < maxCartUpdateAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cartService.AddItem(ctx, cart.ID, product, 1)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	cart, err = repo.GetCart(ctx, cart.ID)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, maxCartUpdateAttempts, cart.Items[0].Quantity)
+	assert.Equal(t, maxCartUpdateAttempts, cart.Version)
+}
+
+func TestCartServiceStockCheck(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	cartService := NewCartService(repo, nil, nil)
+
+	lamp := &domain.Product{ID: "prod-lamp", Name: "Lamp", Price: 30.00, Stock: 5}
+	require.NoError(t, repo.CreateProduct(ctx, lamp))
+
+	t.Run("Counts What Is Already In The Cart", func(t *testing.T) {
+		cart, err := cartService.CreateCart(ctx, "cust-default")
+		require.NoError(t, err)
+		require.NoError(t, cartService.AddItem(ctx, cart.ID, lamp, 2))
+
+		err = cartService.AddItem(ctx, cart.ID, lamp, 4)
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInventoryError))
+		assert.Contains(t, err.Error(), "only 3 more of Lamp left")
+
+		require.NoError(t, cartService.AddItem(ctx, cart.ID, lamp, 3))
+
+		err = cartService.UpdateQuantity(ctx, cart.ID, lamp.ID, 6)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInventoryError))
+		require.NoError(t, cartService.UpdateQuantity(ctx, cart.ID, lamp.ID, 4))
+	})
+
+	t.Run("Backorders Skip The Check", func(t *testing.T) {
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-backorder", Email: "backorder@example.com"}))
+		cart, err := cartService.CreateCart(ctx, "cust-backorder")
+		require.NoError(t, err)
+
+		cartService.SetAllowBackorders(true)
+		defer cartService.SetAllowBackorders(false)
+		require.NoError(t, cartService.AddItem(ctx, cart.ID, lamp, 8))
+	})
+}
+
+func TestCartServiceEvents(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*CartService, repository.Repository, *recordingObserver) {
+		repo := repository.NewMemoryRepositoryWithSeed(repository.SeedNone)
+		recorder := &recordingObserver{}
+		subject := observer.NewSubject()
+		subject.AttachFiltered(recorder, observer.CartEvents...)
+		return NewCartService(repo, subject, nil), repo, recorder
+	}
+
+	eventTypes := func(recorder *recordingObserver) []observer.EventType {
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		types := []observer.EventType{}
+		for _, event := range recorder.events {
+			types = append(types, event.Type)
+		}
+		return types
+	}
+
+	t.Run("Create And Add Item", func(t *testing.T) {
+		cartService, repo, recorder := setup(t)
+		mug := &domain.Product{ID: "prod-mug", SKU: "MUG-001", Price: 8.00, Stock: 10}
+		require.NoError(t, repo.CreateProduct(ctx, mug))
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-events", Email: "events@example.com"}))
+
+		cart, err := cartService.CreateCart(ctx, "cust-events")
+		require.NoError(t, err)
+		require.NoError(t, cartService.AddItem(ctx, cart.ID, mug, 2))
+
+		assert.Equal(t, []observer.EventType{observer.EventCartCreated, observer.EventItemAdded}, eventTypes(recorder))
+		added := recorder.events[1]
+		assert.Equal(t, cart.ID, added.CartID)
+		assert.Equal(t, 16.00, added.Amount)
+		assert.Equal(t, "MUG-001", added.Metadata["sku"])
+	})
+
+	t.Run("Purge Covers Every Page", func(t *testing.T) {
+		cartService, repo, recorder := setup(t)
+		stale := time.Now().Add(-48 * time.Hour)
+
+		total := purgePageSize + 20
+		for i := 0; i < total; i++ {
+			cart := &domain.Cart{
+				ID:         fmt.Sprintf("cart-stale-%04d", i),
+				CustomerID: fmt.Sprintf("cust-stale-%04d", i),
+				Items:      []domain.CartItem{},
+				UpdatedAt:  stale,
+			}
+			if i%2 == 0 {
+				cart.Items = append(cart.Items, domain.CartItem{ProductID: "prod-mug", Quantity: 1, Price: 8.00})
+			}
+			require.NoError(t, repo.CreateCart(ctx, cart))
+		}
+		require.NoError(t, repo.CreateCart(ctx, &domain.Cart{ID: "cart-fresh", CustomerID: "cust-fresh", UpdatedAt: time.Now()}))
+
+		purged, err := cartService.PurgeAbandonedCarts(ctx, 24*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, total, purged)
+
+		remaining, err := repo.ListCarts(ctx, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, "cart-fresh", remaining[0].ID)
+
+		// Only carts with items are reported as abandoned.
+		assert.Equal(t, total/2, recorder.count())
+		for _, eventType := range eventTypes(recorder) {
+			assert.Equal(t, observer.EventCartAbandoned, eventType)
+		}
+	})
+}
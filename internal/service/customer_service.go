@@ -2,43 +2,548 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/i18n"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
+	"github.com/ecommerce/payment-system/pkg/validator"
 	"go.uber.org/zap"
 )
 
 type CustomerService struct {
-	repo repository.Repository
+	repo         repository.Repository
+	eventSubject *observer.Subject
+	cashbackMu   sync.Mutex
+	loyaltyMu    sync.Mutex
 }
 
-func NewCustomerService(repo repository.Repository) *CustomerService {
-	return &CustomerService{repo: repo}
+func NewCustomerService(repo repository.Repository, eventSubject *observer.Subject) *CustomerService {
+	return &CustomerService{repo: repo, eventSubject: eventSubject}
+}
+
+func (s *CustomerService) ValidateRegistration(ctx context.Context, customer *domain.Customer) error {
+	customer.Email = strings.TrimSpace(customer.Email)
+	customer.Name = strings.TrimSpace(customer.Name)
+
+	if customer.Email == "" || customer.Name == "" {
+		return errors.NewValidationError("email and name are required")
+	}
+
+	if err := validator.NewEmailValidator().Validate(customer.Email); err != nil {
+		return errors.Wrap(err, errors.ErrCodeValidation, fmt.Sprintf("invalid email %q", customer.Email))
+	}
+
+	if customer.Phone != "" {
+		if err := validator.NewPhoneValidator().Validate(customer.Phone); err != nil {
+			return errors.Wrap(err, errors.ErrCodeValidation, fmt.Sprintf("invalid phone %q", customer.Phone))
+		}
+	}
+
+	if customer.Locale != "" {
+		locale, err := validateLocale(customer.Locale)
+		if err != nil {
+			return err
+		}
+		customer.Locale = locale
+	}
+
+	if _, err := s.repo.GetCustomerByEmail(ctx, customer.Email); err == nil {
+		return errors.NewAlreadyExistsError(fmt.Sprintf("customer with email %s", customer.Email))
+	}
+
+	return nil
+}
+
+func (s *CustomerService) Register(ctx context.Context, customer *domain.Customer) error {
+	if err := s.ValidateRegistration(ctx, customer); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if customer.ID == "" {
+		customer.ID = domain.NewID()
+	}
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	if err := s.repo.CreateCustomer(ctx, customer); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternalError, "failed to create customer")
+	}
+
+	logger.Info("Customer registered",
+		zap.String("customer_id", customer.ID),
+		zap.String("email", customer.Email),
+	)
+
+	return nil
+}
+
+const (
+	CustomerImportImported = "imported"
+	CustomerImportValid    = "valid"
+	CustomerImportSkipped  = "skipped"
+	CustomerImportFailed   = "failed"
+)
+
+type CustomerImportOutcome struct {
+	Row        int    `json:"row"`
+	Email      string `json:"email"`
+	Result     string `json:"result"`
+	Reason     string `json:"reason,omitempty"`
+	CustomerID string `json:"customer_id,omitempty"`
+}
+
+// ImportCustomers registers each customer on its own, so a bad row does not
+// abort the batch. Emails already registered or repeated earlier in the batch
+// are skipped; dryRun only validates.
+func (s *CustomerService) ImportCustomers(ctx context.Context, customers []*domain.Customer, dryRun bool) []CustomerImportOutcome {
+	outcomes := make([]CustomerImportOutcome, 0, len(customers))
+	seen := make(map[string]bool)
+
+	for i, customer := range customers {
+		outcome := CustomerImportOutcome{Row: i + 1, Email: customer.Email}
+		key := strings.ToLower(strings.TrimSpace(customer.Email))
+
+		if key != "" && seen[key] {
+			outcome.Result = CustomerImportSkipped
+			outcome.Reason = "duplicate in file"
+			outcomes = append(outcomes, outcome)
+			continue
+		}
+		seen[key] = true
+
+		var err error
+		if dryRun {
+			err = s.ValidateRegistration(ctx, customer)
+		} else {
+			err = s.Register(ctx, customer)
+		}
+
+		switch {
+		case errors.IsErrorCode(err, errors.ErrCodeAlreadyExists):
+			outcome.Result = CustomerImportSkipped
+			outcome.Reason = "already registered"
+		case err != nil:
+			outcome.Result = CustomerImportFailed
+			outcome.Reason = err.Error()
+		case dryRun:
+			outcome.Result = CustomerImportValid
+		default:
+			outcome.Result = CustomerImportImported
+			outcome.CustomerID = customer.ID
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes
 }
 
 func (s *CustomerService) GetCustomer(ctx context.Context, id string) (*domain.Customer, error) {
 	return s.repo.GetCustomer(ctx, id)
 }
 
-func (s *CustomerService) UpdateLoyaltyPoints(ctx context.Context, customerID string, earned, redeemed int) error {
+// CreditCashback adds amount to the customer's cashback balance; reason and
+// transactionID are recorded in the cashback ledger.
+func (s *CustomerService) CreditCashback(ctx context.Context, customerID, transactionID, reason string, amount float64) error {
+	s.cashbackMu.Lock()
+	defer s.cashbackMu.Unlock()
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	return s.recordCashback(ctx, customer, transactionID, reason, amount)
+}
+
+// ClawBackCashback takes back cashback paid out for a refunded order. Cashback
+// the customer already spent cannot be recovered, so at most the current
+// balance is debited.
+func (s *CustomerService) ClawBackCashback(ctx context.Context, customerID, transactionID string, amount float64) error {
+	s.cashbackMu.Lock()
+	defer s.cashbackMu.Unlock()
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	amount = math.Min(amount, customer.CashbackBalance)
+	if amount <= 0 {
+		return nil
+	}
+
+	return s.recordCashback(ctx, customer, transactionID, domain.CashbackReasonClawback, -amount)
+}
+
+func (s *CustomerService) DebitCashback(ctx context.Context, customerID, transactionID string, amount float64) error {
+	if amount <= 0 {
+		return errors.NewValidationError("cashback to redeem must be positive")
+	}
+
+	s.cashbackMu.Lock()
+	defer s.cashbackMu.Unlock()
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if amount > customer.CashbackBalance {
+		return errors.NewValidationError(
+			fmt.Sprintf("insufficient cashback balance: requested %.2f, available %.2f", amount, customer.CashbackBalance),
+		)
+	}
+
+	return s.recordCashback(ctx, customer, transactionID, domain.CashbackReasonRedeem, -amount)
+}
+
+// recordCashback applies delta to the balance and writes the ledger entry in
+// one repository call. The caller holds cashbackMu.
+func (s *CustomerService) recordCashback(ctx context.Context, customer *domain.Customer, transactionID, reason string, delta float64) error {
+	entry := &domain.CashbackEntry{
+		ID:            domain.NewID(),
+		CustomerID:    customer.ID,
+		TransactionID: transactionID,
+		Delta:         money.Round(delta),
+		Reason:        reason,
+		Balance:       money.Round(customer.CashbackBalance + delta),
+		CreatedAt:     time.Now(),
+	}
+
+	if err := s.repo.RecordCashback(ctx, entry); err != nil {
+		return err
+	}
+	customer.CashbackBalance = entry.Balance
+
+	logger.Info("Cashback balance changed",
+		zap.String("customer_id", customer.ID),
+		zap.Float64("delta", entry.Delta),
+		zap.String("reason", reason),
+		zap.Float64("new_balance", entry.Balance),
+	)
+
+	return nil
+}
+
+func (s *CustomerService) CashbackHistory(ctx context.Context, customerID string) ([]*domain.CashbackEntry, error) {
+	return s.repo.ListCashbackEntries(ctx, customerID)
+}
+
+// SetSpendingLimit overrides the configured spending limit for one customer;
+// 0 clears the override.
+func (s *CustomerService) SetSpendingLimit(ctx context.Context, customerID string, limit float64) error {
+	if limit < 0 {
+		return errors.NewValidationError("spending limit cannot be negative")
+	}
+
 	customer, err := s.repo.GetCustomer(ctx, customerID)
 	if err != nil {
 		return err
 	}
 
-	customer.LoyaltyPoints = customer.LoyaltyPoints + earned - redeemed
+	customer.SpendingLimit = limit
+	if err := s.repo.UpdateCustomer(ctx, customer); err != nil {
+		return err
+	}
+
+	logger.Info("Spending limit updated",
+		zap.String("customer_id", customerID),
+		zap.Float64("limit", limit),
+	)
+
+	return nil
+}
+
+func (s *CustomerService) SetLocale(ctx context.Context, customerID, locale string) error {
+	if locale != "" {
+		var err error
+		if locale, err = validateLocale(locale); err != nil {
+			return err
+		}
+	}
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	customer.Locale = locale
+	if err := s.repo.UpdateCustomer(ctx, customer); err != nil {
+		return err
+	}
+
+	logger.Info("Customer locale updated",
+		zap.String("customer_id", customerID),
+		zap.String("locale", locale),
+	)
+
+	return nil
+}
+
+func validateLocale(locale string) (string, error) {
+	if !i18n.Supported(locale) {
+		return "", errors.NewValidationError(fmt.Sprintf(
+			"unsupported locale %q (expected one of %s)", locale, strings.Join(i18n.Locales(), ", "),
+		))
+	}
+	return i18n.Normalize(locale), nil
+}
+
+// DeleteCustomer removes a customer and their carts. Customers with
+// transactions that are neither refunded nor failed are refused unless force
+// is set. Customers with any transaction history are anonymized rather than
+// deleted so financial records keep a valid customer reference; the returned
+// bool reports whether that happened.
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id string, force bool) (bool, error) {
+	customer, err := s.repo.GetCustomer(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	history, active, err := s.transactionHistory(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if active > 0 && !force {
+		return false, errors.NewValidationError("customer has active transactions").
+			WithDetails("active_transactions", active)
+	}
+
+	if history == 0 {
+		if err := s.repo.DeleteCustomer(ctx, id); err != nil {
+			return false, err
+		}
+		logger.Info("Customer deleted", zap.String("customer_id", id))
+		return false, nil
+	}
+
+	if err := s.anonymize(ctx, customer); err != nil {
+		return false, err
+	}
+
+	logger.Info("Customer anonymized",
+		zap.String("customer_id", id),
+		zap.Int("transactions", history),
+		zap.Int("active_transactions", active),
+	)
+
+	return true, nil
+}
+
+func (s *CustomerService) transactionHistory(ctx context.Context, customerID string) (total, active int, err error) {
+	const pageSize = 100
+
+	for offset := 0; ; offset += pageSize {
+		transactions, err := s.repo.ListTransactionsByCustomer(ctx, customerID, pageSize, offset)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		for _, tx := range transactions {
+			total++
+			if tx.Status != domain.TransactionStatusRefunded && tx.Status != domain.TransactionStatusFailed {
+				active++
+			}
+		}
+
+		if len(transactions) < pageSize {
+			return total, active, nil
+		}
+	}
+}
+
+func (s *CustomerService) anonymize(ctx context.Context, customer *domain.Customer) error {
+	customer.Email = fmt.Sprintf("deleted-%s@anonymized.invalid", customer.ID)
+	customer.Name = "Deleted Customer"
+	customer.Phone = ""
+	customer.Address = domain.Address{}
+	customer.UpdatedAt = time.Now()
 
 	if err := s.repo.UpdateCustomer(ctx, customer); err != nil {
 		return err
 	}
 
+	methods, err := s.repo.ListPaymentMethodsByCustomer(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+	for _, method := range methods {
+		if err := s.repo.DeletePaymentMethod(ctx, method.ID); err != nil {
+			return err
+		}
+	}
+
+	for {
+		cart, err := s.repo.GetCartByCustomer(ctx, customer.ID)
+		if errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteCart(ctx, cart.ID); err != nil {
+			return err
+		}
+	}
+}
+
+// UpdateLoyaltyPoints applies the changes in order as a single balance update
+// and emits a loyalty_changed event for each of them. Zero deltas are skipped.
+func (s *CustomerService) UpdateLoyaltyPoints(ctx context.Context, customerID, transactionID string, changes ...observer.LoyaltyChange) error {
+	customer, applied, err := s.applyLoyaltyChanges(ctx, customerID, changes)
+	if err != nil || len(applied) == 0 {
+		return err
+	}
+
 	logger.Info("Loyalty points updated",
 		zap.String("customer_id", customerID),
-		zap.Int("earned", earned),
-		zap.Int("redeemed", redeemed),
+		zap.String("transaction_id", transactionID),
+		zap.Int("changes", len(applied)),
 		zap.Int("new_balance", customer.LoyaltyPoints),
 	)
 
+	s.notifyLoyaltyChanges(ctx, customer, transactionID, applied)
+
 	return nil
 }
+
+func (s *CustomerService) applyLoyaltyChanges(ctx context.Context, customerID string, changes []observer.LoyaltyChange) (*domain.Customer, []observer.LoyaltyChange, error) {
+	s.loyaltyMu.Lock()
+	defer s.loyaltyMu.Unlock()
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applied := make([]observer.LoyaltyChange, 0, len(changes))
+	for _, change := range changes {
+		if change.Delta == 0 {
+			continue
+		}
+		customer.LoyaltyPoints += change.Delta
+		change.NewBalance = customer.LoyaltyPoints
+		applied = append(applied, change)
+	}
+	if len(applied) == 0 {
+		return customer, nil, nil
+	}
+
+	if err := s.repo.UpdateCustomer(ctx, customer); err != nil {
+		return nil, nil, err
+	}
+	return customer, applied, nil
+}
+
+// AdjustLoyaltyPoints is a manual correction; it may not take the balance
+// below zero.
+func (s *CustomerService) AdjustLoyaltyPoints(ctx context.Context, customerID string, delta int) error {
+	if delta == 0 {
+		return errors.NewValidationError("adjustment must not be zero")
+	}
+
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if customer.LoyaltyPoints+delta < 0 {
+		return errors.NewValidationError(
+			fmt.Sprintf("adjustment of %d exceeds balance of %d points", delta, customer.LoyaltyPoints),
+		)
+	}
+
+	return s.UpdateLoyaltyPoints(ctx, customerID, "", observer.LoyaltyChange{
+		Delta:  delta,
+		Reason: observer.LoyaltyReasonAdjust,
+	})
+}
+
+func (s *CustomerService) LoyaltyHistory(ctx context.Context, customerID string) ([]*domain.LoyaltyEntry, error) {
+	return s.repo.ListLoyaltyEntries(ctx, customerID)
+}
+
+func (s *CustomerService) AddPaymentMethod(ctx context.Context, customerID, method string, entered domain.PaymentDetails) (*domain.SavedPaymentMethod, error) {
+	if _, err := s.repo.GetCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	token, details, err := payment.Tokenize(method, payment.ConfigFromDetails(entered))
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &domain.SavedPaymentMethod{
+		ID:         domain.NewID(),
+		CustomerID: customerID,
+		Method:     method,
+		Token:      token,
+		Details:    details,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.repo.CreatePaymentMethod(ctx, saved); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to save payment method")
+	}
+
+	logger.Info("Payment method saved",
+		zap.String("customer_id", customerID),
+		zap.String("payment_method_id", saved.ID),
+		zap.String("method", method),
+	)
+
+	return saved, nil
+}
+
+func (s *CustomerService) ListPaymentMethods(ctx context.Context, customerID string) ([]*domain.SavedPaymentMethod, error) {
+	return s.repo.ListPaymentMethodsByCustomer(ctx, customerID)
+}
+
+// Methods of other customers are reported as not found.
+func (s *CustomerService) GetPaymentMethod(ctx context.Context, customerID, id string) (*domain.SavedPaymentMethod, error) {
+	saved, err := s.repo.GetPaymentMethod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if saved.CustomerID != customerID {
+		return nil, errors.NewNotFoundError("payment method")
+	}
+	return saved, nil
+}
+
+func (s *CustomerService) RemovePaymentMethod(ctx context.Context, customerID, id string) error {
+	if _, err := s.GetPaymentMethod(ctx, customerID, id); err != nil {
+		return err
+	}
+	return s.repo.DeletePaymentMethod(ctx, id)
+}
+
+func (s *CustomerService) notifyLoyaltyChanges(ctx context.Context, customer *domain.Customer, transactionID string, changes []observer.LoyaltyChange) {
+	if s.eventSubject == nil {
+		return
+	}
+
+	for i := range changes {
+		s.eventSubject.Notify(ctx, observer.Event{
+			Type:          observer.EventLoyaltyChanged,
+			TransactionID: transactionID,
+			CustomerID:    customer.ID,
+			CustomerEmail: customer.Email,
+			CustomerName:  customer.Name,
+			Locale:        customer.Locale,
+			Loyalty:       &changes[i],
+			Timestamp:     time.Now().Format(time.RFC3339),
+		})
+	}
+}
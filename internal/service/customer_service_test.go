@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerServiceRegister(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	customers := NewCustomerService(repo, nil)
+
+	t.Run("Trims And Stores A Valid Customer", func(t *testing.T) {
+		customer := &domain.Customer{Email: "  jane@example.com ", Name: " Jane Doe ", Phone: "+1 (555) 123-4567"}
+		require.NoError(t, customers.Register(ctx, customer))
+		assert.NotEmpty(t, customer.ID)
+		assert.False(t, customer.CreatedAt.IsZero())
+
+		stored, err := repo.GetCustomerByEmail(ctx, "jane@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, customer.ID, stored.ID)
+		assert.Equal(t, "Jane Doe", stored.Name)
+	})
+
+	t.Run("Rejects Invalid Rows", func(t *testing.T) {
+		for name, customer := range map[string]*domain.Customer{
+			"missing name":  {Email: "nameless@example.com"},
+			"invalid email": {Email: "not-an-email", Name: "Bad Email"},
+			"invalid phone": {Email: "phone@example.com", Name: "Bad Phone", Phone: "12ab"},
+		} {
+			err := customers.Register(ctx, customer)
+			assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation), name)
+		}
+	})
+
+	t.Run("Rejects A Registered Email", func(t *testing.T) {
+		err := customers.Register(ctx, &domain.Customer{Email: "jane@example.com", Name: "Jane Again"})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeAlreadyExists))
+	})
+}
+
+func TestCustomerServiceImportCustomers(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*CustomerService, *repository.MemoryRepository) {
+		repo := repository.NewMemoryRepositoryWithSeed(repository.SeedNone)
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{ID: "cust-existing", Email: "existing@example.com", Name: "Existing"}))
+		return NewCustomerService(repo, nil), repo
+	}
+	batch := func() []*domain.Customer {
+		return []*domain.Customer{
+			{Email: "new@example.com", Name: "New Customer"},
+			{Email: "NEW@example.com", Name: "Same Email"},
+			{Email: "existing@example.com", Name: "Existing Again"},
+			{Email: "broken", Name: "Broken Email"},
+			{Email: "last@example.com", Name: "Last Customer"},
+		}
+	}
+	results := func(outcomes []CustomerImportOutcome) []string {
+		values := make([]string, len(outcomes))
+		for i, outcome := range outcomes {
+			values[i] = outcome.Result
+		}
+		return values
+	}
+
+	t.Run("Reports Each Row Without Aborting", func(t *testing.T) {
+		customers, repo := setup(t)
+
+		outcomes := customers.ImportCustomers(ctx, batch(), false)
+		assert.Equal(t, []string{
+			CustomerImportImported,
+			CustomerImportSkipped,
+			CustomerImportSkipped,
+			CustomerImportFailed,
+			CustomerImportImported,
+		}, results(outcomes))
+		assert.Equal(t, "duplicate in file", outcomes[1].Reason)
+		assert.Equal(t, "already registered", outcomes[2].Reason)
+		assert.Equal(t, 5, outcomes[4].Row)
+
+		stored, err := repo.GetCustomerByEmail(ctx, "last@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, outcomes[4].CustomerID, stored.ID)
+	})
+
+	t.Run("Dry Run Saves Nothing", func(t *testing.T) {
+		customers, repo := setup(t)
+
+		outcomes := customers.ImportCustomers(ctx, batch(), true)
+		assert.Equal(t, CustomerImportValid, outcomes[0].Result)
+		assert.Equal(t, CustomerImportFailed, outcomes[3].Result)
+		assert.Empty(t, outcomes[0].CustomerID)
+
+		_, err := repo.GetCustomerByEmail(ctx, "new@example.com")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	})
+}
+
+func TestCustomerServicePaymentMethods(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	customer := &domain.Customer{ID: "cust-vault", Email: "vault@example.com", Name: "Vault Tester"}
+	require.NoError(t, repo.CreateCustomer(ctx, customer))
+	customers := NewCustomerService(repo, nil)
+
+	t.Run("Stores A Token Instead Of The Card Number", func(t *testing.T) {
+		saved, err := customers.AddPaymentMethod(ctx, customer.ID, "credit_card", domain.PaymentDetails{
+			CardNumber: "4532015112830366",
+			CardHolder: "Vault Tester",
+			ExpiryDate: "12/30",
+			CVV:        "123",
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(saved.Token, payment.VaultTokenPrefix))
+		assert.Equal(t, "****0366", saved.Details["last_4_digits"])
+
+		data, err := json.Marshal(saved)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "4532015112830366")
+
+		methods, err := customers.ListPaymentMethods(ctx, customer.ID)
+		require.NoError(t, err)
+		require.Len(t, methods, 1)
+		assert.Equal(t, saved.ID, methods[0].ID)
+	})
+
+	t.Run("Rejects Invalid Details", func(t *testing.T) {
+		_, err := customers.AddPaymentMethod(ctx, customer.ID, "credit_card", domain.PaymentDetails{
+			CardNumber: "1234",
+			CardHolder: "Vault Tester",
+			ExpiryDate: "12/30",
+			CVV:        "123",
+		})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInvalidPayment))
+	})
+
+	t.Run("Only Removes The Customer's Own Methods", func(t *testing.T) {
+		methods, err := customers.ListPaymentMethods(ctx, customer.ID)
+		require.NoError(t, err)
+		require.Len(t, methods, 1)
+
+		err = customers.RemovePaymentMethod(ctx, "cust-other", methods[0].ID)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+
+		require.NoError(t, customers.RemovePaymentMethod(ctx, customer.ID, methods[0].ID))
+		methods, err = customers.ListPaymentMethods(ctx, customer.ID)
+		require.NoError(t, err)
+		assert.Empty(t, methods)
+	})
+}
+
+func TestCustomerServiceDeleteCustomer(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, statuses ...domain.TransactionStatus) (*CustomerService, repository.Repository, *domain.Customer) {
+		repo := repository.NewMemoryRepository()
+		customer := &domain.Customer{
+			ID:      "cust-delete",
+			Email:   "delete.me@example.com",
+			Name:    "Delete Me",
+			Phone:   "+15551234567",
+			Address: domain.Address{Street: "1 Main St", City: "Austin"},
+		}
+		require.NoError(t, repo.CreateCustomer(ctx, customer))
+		require.NoError(t, repo.CreateCart(ctx, &domain.Cart{ID: "cart-delete", CustomerID: customer.ID}))
+		require.NoError(t, repo.CreatePaymentMethod(ctx, &domain.SavedPaymentMethod{
+			ID:         "pm-delete",
+			CustomerID: customer.ID,
+			Method:     "credit_card",
+			Token:      payment.VaultTokenPrefix + "delete",
+		}))
+
+		for i, status := range statuses {
+			require.NoError(t, repo.CreateTransaction(ctx, &domain.Transaction{
+				ID:         customer.ID + "-tx-" + string(rune('a'+i)),
+				CustomerID: customer.ID,
+				Amount:     10,
+				Status:     status,
+			}))
+		}
+
+		return NewCustomerService(repo, nil), repo, customer
+	}
+
+	t.Run("Deletes Customer Without History", func(t *testing.T) {
+		customers, repo, customer := setup(t)
+
+		anonymized, err := customers.DeleteCustomer(ctx, customer.ID, false)
+		require.NoError(t, err)
+		assert.False(t, anonymized)
+
+		_, err = repo.GetCustomer(ctx, customer.ID)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+		_, err = repo.GetCart(ctx, "cart-delete")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	})
+
+	t.Run("Refuses Customer With Active Transactions", func(t *testing.T) {
+		customers, repo, customer := setup(t, domain.TransactionStatusCompleted, domain.TransactionStatusRefunded)
+
+		_, err := customers.DeleteCustomer(ctx, customer.ID, false)
+		require.Error(t, err)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+		assert.Contains(t, err.Error(), "customer has active transactions")
+
+		stored, err := repo.GetCustomer(ctx, customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "delete.me@example.com", stored.Email)
+	})
+
+	t.Run("Force Anonymizes And Removes Carts And Saved Methods", func(t *testing.T) {
+		customers, repo, customer := setup(t, domain.TransactionStatusCompleted)
+
+		anonymized, err := customers.DeleteCustomer(ctx, customer.ID, true)
+		require.NoError(t, err)
+		assert.True(t, anonymized)
+
+		stored, err := repo.GetCustomer(ctx, customer.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "deleted-cust-delete@anonymized.invalid", stored.Email)
+		assert.Equal(t, "Deleted Customer", stored.Name)
+		assert.Empty(t, stored.Phone)
+		assert.Equal(t, domain.Address{}, stored.Address)
+
+		_, err = repo.GetCart(ctx, "cart-delete")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+		_, err = repo.GetPaymentMethod(ctx, "pm-delete")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+
+		transactions, err := repo.ListTransactionsByCustomer(ctx, customer.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, transactions, 1)
+	})
+
+	t.Run("Inactive History Is Anonymized", func(t *testing.T) {
+		customers, _, customer := setup(t, domain.TransactionStatusFailed, domain.TransactionStatusRefunded)
+
+		anonymized, err := customers.DeleteCustomer(ctx, customer.ID, false)
+		require.NoError(t, err)
+		assert.True(t, anonymized)
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type DiscountService struct {
+	repo repository.Repository
+}
+
+func NewDiscountService(repo repository.Repository) *DiscountService {
+	return &DiscountService{repo: repo}
+}
+
+func (s *DiscountService) CreateDiscount(ctx context.Context, discount *domain.Discount) error {
+	if discount.Value <= 0 {
+		return errors.NewValidationError("discount value must be positive")
+	}
+	if discount.Type == domain.DiscountTypePercentage && discount.Value > 100 {
+		return errors.NewValidationError("percentage discount cannot exceed 100%")
+	}
+
+	if discount.ID == "" {
+		discount.ID = domain.NewID()
+	}
+	discount.Code = domain.NormalizeDiscountCode(discount.Code)
+	discount.CreatedAt = time.Now()
+
+	if err := s.repo.CreateDiscount(ctx, discount); err != nil {
+		return err
+	}
+
+	logger.Info("Discount created",
+		zap.String("code", discount.Code),
+		zap.String("type", string(discount.Type)),
+		zap.Float64("value", discount.Value),
+	)
+
+	return nil
+}
+
+// GetDiscountByCode returns the discount only when it can be applied now;
+// unknown, inactive and expired codes are validation errors.
+func (s *DiscountService) GetDiscountByCode(ctx context.Context, code string) (*domain.Discount, error) {
+	discount, err := s.repo.GetDiscountByCode(ctx, code)
+	if errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+		return nil, errors.NewValidationError("invalid discount code: " + domain.NormalizeDiscountCode(code))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !discount.IsActive {
+		return nil, errors.NewValidationError("discount code is no longer active: " + discount.Code)
+	}
+	if !discount.IsValid() {
+		return nil, errors.NewValidationError("discount code has expired: " + discount.Code)
+	}
+
+	return discount, nil
+}
+
+func (s *DiscountService) ListDiscounts(ctx context.Context, limit, offset int) ([]*domain.Discount, error) {
+	return s.repo.ListDiscounts(ctx, limit, offset)
+}
+
+func (s *DiscountService) Deactivate(ctx context.Context, code string) error {
+	discount, err := s.repo.GetDiscountByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	discount.IsActive = false
+	return s.repo.UpdateDiscount(ctx, discount)
+}
+
+func (s *DiscountService) DeleteDiscount(ctx context.Context, code string) error {
+	return s.repo.DeleteDiscount(ctx, code)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// EscrowService settles payments taken with the escrow strategy, whose funds
+// stay held until delivery is confirmed.
+type EscrowService struct {
+	repo         repository.Repository
+	eventSubject *observer.Subject
+}
+
+func NewEscrowService(repo repository.Repository, eventSubject *observer.Subject) *EscrowService {
+	return &EscrowService{
+		repo:         repo,
+		eventSubject: eventSubject,
+	}
+}
+
+// Release pays the held funds out once delivery is confirmed, completing the
+// transaction.
+func (s *EscrowService) Release(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	transaction, err := s.getHeld(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transaction.Status = domain.TransactionStatusCompleted
+	transaction.PaymentDetails["escrow_status"] = domain.EscrowStatusReleased
+	transaction.Metadata["escrow_released_at"] = now.Format(time.RFC3339)
+	transaction.ProcessedAt = now
+
+	if err := s.repo.UpdateTransaction(ctx, transaction); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to record escrow release")
+	}
+
+	logger.Info("Escrow released",
+		zap.String("transaction_id", transaction.ID),
+		zap.Float64("amount", transaction.Financials().Total),
+	)
+
+	return transaction, nil
+}
+
+// Refund returns the held funds to the customer. Like a regular refund it is
+// recorded as a refund transaction pointing at the original.
+func (s *EscrowService) Refund(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	original, err := s.getHeld(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := original.Financials().Total
+	now := time.Now()
+	refund := &domain.Transaction{
+		ID:            domain.NewID(),
+		CustomerID:    original.CustomerID,
+		Amount:        amount,
+		Status:        domain.TransactionStatusRefunded,
+		PaymentMethod: original.PaymentMethod,
+		Strategy:      original.Strategy,
+		Metadata: map[string]interface{}{
+			"refund_of":       original.ID,
+			"refunded_amount": amount,
+			"escrow_refund":   true,
+		},
+		ProcessedAt: now,
+		CreatedAt:   now,
+	}
+	if original.IsConverted() {
+		refund.LockExchangeRate(original.ExchangeRate, amount, original.DisplayCurrency, original.BaseCurrency)
+	}
+
+	if err := s.repo.CreateTransaction(ctx, refund); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to record escrow refund")
+	}
+
+	original.Status = domain.TransactionStatusRefunded
+	original.PaymentDetails["escrow_status"] = domain.EscrowStatusRefunded
+	original.Metadata["refunded_amount"] = amount
+	original.Metadata["refund_transaction_ids"] = []string{refund.ID}
+
+	if err := s.repo.UpdateTransaction(ctx, original); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to update escrow transaction")
+	}
+
+	if s.eventSubject != nil {
+		s.eventSubject.Notify(ctx, observer.Event{
+			Type:          observer.EventRefundIssued,
+			TransactionID: refund.ID,
+			CustomerID:    refund.CustomerID,
+			Amount:        amount,
+			PaymentMethod: refund.PaymentMethod,
+			Metadata: map[string]interface{}{
+				"refund_of":       original.ID,
+				"refunded_amount": amount,
+				"escrow_refund":   true,
+			},
+			Timestamp: now.Format(time.RFC3339),
+		})
+	}
+
+	logger.Info("Escrow refunded",
+		zap.String("transaction_id", original.ID),
+		zap.String("refund_id", refund.ID),
+		zap.Float64("amount", amount),
+	)
+
+	return refund, nil
+}
+
+func (s *EscrowService) getHeld(ctx context.Context, transactionID string) (*domain.Transaction, error) {
+	transaction, err := s.repo.GetTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.Status != domain.TransactionStatusHeld {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("transaction %s is not held in escrow (status %s)", transactionID, transaction.Status),
+		)
+	}
+
+	if transaction.PaymentDetails == nil {
+		transaction.PaymentDetails = make(map[string]interface{})
+	}
+	if transaction.Metadata == nil {
+		transaction.Metadata = make(map[string]interface{})
+	}
+	return transaction, nil
+}
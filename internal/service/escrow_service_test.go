@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscrowService(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	escrow := NewEscrowService(repo, nil)
+
+	hold := func(id string) {
+		require.NoError(t, repo.CreateTransaction(ctx, &domain.Transaction{
+			ID:             id,
+			CustomerID:     "cust-default",
+			Amount:         1200,
+			Status:         domain.TransactionStatusHeld,
+			PaymentMethod:  "credit_card",
+			Strategy:       "escrow",
+			PaymentDetails: map[string]interface{}{"escrow_status": domain.EscrowStatusHeld},
+			Metadata:       map[string]interface{}{"charged_amount": 1320.0},
+			CreatedAt:      time.Now(),
+		}))
+	}
+
+	t.Run("Release Completes The Transaction", func(t *testing.T) {
+		hold("tx-release")
+
+		released, err := escrow.Release(ctx, "tx-release")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusCompleted, released.Status)
+
+		stored, err := repo.GetTransaction(ctx, "tx-release")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusCompleted, stored.Status)
+		assert.Equal(t, domain.EscrowStatusReleased, stored.PaymentDetails["escrow_status"])
+
+		_, err = escrow.Release(ctx, "tx-release")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Refund Returns The Charged Amount", func(t *testing.T) {
+		hold("tx-refund")
+
+		refund, err := escrow.Refund(ctx, "tx-refund")
+		require.NoError(t, err)
+		assert.Equal(t, 1320.0, refund.Amount)
+		assert.Equal(t, "tx-refund", refund.Metadata["refund_of"])
+
+		stored, err := repo.GetTransaction(ctx, "tx-refund")
+		require.NoError(t, err)
+		assert.Equal(t, domain.TransactionStatusRefunded, stored.Status)
+		assert.Equal(t, domain.EscrowStatusRefunded, stored.PaymentDetails["escrow_status"])
+		assert.Equal(t, 0.0, stored.Financials().Net)
+
+		_, err = escrow.Release(ctx, "tx-refund")
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+}
@@ -3,7 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
 	"github.com/ecommerce/payment-system/internal/repository"
 	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
@@ -11,76 +16,583 @@ import (
 )
 
 type InventoryService struct {
-	repo repository.Repository
+	// mu keeps concurrent checkouts from overselling.
+	mu                sync.Mutex
+	repo              repository.Repository
+	eventSubject      *observer.Subject
+	lowStockThreshold int
+	reservationTTL    time.Duration
+	now               func() time.Time
+	stopSweeper       func()
 }
 
-func NewInventoryService(repo repository.Repository) *InventoryService {
-	return &InventoryService{repo: repo}
+type InventoryOptions struct {
+	// LowStockThreshold of 0 disables the low stock alert.
+	LowStockThreshold int
+	ReservationTTL    time.Duration
 }
 
-func (s *InventoryService) CheckAvailability(ctx context.Context, productID string, quantity int) (bool, error) {
+const defaultReservationTTL = 15 * time.Minute
+
+func NewInventoryService(repo repository.Repository, eventSubject *observer.Subject, options InventoryOptions) *InventoryService {
+	if options.ReservationTTL <= 0 {
+		options.ReservationTTL = defaultReservationTTL
+	}
+
+	return &InventoryService{
+		repo:              repo,
+		eventSubject:      eventSubject,
+		lowStockThreshold: options.LowStockThreshold,
+		reservationTTL:    options.ReservationTTL,
+		now:               time.Now,
+	}
+}
+
+func (s *InventoryService) GetProduct(ctx context.Context, productID string) (*domain.Product, error) {
+	return s.repo.GetProduct(ctx, productID)
+}
+
+// ProductUpdate holds the catalog fields an admin can change; nil fields are
+// left as they are.
+type ProductUpdate struct {
+	Name      *string
+	Price     *float64
+	Stock     *int
+	Category  *string
+	TaxExempt *bool
+}
+
+// CreateProduct adds a product to the catalog. SKUs are unique.
+func (s *InventoryService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	if err := validateProduct(product); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetProductBySKU(ctx, product.SKU); err == nil {
+		return errors.NewAlreadyExistsError(fmt.Sprintf("product with SKU %s", product.SKU))
+	} else if !errors.IsErrorCode(err, errors.ErrCodeNotFound) {
+		return err
+	}
+
+	now := s.now()
+	if product.ID == "" {
+		product.ID = domain.NewID()
+	}
+	product.CreatedAt = now
+	product.UpdatedAt = now
+
+	if err := s.repo.CreateProduct(ctx, product); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternalError, "failed to create product")
+	}
+
+	logger.Info("Product created",
+		zap.String("product_id", product.ID),
+		zap.String("sku", product.SKU),
+	)
+	return nil
+}
+
+// UpdateProduct applies an admin's catalog changes. It holds the inventory
+// lock so a stock change cannot interleave with a checkout's decrement.
+func (s *InventoryService) UpdateProduct(ctx context.Context, productID string, update ProductUpdate) (*domain.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	product, err := s.repo.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		product.Name = *update.Name
+	}
+	if update.Price != nil {
+		product.Price = *update.Price
+	}
+	if update.Stock != nil {
+		product.Stock = *update.Stock
+	}
+	if update.Category != nil {
+		product.Category = *update.Category
+	}
+	if update.TaxExempt != nil {
+		product.TaxExempt = *update.TaxExempt
+	}
+	if err := validateProduct(product); err != nil {
+		return nil, err
+	}
+	product.UpdatedAt = s.now()
+
+	if err := s.repo.UpdateProduct(ctx, product); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to update product")
+	}
+
+	logger.Info("Product updated", zap.String("product_id", product.ID))
+	return product, nil
+}
+
+// DeleteProduct removes a product from the catalog. Products with stock held
+// by a checkout in progress cannot be deleted until the hold resolves.
+func (s *InventoryService) DeleteProduct(ctx context.Context, productID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.repo.GetProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	reserved, err := s.repo.ReservedQuantity(ctx, productID, s.now())
+	if err != nil {
+		return err
+	}
+	if reserved > 0 {
+		return errors.New(errors.ErrCodeConflict,
+			fmt.Sprintf("product %s has %d unit(s) held by checkouts in progress", productID, reserved),
+		)
+	}
+
+	if err := s.repo.DeleteProduct(ctx, productID); err != nil {
+		return err
+	}
+
+	logger.Info("Product deleted", zap.String("product_id", productID))
+	return nil
+}
+
+func validateProduct(product *domain.Product) error {
+	switch {
+	case strings.TrimSpace(product.Name) == "":
+		return errors.NewValidationError("product name is required")
+	case strings.TrimSpace(product.SKU) == "":
+		return errors.NewValidationError("product SKU is required")
+	case product.Price <= 0:
+		return errors.NewValidationError(fmt.Sprintf("product price must be positive, got %.2f", product.Price))
+	case product.Stock < 0:
+		return errors.NewValidationError(fmt.Sprintf("product stock cannot be negative, got %d", product.Stock))
+	}
+	return nil
+}
+
+func (s *InventoryService) AvailableStock(ctx context.Context, productID string) (int, error) {
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err != nil {
+		return 0, err
+	}
+	return s.available(ctx, product)
+}
+
+func (s *InventoryService) CheckAvailability(ctx context.Context, productID string, quantity int) (bool, error) {
+	available, err := s.AvailableStock(ctx, productID)
 	if err != nil {
 		return false, err
 	}
 
-	available := product.Stock >= quantity
+	sufficient := available >= quantity
 
 	logger.Debug("Inventory check",
 		zap.String("product_id", productID),
 		zap.Int("requested", quantity),
-		zap.Int("available", product.Stock),
-		zap.Bool("sufficient", available),
+		zap.Int("available", available),
+		zap.Bool("sufficient", sufficient),
 	)
 
-	return available, nil
+	return sufficient, nil
 }
 
 func (s *InventoryService) ReserveStock(ctx context.Context, productID string, quantity int) error {
-	product, err := s.repo.GetProduct(ctx, productID)
+	change, err := s.reserve(ctx, productID, quantity)
 	if err != nil {
 		return err
 	}
 
-	if product.Stock < quantity {
-		return errors.NewInventoryError(
+	s.notifyChanges(ctx, "", []observer.InventoryChange{change})
+	return nil
+}
+
+func (s *InventoryService) ReleaseStock(ctx context.Context, productID string, quantity int) error {
+	change, err := s.release(ctx, productID, quantity, observer.InventoryReasonRelease)
+	if err != nil {
+		return err
+	}
+
+	s.notifyChanges(ctx, "", []observer.InventoryChange{change})
+	return nil
+}
+
+// RestockItems returns the items of a refunded order to stock, reporting them
+// as one inventory event.
+func (s *InventoryService) RestockItems(ctx context.Context, transactionID string, items []domain.CartItem) error {
+	changes := make([]observer.InventoryChange, 0, len(items))
+	var restockErr error
+
+	for _, item := range items {
+		change, err := s.release(ctx, item.ProductID, item.Quantity, observer.InventoryReasonRestock)
+		if err != nil {
+			restockErr = err
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	s.notifyChanges(ctx, transactionID, changes)
+	return restockErr
+}
+
+// ReserveItems holds expire, so an interrupted checkout cannot leak stock.
+func (s *InventoryService) ReserveItems(ctx context.Context, transactionID, cartID string, items []domain.CartItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	reservations := make([]*domain.Reservation, 0, len(items))
+
+	for _, item := range items {
+		reservation, err := s.hold(ctx, transactionID, cartID, item, now)
+		if err != nil {
+			for _, held := range reservations {
+				if deleteErr := s.repo.DeleteReservation(ctx, held.ID); deleteErr != nil {
+					logger.Error("Failed to undo partial reservation",
+						zap.Error(deleteErr),
+						zap.String("product_id", held.ProductID),
+					)
+				}
+			}
+			return err
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	return nil
+}
+
+// CommitItems decrements stock even for expired holds; the customer has paid.
+func (s *InventoryService) CommitItems(ctx context.Context, transactionID string, items []domain.CartItem) error {
+	changes, err := s.commit(ctx, transactionID, items)
+	s.notifyChanges(ctx, transactionID, changes)
+	return err
+}
+
+func (s *InventoryService) commit(ctx context.Context, transactionID string, items []domain.CartItem) ([]observer.InventoryChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.repo.ListReservationsByTransaction(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		logger.Warn("Committing checkout without active reservations",
+			zap.String("transaction_id", transactionID),
+		)
+	}
+
+	changes := make([]observer.InventoryChange, 0, len(items))
+
+	var firstErr error
+	for _, item := range items {
+		change, err := s.decrement(ctx, item.ProductID, item.Quantity)
+		if err != nil {
+			logger.Error("Failed to commit stock",
+				zap.Error(err),
+				zap.String("product_id", item.ProductID),
+			)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	s.deleteReservations(ctx, reservations)
+	return changes, firstErr
+}
+
+func (s *InventoryService) ReleaseReservations(ctx context.Context, transactionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, err := s.repo.ListReservationsByTransaction(ctx, transactionID)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteReservations(ctx, reservations)
+}
+
+func (s *InventoryService) ReleaseExpired(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired, err := s.repo.DeleteExpiredReservations(ctx, s.now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, reservation := range expired {
+		logger.Info("Expired reservation released",
+			zap.String("reservation_id", reservation.ID),
+			zap.String("product_id", reservation.ProductID),
+			zap.Int("quantity", reservation.Quantity),
+			zap.String("transaction_id", reservation.TransactionID),
+			zap.String("cart_id", reservation.CartID),
+		)
+	}
+
+	return len(expired), nil
+}
+
+// StartSweeper releases expired holds every interval until Close.
+func (s *InventoryService) StartSweeper(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	sweep := func() {
+		if _, err := s.ReleaseExpired(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Failed to release expired reservations", zap.Error(err))
+		}
+	}
+
+	sweep()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sweep()
+			}
+		}
+	}()
+
+	s.stopSweeper = func() {
+		cancel()
+		<-done
+	}
+}
+
+// Close stops the sweeper, waiting for a sweep in progress to finish.
+func (s *InventoryService) Close() error {
+	if s.stopSweeper != nil {
+		s.stopSweeper()
+		s.stopSweeper = nil
+	}
+	return nil
+}
+
+func (s *InventoryService) available(ctx context.Context, product *domain.Product) (int, error) {
+	reserved, err := s.repo.ReservedQuantity(ctx, product.ID, s.now())
+	if err != nil {
+		return 0, err
+	}
+	return product.Stock - reserved, nil
+}
+
+func (s *InventoryService) hold(ctx context.Context, transactionID, cartID string, item domain.CartItem, now time.Time) (*domain.Reservation, error) {
+	product, err := s.repo.GetProduct(ctx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := s.available(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	if available < item.Quantity {
+		return nil, errors.NewInventoryError(
+			fmt.Sprintf("insufficient stock for product %s: have %d, need %d",
+				product.Name, available, item.Quantity),
+		)
+	}
+
+	reservation := &domain.Reservation{
+		ID:            domain.NewID(),
+		ProductID:     item.ProductID,
+		Quantity:      item.Quantity,
+		CartID:        cartID,
+		TransactionID: transactionID,
+		ExpiresAt:     now.Add(s.reservationTTL),
+		CreatedAt:     now,
+	}
+
+	if err := s.repo.CreateReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Stock reserved",
+		zap.String("product_id", item.ProductID),
+		zap.Int("quantity", item.Quantity),
+		zap.Int("available", available-item.Quantity),
+		zap.Time("expires_at", reservation.ExpiresAt),
+	)
+
+	return reservation, nil
+}
+
+func (s *InventoryService) deleteReservations(ctx context.Context, reservations []*domain.Reservation) error {
+	var firstErr error
+	for _, reservation := range reservations {
+		if err := s.repo.DeleteReservation(ctx, reservation.ID); err != nil {
+			logger.Error("Failed to delete reservation",
+				zap.Error(err),
+				zap.String("reservation_id", reservation.ID),
+			)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		logger.Info("Reservation released",
+			zap.String("product_id", reservation.ProductID),
+			zap.Int("quantity", reservation.Quantity),
+			zap.String("transaction_id", reservation.TransactionID),
+		)
+	}
+	return firstErr
+}
+
+func (s *InventoryService) reserve(ctx context.Context, productID string, quantity int) (observer.InventoryChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err != nil {
+		return observer.InventoryChange{}, err
+	}
+
+	available, err := s.available(ctx, product)
+	if err != nil {
+		return observer.InventoryChange{}, err
+	}
+
+	if available < quantity {
+		return observer.InventoryChange{}, errors.NewInventoryError(
 			fmt.Sprintf("insufficient stock for product %s: have %d, need %d",
-				product.Name, product.Stock, quantity),
+				product.Name, available, quantity),
 		)
 	}
 
+	return s.decrement(ctx, productID, quantity)
+}
+
+// decrement removes stock; callers hold s.mu.
+func (s *InventoryService) decrement(ctx context.Context, productID string, quantity int) (observer.InventoryChange, error) {
+	product, err := s.repo.GetProduct(ctx, productID)
+	if err != nil {
+		return observer.InventoryChange{}, err
+	}
+
 	product.Stock -= quantity
 
 	if err := s.repo.UpdateProduct(ctx, product); err != nil {
-		return err
+		return observer.InventoryChange{}, err
 	}
 
-	logger.Info("Stock reserved",
+	logger.Info("Stock decremented",
 		zap.String("product_id", productID),
 		zap.Int("quantity", quantity),
 		zap.Int("remaining", product.Stock),
 	)
 
-	return nil
+	return observer.InventoryChange{
+		ProductID: productID,
+		SKU:       product.SKU,
+		Delta:     -quantity,
+		NewLevel:  product.Stock,
+		Reason:    observer.InventoryReasonReservation,
+	}, nil
 }
 
-func (s *InventoryService) ReleaseStock(ctx context.Context, productID string, quantity int) error {
+func (s *InventoryService) release(ctx context.Context, productID string, quantity int, reason string) (observer.InventoryChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	product, err := s.repo.GetProduct(ctx, productID)
 	if err != nil {
-		return err
+		return observer.InventoryChange{}, err
 	}
 
 	product.Stock += quantity
 
 	if err := s.repo.UpdateProduct(ctx, product); err != nil {
-		return err
+		return observer.InventoryChange{}, err
 	}
 
 	logger.Info("Stock released",
 		zap.String("product_id", productID),
 		zap.Int("quantity", quantity),
 		zap.Int("new_stock", product.Stock),
+		zap.String("reason", reason),
 	)
 
-	return nil
+	return observer.InventoryChange{
+		ProductID: productID,
+		SKU:       product.SKU,
+		Delta:     quantity,
+		NewLevel:  product.Stock,
+		Reason:    reason,
+	}, nil
+}
+
+func (s *InventoryService) notifyChanges(ctx context.Context, transactionID string, changes []observer.InventoryChange) {
+	if s.eventSubject == nil || len(changes) == 0 {
+		return
+	}
+
+	s.eventSubject.Notify(ctx, observer.Event{
+		Type:          observer.EventInventoryChanged,
+		TransactionID: transactionID,
+		Inventory:     changes,
+		Metadata: map[string]interface{}{
+			"change_count": len(changes),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+
+	for _, change := range changes {
+		if s.crossedLowStock(change) {
+			s.notifyLowStock(ctx, transactionID, change)
+		}
+	}
+}
+
+// crossedLowStock reports whether a change took stock from above the
+// threshold to at or below it. Releases and further reservations while
+// already low do not re-alert.
+func (s *InventoryService) crossedLowStock(change observer.InventoryChange) bool {
+	if s.lowStockThreshold <= 0 || change.Delta >= 0 {
+		return false
+	}
+	previous := change.NewLevel - change.Delta
+	return previous > s.lowStockThreshold && change.NewLevel <= s.lowStockThreshold
+}
+
+func (s *InventoryService) notifyLowStock(ctx context.Context, transactionID string, change observer.InventoryChange) {
+	logger.Warn("Product stock is low",
+		zap.String("product_id", change.ProductID),
+		zap.String("sku", change.SKU),
+		zap.Int("remaining", change.NewLevel),
+		zap.Int("threshold", s.lowStockThreshold),
+	)
+
+	s.eventSubject.Notify(ctx, observer.Event{
+		Type:          observer.EventLowStock,
+		TransactionID: transactionID,
+		Inventory:     []observer.InventoryChange{change},
+		Metadata: map[string]interface{}{
+			"product_id": change.ProductID,
+			"sku":        change.SKU,
+			"remaining":  change.NewLevel,
+			"threshold":  s.lowStockThreshold,
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
 }
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []observer.Event
+}
+
+func (r *recordingObserver) Notify(ctx context.Context, event observer.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordingObserver) GetName() string {
+	return "recorder"
+}
+
+func (r *recordingObserver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestInventoryServiceLowStock(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*InventoryService, *recordingObserver) {
+		repo := repository.NewMemoryRepository()
+		require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: "prod-low", SKU: "LOW-001", Stock: 8}))
+
+		recorder := &recordingObserver{}
+		subject := observer.NewSubject()
+		subject.AttachFiltered(recorder, observer.EventLowStock)
+
+		return NewInventoryService(repo, subject, InventoryOptions{LowStockThreshold: 5}), recorder
+	}
+
+	t.Run("Alerts When Crossing Threshold", func(t *testing.T) {
+		inventory, recorder := setup(t)
+
+		require.NoError(t, inventory.ReserveStock(ctx, "prod-low", 2))
+		assert.Equal(t, 0, recorder.count())
+
+		require.NoError(t, inventory.ReserveStock(ctx, "prod-low", 2))
+		require.Equal(t, 1, recorder.count())
+
+		event := recorder.events[0]
+		require.Len(t, event.Inventory, 1)
+		assert.Equal(t, "prod-low", event.Inventory[0].ProductID)
+		assert.Equal(t, "LOW-001", event.Inventory[0].SKU)
+		assert.Equal(t, 4, event.Inventory[0].NewLevel)
+	})
+
+	t.Run("Does Not Re-Alert While Low", func(t *testing.T) {
+		inventory, recorder := setup(t)
+
+		require.NoError(t, inventory.ReserveStock(ctx, "prod-low", 4))
+		require.NoError(t, inventory.ReleaseStock(ctx, "prod-low", 1))
+		require.NoError(t, inventory.ReserveStock(ctx, "prod-low", 1))
+		assert.Equal(t, 1, recorder.count())
+	})
+
+	t.Run("Alerts Again After Restocking Above Threshold", func(t *testing.T) {
+		inventory, recorder := setup(t)
+
+		require.NoError(t, inventory.ReserveStock(ctx, "prod-low", 4))
+		require.NoError(t, inventory.ReleaseStock(ctx, "prod-low", 4))
+		assert.Equal(t, 1, recorder.count())
+
+		items := []domain.CartItem{{ProductID: "prod-low", Quantity: 3}}
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items))
+		assert.Equal(t, 1, recorder.count())
+
+		require.NoError(t, inventory.CommitItems(ctx, "tx-1", items))
+		require.Equal(t, 2, recorder.count())
+		assert.Equal(t, "tx-1", recorder.events[1].TransactionID)
+	})
+}
+
+func TestInventoryServiceChangeEvents(t *testing.T) {
+	ctx := context.Background()
+
+	repo := repository.NewMemoryRepository()
+	require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: "prod-a", SKU: "A-001", Stock: 10}))
+	require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: "prod-b", SKU: "B-001", Stock: 10}))
+
+	recorder := &recordingObserver{}
+	subject := observer.NewSubject()
+	subject.AttachFiltered(recorder, observer.EventInventoryChanged)
+	inventory := NewInventoryService(repo, subject, InventoryOptions{})
+
+	items := []domain.CartItem{{ProductID: "prod-a", Quantity: 2}, {ProductID: "prod-b", Quantity: 3}}
+
+	t.Run("Commit Reports One Batched Event", func(t *testing.T) {
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items))
+		assert.Equal(t, 0, recorder.count())
+
+		require.NoError(t, inventory.CommitItems(ctx, "tx-1", items))
+		require.Equal(t, 1, recorder.count())
+
+		event := recorder.events[0]
+		assert.Equal(t, "tx-1", event.TransactionID)
+		assert.Equal(t, []observer.InventoryChange{
+			{ProductID: "prod-a", SKU: "A-001", Delta: -2, NewLevel: 8, Reason: observer.InventoryReasonReservation},
+			{ProductID: "prod-b", SKU: "B-001", Delta: -3, NewLevel: 7, Reason: observer.InventoryReasonReservation},
+		}, event.Inventory)
+	})
+
+	t.Run("Restock Reports One Batched Event", func(t *testing.T) {
+		require.NoError(t, inventory.RestockItems(ctx, "tx-1", items))
+		require.Equal(t, 2, recorder.count())
+
+		event := recorder.events[1]
+		assert.Equal(t, "tx-1", event.TransactionID)
+		assert.Equal(t, []observer.InventoryChange{
+			{ProductID: "prod-a", SKU: "A-001", Delta: 2, NewLevel: 10, Reason: observer.InventoryReasonRestock},
+			{ProductID: "prod-b", SKU: "B-001", Delta: 3, NewLevel: 10, Reason: observer.InventoryReasonRestock},
+		}, event.Inventory)
+	})
+
+	t.Run("Release Reports Its Reason", func(t *testing.T) {
+		require.NoError(t, inventory.ReleaseStock(ctx, "prod-a", 1))
+		require.Equal(t, 3, recorder.count())
+
+		event := recorder.events[2]
+		require.Len(t, event.Inventory, 1)
+		assert.Equal(t, observer.InventoryReasonRelease, event.Inventory[0].Reason)
+		assert.Equal(t, 11, event.Inventory[0].NewLevel)
+	})
+
+	t.Run("Restock Of Unknown Product Still Reports The Rest", func(t *testing.T) {
+		err := inventory.RestockItems(ctx, "tx-2", []domain.CartItem{
+			{ProductID: "prod-missing", Quantity: 1},
+			{ProductID: "prod-b", Quantity: 1},
+		})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+		require.Equal(t, 4, recorder.count())
+
+		event := recorder.events[3]
+		require.Len(t, event.Inventory, 1)
+		assert.Equal(t, "prod-b", event.Inventory[0].ProductID)
+	})
+}
+
+func TestInventoryServiceReservations(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T) (*InventoryService, repository.Repository) {
+		repo := repository.NewMemoryRepository()
+		require.NoError(t, repo.CreateProduct(ctx, &domain.Product{ID: "prod-held", Name: "Held", Stock: 5}))
+
+		inventory := NewInventoryService(repo, nil, InventoryOptions{ReservationTTL: 10 * time.Minute})
+		inventory.now = func() time.Time { return now }
+		return inventory, repo
+	}
+	items := func(quantity int) []domain.CartItem {
+		return []domain.CartItem{{ProductID: "prod-held", Quantity: quantity}}
+	}
+
+	t.Run("Holds Reduce Availability Without Touching Stock", func(t *testing.T) {
+		inventory, repo := setup(t)
+
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items(3)))
+
+		available, err := inventory.AvailableStock(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 2, available)
+
+		product, err := repo.GetProduct(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 5, product.Stock)
+
+		err = inventory.ReserveItems(ctx, "tx-2", "cart-2", items(3))
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeInventoryError))
+	})
+
+	t.Run("Commit Decrements Stock And Drops Holds", func(t *testing.T) {
+		inventory, repo := setup(t)
+
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items(3)))
+		require.NoError(t, inventory.CommitItems(ctx, "tx-1", items(3)))
+
+		product, err := repo.GetProduct(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 2, product.Stock)
+
+		available, err := inventory.AvailableStock(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 2, available)
+	})
+
+	t.Run("Release Restores Availability", func(t *testing.T) {
+		inventory, _ := setup(t)
+
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items(5)))
+		require.NoError(t, inventory.ReleaseReservations(ctx, "tx-1"))
+
+		available, err := inventory.AvailableStock(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 5, available)
+	})
+
+	t.Run("Expired Holds Are Swept", func(t *testing.T) {
+		inventory, repo := setup(t)
+
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items(4)))
+
+		released, err := inventory.ReleaseExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, released)
+
+		inventory.now = func() time.Time { return now.Add(10 * time.Minute) }
+
+		available, err := inventory.AvailableStock(ctx, "prod-held")
+		require.NoError(t, err)
+		assert.Equal(t, 5, available)
+
+		released, err = inventory.ReleaseExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+
+		remaining, err := repo.ListReservationsByTransaction(ctx, "tx-1")
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+
+	t.Run("Sweeper Runs Until Close", func(t *testing.T) {
+		inventory, repo := setup(t)
+
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", items(4)))
+		inventory.now = func() time.Time { return now.Add(10 * time.Minute) }
+
+		inventory.StartSweeper(time.Hour)
+		require.NoError(t, inventory.Close())
+		require.NoError(t, inventory.Close())
+
+		remaining, err := repo.ListReservationsByTransaction(ctx, "tx-1")
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
+
+func TestInventoryServiceCatalog(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	inventory := NewInventoryService(repo, nil, InventoryOptions{})
+
+	hub := &domain.Product{Name: "USB Hub", SKU: "HUB-001", Price: 24.99, Stock: 10}
+	require.NoError(t, inventory.CreateProduct(ctx, hub))
+	require.NotEmpty(t, hub.ID)
+
+	t.Run("Rejects Duplicate SKU", func(t *testing.T) {
+		err := inventory.CreateProduct(ctx, &domain.Product{Name: "Other Hub", SKU: "HUB-001", Price: 19.99})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeAlreadyExists))
+	})
+
+	t.Run("Rejects Non-Positive Price", func(t *testing.T) {
+		err := inventory.CreateProduct(ctx, &domain.Product{Name: "Freebie", SKU: "FREE-001", Price: 0})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+
+		price := -1.0
+		_, err = inventory.UpdateProduct(ctx, hub.ID, ProductUpdate{Price: &price})
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeValidation))
+	})
+
+	t.Run("Updates Only The Given Fields", func(t *testing.T) {
+		price, stock := 21.99, 30
+		updated, err := inventory.UpdateProduct(ctx, hub.ID, ProductUpdate{Price: &price, Stock: &stock})
+		require.NoError(t, err)
+		assert.Equal(t, 21.99, updated.Price)
+		assert.Equal(t, 30, updated.Stock)
+		assert.Equal(t, "USB Hub", updated.Name)
+	})
+
+	t.Run("Refuses To Delete Held Stock", func(t *testing.T) {
+		require.NoError(t, inventory.ReserveItems(ctx, "tx-1", "cart-1", []domain.CartItem{{ProductID: hub.ID, Quantity: 2}}))
+		err := inventory.DeleteProduct(ctx, hub.ID)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeConflict))
+
+		require.NoError(t, inventory.ReleaseReservations(ctx, "tx-1"))
+		require.NoError(t, inventory.DeleteProduct(ctx, hub.ID))
+		_, err = repo.GetProduct(ctx, hub.ID)
+		assert.True(t, errors.IsErrorCode(err, errors.ErrCodeNotFound))
+	})
+}
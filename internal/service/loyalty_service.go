@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type LoyaltyHold struct {
+	ID         string
+	CustomerID string
+	Points     int
+	CreatedAt  time.Time
+}
+
+type LoyaltyService struct {
+	customerService *CustomerService
+	holds           map[string]*LoyaltyHold
+	mu              sync.Mutex
+}
+
+func NewLoyaltyService(customerService *CustomerService) *LoyaltyService {
+	return &LoyaltyService{
+		customerService: customerService,
+		holds:           make(map[string]*LoyaltyHold),
+	}
+}
+
+func (s *LoyaltyService) Hold(ctx context.Context, customerID string, points int) (*LoyaltyHold, error) {
+	if points <= 0 {
+		return nil, errors.NewValidationError("points to hold must be positive")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customer, err := s.customerService.GetCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	available := customer.LoyaltyPoints - s.heldPoints(customerID)
+	if points > available {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("insufficient loyalty points: requested %d, available %d", points, available),
+		)
+	}
+
+	hold := &LoyaltyHold{
+		ID:         domain.NewID(),
+		CustomerID: customerID,
+		Points:     points,
+		CreatedAt:  time.Now(),
+	}
+	s.holds[hold.ID] = hold
+
+	logger.Info("Loyalty points held",
+		zap.String("hold_id", hold.ID),
+		zap.String("customer_id", customerID),
+		zap.Int("points", points),
+		zap.Int("available", available-points),
+	)
+
+	return hold, nil
+}
+
+// Commit redeems the held points and credits the points earned by the same
+// checkout in one balance update.
+func (s *LoyaltyService) Commit(ctx context.Context, holdID, transactionID string, earned int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold, ok := s.holds[holdID]
+	if !ok {
+		return errors.NewNotFoundError("loyalty hold")
+	}
+
+	delete(s.holds, holdID)
+
+	err := s.customerService.UpdateLoyaltyPoints(ctx, hold.CustomerID, transactionID,
+		observer.LoyaltyChange{Delta: -hold.Points, Reason: observer.LoyaltyReasonRedeem},
+		observer.LoyaltyChange{Delta: earned, Reason: observer.LoyaltyReasonEarn},
+	)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Loyalty hold committed",
+		zap.String("hold_id", holdID),
+		zap.String("customer_id", hold.CustomerID),
+		zap.Int("redeemed", hold.Points),
+		zap.Int("earned", earned),
+	)
+
+	return nil
+}
+
+func (s *LoyaltyService) Release(holdID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold, ok := s.holds[holdID]
+	if !ok {
+		return
+	}
+
+	delete(s.holds, holdID)
+
+	logger.Info("Loyalty hold released",
+		zap.String("hold_id", holdID),
+		zap.String("customer_id", hold.CustomerID),
+		zap.Int("points", hold.Points),
+	)
+}
+
+func (s *LoyaltyService) AvailablePoints(ctx context.Context, customerID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customer, err := s.customerService.GetCustomer(ctx, customerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return customer.LoyaltyPoints - s.heldPoints(customerID), nil
+}
+
+func (s *LoyaltyService) heldPoints(customerID string) int {
+	held := 0
+	for _, hold := range s.holds {
+		if hold.CustomerID == customerID {
+			held += hold.Points
+		}
+	}
+	return held
+}
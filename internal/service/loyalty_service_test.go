@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/observer"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoyaltyService(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*LoyaltyService, *CustomerService) {
+		repo := repository.NewMemoryRepository()
+		subject := observer.NewSubject()
+		subject.AttachFiltered(observer.NewLoyaltyLedger(repo), observer.LoyaltyEvents...)
+		customerService := NewCustomerService(repo, subject)
+		require.NoError(t, repo.CreateCustomer(ctx, &domain.Customer{
+			ID:            "cust-loyalty",
+			Email:         "jane@example.com",
+			Name:          "Jane",
+			LoyaltyPoints: 1000,
+		}))
+		return NewLoyaltyService(customerService), customerService
+	}
+
+	t.Run("Held Points Cannot Be Double Spent", func(t *testing.T) {
+		loyalty, _ := setup(t)
+
+		_, err := loyalty.Hold(ctx, "cust-loyalty", 700)
+		require.NoError(t, err)
+
+		_, err = loyalty.Hold(ctx, "cust-loyalty", 500)
+		assert.Error(t, err)
+
+		available, err := loyalty.AvailablePoints(ctx, "cust-loyalty")
+		require.NoError(t, err)
+		assert.Equal(t, 300, available)
+	})
+
+	t.Run("Commit Deducts Held Points", func(t *testing.T) {
+		loyalty, customers := setup(t)
+
+		hold, err := loyalty.Hold(ctx, "cust-loyalty", 400)
+		require.NoError(t, err)
+		require.NoError(t, loyalty.Commit(ctx, hold.ID, "tx-loyalty", 50))
+
+		customer, err := customers.GetCustomer(ctx, "cust-loyalty")
+		require.NoError(t, err)
+		assert.Equal(t, 650, customer.LoyaltyPoints)
+
+		assert.Error(t, loyalty.Commit(ctx, hold.ID, "tx-loyalty", 50))
+	})
+
+	t.Run("Release Returns Points", func(t *testing.T) {
+		loyalty, customers := setup(t)
+
+		hold, err := loyalty.Hold(ctx, "cust-loyalty", 1000)
+		require.NoError(t, err)
+		loyalty.Release(hold.ID)
+
+		_, err = loyalty.Hold(ctx, "cust-loyalty", 1000)
+		assert.NoError(t, err)
+
+		customer, err := customers.GetCustomer(ctx, "cust-loyalty")
+		require.NoError(t, err)
+		assert.Equal(t, 1000, customer.LoyaltyPoints)
+	})
+	t.Run("Balance Changes Are Recorded In Ledger", func(t *testing.T) {
+		loyalty, customers := setup(t)
+
+		hold, err := loyalty.Hold(ctx, "cust-loyalty", 400)
+		require.NoError(t, err)
+		require.NoError(t, loyalty.Commit(ctx, hold.ID, "tx-loyalty", 50))
+		require.NoError(t, customers.AdjustLoyaltyPoints(ctx, "cust-loyalty", -150))
+
+		entries, err := customers.LoyaltyHistory(ctx, "cust-loyalty")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		assert.Equal(t, -400, entries[0].Delta)
+		assert.Equal(t, observer.LoyaltyReasonRedeem, entries[0].Reason)
+		assert.Equal(t, 600, entries[0].Balance)
+		assert.Equal(t, "tx-loyalty", entries[0].TransactionID)
+
+		assert.Equal(t, 50, entries[1].Delta)
+		assert.Equal(t, observer.LoyaltyReasonEarn, entries[1].Reason)
+		assert.Equal(t, 650, entries[1].Balance)
+
+		assert.Equal(t, -150, entries[2].Delta)
+		assert.Equal(t, observer.LoyaltyReasonAdjust, entries[2].Reason)
+		assert.Equal(t, 500, entries[2].Balance)
+	})
+
+	t.Run("Adjustment Cannot Go Below Zero", func(t *testing.T) {
+		_, customers := setup(t)
+
+		assert.Error(t, customers.AdjustLoyaltyPoints(ctx, "cust-loyalty", -1001))
+		assert.Error(t, customers.AdjustLoyaltyPoints(ctx, "cust-loyalty", 0))
+
+		entries, err := customers.LoyaltyHistory(ctx, "cust-loyalty")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
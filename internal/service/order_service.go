@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type OrderService struct {
+	repo repository.Repository
+}
+
+func NewOrderService(repo repository.Repository) *OrderService {
+	return &OrderService{repo: repo}
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, cart *domain.Cart, transactionID string) (*domain.Order, error) {
+	order := domain.NewOrder(cart, transactionID)
+
+	if err := s.repo.CreateOrder(ctx, order); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "failed to create order")
+	}
+
+	logger.Info("Order created",
+		zap.String("order_id", order.ID),
+		zap.String("customer_id", order.CustomerID),
+		zap.String("transaction_id", transactionID),
+	)
+
+	return order, nil
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, id string) (*domain.Order, error) {
+	return s.repo.GetOrder(ctx, id)
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, customerID string, limit, offset int) ([]*domain.Order, error) {
+	return s.repo.ListOrdersByCustomer(ctx, customerID, limit, offset)
+}
+
+// FindByTransaction looks through a customer's orders for the one placed by
+// transactionID.
+func (s *OrderService) FindByTransaction(ctx context.Context, customerID, transactionID string) (*domain.Order, error) {
+	const pageSize = 100
+	for offset := 0; ; offset += pageSize {
+		orders, err := s.repo.ListOrdersByCustomer(ctx, customerID, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range orders {
+			if order.TransactionID == transactionID {
+				return order, nil
+			}
+		}
+		if len(orders) < pageSize {
+			return nil, errors.NewNotFoundError("order for transaction " + transactionID)
+		}
+	}
+}
+
+func (s *OrderService) MarkPaid(ctx context.Context, order *domain.Order, total float64) error {
+	order.Total = total
+	return s.transition(ctx, order, domain.OrderStatusPaid)
+}
+
+func (s *OrderService) Cancel(ctx context.Context, order *domain.Order) error {
+	return s.transition(ctx, order, domain.OrderStatusCancelled)
+}
+
+func (s *OrderService) MarkRefunded(ctx context.Context, order *domain.Order) error {
+	return s.transition(ctx, order, domain.OrderStatusRefunded)
+}
+
+func (s *OrderService) Fulfill(ctx context.Context, orderID, carrier, trackingNumber string) (*domain.Order, error) {
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Fulfillments = append(order.Fulfillments, domain.Fulfillment{
+		ID:             domain.NewID(),
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+		CreatedAt:      time.Now(),
+	})
+
+	if err := s.transition(ctx, order, domain.OrderStatusFulfilled); err != nil {
+		order.Fulfillments = order.Fulfillments[:len(order.Fulfillments)-1]
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) Complete(ctx context.Context, orderID string) (*domain.Order, error) {
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transition(ctx, order, domain.OrderStatusCompleted); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *OrderService) transition(ctx context.Context, order *domain.Order, status domain.OrderStatus) error {
+	if !order.CanTransitionTo(status) {
+		return errors.NewValidationError(
+			fmt.Sprintf("order %s cannot move from %s to %s", order.ID, order.Status, status),
+		)
+	}
+
+	previous := order.Status
+	order.Status = status
+	order.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateOrder(ctx, order); err != nil {
+		order.Status = previous
+		return err
+	}
+
+	logger.Info("Order status changed",
+		zap.String("order_id", order.ID),
+		zap.String("from", string(previous)),
+		zap.String("to", string(status)),
+	)
+
+	return nil
+}
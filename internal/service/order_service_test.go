@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderService(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*OrderService, *domain.Order) {
+		orders := NewOrderService(repository.NewMemoryRepository())
+		cart := &domain.Cart{ID: "cart-order", CustomerID: "cust-order"}
+		cart.AddItem(domain.Product{ID: "prod-order", Name: "Widget", Price: 20}, 2)
+
+		order, err := orders.CreateOrder(ctx, cart, "tx-order")
+		require.NoError(t, err)
+		return orders, order
+	}
+
+	t.Run("Follows Lifecycle", func(t *testing.T) {
+		orders, order := setup(t)
+		assert.Equal(t, domain.OrderStatusCreated, order.Status)
+		assert.Equal(t, 40.0, order.Subtotal)
+
+		require.NoError(t, orders.MarkPaid(ctx, order, 43.2))
+
+		fulfilled, err := orders.Fulfill(ctx, order.ID, "DHL", "TRACK-1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusFulfilled, fulfilled.Status)
+		assert.Len(t, fulfilled.Fulfillments, 1)
+
+		completed, err := orders.Complete(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusCompleted, completed.Status)
+		assert.Equal(t, 43.2, completed.Total)
+	})
+
+	t.Run("Rejects Invalid Transition", func(t *testing.T) {
+		orders, order := setup(t)
+
+		_, err := orders.Fulfill(ctx, order.ID, "DHL", "TRACK-1")
+		assert.Error(t, err)
+
+		require.NoError(t, orders.Cancel(ctx, order))
+		assert.Error(t, orders.MarkPaid(ctx, order, 40))
+
+		stored, err := orders.GetOrder(ctx, order.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.OrderStatusCancelled, stored.Status)
+		assert.Empty(t, stored.Fulfillments)
+	})
+}
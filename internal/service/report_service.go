@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/money"
+)
+
+// ReportService builds finance rollups from stored transactions.
+type ReportService struct {
+	repo repository.Repository
+}
+
+func NewReportService(repo repository.Repository) *ReportService {
+	return &ReportService{repo: repo}
+}
+
+type SalesTotals struct {
+	Count   int     `json:"count"`
+	Revenue float64 `json:"revenue"`
+}
+
+type SalesReport struct {
+	Date              string                  `json:"date"`
+	Count             int                     `json:"count"`
+	Revenue           float64                 `json:"revenue"`
+	AverageOrderValue float64                 `json:"average_order_value"`
+	Tax               float64                 `json:"tax"`
+	Discount          float64                 `json:"discount"`
+	CashbackRedeemed  float64                 `json:"cashback_redeemed"`
+	CashbackEarned    float64                 `json:"cashback_earned"`
+	Refunded          float64                 `json:"refunded"`
+	ByMethod          map[string]*SalesTotals `json:"by_payment_method"`
+}
+
+// DailySalesReport totals the completed transactions created on date's
+// calendar day, in date's location. Revenue is what was charged; discount
+// includes loyalty point discounts. Converted transactions count in the base
+// currency at the rate locked at checkout.
+//
+// The amounts other than the subtotal live in each transaction's payment
+// details, so the day's transactions are summed here rather than by the
+// storage backend.
+func (s *ReportService) DailySalesReport(ctx context.Context, date time.Time) (*SalesReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	report := &SalesReport{
+		Date:     start.Format("2006-01-02"),
+		ByMethod: make(map[string]*SalesTotals),
+	}
+
+	filter := repository.TransactionFilter{
+		Status:        domain.TransactionStatusCompleted,
+		CreatedAfter:  start,
+		CreatedBefore: start.AddDate(0, 0, 1),
+	}
+
+	err := eachTransaction(ctx, s.repo, filter, func(transaction *domain.Transaction) {
+		f := transaction.Financials()
+		rate := 1.0
+		if transaction.IsConverted() {
+			rate = transaction.ExchangeRate
+		}
+		revenue := money.Round(f.Total * rate)
+
+		report.Count++
+		report.Revenue = money.Round(report.Revenue + revenue)
+		report.Tax = money.Round(report.Tax + f.Tax*rate)
+		report.Discount = money.Round(report.Discount + (f.Discount+f.LoyaltyDiscount)*rate)
+		report.CashbackRedeemed = money.Round(report.CashbackRedeemed + f.CashbackRedeemed*rate)
+		report.CashbackEarned = money.Round(report.CashbackEarned + cashbackEarned(transaction)*rate)
+		report.Refunded = money.Round(report.Refunded + f.Refunded*rate)
+
+		totals := report.ByMethod[transaction.PaymentMethod]
+		if totals == nil {
+			totals = &SalesTotals{}
+			report.ByMethod[transaction.PaymentMethod] = totals
+		}
+		totals.Count++
+		totals.Revenue = money.Round(totals.Revenue + revenue)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Count > 0 {
+		report.AverageOrderValue = money.Round(report.Revenue / float64(report.Count))
+	}
+
+	return report, nil
+}
+
+func cashbackEarned(transaction *domain.Transaction) float64 {
+	switch v := transaction.PaymentDetails["cashback_amount"].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	default:
+		return 0
+	}
+}
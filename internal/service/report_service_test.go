@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportServiceDailySalesReport(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	reports := NewReportService(repo)
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, tx := range []*domain.Transaction{
+		{ID: "tx-1", Amount: 100, Status: domain.TransactionStatusCompleted, PaymentMethod: "credit_card", CreatedAt: day.Add(9 * time.Hour),
+			PaymentDetails: map[string]interface{}{"discount_amount": 10.0, "tax_amount": 7.2, "cashback_amount": 1.5}},
+		{ID: "tx-2", Amount: 50, Status: domain.TransactionStatusCompleted, PaymentMethod: "paypal", CreatedAt: day.Add(23 * time.Hour),
+			PaymentDetails: map[string]interface{}{"tax_amount": 4.0, "loyalty_discount": 5.0}},
+		{ID: "tx-failed", Amount: 70, Status: domain.TransactionStatusFailed, PaymentMethod: "paypal", CreatedAt: day.Add(time.Hour)},
+		{ID: "tx-next-day", Amount: 30, Status: domain.TransactionStatusCompleted, PaymentMethod: "paypal", CreatedAt: day.AddDate(0, 0, 1)},
+	} {
+		tx.CustomerID = "cust-default"
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+
+	t.Run("Totals The Day's Completed Sales", func(t *testing.T) {
+		report, err := reports.DailySalesReport(ctx, day.Add(15*time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, "2026-03-10", report.Date)
+		assert.Equal(t, 2, report.Count)
+		assert.Equal(t, 146.2, report.Revenue)
+		assert.Equal(t, 73.1, report.AverageOrderValue)
+		assert.Equal(t, 11.2, report.Tax)
+		assert.Equal(t, 15.0, report.Discount)
+		assert.Equal(t, 1.5, report.CashbackEarned)
+		assert.Equal(t, &SalesTotals{Count: 1, Revenue: 97.2}, report.ByMethod["credit_card"])
+		assert.Equal(t, &SalesTotals{Count: 1, Revenue: 49}, report.ByMethod["paypal"])
+	})
+
+	t.Run("Empty Day", func(t *testing.T) {
+		report, err := reports.DailySalesReport(ctx, day.AddDate(0, 0, -1))
+		require.NoError(t, err)
+		assert.Zero(t, report.Count)
+		assert.Zero(t, report.AverageOrderValue)
+	})
+}
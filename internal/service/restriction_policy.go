@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/pkg/errors"
+)
+
+type ProductRestriction struct {
+	Category   string
+	ProductIDs []string
+	Regions    []string
+}
+
+type RestrictionPolicy struct {
+	rules []ProductRestriction
+}
+
+func NewRestrictionPolicy(rules []ProductRestriction) *RestrictionPolicy {
+	return &RestrictionPolicy{rules: rules}
+}
+
+func (p *RestrictionPolicy) CheckProduct(customer *domain.Customer, product *domain.Product) error {
+	if p == nil || customer == nil || product == nil {
+		return nil
+	}
+
+	for _, rule := range p.rules {
+		if !rule.matchesProduct(product) {
+			continue
+		}
+
+		if region, blocked := rule.blockedRegion(customer.Address); blocked {
+			return errors.NewValidationError(
+				fmt.Sprintf("product %s (%s) cannot be sold to customers in %s", product.Name, product.ID, region),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (p *RestrictionPolicy) CheckCart(customer *domain.Customer, cart *domain.Cart) error {
+	for _, item := range cart.Items {
+		product := item.Product
+		if err := p.CheckProduct(customer, &product); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r ProductRestriction) matchesProduct(product *domain.Product) bool {
+	if r.Category != "" && strings.EqualFold(r.Category, product.Category) {
+		return true
+	}
+
+	for _, id := range r.ProductIDs {
+		if id == product.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r ProductRestriction) blockedRegion(address domain.Address) (string, bool) {
+	for _, region := range r.Regions {
+		if address.State != "" && strings.EqualFold(region, address.State) {
+			return address.State, true
+		}
+		if address.Country != "" && strings.EqualFold(region, address.Country) {
+			return address.Country, true
+		}
+	}
+
+	return "", false
+}
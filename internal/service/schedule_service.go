@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// PaymentProvider builds the payment instance that charges the customer's
+// saved payment method for an installment or a subscription renewal.
+type PaymentProvider func(ctx context.Context, customerID, method, savedMethodID string) (payment.Payment, error)
+
+type ScheduleService struct {
+	repo       repository.Repository
+	paymentFor PaymentProvider
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+func NewScheduleService(repo repository.Repository) *ScheduleService {
+	return &ScheduleService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+func (s *ScheduleService) SetPaymentProvider(provider PaymentProvider) {
+	s.paymentFor = provider
+}
+
+func (s *ScheduleService) CreateSchedule(ctx context.Context, schedule *domain.PaymentSchedule) error {
+	if err := s.repo.CreatePaymentSchedule(ctx, schedule); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternalError, "failed to save payment schedule")
+	}
+
+	logger.Info("Payment schedule created",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("customer_id", schedule.CustomerID),
+		zap.Int("installments", schedule.Installments),
+	)
+
+	return nil
+}
+
+func (s *ScheduleService) GetSchedule(ctx context.Context, id string) (*domain.PaymentSchedule, error) {
+	return s.repo.GetPaymentSchedule(ctx, id)
+}
+
+func (s *ScheduleService) ListSchedules(ctx context.Context, limit, offset int) ([]*domain.PaymentSchedule, error) {
+	return s.repo.ListPaymentSchedules(ctx, limit, offset)
+}
+
+// ChargeNextInstallment charges the first unpaid installment of the schedule
+// and records it as its own transaction. A failed charge marks the
+// installment failed so it can be retried.
+func (s *ScheduleService) ChargeNextInstallment(ctx context.Context, scheduleID string) (*domain.PaymentSchedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedule, err := s.repo.GetPaymentSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	installment := schedule.NextInstallment()
+	if installment == nil {
+		return nil, errors.NewValidationError("payment schedule is already fully paid")
+	}
+
+	if s.paymentFor == nil {
+		return nil, errors.New(errors.ErrCodeInternalError, "no payment provider configured for schedules")
+	}
+
+	paymentInstance, err := s.paymentFor(ctx, schedule.CustomerID, schedule.PaymentMethod, schedule.SavedMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Charging installment",
+		zap.String("schedule_id", schedule.ID),
+		zap.Int("installment", installment.InstallmentNumber),
+		zap.Float64("amount", installment.Amount),
+	)
+
+	result, err := payment.ProcessAmount(ctx, paymentInstance, installment.Amount)
+	now := s.now()
+	schedule.UpdatedAt = now
+
+	if err != nil {
+		installment.Status = domain.InstallmentStatusFailed
+		if updateErr := s.repo.UpdatePaymentSchedule(ctx, schedule); updateErr != nil {
+			logger.Error("Failed to save payment schedule",
+				zap.Error(updateErr),
+				zap.String("schedule_id", schedule.ID),
+			)
+		}
+		return nil, errors.Wrap(err, errors.ErrCodePaymentFailed, "installment payment failed")
+	}
+
+	transaction := &domain.Transaction{
+		ID:             domain.NewID(),
+		CustomerID:     schedule.CustomerID,
+		Amount:         installment.Amount,
+		Status:         domain.TransactionStatusCompleted,
+		PaymentMethod:  schedule.PaymentMethod,
+		Strategy:       "deferred",
+		PaymentDetails: result.Metadata,
+		Metadata: map[string]interface{}{
+			"schedule_id":    schedule.ID,
+			"installment":    installment.InstallmentNumber,
+			"charged_amount": result.Amount,
+		},
+		ProcessedAt: now,
+		CreatedAt:   now,
+	}
+
+	if err := s.repo.CreateTransaction(ctx, transaction); err != nil {
+		logger.Error("Failed to save installment transaction",
+			zap.Error(err),
+			zap.String("schedule_id", schedule.ID),
+		)
+	}
+
+	installment.MarkPaid(transaction.ID, now)
+
+	if err := s.repo.UpdatePaymentSchedule(ctx, schedule); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternalError, "installment charged but schedule could not be saved")
+	}
+
+	logger.Info("Installment charged",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("transaction_id", transaction.ID),
+		zap.Int("installment", installment.InstallmentNumber),
+	)
+
+	return schedule, nil
+}
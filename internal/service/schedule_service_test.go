@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleServiceChargeNextInstallment(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	setup := func(t *testing.T, balance float64) (*ScheduleService, *domain.PaymentSchedule) {
+		repo := repository.NewMemoryRepository()
+		schedules := NewScheduleService(repo)
+		schedules.SetPaymentProvider(func(ctx context.Context, customerID, method, savedMethodID string) (payment.Payment, error) {
+			return payment.NewGiftCardPayment("ABCD-EFGH-JKLM-1234", balance)
+		})
+
+		schedule := &domain.PaymentSchedule{
+			ID:            "sched-1",
+			CustomerID:    "cust-default",
+			PaymentMethod: "gift_card",
+			TotalAmount:   180,
+			Installments:  3,
+			CreatedAt:     start,
+		}
+		for i := 0; i < 3; i++ {
+			schedule.Payments = append(schedule.Payments, domain.ScheduledInstallment{
+				InstallmentNumber: i + 1,
+				Amount:            60,
+				DueDate:           start.AddDate(0, i, 0),
+				Status:            domain.InstallmentStatusPending,
+			})
+		}
+		schedule.Payments[0].MarkPaid("tx-checkout", start)
+
+		require.NoError(t, schedules.CreateSchedule(ctx, schedule))
+		return schedules, schedule
+	}
+
+	t.Run("Charges Installments In Order", func(t *testing.T) {
+		schedules, schedule := setup(t, 500)
+
+		updated, err := schedules.ChargeNextInstallment(ctx, schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.PaidCount())
+		assert.Equal(t, 60.0, updated.RemainingAmount())
+		assert.NotEmpty(t, updated.Payments[1].TransactionID)
+
+		updated, err = schedules.ChargeNextInstallment(ctx, schedule.ID)
+		require.NoError(t, err)
+		assert.True(t, updated.IsComplete())
+
+		_, err = schedules.ChargeNextInstallment(ctx, schedule.ID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Marks Failed Installment For Retry", func(t *testing.T) {
+		schedules, schedule := setup(t, 10)
+
+		_, err := schedules.ChargeNextInstallment(ctx, schedule.ID)
+		require.Error(t, err)
+
+		stored, err := schedules.GetSchedule(ctx, schedule.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InstallmentStatusFailed, stored.Payments[1].Status)
+		assert.Equal(t, &stored.Payments[1], stored.NextInstallment())
+	})
+}
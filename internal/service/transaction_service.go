@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/repository"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
@@ -36,6 +38,167 @@ func (s *TransactionService) GetTransaction(ctx context.Context, id string) (*do
 	return s.repo.GetTransaction(ctx, id)
 }
 
+func (s *TransactionService) GetTransactionByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	return s.repo.GetTransactionByIdempotencyKey(ctx, key)
+}
+
+func (s *TransactionService) AbandonStaleTransaction(ctx context.Context, key string, staleBefore time.Time) error {
+	return s.repo.AbandonStaleTransaction(ctx, key, staleBefore)
+}
+
+func (s *TransactionService) UpdateTransaction(ctx context.Context, transaction *domain.Transaction) error {
+	return s.repo.UpdateTransaction(ctx, transaction)
+}
+
+func (s *TransactionService) CreateReceipt(ctx context.Context, receipt *domain.Receipt) error {
+	return s.repo.CreateReceipt(ctx, receipt)
+}
+
+func (s *TransactionService) GetReceipt(ctx context.Context, id string) (*domain.Receipt, error) {
+	return s.repo.GetReceipt(ctx, id)
+}
+
+func (s *TransactionService) GetReceiptByTransaction(ctx context.Context, transactionID string) (*domain.Receipt, error) {
+	return s.repo.GetReceiptByTransaction(ctx, transactionID)
+}
+
 func (s *TransactionService) GetCustomerTransactions(ctx context.Context, customerID string, limit, offset int) ([]*domain.Transaction, error) {
 	return s.repo.ListTransactionsByCustomer(ctx, customerID, limit, offset)
 }
+
+// spendStatuses are the transaction states that hold the customer's money:
+// completed charges plus authorizations and escrow holds not yet settled.
+var spendStatuses = []domain.TransactionStatus{
+	domain.TransactionStatusCompleted,
+	domain.TransactionStatusAuthorized,
+	domain.TransactionStatusHeld,
+}
+
+// CustomerSpend sums what a customer was charged, net of refunds, across
+// transactions in spendStatuses created at or after since.
+func (s *TransactionService) CustomerSpend(ctx context.Context, customerID string, since time.Time) (float64, error) {
+	total := 0.0
+	for _, status := range spendStatuses {
+		filter := repository.TransactionFilter{
+			CustomerID:   customerID,
+			Status:       status,
+			CreatedAfter: since,
+		}
+
+		err := s.eachTransaction(ctx, filter, func(transaction *domain.Transaction) {
+			total += transaction.Financials().Net
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+type RefundTotals struct {
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+func (t *RefundTotals) add(amount float64) {
+	t.Count++
+	t.Amount = money.Round((t.Amount + amount))
+}
+
+type RefundReport struct {
+	From     time.Time                `json:"from"`
+	To       time.Time                `json:"to"`
+	Total    RefundTotals             `json:"total"`
+	ByReason map[string]*RefundTotals `json:"by_reason"`
+	ByMethod map[string]*RefundTotals `json:"by_payment_method"`
+}
+
+// RefundReport summarizes refunds created in [from, to) by reason and by the
+// original payment method. Refunds recorded before reasons existed are
+// grouped under "unspecified".
+func (s *TransactionService) RefundReport(ctx context.Context, from, to time.Time) (*RefundReport, error) {
+	report := &RefundReport{
+		From:     from,
+		To:       to,
+		ByReason: make(map[string]*RefundTotals),
+		ByMethod: make(map[string]*RefundTotals),
+	}
+
+	filter := repository.TransactionFilter{
+		Status:        domain.TransactionStatusRefunded,
+		CreatedAfter:  from,
+		CreatedBefore: to,
+	}
+
+	err := s.eachTransaction(ctx, filter, func(transaction *domain.Transaction) {
+		// Fully refunded originals share the status; only refund records count.
+		if transaction.RefundOf() == "" {
+			return
+		}
+
+		reason := string(transaction.RefundReason())
+		if reason == "" {
+			reason = "unspecified"
+		}
+
+		report.Total.add(transaction.Amount)
+		refundTotals(report.ByReason, reason).add(transaction.Amount)
+		refundTotals(report.ByMethod, transaction.PaymentMethod).add(transaction.Amount)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func refundTotals(totals map[string]*RefundTotals, key string) *RefundTotals {
+	if totals[key] == nil {
+		totals[key] = &RefundTotals{}
+	}
+	return totals[key]
+}
+
+// ExportTransactions calls fn for every transaction matching filter, newest
+// first, stopping at the first error fn returns.
+func (s *TransactionService) ExportTransactions(ctx context.Context, filter repository.TransactionFilter, fn func(*domain.Transaction) error) error {
+	return pageTransactions(ctx, s.repo, filter, fn)
+}
+
+func (s *TransactionService) eachTransaction(ctx context.Context, filter repository.TransactionFilter, fn func(*domain.Transaction)) error {
+	return eachTransaction(ctx, s.repo, filter, fn)
+}
+
+func eachTransaction(ctx context.Context, repo repository.Repository, filter repository.TransactionFilter, fn func(*domain.Transaction)) error {
+	return pageTransactions(ctx, repo, filter, func(transaction *domain.Transaction) error {
+		fn(transaction)
+		return nil
+	})
+}
+
+// pageTransactions pages through the transactions matching filter. Each page
+// resumes after the last row seen rather than at an offset, so rows sharing a
+// timestamp or inserted meanwhile are neither skipped nor repeated.
+func pageTransactions(ctx context.Context, repo repository.Repository, filter repository.TransactionFilter, fn func(*domain.Transaction) error) error {
+	const pageSize = 100
+
+	for {
+		transactions, err := repo.ListTransactions(ctx, filter, pageSize, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, transaction := range transactions {
+			if err := fn(transaction); err != nil {
+				return err
+			}
+		}
+
+		if len(transactions) < pageSize {
+			return nil
+		}
+		last := transactions[len(transactions)-1]
+		filter.After = &repository.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionServiceRefundReport(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	transactions := NewTransactionService(repo)
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for _, tx := range []*domain.Transaction{
+		{ID: "orig-1", Amount: 80, Status: domain.TransactionStatusRefunded, PaymentMethod: "credit_card", CreatedAt: day},
+		{ID: "ref-1", Amount: 80, Status: domain.TransactionStatusRefunded, PaymentMethod: "credit_card", CreatedAt: day,
+			Metadata: map[string]interface{}{"refund_of": "orig-1", "refund_reason": "defective"}},
+		{ID: "ref-2", Amount: 15.5, Status: domain.TransactionStatusRefunded, PaymentMethod: "paypal", CreatedAt: day,
+			Metadata: map[string]interface{}{"refund_of": "orig-2", "refund_reason": "defective"}},
+		{ID: "ref-3", Amount: 20, Status: domain.TransactionStatusRefunded, PaymentMethod: "paypal", CreatedAt: day,
+			Metadata: map[string]interface{}{"refund_of": "orig-3"}},
+		{ID: "ref-outside", Amount: 99, Status: domain.TransactionStatusRefunded, PaymentMethod: "paypal", CreatedAt: day.AddDate(0, 1, 0),
+			Metadata: map[string]interface{}{"refund_of": "orig-4", "refund_reason": "fraud"}},
+	} {
+		tx.CustomerID = "cust-default"
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+
+	report, err := transactions.RefundReport(ctx, day.AddDate(0, 0, -1), day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+
+	assert.Equal(t, RefundTotals{Count: 3, Amount: 115.5}, report.Total)
+	assert.Equal(t, &RefundTotals{Count: 2, Amount: 95.5}, report.ByReason["defective"])
+	assert.Equal(t, &RefundTotals{Count: 1, Amount: 20}, report.ByReason["unspecified"])
+	assert.Equal(t, &RefundTotals{Count: 2, Amount: 35.5}, report.ByMethod["paypal"])
+	assert.NotContains(t, report.ByReason, "fraud")
+}
+
+func TestTransactionServiceCustomerSpend(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	transactions := NewTransactionService(repo)
+	now := time.Now()
+
+	for _, tx := range []*domain.Transaction{
+		{ID: "completed", Amount: 40, Status: domain.TransactionStatusCompleted},
+		{ID: "authorized", Amount: 25, Status: domain.TransactionStatusAuthorized},
+		{ID: "held", Amount: 10, Status: domain.TransactionStatusHeld},
+		{ID: "failed", Amount: 99, Status: domain.TransactionStatusFailed},
+		{ID: "old", Amount: 500, Status: domain.TransactionStatusCompleted, CreatedAt: now.AddDate(0, -2, 0)},
+	} {
+		tx.CustomerID = "cust-spend"
+		tx.PaymentMethod = "credit_card"
+		if tx.CreatedAt.IsZero() {
+			tx.CreatedAt = now
+		}
+		require.NoError(t, repo.CreateTransaction(ctx, tx))
+	}
+
+	spend, err := transactions.CustomerSpend(ctx, "cust-spend", now.AddDate(0, 0, -30))
+	require.NoError(t, err)
+	assert.InDelta(t, 75.0, spend, 0.001)
+}
+
+func TestTransactionServiceExportTransactions(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryRepository()
+	transactions := NewTransactionService(repo)
+	day := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 250; i++ {
+		require.NoError(t, repo.CreateTransaction(ctx, &domain.Transaction{
+			ID:         fmt.Sprintf("tx-%03d", i),
+			CustomerID: "cust-default",
+			Amount:     10,
+			Status:     domain.TransactionStatusCompleted,
+			CreatedAt:  day,
+		}))
+	}
+
+	t.Run("Exports Rows Sharing A Timestamp Once", func(t *testing.T) {
+		seen := make(map[string]int)
+		err := transactions.ExportTransactions(ctx, repository.TransactionFilter{}, func(tx *domain.Transaction) error {
+			seen[tx.ID]++
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, seen, 250)
+		for id, count := range seen {
+			assert.Equal(t, 1, count, id)
+		}
+	})
+
+	t.Run("Ignores Newer Rows Inserted Mid Export", func(t *testing.T) {
+		seen := make(map[string]int)
+		inserted := false
+		err := transactions.ExportTransactions(ctx, repository.TransactionFilter{}, func(tx *domain.Transaction) error {
+			seen[tx.ID]++
+			if !inserted {
+				inserted = true
+				return repo.CreateTransaction(ctx, &domain.Transaction{
+					ID:         "tx-late",
+					CustomerID: "cust-default",
+					Amount:     10,
+					Status:     domain.TransactionStatusCompleted,
+					CreatedAt:  day.Add(time.Hour),
+				})
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, seen, 250)
+		assert.NotContains(t, seen, "tx-late")
+		for id, count := range seen {
+			assert.Equal(t, 1, count, id)
+		}
+	})
+}
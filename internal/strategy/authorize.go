@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/validator"
+	"go.uber.org/zap"
+)
+
+type AuthorizeOnlyStrategy struct {
+	minAmount float64
+	maxAmount float64
+}
+
+func NewAuthorizeOnlyStrategy(minAmount, maxAmount float64) *AuthorizeOnlyStrategy {
+	return &AuthorizeOnlyStrategy{
+		minAmount: minAmount,
+		maxAmount: maxAmount,
+	}
+}
+
+func (s *AuthorizeOnlyStrategy) Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Executing authorize-only payment strategy",
+		zap.String("payment_type", payment.GetType()),
+		zap.Float64("amount", amount),
+	)
+
+	if payment.GetType() != "credit_card" {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("authorize strategy is only supported for credit cards, got %s", payment.GetType()),
+		)
+	}
+
+	if err := validateLimits(ctx, s, amount); err != nil {
+		return nil, err
+	}
+
+	request.AuthorizeOnly = true
+	result, err := payment.Process(ctx, request)
+	if err != nil {
+		logger.Error("Payment authorization failed",
+			zap.Error(err),
+			zap.Float64("amount", amount),
+		)
+		return nil, errors.Wrap(err, errors.ErrCodePaymentFailed, "payment authorization failed")
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Strategy = s.GetName()
+	result.Metadata["payment_strategy"] = result.Strategy
+	result.Metadata["capture_pending"] = true
+	result.Metadata["authorized_amount"] = result.Amount
+
+	logger.Info("Payment authorized, awaiting capture",
+		zap.String("transaction_id", result.TransactionID),
+		zap.Float64("authorized_amount", result.Amount),
+	)
+
+	return result, nil
+}
+
+func (s *AuthorizeOnlyStrategy) GetName() string {
+	return "authorize"
+}
+
+func (s *AuthorizeOnlyStrategy) ValidateAmount(amount float64) error {
+	v := validator.NewAmountValidator()
+	return v.Validate(amount, s.minAmount, s.maxAmount)
+}
@@ -3,6 +3,7 @@ package strategy
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/pkg/errors"
@@ -11,11 +12,16 @@ import (
 	"go.uber.org/zap"
 )
 
+// ScheduleMetadataKey holds the *DeferredPaymentSchedule in the result
+// metadata so the caller can persist the remaining installments.
+const ScheduleMetadataKey = "payment_schedule"
+
 type DeferredPaymentStrategy struct {
 	minAmount    float64
 	maxAmount    float64
 	installments int
 	interestRate float64
+	now          func() time.Time
 }
 
 func NewDeferredPaymentStrategy(minAmount, maxAmount float64, installments int, interestRate float64) *DeferredPaymentStrategy {
@@ -24,21 +30,24 @@ func NewDeferredPaymentStrategy(minAmount, maxAmount float64, installments int,
 		maxAmount:    maxAmount,
 		installments: installments,
 		interestRate: interestRate,
+		now:          time.Now,
 	}
 }
 
-func (s *DeferredPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, amount float64) (*payment.PaymentResult, error) {
+func (s *DeferredPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Executing deferred payment strategy",
 		zap.String("payment_type", payment.GetType()),
 		zap.Float64("amount", amount),
 		zap.Int("installments", s.installments),
 	)
 
-	if err := s.ValidateAmount(amount); err != nil {
+	if err := validateLimits(ctx, s, amount); err != nil {
 		return nil, err
 	}
 
-	schedule := CreateDeferredSchedule(amount, s.installments, s.interestRate)
+	schedule := CreateDeferredSchedule(amount, s.installments, s.interestRate, s.now())
 
 	firstInstallment := schedule.Payments[0].Amount
 
@@ -48,7 +57,7 @@ func (s *DeferredPaymentStrategy) Execute(ctx context.Context, payment payment.P
 		zap.Int("installments", s.installments),
 	)
 
-	result, err := payment.Process(ctx, firstInstallment)
+	result, err := payment.Process(ctx, request.WithAmount(firstInstallment))
 	if err != nil {
 		logger.Error("Deferred payment first installment failed",
 			zap.Error(err),
@@ -57,16 +66,20 @@ func (s *DeferredPaymentStrategy) Execute(ctx context.Context, payment payment.P
 		return nil, errors.Wrap(err, errors.ErrCodePaymentFailed, "deferred payment processing failed")
 	}
 
+	schedule.Payments[0].MarkPaid(result.TransactionID, s.now())
+
 	if result.Metadata == nil {
 		result.Metadata = make(map[string]interface{})
 	}
-	result.Metadata["payment_strategy"] = "deferred"
+	result.Strategy = "deferred"
+	result.Metadata["payment_strategy"] = result.Strategy
 	result.Metadata["schedule_id"] = schedule.ID
 	result.Metadata["total_amount"] = schedule.TotalAmount
 	result.Metadata["installments"] = s.installments
 	result.Metadata["interest_rate"] = s.interestRate
 	result.Metadata["first_installment"] = firstInstallment
 	result.Metadata["remaining_installments"] = s.installments - 1
+	result.Metadata[ScheduleMetadataKey] = schedule
 
 	result.OriginalAmount = amount
 	result.Amount = firstInstallment
@@ -81,6 +94,16 @@ func (s *DeferredPaymentStrategy) Execute(ctx context.Context, payment payment.P
 	return result, nil
 }
 
+// Preview returns the full installment plan for amount without charging
+// anything, so customers can see every installment before committing.
+func (s *DeferredPaymentStrategy) Preview(amount float64) (*DeferredPaymentSchedule, error) {
+	if err := s.ValidateAmount(amount); err != nil {
+		return nil, err
+	}
+
+	return CreateDeferredSchedule(amount, s.installments, s.interestRate, s.now()), nil
+}
+
 func (s *DeferredPaymentStrategy) GetName() string {
 	return fmt.Sprintf("deferred_%d_installments", s.installments)
 }
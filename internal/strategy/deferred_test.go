@@ -0,0 +1,35 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferredPaymentStrategyPreview(t *testing.T) {
+	s := NewDeferredPaymentStrategy(100, 10000, 4, 10)
+	start := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return start }
+
+	t.Run("Returns Full Amortization", func(t *testing.T) {
+		schedule, err := s.Preview(1000)
+		require.NoError(t, err)
+
+		require.Len(t, schedule.Payments, 4)
+		for i, p := range schedule.Payments {
+			assert.Equal(t, i+1, p.InstallmentNumber)
+			assert.InDelta(t, 275.0, p.Amount, 0.001)
+			assert.Equal(t, start.AddDate(0, i, 0), p.DueDate)
+			assert.Nil(t, p.PaidAt)
+		}
+		assert.Equal(t, 1100.0, schedule.TotalWithInterest())
+		assert.Equal(t, 0, schedule.PaidCount())
+	})
+
+	t.Run("Validates Amount", func(t *testing.T) {
+		_, err := s.Preview(50)
+		assert.Error(t, err)
+	})
+}
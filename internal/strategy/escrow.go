@@ -0,0 +1,72 @@
+package strategy
+
+import (
+	"context"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// EscrowPaymentStrategy takes the payment but holds the funds until delivery
+// is confirmed; service.EscrowService releases or refunds them later.
+type EscrowPaymentStrategy struct {
+	minAmount float64
+	maxAmount float64
+}
+
+func NewEscrowPaymentStrategy(minAmount, maxAmount float64) *EscrowPaymentStrategy {
+	return &EscrowPaymentStrategy{
+		minAmount: minAmount,
+		maxAmount: maxAmount,
+	}
+}
+
+func (s *EscrowPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Executing escrow payment strategy",
+		zap.String("payment_type", payment.GetType()),
+		zap.Float64("amount", amount),
+	)
+
+	if err := validateLimits(ctx, s, amount); err != nil {
+		return nil, err
+	}
+
+	result, err := payment.Process(ctx, request)
+	if err != nil {
+		logger.Error("Escrow payment failed",
+			zap.Error(err),
+			zap.Float64("amount", amount),
+		)
+		return nil, errors.Wrap(err, errors.ErrCodePaymentFailed, "escrow payment processing failed")
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Strategy = s.GetName()
+	result.Metadata["payment_strategy"] = result.Strategy
+	result.Metadata["escrow_status"] = domain.EscrowStatusHeld
+	result.Metadata["escrow_amount"] = result.Amount
+
+	logger.Info("Payment held in escrow, awaiting delivery confirmation",
+		zap.String("transaction_id", result.TransactionID),
+		zap.Float64("held_amount", result.Amount),
+	)
+
+	return result, nil
+}
+
+func (s *EscrowPaymentStrategy) GetName() string {
+	return "escrow"
+}
+
+func (s *EscrowPaymentStrategy) ValidateAmount(amount float64) error {
+	v := validator.NewAmountValidator()
+	return v.Validate(amount, s.minAmount, s.maxAmount)
+}
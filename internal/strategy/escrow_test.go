@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscrowPaymentStrategy(t *testing.T) {
+	strategy := NewEscrowPaymentStrategy(100.0, 20000.0)
+
+	basePayment, _ := payment.NewCreditCardPayment(
+		"4532015112830366",
+		"John Doe",
+		"12/30",
+		"123",
+	)
+
+	t.Run("Holds The Payment", func(t *testing.T) {
+		result, err := strategy.Execute(context.Background(), basePayment, payment.PaymentRequest{Amount: 1500.00})
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "escrow", result.Strategy)
+		assert.Equal(t, domain.EscrowStatusHeld, result.Metadata["escrow_status"])
+		assert.Equal(t, 1500.00, result.Metadata["escrow_amount"])
+	})
+
+	t.Run("Amount Outside Escrow Limits", func(t *testing.T) {
+		_, err := strategy.Execute(context.Background(), basePayment, payment.PaymentRequest{Amount: 50.00})
+		assert.ErrorContains(t, err, "below minimum 100.00")
+	})
+}
@@ -22,17 +22,19 @@ func NewInstantPaymentStrategy(minAmount, maxAmount float64) *InstantPaymentStra
 	}
 }
 
-func (s *InstantPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, amount float64) (*payment.PaymentResult, error) {
+func (s *InstantPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
 	logger.Info("Executing instant payment strategy",
 		zap.String("payment_type", payment.GetType()),
 		zap.Float64("amount", amount),
 	)
 
-	if err := s.ValidateAmount(amount); err != nil {
+	if err := validateLimits(ctx, s, amount); err != nil {
 		return nil, err
 	}
 
-	result, err := payment.Process(ctx, amount)
+	result, err := payment.Process(ctx, request)
 	if err != nil {
 		logger.Error("Instant payment failed",
 			zap.Error(err),
@@ -44,7 +46,8 @@ func (s *InstantPaymentStrategy) Execute(ctx context.Context, payment payment.Pa
 	if result.Metadata == nil {
 		result.Metadata = make(map[string]interface{})
 	}
-	result.Metadata["payment_strategy"] = "instant"
+	result.Strategy = s.GetName()
+	result.Metadata["payment_strategy"] = result.Strategy
 
 	logger.Info("Instant payment completed successfully",
 		zap.String("transaction_id", result.TransactionID),
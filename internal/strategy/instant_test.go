@@ -15,27 +15,28 @@ func TestInstantPaymentStrategy(t *testing.T) {
 	basePayment, _ := payment.NewCreditCardPayment(
 		"4532015112830366",
 		"John Doe",
-		"12/25",
+		"12/30",
 		"123",
 	)
 
 	t.Run("Successful Payment", func(t *testing.T) {
 		ctx := context.Background()
-		result, err := strategy.Execute(ctx, basePayment, 100.00)
+		result, err := strategy.Execute(ctx, basePayment, payment.PaymentRequest{Amount: 100.00})
 		require.NoError(t, err)
 		assert.True(t, result.Success)
 		assert.Equal(t, 100.00, result.Amount)
+		assert.Equal(t, "instant", result.Strategy)
 	})
 
 	t.Run("Amount Below Minimum", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := strategy.Execute(ctx, basePayment, 0.50)
+		_, err := strategy.Execute(ctx, basePayment, payment.PaymentRequest{Amount: 0.50})
 		assert.Error(t, err)
 	})
 
 	t.Run("Amount Above Maximum", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := strategy.Execute(ctx, basePayment, 15000.00)
+		_, err := strategy.Execute(ctx, basePayment, payment.PaymentRequest{Amount: 15000.00})
 		assert.Error(t, err)
 	})
 }
@@ -0,0 +1,94 @@
+package strategy
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
+	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/validator"
+	"go.uber.org/zap"
+)
+
+const SubscriptionMetadataKey = "subscription"
+
+type RecurringPaymentStrategy struct {
+	minAmount float64
+	maxAmount float64
+	interval  domain.BillingInterval
+	now       func() time.Time
+}
+
+func NewRecurringPaymentStrategy(minAmount, maxAmount float64, interval domain.BillingInterval) *RecurringPaymentStrategy {
+	return &RecurringPaymentStrategy{
+		minAmount: minAmount,
+		maxAmount: maxAmount,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+func (s *RecurringPaymentStrategy) Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	amount := request.Amount
+
+	logger.Info("Executing recurring payment strategy",
+		zap.String("payment_type", payment.GetType()),
+		zap.Float64("amount", amount),
+		zap.String("interval", string(s.interval)),
+	)
+
+	if err := validateLimits(ctx, s, amount); err != nil {
+		return nil, err
+	}
+
+	result, err := payment.Process(ctx, request)
+	if err != nil {
+		logger.Error("Recurring payment initial charge failed",
+			zap.Error(err),
+			zap.Float64("amount", amount),
+		)
+		return nil, errors.Wrap(err, errors.ErrCodePaymentFailed, "recurring payment processing failed")
+	}
+
+	now := s.now()
+	subscription := &domain.Subscription{
+		ID:              domain.NewID(),
+		Amount:          result.Amount,
+		Interval:        s.interval,
+		Status:          domain.SubscriptionStatusActive,
+		NextBillingDate: s.interval.Next(now),
+		ChargeCount:     1,
+		LastChargedAt:   &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+	result.Strategy = s.GetName()
+	result.Metadata["payment_strategy"] = result.Strategy
+	result.Metadata["subscription_id"] = subscription.ID
+	result.Metadata["billing_interval"] = string(s.interval)
+	result.Metadata["next_billing_date"] = subscription.NextBillingDate.Format("2006-01-02")
+	result.Metadata[SubscriptionMetadataKey] = subscription
+
+	logger.Info("Subscription started",
+		zap.String("transaction_id", result.TransactionID),
+		zap.String("subscription_id", subscription.ID),
+		zap.Time("next_billing_date", subscription.NextBillingDate),
+	)
+
+	return result, nil
+}
+
+func (s *RecurringPaymentStrategy) GetName() string {
+	return "recurring"
+}
+
+func (s *RecurringPaymentStrategy) ValidateAmount(amount float64) error {
+	v := validator.NewAmountValidator()
+	return v.Validate(amount, s.minAmount, s.maxAmount)
+}
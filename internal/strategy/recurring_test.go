@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecommerce/payment-system/internal/domain"
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringPaymentStrategy(t *testing.T) {
+	start := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	basePayment, _ := payment.NewCreditCardPayment(
+		"4532015112830366",
+		"John Doe",
+		"12/30",
+		"123",
+	)
+
+	t.Run("Charges First Period And Starts Subscription", func(t *testing.T) {
+		strategy := NewRecurringPaymentStrategy(1.0, 10000.0, domain.BillingIntervalWeekly)
+		strategy.now = func() time.Time { return start }
+
+		result, err := strategy.Execute(context.Background(), basePayment, payment.PaymentRequest{Amount: 49.99})
+		require.NoError(t, err)
+		assert.Equal(t, "recurring", result.Strategy)
+
+		subscription, ok := result.Metadata[SubscriptionMetadataKey].(*domain.Subscription)
+		require.True(t, ok)
+		assert.Equal(t, subscription.ID, result.Metadata["subscription_id"])
+		assert.Equal(t, "2026-02-07", result.Metadata["next_billing_date"])
+		assert.Equal(t, 49.99, subscription.Amount)
+		assert.Equal(t, 1, subscription.ChargeCount)
+		assert.Equal(t, domain.SubscriptionStatusActive, subscription.Status)
+	})
+
+	t.Run("Amount Outside Limits", func(t *testing.T) {
+		strategy := NewRecurringPaymentStrategy(10.0, 100.0, domain.BillingIntervalMonthly)
+		_, err := strategy.Execute(context.Background(), basePayment, payment.PaymentRequest{Amount: 500.00})
+		assert.Error(t, err)
+	})
+}
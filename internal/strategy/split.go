@@ -7,14 +7,74 @@ import (
 	"github.com/ecommerce/payment-system/internal/payment"
 	"github.com/ecommerce/payment-system/pkg/errors"
 	"github.com/ecommerce/payment-system/pkg/logger"
+	"github.com/ecommerce/payment-system/pkg/money"
 	"go.uber.org/zap"
 )
 
+const DefaultSplitTolerance = 0.01
+
 type SplitPaymentStrategy struct {
-	payments []SplitPaymentItem
+	payments    []SplitPaymentItem
+	tolerance   float64
+	residualLeg int
+	// even legs have no fixed amounts; each execution splits the total with
+	// SplitEvenly.
+	even bool
+}
+
+type SplitPaymentConfig struct {
+	Tolerance   float64
+	ResidualLeg int
 }
 
 func NewSplitPaymentStrategy(payments []SplitPaymentItem) (*SplitPaymentStrategy, error) {
+	return NewSplitPaymentStrategyWithConfig(payments, SplitPaymentConfig{
+		Tolerance:   DefaultSplitTolerance,
+		ResidualLeg: len(payments) - 1,
+	})
+}
+
+// NewEvenSplitPaymentStrategy charges the total in equal parts, one per
+// payment, with leftover cents going to the first parts.
+func NewEvenSplitPaymentStrategy(payments []payment.Payment) (*SplitPaymentStrategy, error) {
+	items := make([]SplitPaymentItem, len(payments))
+	for i, p := range payments {
+		items[i] = SplitPaymentItem{Payment: p}
+	}
+
+	s, err := NewSplitPaymentStrategy(items)
+	if err != nil {
+		return nil, err
+	}
+	s.even = true
+	return s, nil
+}
+
+// SplitEvenly divides total into n amounts that sum exactly to it in cents.
+// The remainder is handed out one cent at a time starting with the first
+// part, so $10.00 / 3 is 3.34, 3.33, 3.33.
+func SplitEvenly(total float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+
+	cents := money.ToCents(total)
+	base := cents / int64(n)
+	remainder := cents % int64(n)
+
+	parts := make([]float64, n)
+	for i := range parts {
+		part := base
+		if int64(i) < remainder {
+			part++
+		}
+		parts[i] = money.FromCents(part)
+	}
+
+	return parts
+}
+
+func NewSplitPaymentStrategyWithConfig(payments []SplitPaymentItem, config SplitPaymentConfig) (*SplitPaymentStrategy, error) {
 	if len(payments) == 0 {
 		return nil, errors.NewValidationError("at least one payment method is required")
 	}
@@ -23,12 +83,26 @@ func NewSplitPaymentStrategy(payments []SplitPaymentItem) (*SplitPaymentStrategy
 		return nil, errors.NewValidationError("maximum 5 payment methods allowed for split payment")
 	}
 
+	if config.Tolerance < 0 {
+		return nil, errors.NewValidationError("split tolerance cannot be negative")
+	}
+
+	if config.ResidualLeg < 0 || config.ResidualLeg >= len(payments) {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("residual leg %d is out of range (1-%d)", config.ResidualLeg+1, len(payments)),
+		)
+	}
+
 	return &SplitPaymentStrategy{
-		payments: payments,
+		payments:    payments,
+		tolerance:   config.Tolerance,
+		residualLeg: config.ResidualLeg,
 	}, nil
 }
 
-func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, totalAmount float64) (*payment.PaymentResult, error) {
+func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	totalAmount := request.Amount
+
 	logger.Info("Executing split payment strategy",
 		zap.Float64("total_amount", totalAmount),
 		zap.Int("payment_methods", len(s.payments)),
@@ -37,31 +111,25 @@ func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, t
 	if err := s.ValidateAmount(totalAmount); err != nil {
 		return nil, err
 	}
+	totalAmount = money.Round(totalAmount)
 
-	var splitSum float64
-	for _, item := range s.payments {
-		splitSum += item.Amount
-	}
-
-	if fmt.Sprintf("%.2f", splitSum) != fmt.Sprintf("%.2f", totalAmount) {
-		return nil, errors.NewValidationError(
-			fmt.Sprintf("split payment amounts (%.2f) do not match total amount (%.2f)",
-				splitSum, totalAmount),
-		)
+	legs, err := s.reconcile(totalAmount)
+	if err != nil {
+		return nil, err
 	}
 
 	var processedResults []*payment.PaymentResult
-	var totalProcessed float64
+	var processedCents int64
 
-	for i, item := range s.payments {
+	for i, item := range legs {
 		logger.Info("Processing split payment part",
 			zap.Int("part", i+1),
-			zap.Int("total_parts", len(s.payments)),
+			zap.Int("total_parts", len(legs)),
 			zap.Float64("amount", item.Amount),
 			zap.String("payment_type", item.Payment.GetType()),
 		)
 
-		result, err := item.Payment.Process(ctx, item.Amount)
+		result, err := item.Payment.Process(ctx, request.WithAmount(item.Amount))
 		if err != nil {
 
 			s.rollbackPayments(ctx, processedResults)
@@ -70,7 +138,7 @@ func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, t
 		}
 
 		processedResults = append(processedResults, result)
-		totalProcessed += item.Amount
+		processedCents += money.ToCents(item.Amount)
 	}
 
 	combinedResult := &payment.PaymentResult{
@@ -78,9 +146,10 @@ func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, t
 		TransactionID:   processedResults[0].TransactionID,
 		Amount:          totalAmount,
 		OriginalAmount:  totalAmount,
-		ProcessedAmount: totalProcessed,
-		Currency:        "USD",
+		ProcessedAmount: money.FromCents(processedCents),
+		Currency:        payment.CurrencyFromContext(ctx),
 		PaymentMethod:   "split",
+		Strategy:        "split",
 		Message:         fmt.Sprintf("Split payment completed across %d methods", len(s.payments)),
 		Metadata: map[string]interface{}{
 			"payment_strategy": "split",
@@ -99,6 +168,45 @@ func (s *SplitPaymentStrategy) Execute(ctx context.Context, _ payment.Payment, t
 	return combinedResult, nil
 }
 
+func (s *SplitPaymentStrategy) reconcile(totalAmount float64) ([]SplitPaymentItem, error) {
+	if s.even {
+		legs := make([]SplitPaymentItem, len(s.payments))
+		for i, amount := range SplitEvenly(totalAmount, len(s.payments)) {
+			legs[i] = SplitPaymentItem{Payment: s.payments[i].Payment, Amount: amount}
+		}
+		return legs, nil
+	}
+
+	// Compare in cents: summing the legs as floats can drift a fraction of
+	// a cent away from a total that matches exactly.
+	legs := make([]SplitPaymentItem, len(s.payments))
+	var splitCents int64
+	for i, item := range s.payments {
+		legs[i] = SplitPaymentItem{Payment: item.Payment, Amount: money.Round(item.Amount)}
+		splitCents += money.ToCents(item.Amount)
+	}
+
+	residual := money.ToCents(totalAmount) - splitCents
+	if residual < -money.ToCents(s.tolerance) || residual > money.ToCents(s.tolerance) {
+		return nil, errors.NewValidationError(
+			fmt.Sprintf("split payment amounts (%.2f) do not match total amount (%.2f)",
+				money.FromCents(splitCents), totalAmount),
+		)
+	}
+
+	if residual != 0 {
+		leg := &legs[s.residualLeg]
+		leg.Amount = money.FromCents(money.ToCents(leg.Amount) + residual)
+
+		logger.Debug("Split residual assigned",
+			zap.Int("part", s.residualLeg+1),
+			zap.Float64("residual", money.FromCents(residual)),
+		)
+	}
+
+	return legs, nil
+}
+
 func (s *SplitPaymentStrategy) GetName() string {
 	return fmt.Sprintf("split_%d_methods", len(s.payments))
 }
@@ -108,6 +216,15 @@ func (s *SplitPaymentStrategy) ValidateAmount(amount float64) error {
 		return errors.NewValidationError("amount must be positive")
 	}
 
+	if s.even {
+		if money.ToCents(amount) < int64(len(s.payments)) {
+			return errors.NewValidationError(
+				fmt.Sprintf("amount %.2f is too small to split %d ways", amount, len(s.payments)),
+			)
+		}
+		return nil
+	}
+
 	for i, item := range s.payments {
 		if item.Amount <= 0 {
 			return errors.NewValidationError(
@@ -149,3 +266,29 @@ func (s *SplitPaymentStrategy) getSplitDetails(results []*payment.PaymentResult)
 
 	return details
 }
+
+// SplitPayment runs a split strategy as a payment, so it can sit at the
+// bottom of a decorator chain: decorators price the order once and only the
+// final charge is split across the legs.
+type SplitPayment struct {
+	strategy *SplitPaymentStrategy
+}
+
+func NewSplitPayment(strategy *SplitPaymentStrategy) *SplitPayment {
+	return &SplitPayment{strategy: strategy}
+}
+
+func (p *SplitPayment) Process(ctx context.Context, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	return p.strategy.Execute(ctx, nil, request)
+}
+
+func (p *SplitPayment) GetType() string {
+	return "split"
+}
+
+func (p *SplitPayment) GetDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"type":  "split",
+		"parts": len(p.strategy.payments),
+	}
+}
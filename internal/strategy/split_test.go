@@ -0,0 +1,112 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPaymentStrategy(t *testing.T) {
+	newCard := func() payment.Payment {
+		p, err := payment.NewCreditCardPayment("4532015112830366", "John Doe", "12/30", "123")
+		require.NoError(t, err)
+		return p
+	}
+
+	t.Run("Float Noise Within Tolerance", func(t *testing.T) {
+		strategy, err := NewSplitPaymentStrategy([]SplitPaymentItem{
+			{Payment: newCard(), Amount: 10.1},
+			{Payment: newCard(), Amount: 20.2},
+		})
+		require.NoError(t, err)
+
+		result, err := strategy.Execute(context.Background(), nil, payment.PaymentRequest{Amount: 30.3})
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "split", result.Strategy)
+		assert.InDelta(t, 30.3, result.ProcessedAmount, 1e-9)
+	})
+
+	t.Run("Residual Assigned To Designated Leg", func(t *testing.T) {
+		strategy, err := NewSplitPaymentStrategyWithConfig([]SplitPaymentItem{
+			{Payment: newCard(), Amount: 10.10},
+			{Payment: newCard(), Amount: 20.19},
+		}, SplitPaymentConfig{Tolerance: DefaultSplitTolerance, ResidualLeg: 0})
+		require.NoError(t, err)
+
+		result, err := strategy.Execute(context.Background(), nil, payment.PaymentRequest{Amount: 30.30})
+		require.NoError(t, err)
+
+		details := result.Metadata["split_details"].([]map[string]interface{})
+		assert.InDelta(t, 10.11, details[0]["amount"].(float64), 1e-9)
+		assert.InDelta(t, 20.19, details[1]["amount"].(float64), 1e-9)
+	})
+
+	t.Run("Mismatch Beyond Tolerance", func(t *testing.T) {
+		strategy, err := NewSplitPaymentStrategy([]SplitPaymentItem{
+			{Payment: newCard(), Amount: 10.00},
+			{Payment: newCard(), Amount: 20.00},
+		})
+		require.NoError(t, err)
+
+		_, err = strategy.Execute(context.Background(), nil, payment.PaymentRequest{Amount: 30.05})
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid Residual Leg", func(t *testing.T) {
+		_, err := NewSplitPaymentStrategyWithConfig([]SplitPaymentItem{
+			{Payment: newCard(), Amount: 10.00},
+		}, SplitPaymentConfig{Tolerance: DefaultSplitTolerance, ResidualLeg: 3})
+		assert.Error(t, err)
+	})
+
+	t.Run("Even Split Charges Equal Parts", func(t *testing.T) {
+		strategy, err := NewEvenSplitPaymentStrategy([]payment.Payment{newCard(), newCard(), newCard()})
+		require.NoError(t, err)
+
+		result, err := strategy.Execute(context.Background(), nil, payment.PaymentRequest{Amount: 10.00})
+		require.NoError(t, err)
+		assert.InDelta(t, 10.00, result.ProcessedAmount, 1e-9)
+
+		details := result.Metadata["split_details"].([]map[string]interface{})
+		require.Len(t, details, 3)
+		assert.InDelta(t, 3.34, details[0]["amount"].(float64), 1e-9)
+		assert.InDelta(t, 3.33, details[2]["amount"].(float64), 1e-9)
+
+		_, err = strategy.Execute(context.Background(), nil, payment.PaymentRequest{Amount: 0.02})
+		assert.Error(t, err)
+	})
+}
+
+func TestSplitEvenly(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		total float64
+		n     int
+		want  []float64
+	}{
+		{name: "Divides Evenly", total: 9.00, n: 3, want: []float64{3.00, 3.00, 3.00}},
+		{name: "One Cent Left Over", total: 10.00, n: 3, want: []float64{3.34, 3.33, 3.33}},
+		{name: "Two Cents Left Over", total: 100.01, n: 3, want: []float64{33.34, 33.34, 33.33}},
+		{name: "Float Noise In Total", total: 0.1 + 0.2, n: 2, want: []float64{0.15, 0.15}},
+		{name: "Single Part", total: 12.34, n: 1, want: []float64{12.34}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parts := SplitEvenly(tc.total, tc.n)
+			require.Len(t, parts, len(tc.want))
+
+			cents := int64(0)
+			for i, part := range parts {
+				assert.InDelta(t, tc.want[i], part, 1e-9)
+				cents += int64(math.Round(part * 100))
+			}
+			assert.Equal(t, int64(math.Round(tc.total*100)), cents)
+		})
+	}
+
+	assert.Nil(t, SplitEvenly(10, 0))
+}
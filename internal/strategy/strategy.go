@@ -2,17 +2,32 @@ package strategy
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecommerce/payment-system/internal/domain"
 	"github.com/ecommerce/payment-system/internal/payment"
+	"github.com/ecommerce/payment-system/pkg/errors"
 )
 
 type PaymentStrategy interface {
-	Execute(ctx context.Context, payment payment.Payment, amount float64) (*payment.PaymentResult, error)
+	Execute(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error)
 	GetName() string
 	ValidateAmount(amount float64) error
 }
 
+// validateLimits checks an amount in the charge currency against the
+// strategy's limits, which are set in currency.DefaultCurrency.
+func validateLimits(ctx context.Context, s PaymentStrategy, amount float64) error {
+	converted, err := payment.ToLimitCurrency(ctx, amount)
+	if err != nil {
+		return err
+	}
+	if err := s.ValidateAmount(converted); err != nil {
+		return errors.Wrap(err, errors.ErrCodeValidation, s.GetName()+" strategy limit check failed")
+	}
+	return nil
+}
+
 type PaymentContext struct {
 	strategy PaymentStrategy
 }
@@ -27,8 +42,8 @@ func (c *PaymentContext) SetStrategy(strategy PaymentStrategy) {
 	c.strategy = strategy
 }
 
-func (c *PaymentContext) ExecutePayment(ctx context.Context, payment payment.Payment, amount float64) (*payment.PaymentResult, error) {
-	return c.strategy.Execute(ctx, payment, amount)
+func (c *PaymentContext) ExecutePayment(ctx context.Context, payment payment.Payment, request payment.PaymentRequest) (*payment.PaymentResult, error) {
+	return c.strategy.Execute(ctx, payment, request)
 }
 
 type SplitPaymentItem struct {
@@ -36,28 +51,21 @@ type SplitPaymentItem struct {
 	Amount  float64
 }
 
-type DeferredPaymentSchedule struct {
-	ID           string                       `json:"id"`
-	TotalAmount  float64                      `json:"total_amount"`
-	Installments int                          `json:"installments"`
-	InterestRate float64                      `json:"interest_rate"`
-	Payments     []DeferredPaymentInstallment `json:"payments"`
-}
+type DeferredPaymentSchedule = domain.PaymentSchedule
 
-type DeferredPaymentInstallment struct {
-	InstallmentNumber int     `json:"installment_number"`
-	Amount            float64 `json:"amount"`
-	DueDate           string  `json:"due_date"`
-	Status            string  `json:"status"`
-}
+type DeferredPaymentInstallment = domain.ScheduledInstallment
 
-func CreateDeferredSchedule(amount float64, installments int, interestRate float64) *DeferredPaymentSchedule {
+// CreateDeferredSchedule splits the amount plus interest into equal
+// installments due monthly, the first one on start.
+func CreateDeferredSchedule(amount float64, installments int, interestRate float64, start time.Time) *DeferredPaymentSchedule {
 	schedule := &DeferredPaymentSchedule{
 		ID:           domain.NewID(),
 		TotalAmount:  amount,
 		Installments: installments,
 		InterestRate: interestRate,
 		Payments:     make([]DeferredPaymentInstallment, 0, installments),
+		CreatedAt:    start,
+		UpdatedAt:    start,
 	}
 
 	totalWithInterest := amount * (1 + interestRate/100)
@@ -67,8 +75,8 @@ func CreateDeferredSchedule(amount float64, installments int, interestRate float
 		schedule.Payments = append(schedule.Payments, DeferredPaymentInstallment{
 			InstallmentNumber: i + 1,
 			Amount:            installmentAmount,
-			DueDate:           "",
-			Status:            "pending",
+			DueDate:           start.AddDate(0, i, 0),
+			Status:            domain.InstallmentStatusPending,
 		})
 	}
 
@@ -0,0 +1,2 @@
+-- Record the payment strategy (instant, deferred, split) on each transaction
+ALTER TABLE transactions ADD COLUMN strategy TEXT DEFAULT '';
@@ -0,0 +1,17 @@
+-- Orders track the goods lifecycle separately from the transaction (money) lifecycle
+CREATE TABLE IF NOT EXISTS orders (
+    id TEXT PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    transaction_id TEXT,
+    cart_id TEXT,
+    status TEXT NOT NULL,
+    items TEXT,
+    subtotal REAL NOT NULL,
+    total REAL NOT NULL,
+    fulfillments TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_customer ON orders(customer_id);
@@ -0,0 +1,2 @@
+-- Cashback credited to the customer when cashback.payout is "balance"
+ALTER TABLE customers ADD COLUMN cashback_balance REAL DEFAULT 0;
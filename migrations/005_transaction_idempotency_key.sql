@@ -0,0 +1,5 @@
+-- Idempotency key supplied by the client so a retried checkout is not charged twice
+ALTER TABLE transactions ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key
+    ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
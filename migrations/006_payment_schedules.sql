@@ -0,0 +1,16 @@
+-- Deferred payment schedules so remaining installments can be charged later
+CREATE TABLE IF NOT EXISTS payment_schedules (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT,
+    customer_id TEXT NOT NULL,
+    payment_method TEXT NOT NULL,
+    total_amount REAL NOT NULL,
+    installments INTEGER NOT NULL,
+    interest_rate REAL DEFAULT 0,
+    payments TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_schedules_customer ON payment_schedules(customer_id);
@@ -0,0 +1,13 @@
+-- Discount codes looked up at checkout instead of a hardcoded percentage
+CREATE TABLE IF NOT EXISTS discounts (
+    id TEXT PRIMARY KEY,
+    code TEXT UNIQUE NOT NULL,
+    description TEXT,
+    type TEXT NOT NULL,
+    value REAL NOT NULL,
+    min_amount REAL DEFAULT 0,
+    max_amount REAL DEFAULT 0,
+    expires_at DATETIME,
+    is_active BOOLEAN DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
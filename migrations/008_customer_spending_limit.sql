@@ -0,0 +1,2 @@
+-- Per-customer rolling spend cap; 0 falls back to checkout.spending_limit
+ALTER TABLE customers ADD COLUMN spending_limit REAL DEFAULT 0;
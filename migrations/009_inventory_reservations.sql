@@ -0,0 +1,14 @@
+-- Time-limited stock holds for in-flight checkouts; expired rows are swept
+CREATE TABLE IF NOT EXISTS inventory_reservations (
+    id TEXT PRIMARY KEY,
+    product_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    cart_id TEXT,
+    transaction_id TEXT,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_product ON inventory_reservations(product_id);
+CREATE INDEX IF NOT EXISTS idx_inventory_reservations_transaction ON inventory_reservations(transaction_id);
@@ -0,0 +1,12 @@
+-- Loyalty point movements, recorded from loyalty_changed events
+CREATE TABLE IF NOT EXISTS loyalty_ledger (
+    id TEXT PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    transaction_id TEXT,
+    delta INTEGER NOT NULL,
+    reason TEXT NOT NULL,
+    balance INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_ledger_customer ON loyalty_ledger(customer_id);
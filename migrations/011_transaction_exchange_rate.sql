@@ -0,0 +1,7 @@
+-- Exchange rate frozen on converted transactions so the charged amount can be
+-- reconciled with its base-currency value after rates move
+ALTER TABLE transactions ADD COLUMN exchange_rate REAL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN base_amount REAL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN base_currency TEXT DEFAULT '';
+ALTER TABLE transactions ADD COLUMN display_amount REAL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN display_currency TEXT DEFAULT '';
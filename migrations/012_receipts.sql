@@ -0,0 +1,8 @@
+-- Receipts issued at checkout, kept so they can be exported later
+CREATE TABLE IF NOT EXISTS receipts (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL UNIQUE,
+    customer_id TEXT NOT NULL,
+    data TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- Cart version for optimistic locking: updates only apply when the version
+-- they read is still current, so concurrent add-to-cart calls cannot drop
+-- each other's items
+ALTER TABLE carts ADD COLUMN version INTEGER DEFAULT 0;
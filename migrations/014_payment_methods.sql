@@ -0,0 +1,11 @@
+-- Saved payment methods; only a vault token and masked details are stored
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id TEXT PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    method TEXT NOT NULL,
+    token TEXT NOT NULL UNIQUE,
+    details TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_customer ON payment_methods(customer_id);
@@ -0,0 +1,21 @@
+-- Subscriptions started with the recurring strategy, charged by billing runs
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT,
+    customer_id TEXT NOT NULL,
+    payment_method TEXT NOT NULL,
+    amount REAL NOT NULL,
+    billing_interval TEXT NOT NULL,
+    status TEXT NOT NULL,
+    next_billing_date DATETIME NOT NULL,
+    charge_count INTEGER DEFAULT 0,
+    failed_attempts INTEGER DEFAULT 0,
+    last_transaction_id TEXT DEFAULT '',
+    last_charged_at DATETIME,
+    canceled_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES customers(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_next_billing ON subscriptions(status, next_billing_date);
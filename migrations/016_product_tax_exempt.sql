@@ -0,0 +1,2 @@
+-- Products that are never taxed, whatever their category
+ALTER TABLE products ADD COLUMN tax_exempt BOOLEAN DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Saved payment method charged for later installments and subscription renewals
+ALTER TABLE payment_schedules ADD COLUMN saved_method_id TEXT DEFAULT '';
+ALTER TABLE subscriptions ADD COLUMN saved_method_id TEXT DEFAULT '';
@@ -0,0 +1,12 @@
+-- Cashback balance movements, written with the balance change they record
+CREATE TABLE IF NOT EXISTS cashback_ledger (
+    id TEXT PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    transaction_id TEXT,
+    delta REAL NOT NULL,
+    reason TEXT NOT NULL,
+    balance REAL NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cashback_ledger_customer ON cashback_ledger(customer_id);
@@ -3,22 +3,39 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
 )
 
+// ErrCodeConflict means a request raced a concurrent change (a cart write, a
+// stale quote) and may succeed when retried against fresh state.
+// ErrCodePriceChanged means the cart was repriced and the buyer has to review
+// the new totals before checking out again.
 const (
-	ErrCodeValidation        = "VALIDATION_ERROR"
-	ErrCodeNotFound          = "NOT_FOUND"
-	ErrCodeAlreadyExists     = "ALREADY_EXISTS"
-	ErrCodeUnauthorized      = "UNAUTHORIZED"
-	ErrCodeInternalError     = "INTERNAL_ERROR"
-	ErrCodePaymentFailed     = "PAYMENT_FAILED"
-	ErrCodeInsufficientFunds = "INSUFFICIENT_FUNDS"
-	ErrCodeInvalidPayment    = "INVALID_PAYMENT"
-	ErrCodeFraudDetected     = "FRAUD_DETECTED"
-	ErrCodeInventoryError    = "INVENTORY_ERROR"
-	ErrCodeTimeout           = "TIMEOUT"
+	ErrCodeValidation          = "VALIDATION_ERROR"
+	ErrCodeNotFound            = "NOT_FOUND"
+	ErrCodeAlreadyExists       = "ALREADY_EXISTS"
+	ErrCodeUnauthorized        = "UNAUTHORIZED"
+	ErrCodeInternalError       = "INTERNAL_ERROR"
+	ErrCodePaymentFailed       = "PAYMENT_FAILED"
+	ErrCodeInsufficientFunds   = "INSUFFICIENT_FUNDS"
+	ErrCodeInvalidPayment      = "INVALID_PAYMENT"
+	ErrCodeFraudDetected       = "FRAUD_DETECTED"
+	ErrCodeInventoryError      = "INVENTORY_ERROR"
+	ErrCodeTimeout             = "TIMEOUT"
+	ErrCodeRateLimited         = "RATE_LIMITED"
+	ErrCodeQuoteExpired        = "QUOTE_EXPIRED"
+	ErrCodeConflict            = "CONFLICT"
+	ErrCodeCurrencyUnavailable = "CURRENCY_UNAVAILABLE"
+	ErrCodeTaxUnavailable      = "TAX_UNAVAILABLE"
+	ErrCodeFeatureDisabled     = "FEATURE_DISABLED"
+	ErrCodePriceChanged        = "PRICE_CHANGED"
 )
 
+const DetailRetryAfter = "retry_after"
+
 type AppError struct {
 	Code    string
 	Message string
@@ -59,6 +76,13 @@ func (e *AppError) WithDetails(key string, value interface{}) *AppError {
 	return e
 }
 
+func (e *AppError) WithRetryAfter(retryAfter time.Duration) *AppError {
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	return e.WithDetails(DetailRetryAfter, retryAfter)
+}
+
 func NewValidationError(message string) *AppError {
 	return New(ErrCodeValidation, message)
 }
@@ -103,6 +127,26 @@ func NewTimeoutError(message string) *AppError {
 	return New(ErrCodeTimeout, message)
 }
 
+func NewCurrencyError(from, to string) *AppError {
+	return New(ErrCodeCurrencyUnavailable, fmt.Sprintf("currency conversion unavailable: %s to %s", from, to)).
+		WithDetails("from", from).
+		WithDetails("to", to)
+}
+
+func NewFeatureDisabledError(feature string) *AppError {
+	return New(ErrCodeFeatureDisabled, fmt.Sprintf("feature %s is disabled", feature)).
+		WithDetails("feature", feature)
+}
+
+func NewTaxError(region string, err error) *AppError {
+	return Wrap(err, ErrCodeTaxUnavailable, fmt.Sprintf("tax rate unavailable for region %s", region)).
+		WithDetails("region", region)
+}
+
+func NewRateLimitError(message string, retryAfter time.Duration) *AppError {
+	return New(ErrCodeRateLimited, message).WithRetryAfter(retryAfter)
+}
+
 func IsErrorCode(err error, code string) bool {
 	var appErr *AppError
 	if errors.As(err, &appErr) {
@@ -111,6 +155,124 @@ func IsErrorCode(err error, code string) bool {
 	return false
 }
 
+func HasErrorCode(err error, code string) bool {
+	for err != nil {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			return false
+		}
+		if appErr.Code == code {
+			return true
+		}
+		err = appErr.Err
+	}
+	return false
+}
+
+// Detail returns the first value stored under key along the AppError chain.
+func Detail(err error, key string) (interface{}, bool) {
+	for err != nil {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			return nil, false
+		}
+		if value, ok := appErr.Details[key]; ok {
+			return value, true
+		}
+		err = appErr.Err
+	}
+	return nil, false
+}
+
+// Cause returns the innermost AppError along the chain, whose code is the
+// most specific one, or nil if err holds no AppError.
+func Cause(err error) *AppError {
+	var cause *AppError
+	for err != nil {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			break
+		}
+		cause = appErr
+		err = appErr.Err
+	}
+	return cause
+}
+
+// AllDetails merges the details of every AppError along the chain; outer
+// errors win when a key repeats.
+func AllDetails(err error) map[string]interface{} {
+	details := make(map[string]interface{})
+	for err != nil {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			break
+		}
+		for key, value := range appErr.Details {
+			if _, ok := details[key]; !ok {
+				details[key] = value
+			}
+		}
+		err = appErr.Err
+	}
+	return details
+}
+
+func RetryAfter(err error) (time.Duration, bool) {
+	for err != nil {
+		var appErr *AppError
+		if !errors.As(err, &appErr) {
+			return 0, false
+		}
+		if retryAfter, ok := appErr.Details[DetailRetryAfter].(time.Duration); ok {
+			return retryAfter, true
+		}
+		err = appErr.Err
+	}
+	return 0, false
+}
+
+// WriteRetryAfter sets the Retry-After header from err's retry-after detail,
+// in whole seconds rounded up. Nothing is written when err carries none.
+func WriteRetryAfter(w http.ResponseWriter, err error) {
+	retryAfter, ok := RetryAfter(err)
+	if !ok {
+		return
+	}
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
+var httpStatuses = map[string]int{
+	ErrCodeValidation:          http.StatusBadRequest,
+	ErrCodeInvalidPayment:      http.StatusBadRequest,
+	ErrCodeUnauthorized:        http.StatusUnauthorized,
+	ErrCodePaymentFailed:       http.StatusPaymentRequired,
+	ErrCodeInsufficientFunds:   http.StatusPaymentRequired,
+	ErrCodeFraudDetected:       http.StatusForbidden,
+	ErrCodeNotFound:            http.StatusNotFound,
+	ErrCodeAlreadyExists:       http.StatusConflict,
+	ErrCodeConflict:            http.StatusConflict,
+	ErrCodeInventoryError:      http.StatusConflict,
+	ErrCodeQuoteExpired:        http.StatusGone,
+	ErrCodePriceChanged:        http.StatusConflict,
+	ErrCodeCurrencyUnavailable: http.StatusUnprocessableEntity,
+	ErrCodeTaxUnavailable:      http.StatusServiceUnavailable,
+	ErrCodeFeatureDisabled:     http.StatusForbidden,
+	ErrCodeRateLimited:         http.StatusTooManyRequests,
+	ErrCodeTimeout:             http.StatusGatewayTimeout,
+}
+
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if status, ok := httpStatuses[GetErrorCode(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
 func GetErrorCode(err error) string {
 	var appErr *AppError
 	if errors.As(err, &appErr) {
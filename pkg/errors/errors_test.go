@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	t.Run("Currency Unavailable", func(t *testing.T) {
+		err := NewCurrencyError("USD", "XYZ")
+		assert.Equal(t, http.StatusUnprocessableEntity, HTTPStatus(err))
+		assert.Equal(t, http.StatusUnprocessableEntity, HTTPStatus(fmt.Errorf("quote failed: %w", err)))
+	})
+
+	t.Run("Unknown Errors Are Internal", func(t *testing.T) {
+		assert.Equal(t, http.StatusInternalServerError, HTTPStatus(fmt.Errorf("boom")))
+		assert.Equal(t, http.StatusOK, HTTPStatus(nil))
+	})
+}
+
+func TestWriteRetryAfter(t *testing.T) {
+	t.Run("Rounds Up To Whole Seconds", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		WriteRetryAfter(recorder, NewRateLimitError("slow down", 1500*time.Millisecond))
+		assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("Wrapped Errors", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		err := Wrap(NewRateLimitError("slow down", 30*time.Second), ErrCodePaymentFailed, "charge failed")
+		WriteRetryAfter(recorder, fmt.Errorf("checkout: %w", err))
+		assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	})
+
+	t.Run("No Retry After", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		WriteRetryAfter(recorder, NewValidationError("bad input"))
+		_, ok := recorder.Header()["Retry-After"]
+		assert.False(t, ok)
+	})
+}
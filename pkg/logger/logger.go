@@ -2,12 +2,20 @@ package logger
 
 import (
 	"os"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var log *zap.Logger
+var (
+	log *zap.Logger
+
+	// fallback is used when Init was never called, e.g. in tests; it is
+	// created once so concurrent callers share it.
+	fallback     *zap.Logger
+	fallbackOnce sync.Once
+)
 
 func Init(level, format, output, filePath string) error {
 	var config zap.Config
@@ -41,8 +49,10 @@ func Init(level, format, output, filePath string) error {
 
 func Get() *zap.Logger {
 	if log == nil {
-
-		log, _ = zap.NewDevelopment()
+		fallbackOnce.Do(func() {
+			fallback, _ = zap.NewDevelopment()
+		})
+		return fallback
 	}
 	return log
 }
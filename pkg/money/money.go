@@ -0,0 +1,32 @@
+package money
+
+import "math"
+
+// Amounts are float64 throughout the system but are only meaningful to the
+// cent. Anything computed from a rate (tax, discounts, cashback, loyalty)
+// is rounded here before it is charged, compared or summed, so repeated
+// arithmetic cannot leave fractions of a cent behind.
+
+// ToCents converts an amount to whole cents, rounding half away from zero.
+func ToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func FromCents(cents int64) float64 {
+	return float64(cents) / 100
+}
+
+// Round rounds an amount to 2 decimal places.
+func Round(amount float64) float64 {
+	return FromCents(ToCents(amount))
+}
+
+// Percent returns percentage percent of amount, rounded to the cent.
+func Percent(amount, percentage float64) float64 {
+	return Round(amount * percentage / 100)
+}
+
+// Equal reports whether two amounts are the same to the cent.
+func Equal(a, b float64) bool {
+	return ToCents(a) == ToCents(b)
+}
@@ -0,0 +1,26 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	t.Run("Rounds To Cents", func(t *testing.T) {
+		assert.Equal(t, 3.33, Round(3.333))
+		assert.Equal(t, 3.34, Round(3.335))
+		assert.Equal(t, -3.34, Round(-3.335))
+		assert.Equal(t, 0.3, Round(0.1+0.2))
+	})
+
+	t.Run("Percent", func(t *testing.T) {
+		assert.Equal(t, 3.33, Percent(33.33, 10))
+		assert.Equal(t, 2.67, Percent(33.33, 8))
+	})
+
+	t.Run("Equal Compares Cents", func(t *testing.T) {
+		assert.True(t, Equal(33.33+33.33+33.34, 100))
+		assert.False(t, Equal(99.99, 100))
+	})
+}
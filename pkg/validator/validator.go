@@ -3,19 +3,34 @@ package validator
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
-type CreditCardValidator struct{}
+const (
+	CardBrandVisa       = "visa"
+	CardBrandMastercard = "mastercard"
+	CardBrandAmex       = "amex"
+	CardBrandDiscover   = "discover"
+)
+
+type CreditCardValidator struct {
+	now func() time.Time
+}
 
 func NewCreditCardValidator() *CreditCardValidator {
-	return &CreditCardValidator{}
+	return &CreditCardValidator{now: time.Now}
+}
+
+func normalizeCardNumber(cardNumber string) string {
+	cardNumber = strings.ReplaceAll(cardNumber, " ", "")
+	return strings.ReplaceAll(cardNumber, "-", "")
 }
 
 func (v *CreditCardValidator) ValidateCardNumber(cardNumber string) error {
 
-	cardNumber = strings.ReplaceAll(cardNumber, " ", "")
-	cardNumber = strings.ReplaceAll(cardNumber, "-", "")
+	cardNumber = normalizeCardNumber(cardNumber)
 
 	if len(cardNumber) < 13 || len(cardNumber) > 19 {
 		return fmt.Errorf("invalid card number length")
@@ -49,15 +64,49 @@ func (v *CreditCardValidator) ValidateCardNumber(cardNumber string) error {
 	return nil
 }
 
-func (v *CreditCardValidator) ValidateCVV(cvv string) error {
-	if len(cvv) < 3 || len(cvv) > 4 {
-		return fmt.Errorf("CVV must be 3 or 4 digits")
+// DetectBrand identifies the card network from the issuer prefix alone; it
+// does not check length or the Luhn digit.
+func (v *CreditCardValidator) DetectBrand(cardNumber string) (string, error) {
+	cardNumber = normalizeCardNumber(cardNumber)
+	if len(cardNumber) < 6 || !regexp.MustCompile(`^\d+$`).MatchString(cardNumber) {
+		return "", fmt.Errorf("card number too short to identify the brand")
+	}
+
+	prefix := func(digits int) int {
+		n, _ := strconv.Atoi(cardNumber[:digits])
+		return n
+	}
+
+	switch {
+	case cardNumber[0] == '4':
+		return CardBrandVisa, nil
+	case prefix(2) == 34 || prefix(2) == 37:
+		return CardBrandAmex, nil
+	case prefix(2) >= 51 && prefix(2) <= 55, prefix(4) >= 2221 && prefix(4) <= 2720:
+		return CardBrandMastercard, nil
+	case prefix(4) == 6011, prefix(2) == 65, prefix(3) >= 644 && prefix(3) <= 649,
+		prefix(6) >= 622126 && prefix(6) <= 622925:
+		return CardBrandDiscover, nil
+	default:
+		return "", fmt.Errorf("unsupported card brand")
 	}
+}
 
+// ValidateCVV checks the security code length for the card brand: four
+// digits for American Express, three for the others.
+func (v *CreditCardValidator) ValidateCVV(cvv, brand string) error {
 	if !regexp.MustCompile(`^\d+$`).MatchString(cvv) {
 		return fmt.Errorf("CVV must contain only digits")
 	}
 
+	expected := 3
+	if brand == CardBrandAmex {
+		expected = 4
+	}
+	if len(cvv) != expected {
+		return fmt.Errorf("CVV must be %d digits for %s cards", expected, brand)
+	}
+
 	return nil
 }
 
@@ -83,6 +132,12 @@ func (v *CreditCardValidator) ValidateExpiryDate(expiry string) error {
 		return fmt.Errorf("invalid year in expiry date")
 	}
 
+	// A card stays valid through the last day of its expiry month.
+	now := v.now()
+	if 2000+yearInt < now.Year() || (2000+yearInt == now.Year() && monthInt < int(now.Month())) {
+		return fmt.Errorf("card expired in %s", expiry)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreditCardValidator(t *testing.T) {
+	v := NewCreditCardValidator()
+	v.now = func() time.Time { return time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC) }
+
+	t.Run("Detects Brand From Prefix", func(t *testing.T) {
+		for number, brand := range map[string]string{
+			"4532015112830366":    CardBrandVisa,
+			"5555 5555 5555 4444": CardBrandMastercard,
+			"2221000000000009":    CardBrandMastercard,
+			"378282246310005":     CardBrandAmex,
+			"6011-1111-1111-1117": CardBrandDiscover,
+			"6221260000000000":    CardBrandDiscover,
+		} {
+			detected, err := v.DetectBrand(number)
+			require.NoError(t, err, number)
+			assert.Equal(t, brand, detected, number)
+		}
+
+		_, err := v.DetectBrand("3530111333300000")
+		assert.Error(t, err)
+	})
+
+	t.Run("CVV Length Depends On Brand", func(t *testing.T) {
+		assert.NoError(t, v.ValidateCVV("1234", CardBrandAmex))
+		assert.Error(t, v.ValidateCVV("123", CardBrandAmex))
+		assert.NoError(t, v.ValidateCVV("123", CardBrandVisa))
+		assert.Error(t, v.ValidateCVV("1234", CardBrandVisa))
+		assert.Error(t, v.ValidateCVV("12a", CardBrandVisa))
+	})
+
+	t.Run("Rejects Expired Cards", func(t *testing.T) {
+		assert.NoError(t, v.ValidateExpiryDate("10/26"))
+		assert.NoError(t, v.ValidateExpiryDate("01/27"))
+		assert.Error(t, v.ValidateExpiryDate("09/26"))
+		assert.Error(t, v.ValidateExpiryDate("12/20"))
+		assert.Error(t, v.ValidateExpiryDate("13/30"))
+	})
+}